@@ -1,46 +1,266 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
 	"todo-backend/internal/infrastructure/config"
 	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/logging"
+	"todo-backend/internal/infrastructure/metrics"
+	"todo-backend/internal/infrastructure/ratelimit"
+	"todo-backend/internal/infrastructure/security"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/infrastructure/tracing"
+	"todo-backend/internal/infrastructure/webhook"
 	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
 	"todo-backend/internal/interfaces/routes"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
 )
 
 func main() {
-	cfg, err := config.Load()
-	if err != nil {
-		log.Printf("⚠️  Failed to load configuration: %v", err)
-		log.Println("📝 Using default configuration...")
-		cfg = &config.Config{
-			Server: config.ServerConfig{
-				Host: "0.0.0.0",
-				Port: 8083,
-			},
-			Database: config.DatabaseConfig{
-				Type: "sqlite",
-				File: "todo.db",
-			},
-		}
+	cfg, configErr := config.Load()
+	if configErr != nil {
+		cfg = config.Default()
+	}
+
+	// Every log record, including those of the log package, goes through the
+	// configured logger
+	logger, logLevel, err := logging.New(cfg.Logging, os.Stdout)
+	if err != nil {
+		fatal("Invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+	if configErr != nil {
+		slog.Warn("Failed to load configuration, using the defaults", "error", configErr)
 	} else {
-		log.Printf("Configuration loaded from configs/config.yaml")
+		slog.Info("Configuration loaded", "file", "configs/config.yaml", "environment", cfg.Environment)
 	}
 
 	db, err := database.NewConnection(cfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to SQLite database: %v", err)
+		fatal("Failed to connect to SQLite database", err)
 	}
 
-	todoRepo := database.NewSQLiteTodoRepository(db)
-	todoUseCase := usecases.NewTodoUseCase(todoRepo)
+	auditUseCase := usecases.NewAuditUseCase(database.NewSQLiteAuditRepository(db))
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(verifyAuditChains(db, auditUseCase, os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-projections" {
+		os.Exit(rebuildProjections(cfg, db, os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "adopt-rows" {
+		os.Exit(adoptUnscopedRows(cfg, db, os.Args[2:]))
+	}
+
+	// Kubernetes sends SIGTERM, then SIGKILL after the termination grace period
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background workers are stopped on shutdown, before the database closes
+	workers := server.NewWorkers()
+	readiness := &server.Readiness{}
+
+	// Cleanups run on shutdown once requests are drained, in order
+	cleanups := []func(ctx context.Context) error{workers.Stop}
+
+	todoRepo, err := database.NewTodoRepository(db, cfg.Database)
+	if err != nil {
+		fatal("Failed to set up the todo repository", err)
+	}
+	var tracingMiddleware fiber.Handler
+	if cfg.Tracing.Enabled {
+		provider, err := tracing.NewProvider(context.Background(), cfg.Tracing)
+		if err != nil {
+			fatal("Failed to set up tracing", err)
+		}
+		todoRepo = tracing.NewTodoRepository(todoRepo)
+		tracingMiddleware = middleware.Tracing(otel.GetTracerProvider(), otel.GetTextMapPropagator())
+		cleanups = append(cleanups, provider.Shutdown)
+		slog.Info("Tracing enabled", "exporter", cfg.Tracing.Exporter, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+	var metricsMiddleware fiber.Handler
+	if cfg.Metrics.Enabled {
+		appMetrics, err := newMetrics(cfg.Metrics, db, workers)
+		if err != nil {
+			fatal("Failed to set up metrics", err)
+		}
+		todoRepo = metrics.NewTodoRepository(todoRepo, appMetrics)
+		metricsMiddleware = middleware.Metrics(appMetrics)
+		shutdown, err := serveInternal(cfg.Metrics.Address, adaptor.HTTPHandler(appMetrics.Handler()))
+		if err != nil {
+			fatal("Failed to serve metrics", err)
+		}
+		cleanups = append(cleanups, shutdown)
+	}
+	listRepo := database.NewSQLiteListRepository(db)
+	usageRepo := database.NewSQLiteTenantUsageRepository(db)
+	quotas := func(tenantID string) entities.TenantQuota {
+		quota := cfg.Tenancy.QuotaFor(tenantID)
+		return entities.TenantQuota{MaxTodos: quota.MaxTodos, MaxStorageBytes: quota.MaxStorageBytes}
+	}
+	// The repositories log every change, which is streamed to clients of
+	// /api/todos/stream and read by offline clients of /api/sync
+	changeLogRepo := database.NewSQLiteChangeLogRepository(db)
+	changeStreamUseCase := usecases.NewChangeStreamUseCase(changeLogRepo, listRepo, events.NewBus(), cfg.Stream.Buffer)
+	workers.Every("purge-change-log", time.Hour, func(ctx context.Context) {
+		purgeChangeLog(ctx, db, changeLogRepo, max(cfg.Stream.Retention, cfg.Sync.Retention))
+	})
+	// Domain events are stored in the transaction of the change and relayed
+	// to the subscribers by a worker
+	outboxRepo := database.NewSQLiteOutboxRepository(db)
+	dispatcher := usecases.NewEventDispatcher(outboxRepo)
+	workers.Every("dispatch-events", cfg.Outbox.PollInterval, func(ctx context.Context) {
+		dispatchEvents(ctx, db, dispatcher)
+	})
+	workers.Every("purge-outbox", time.Hour, func(ctx context.Context) {
+		purgeOutbox(ctx, db, dispatcher, cfg.Outbox.Retention)
+	})
+	// Webhook deliveries are enqueued from the outbox and sent by a worker
+	webhookUseCase := usecases.NewWebhookUseCase(database.NewSQLiteWebhookRepository(db), listRepo,
+		webhook.NewHTTPSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks),
+		usecases.WebhookLimits{
+			Timeout:     cfg.Webhooks.Timeout,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BackoffBase: cfg.Webhooks.BackoffBase,
+			BackoffMax:  cfg.Webhooks.BackoffMax,
+		})
+	workers.Every("deliver-webhooks", cfg.Webhooks.PollInterval, func(ctx context.Context) {
+		deliverWebhooks(ctx, db, webhookUseCase)
+	})
+	workers.Every("purge-webhook-deliveries", time.Hour, func(ctx context.Context) {
+		purgeWebhookDeliveries(ctx, db, webhookUseCase, cfg.Webhooks.Retention)
+	})
+	dispatcher.Subscribe("webhooks", webhookUseCase)
+	// The change stream, and the collaboration sessions fed by it, follow the outbox
+	dispatcher.Subscribe("change-stream", changeStreamUseCase)
+	todoUseCase := usecases.NewTodoUseCase(todoRepo,
+		usecases.WithListRepository(listRepo),
+		usecases.WithQuotas(usageRepo, quotas),
+		usecases.WithSync(changeLogRepo, usecases.SyncLimits{Retention: cfg.Sync.Retention, MaxChanges: cfg.Sync.MaxChanges}),
+		usecases.WithOutbox(outboxRepo),
+	)
 	todoHandler := handlers.NewTodoHandler(todoUseCase)
+	// Open streams end on SIGTERM, so that clients resume on another instance
+	changeStreamHandler := handlers.NewChangeStreamHandler(changeStreamUseCase, cfg.Stream.HeartbeatInterval, ctx.Done())
+	jwtManager, err := security.NewJWTManager(cfg.Auth.JWT)
+	if err != nil {
+		fatal("Failed to set up JWT signing keys", err)
+	}
+	hasher := security.NewArgon2idHasher(security.Argon2Params{
+		Memory:      cfg.Auth.Argon2.MemoryKiB,
+		Iterations:  cfg.Auth.Argon2.Iterations,
+		Parallelism: cfg.Auth.Argon2.Parallelism,
+		SaltLength:  security.DefaultArgon2Params.SaltLength,
+		KeyLength:   security.DefaultArgon2Params.KeyLength,
+	})
+	authUseCase := usecases.NewAuthUseCase(
+		database.NewSQLiteUserRepository(db),
+		database.NewSQLiteSessionRepository(db),
+		database.NewSQLiteRefreshTokenRepository(db),
+		hasher,
+		jwtManager,
+		cfg.Auth.SessionTTL,
+		usecases.WithAdminUsernames(cfg.Auth.AdminUsernames...),
+	)
+	authHandler := handlers.NewAuthHandler(authUseCase)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(database.NewSQLiteAPIKeyRepository(db), authUseCase)
+	listUseCase := usecases.NewListUseCase(listRepo, database.NewSQLiteListInviteRepository(db), cfg.Sharing.InviteTTL, usecases.WithListTodos(todoUseCase))
+	commentUseCase := usecases.NewCommentUseCase(todoUseCase, database.NewSQLiteCommentRepository(db))
+	shareLinkUseCase := usecases.NewShareLinkUseCase(listRepo, database.NewSQLiteShareLinkRepository(db), hasher)
+	var rateLimitStore repositories.RateLimitStore
+	switch cfg.RateLimit.Store {
+	case "", "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "database":
+		rateLimitStore = database.NewSQLiteRateLimitStore(db)
+	default:
+		fatal("Invalid rate limit configuration", fmt.Errorf("unknown store %q", cfg.RateLimit.Store))
+	}
+	workers.Every("purge-rate-limit-buckets", time.Minute, func(ctx context.Context) {
+		purgeFullRateLimitBuckets(ctx, rateLimitStore)
+	})
+	rateLimitUseCase := newRateLimitUseCase(cfg, rateLimitStore)
+	var rateLimit, shareLinkRateLimit fiber.Handler
+	if cfg.RateLimit.Enabled {
+		rateLimit = middleware.RateLimit(rateLimitUseCase)
+	}
+	if cfg.Sharing.LinkRateLimit > 0 {
+		shareLinkRateLimit = middleware.RateLimit(rateLimitUseCase)
+	}
+	batchLimits := handlers.BatchLimits{
+		MaxOperations:   cfg.Batch.MaxOperations,
+		MaxPayloadBytes: cfg.Batch.MaxPayloadBytes,
+	}
+	batchHandler := handlers.NewBatchHandler(todoUseCase, batchLimits)
+	collaborationUseCase := usecases.NewCollaborationUseCase(changeStreamUseCase, listRepo, usecases.CollaborationLimits{
+		MaxConnections:        cfg.WebSocket.MaxConnections,
+		MaxConnectionsPerUser: cfg.WebSocket.MaxConnectionsPerUser,
+	})
+	// Like the change streams, WebSockets are closed on SIGTERM
+	collaborationHandler := handlers.NewCollaborationHandler(collaborationUseCase, todoUseCase, handlers.WebSocketSettings{
+		PingInterval:    cfg.WebSocket.PingInterval,
+		PongTimeout:     cfg.WebSocket.PongTimeout,
+		MaxMessageBytes: cfg.WebSocket.MaxMessageBytes,
+		Batch:           batchLimits,
+	}, ctx.Done())
+	idempotencyRepo := database.NewSQLiteIdempotencyRepository(db)
+	workers.Every("purge-idempotency-keys", time.Hour, func(ctx context.Context) {
+		purgeExpiredIdempotencyKeys(ctx, db, idempotencyRepo)
+	})
+
+	cors, err := middleware.CORS(middleware.CORSOptions{
+		AllowOrigins:     cfg.HTTP.CORS.AllowOrigins,
+		AllowMethods:     cfg.HTTP.CORS.AllowMethods,
+		AllowHeaders:     cfg.HTTP.CORS.AllowHeaders,
+		ExposeHeaders:    cfg.HTTP.CORS.ExposeHeaders,
+		AllowCredentials: cfg.HTTP.CORS.AllowCredentials,
+		MaxAge:           cfg.HTTP.CORS.MaxAge,
+	})
+	if err != nil {
+		fatal("Invalid CORS configuration", err)
+	}
+
+	var clientCertificate fiber.Handler
+	if cfg.Server.TLS.Enabled && cfg.Server.TLS.ClientCAFile != "" {
+		serviceAuthUseCase, err := newServiceAuthUseCase(cfg.Server.TLS)
+		if err != nil {
+			fatal("Invalid TLS service clients", err)
+		}
+		clientCertificate = middleware.ClientCertificate(serviceAuthUseCase)
+	}
 
 	app := fiber.New(fiber.Config{
+		// The server start is logged instead
+		DisableStartupMessage: true,
+		// Oversized bodies and headers are refused while the request is read
+		BodyLimit:      cfg.HTTP.MaxBodyBytes,
+		ReadBufferSize: cfg.HTTP.MaxHeaderBytes,
+		// Client IPs, which rate limits are keyed by, are only taken from
+		// the proxy header on requests from a trusted proxy
+		ProxyHeader:             cfg.HTTP.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.HTTP.TrustedProxies,
+		EnableIPValidation:      true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			var e *fiber.Error
@@ -54,19 +274,420 @@ func main() {
 		},
 	})
 
-	routes.SetupRoutes(app, todoHandler)
-	log.Println("✅ Routes configured")
+	routes.SetupRoutes(app, routes.Dependencies{
+		TodoHandler:          todoHandler,
+		BatchHandler:         batchHandler,
+		AuthHandler:          authHandler,
+		APIKeyHandler:        handlers.NewAPIKeyHandler(apiKeyUseCase),
+		ListHandler:          handlers.NewListHandler(listUseCase),
+		CommentHandler:       handlers.NewCommentHandler(commentUseCase),
+		ChangeStreamHandler:  changeStreamHandler,
+		CollaborationHandler: collaborationHandler,
+		SyncHandler:          handlers.NewSyncHandler(todoUseCase, cfg.Sync.MaxMutations),
+		WebhookHandler:       handlers.NewWebhookHandler(webhookUseCase),
+		ShareLinkHandler:     handlers.NewShareLinkHandler(shareLinkUseCase),
+		ShareLinkRateLimit:   shareLinkRateLimit,
+		TenantHandler:        handlers.NewTenantHandler(usecases.NewTenantUseCase(usageRepo, quotas)),
+		AuditHandler:         handlers.NewAuditHandler(auditUseCase),
+		RateLimitHandler:     handlers.NewRateLimitHandler(rateLimitUseCase),
+		LogLevelHandler:      handlers.NewLogLevelHandler(logLevel),
+		Logger:               middleware.RequestLogger(logger),
+		RequestID:            middleware.RequestID(),
+		Tracing:              tracingMiddleware,
+		Metrics:              metricsMiddleware,
+		RateLimit:            rateLimit,
+		Audit:                middleware.Audit(auditUseCase),
+		Authenticate:         middleware.Authenticate(authUseCase, apiKeyUseCase),
+		ClientCertificate:    clientCertificate,
+		Ready:                readiness.Ready,
+		HealthHandler:        handlers.NewHealthHandler(newHealthUseCase(cfg, db, workers, readiness)),
+		Idempotency:          middleware.Idempotency(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.Lease),
+		CORS:                 cors,
+		SecurityHeaders: middleware.SecurityHeaders(middleware.SecurityHeadersOptions{
+			ContentSecurityPolicy: cfg.HTTP.SecurityHeaders.ContentSecurityPolicy,
+			HSTSMaxAge:            cfg.HTTP.SecurityHeaders.HSTSMaxAge,
+			HSTSIncludeSubdomains: cfg.HTTP.SecurityHeaders.HSTSIncludeSubdomains,
+			HSTSPreload:           cfg.HTTP.SecurityHeaders.HSTSPreload,
+			FrameOptions:          cfg.HTTP.SecurityHeaders.FrameOptions,
+			ReferrerPolicy:        cfg.HTTP.SecurityHeaders.ReferrerPolicy,
+		}),
+		LimitJSONBody: middleware.LimitJSONBody(cfg.HTTP.MaxJSONBytes),
+		Tenant: middleware.ResolveTenant(middleware.TenantOptions{
+			Enabled:    cfg.Tenancy.Enabled,
+			Default:    cfg.Tenancy.DefaultTenant,
+			Header:     cfg.Tenancy.Header,
+			BaseDomain: cfg.Tenancy.BaseDomain,
+			Allowed:    cfg.Tenancy.Tenants,
+		}),
+	})
+	for _, route := range app.GetRoutes(true) {
+		slog.Debug("Route", "method", route.Method, "path", route.Path)
+	}
+	slog.Info("Routes configured", "routes", len(app.GetRoutes(true)))
+
+	tlsConfig, err := newTLSConfig(cfg.Server.TLS, workers)
+	if err != nil {
+		fatal("Failed to set up TLS", err)
+	}
+	ln, err := server.Listen(server.ListenOptions{
+		Address: cfg.GetServerAddress(),
+		Socket:  cfg.Server.Socket,
+		TLS:     tlsConfig,
+	})
+	if err != nil {
+		fatal("Failed to start server", err)
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	slog.Info("Server starting", "address", ln.Addr().String(), "scheme", scheme)
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down, draining requests in flight", "drain_delay", cfg.Server.DrainDelay.String())
+	}()
+	err = server.Serve(ctx, app, ln, server.ShutdownOptions{
+		Readiness:  readiness,
+		DrainDelay: cfg.Server.DrainDelay,
+		Timeout:    cfg.Server.ShutdownTimeout,
+	}, append(cleanups, func(context.Context) error {
+		return database.Close(db)
+	})...)
+	if err != nil {
+		fatal("Server did not shut down cleanly", err)
+	}
+	slog.Info("Server stopped")
+}
+
+// newTLSConfig loads the serving certificate, reloading it whenever its files
+// change. It returns nil when TLS is disabled.
+func newTLSConfig(cfg config.TLSConfig, workers *server.Workers) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	certs, err := security.NewCertificateReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	workers.Go(func(ctx context.Context) {
+		err := certs.Watch(ctx, func(err error) {
+			slog.ErrorContext(ctx, "Failed to reload TLS certificate", "error", err)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "TLS certificate will not be reloaded", "error", err)
+		}
+	})
+	return security.NewTLSConfig(cfg, certs)
+}
+
+// serveInternal serves the internal routes on their own listener at address,
+// and returns the function that shuts it down
+func serveInternal(address string, metricsHandler fiber.Handler) (func(ctx context.Context) error, error) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.SetupInternalRoutes(app, metricsHandler)
+	ln, err := server.Listen(server.ListenOptions{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := app.Listener(ln); err != nil {
+			slog.Error("Internal listener stopped", "error", err)
+		}
+	}()
+	slog.Info("Serving metrics", "address", ln.Addr().String())
+	return app.ShutdownWithContext, nil
+}
+
+// newMetrics creates the Prometheus metrics of the database pool and the
+// background workers, and counts todos by state periodically
+func newMetrics(cfg config.MetricsConfig, db *gorm.DB, workers *server.Workers) (*metrics.Metrics, error) {
+	appMetrics := metrics.New()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := appMetrics.RegisterDB("main", sqlDB); err != nil {
+		return nil, err
+	}
+	if err := appMetrics.RegisterWorkers(workers); err != nil {
+		return nil, err
+	}
+
+	countTodos := func(ctx context.Context) {
+		counts, err := database.CountTodosByState(ctx, db)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to count todos", "error", err)
+			return
+		}
+		appMetrics.SetTodoCounts(counts)
+	}
+	countTodos(context.Background())
+	workers.Every("count-todos", cfg.TodoCountInterval, countTodos)
+	return appMetrics, nil
+}
+
+// newHealthUseCase registers the probe checks. Liveness only fails when the
+// process is stuck, so that a database outage does not restart every
+// instance; readiness covers the dependencies.
+func newHealthUseCase(cfg *config.Config, db *gorm.DB, workers *server.Workers, readiness *server.Readiness) *usecases.HealthUseCase {
+	dbCheck := func(name string, check func(ctx context.Context, db *gorm.DB) error) usecases.HealthCheck {
+		return usecases.HealthCheck{
+			Name:     name,
+			Check:    func(ctx context.Context) error { return check(ctx, db) },
+			Timeout:  cfg.Health.Timeout,
+			CacheTTL: cfg.Health.CacheTTL,
+		}
+	}
+	ping := dbCheck("database", database.Ping)
+	migrations := dbCheck("migrations", database.CheckMigrations)
+
+	healthUseCase := usecases.NewHealthUseCase()
+	healthUseCase.Register(usecases.ProbeLiveness,
+		usecases.HealthCheck{Name: "workers", Check: workers.CheckHeartbeats, Timeout: cfg.Health.Timeout},
+	)
+	healthUseCase.Register(usecases.ProbeReadiness,
+		usecases.HealthCheck{Name: "serving", Check: readiness.Check},
+		ping,
+		dbCheck("database-write", database.ProbeWrite),
+		migrations,
+	)
+	if cfg.Health.MinFreeDiskBytes > 0 {
+		dir := filepath.Dir(cfg.Database.File)
+		healthUseCase.Register(usecases.ProbeReadiness, usecases.HealthCheck{
+			Name:     "disk",
+			Check:    func(context.Context) error { return database.CheckFreeSpace(dir, cfg.Health.MinFreeDiskBytes) },
+			Timeout:  cfg.Health.Timeout,
+			CacheTTL: cfg.Health.CacheTTL,
+		})
+	}
+	healthUseCase.Register(usecases.ProbeStartup, ping, migrations)
+	return healthUseCase
+}
+
+// newServiceAuthUseCase maps the configured client certificate subjects to service principals
+func newServiceAuthUseCase(cfg config.TLSConfig) (*usecases.ServiceAuthUseCase, error) {
+	clients := make([]usecases.ServiceClient, len(cfg.ServiceClients))
+	for i, client := range cfg.ServiceClients {
+		scopes := make([]identity.Scope, len(client.Scopes))
+		for j, scope := range client.Scopes {
+			scopes[j] = identity.Scope(scope)
+		}
+		clients[i] = usecases.ServiceClient{
+			Subject:  client.Subject,
+			Name:     client.Name,
+			Scopes:   scopes,
+			TenantID: client.Tenant,
+		}
+	}
+	return usecases.NewServiceAuthUseCase(clients...)
+}
+
+// purgeExpiredIdempotencyKeys deletes idempotency keys past their TTL in every tenant
+func purgeExpiredIdempotencyKeys(ctx context.Context, db *gorm.DB, repo repositories.IdempotencyRepository) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := repo.DeleteExpired(ctx, time.Now())
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge expired idempotency keys", "error", err)
+	}
+}
+
+// purgeChangeLog deletes the changes past their retention in every tenant
+func purgeChangeLog(ctx context.Context, db *gorm.DB, repo repositories.ChangeLogRepository, retention time.Duration) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := repo.DeleteBefore(ctx, time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge the change log", "error", err)
+	}
+}
+
+// dispatchEvents relays the events stored in the outbox of every tenant
+func dispatchEvents(ctx context.Context, db *gorm.DB, dispatcher *usecases.EventDispatcher) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := dispatcher.Dispatch(ctx)
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to dispatch events", "error", err)
+	}
+}
+
+// purgeOutbox deletes the handled outbox events past their retention in every tenant
+func purgeOutbox(ctx context.Context, db *gorm.DB, dispatcher *usecases.EventDispatcher, retention time.Duration) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := dispatcher.Purge(ctx, time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge the outbox", "error", err)
+	}
+}
+
+// deliverWebhooks sends the webhook deliveries that are due in every tenant
+func deliverWebhooks(ctx context.Context, db *gorm.DB, uc *usecases.WebhookUseCase) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := uc.DeliverDue(ctx)
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to deliver webhooks", "error", err)
+	}
+}
+
+// purgeWebhookDeliveries deletes the finished webhook deliveries past their retention in every tenant
+func purgeWebhookDeliveries(ctx context.Context, db *gorm.DB, uc *usecases.WebhookUseCase, retention time.Duration) {
+	err := database.ForEachTenant(ctx, db, func(ctx context.Context) error {
+		_, err := uc.PurgeDeliveries(ctx, time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge webhook deliveries", "error", err)
+	}
+}
+
+// newRateLimitUseCase builds the rate limit policies: the share link limit,
+// and the configured route and default policies when rate limiting is enabled
+func newRateLimitUseCase(cfg *config.Config, store repositories.RateLimitStore) *usecases.RateLimitUseCase {
+	fallback := entities.RateLimitPolicy{Name: "default"}
+	var rules []usecases.RateLimitRule
+	if cfg.Sharing.LinkRateLimit > 0 {
+		rules = append(rules, usecases.RateLimitRule{
+			Method: fiber.MethodGet,
+			Path:   "/s/:token",
+			Policy: entities.RateLimitPolicy{Name: "share-link", Requests: cfg.Sharing.LinkRateLimit, Period: cfg.Sharing.LinkRateWindow},
+		})
+	}
+	if cfg.RateLimit.Enabled {
+		fallback = rateLimitPolicy("default", cfg.RateLimit.Default)
+		for _, route := range cfg.RateLimit.Routes {
+			rules = append(rules, usecases.RateLimitRule{
+				Method: route.Method,
+				Path:   route.Path,
+				Policy: rateLimitPolicy(route.Name, route.RateLimitPolicyConfig),
+			})
+		}
+	}
+	return usecases.NewRateLimitUseCase(store, fallback, rules...)
+}
+
+func rateLimitPolicy(name string, cfg config.RateLimitPolicyConfig) entities.RateLimitPolicy {
+	return entities.RateLimitPolicy{Name: name, Requests: cfg.Requests, Period: cfg.Period, Burst: cfg.Burst}
+}
+
+// purgeFullRateLimitBuckets drops buckets that have refilled, which are the same as new ones
+func purgeFullRateLimitBuckets(ctx context.Context, store repositories.RateLimitStore) {
+	if _, err := store.DeleteFull(ctx, time.Now()); err != nil {
+		slog.ErrorContext(ctx, "Failed to purge rate limit buckets", "error", err)
+	}
+}
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
 
-	log.Println("\n📋 Available Endpoints:")
-	log.Println("  GET    /health           - Health check")
-	log.Println("  GET    /api/todos        - List all todos")
-	log.Println("  POST   /api/todos        - Create new todo")
+// verifyAuditChains implements the verify command: it checks the audit chain
+// of one tenant, or of every tenant, and returns the process exit code
+func verifyAuditChains(db *gorm.DB, auditUseCase *usecases.AuditUseCase, args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	tenantID := flags.String("tenant", "", "verify only this tenant's chain")
+	_ = flags.Parse(args)
 
-	serverAddr := cfg.GetServerAddress()
-	log.Printf("\n🌐 Server starting on %s", serverAddr)
-	log.Printf("🎯 API Base URL: http://%s/api", serverAddr)
+	intact := true
+	verify := func(ctx context.Context) error {
+		results, err := auditUseCase.VerifyAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			fmt.Printf("tenant %q: %d events, last seq %d, last hash %s\n", result.TenantID, result.Events, result.LastSeq, result.LastHash)
+			for _, problem := range result.Problems {
+				fmt.Printf("  seq %d: %s - %s\n", problem.Seq, problem.Kind, problem.Detail)
+			}
+			if !result.OK() {
+				intact = false
+			}
+		}
+		return nil
+	}
 
-	if err := app.Listen(serverAddr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	var err error
+	if *tenantID != "" {
+		err = verify(tenancy.WithTenant(context.Background(), *tenantID))
+	} else {
+		err = database.ForEachTenant(context.Background(), db, verify)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to verify audit log: %v\n", err)
+		return 2
+	}
+	if !intact {
+		fmt.Println("❌ Audit log has been tampered with")
+		return 1
+	}
+	fmt.Println("✅ Audit log is intact")
+	return 0
+}
+
+// rebuildProjections implements the rebuild-projections command: it replays
+// the todo streams of one tenant, or of every tenant, into the todos table
+// and returns the process exit code
+func rebuildProjections(cfg *config.Config, db *gorm.DB, args []string) int {
+	flags := flag.NewFlagSet("rebuild-projections", flag.ExitOnError)
+	tenantID := flags.String("tenant", "", "rebuild only this tenant's projection")
+	_ = flags.Parse(args)
+
+	// In state mode the todos table is the source of truth, and the streams
+	// are not kept up to date
+	if cfg.Database.Mode != database.TodoModeEventSourced {
+		fmt.Fprintf(os.Stderr, "Projections are only kept with database.mode %q\n", database.TodoModeEventSourced)
+		return 2
+	}
+
+	projection := database.NewSQLiteTodoProjection(db)
+	projected := 0
+	rebuild := func(ctx context.Context) error {
+		n, err := projection.Rebuild(ctx)
+		projected += n
+		return err
+	}
+
+	var err error
+	if *tenantID != "" {
+		err = rebuild(tenancy.WithTenant(context.Background(), *tenantID))
+	} else {
+		err = database.ForEachTenant(context.Background(), db, rebuild)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to rebuild projections: %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ Rebuilt the todo projection: %d todos\n", projected)
+	return 0
+}
+
+// adoptUnscopedRows implements the adopt-rows command: it assigns the rows
+// stored before tenancy was introduced to a tenant, the default tenant unless
+// -tenant names another. Run it once when upgrading such a database.
+func adoptUnscopedRows(cfg *config.Config, db *gorm.DB, args []string) int {
+	flags := flag.NewFlagSet("adopt-rows", flag.ExitOnError)
+	tenantID := flags.String("tenant", cfg.Tenancy.DefaultTenant, "assign the rows to this tenant")
+	_ = flags.Parse(args)
+
+	if err := tenancy.ValidateID(*tenantID); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid tenant: %v\n", err)
+		return 2
+	}
+	adopted, err := database.AdoptUnscopedRows(context.Background(), db, *tenantID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to adopt rows: %v\n", err)
+		return 1
 	}
+	fmt.Printf("✅ Assigned %d rows to tenant %q\n", adopted, *tenantID)
+	return 0
 }
@@ -0,0 +1,336 @@
+// Package patch applies RFC 7396 JSON Merge Patch and RFC 6902 JSON Patch
+// documents to JSON objects decoded into map[string]interface{}.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is wrapped by errors for malformed patches and operations that cannot be applied
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrTestFailed is wrapped when a JSON Patch "test" operation does not match
+	ErrTestFailed = errors.New("patch test failed")
+	// ErrFieldNotAllowed is wrapped when a patch modifies a field outside the allowlist
+	ErrFieldNotAllowed = errors.New("field cannot be modified")
+)
+
+// Operation is a single RFC 6902 operation
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyMergePatch merges patch into doc following RFC 7396. Only top-level
+// members listed in mutable may be changed.
+func ApplyMergePatch(doc map[string]interface{}, patch []byte, mutable map[string]bool) (map[string]interface{}, error) {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: merge patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	for field := range changes {
+		if !mutable[field] {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+		}
+	}
+
+	return mergeObject(deepCopy(doc).(map[string]interface{}), changes), nil
+}
+
+func mergeObject(target, changes map[string]interface{}) map[string]interface{} {
+	for key, value := range changes {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			targetObject, ok := target[key].(map[string]interface{})
+			if !ok {
+				targetObject = map[string]interface{}{}
+			}
+			target[key] = mergeObject(targetObject, patchObject)
+			continue
+		}
+		target[key] = value
+	}
+	return target
+}
+
+// ApplyJSONPatch applies the operations in patch to doc following RFC 6902.
+// The operations are applied to a copy, so doc is left untouched when any
+// operation fails. Operations other than "test" may only touch top-level
+// members listed in mutable.
+func ApplyJSONPatch(doc map[string]interface{}, patch []byte, mutable map[string]bool) (map[string]interface{}, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: JSON patch must be an array of operations", ErrInvalidPatch)
+	}
+
+	var result interface{} = deepCopy(doc)
+	for i, op := range ops {
+		var err error
+		result, err = applyOperation(result, op, mutable)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	object, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: patch replaced the document with a non-object", ErrInvalidPatch)
+	}
+	return object, nil
+}
+
+func applyOperation(doc interface{}, op Operation, mutable map[string]bool) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Op != "test" {
+		if err := checkMutable(path, mutable); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := get(doc, path); err != nil {
+				return nil, err
+			}
+			removed, err := remove(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			return add(removed, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrTestFailed, err)
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if err := checkMutable(from, mutable); err != nil {
+				return nil, err
+			}
+			if len(path) > len(from) && isPrefix(from, path) {
+				return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidPatch)
+			}
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		}
+		return add(doc, path, deepCopy(value))
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+func checkMutable(path []string, mutable map[string]bool) error {
+	if len(path) == 0 || !mutable[path[0]] {
+		return fmt.Errorf("%w: /%s", ErrFieldNotAllowed, strings.Join(path, "/"))
+	}
+	return nil
+}
+
+func decodeValue(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("%w: invalid value", ErrInvalidPatch)
+	}
+	return value, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path not found", ErrInvalidPatch)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: path not found", ErrInvalidPatch)
+		}
+	}
+	return current, nil
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		index := len(node)
+		if last != "-" {
+			if index, err = arrayIndex(last, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		updated := append(node[:index:index], append([]interface{}{value}, node[index:]...)...)
+		return replaceParent(doc, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("%w: path not found", ErrInvalidPatch)
+	}
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("%w: path not found", ErrInvalidPatch)
+		}
+		delete(node, last)
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated := append(node[:index:index], node[index+1:]...)
+		return replaceParent(doc, path[:len(path)-1], updated)
+	default:
+		return nil, fmt.Errorf("%w: path not found", ErrInvalidPatch)
+	}
+}
+
+// replaceParent stores a resized array back into its container
+func replaceParent(doc interface{}, path []string, array []interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return array, nil
+	}
+
+	container, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := container.(type) {
+	case map[string]interface{}:
+		node[last] = array
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = array
+	}
+	return doc, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	return index, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(node))
+		for key, child := range node {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(node))
+		for i, child := range node {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
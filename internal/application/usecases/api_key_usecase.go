@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const (
+	// apiKeyMarker starts every API key so that it can be told apart from an access token
+	apiKeyMarker = "tdk_"
+	// apiKeyPrefixBytes is the random part of the public key prefix
+	apiKeyPrefixBytes   = 6
+	maxAPIKeyNameLength = 100
+	// lastUsedResolution limits how often authentication writes last-used timestamps
+	lastUsedResolution = time.Minute
+)
+
+// CreatedAPIKey is a new API key together with its secret, which is never shown again
+type CreatedAPIKey struct {
+	Key    *entities.APIKey
+	Secret string
+}
+
+// UserScopeResolver looks up the scopes a user holds right now
+type UserScopeResolver interface {
+	UserScopes(ctx context.Context, userID string) ([]identity.Scope, error)
+}
+
+type APIKeyUseCase struct {
+	keyRepo repositories.APIKeyRepository
+	users   UserScopeResolver
+}
+
+func NewAPIKeyUseCase(keyRepo repositories.APIKeyRepository, users UserScopeResolver) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		keyRepo: keyRepo,
+		users:   users,
+	}
+}
+
+// CreateAPIKey issues a key for the caller. A key can only carry scopes the caller holds.
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+
+	if err := requireSession(ctx); err != nil {
+		return nil, err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidInput, maxAPIKeyNameLength)
+	}
+	scopes, err := validateScopes(principal, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiresAt must be in the future", ErrInvalidInput)
+	}
+
+	prefix, err := newAPIKeyPrefix()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	token := apiKeyMarker + prefix + "_" + secret
+
+	key := entities.NewAPIKey(principal.UserID, name, prefix, hashToken(token), scopes, req.ExpiresAt)
+	if err := uc.keyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionAPIKeyCreated, key.ID, "", audit.Hash(key))
+	return &CreatedAPIKey{Key: key, Secret: token}, nil
+}
+
+// ListAPIKeys returns the caller's keys without their secrets
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context) ([]*entities.APIKey, error) {
+
+	if err := requireSession(ctx); err != nil {
+		return nil, err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	keys, err := uc.keyRepo.ListByUser(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes one of the caller's keys
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+
+	if err := requireSession(ctx); err != nil {
+		return err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	if err := uc.keyRepo.Delete(ctx, id, principal.UserID); err != nil {
+		if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionAPIKeyRevoked, id, "", "")
+	return nil
+}
+
+// Authenticate resolves an API key to a principal limited to the key's scopes.
+// Scopes the user has lost since the key was created are dropped.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, token string) (*identity.Principal, error) {
+
+	rest, ok := strings.CutPrefix(token, apiKeyMarker)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+	prefix, _, ok := strings.Cut(rest, "_")
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+
+	key, err := uc.keyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+			return nil, identity.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(key.KeyHash)) != 1 {
+		return nil, identity.ErrUnauthenticated
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		return nil, identity.ErrUnauthenticated
+	}
+	held, err := uc.users.UserScopes(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := heldScopes(key.Scopes, held)
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := uc.keyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to record api key use: %w", err)
+		}
+	}
+
+	tenantID, _ := tenancy.FromContext(ctx)
+	return &identity.Principal{
+		UserID:   key.UserID,
+		APIKeyID: key.ID,
+		Scopes:   scopes,
+		TenantID: tenantID,
+	}, nil
+}
+
+// heldScopes keeps the scopes the user holds that the key also grants, so an admin
+// key held by a user who is no longer an admin falls back to the user's own scopes
+func heldScopes(keyScopes, held []identity.Scope) []identity.Scope {
+	key := &identity.Principal{Scopes: keyScopes}
+	scopes := make([]identity.Scope, 0, len(held))
+	for _, scope := range held {
+		if key.HasScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// validateScopes parses requested scopes, rejecting unknown ones and those the caller lacks
+func validateScopes(principal *identity.Principal, requested []string) ([]identity.Scope, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+
+	scopes := make([]identity.Scope, 0, len(requested))
+	seen := make(map[identity.Scope]bool)
+	for _, raw := range requested {
+		scope := identity.Scope(raw)
+		if !scope.IsKnown() {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, raw)
+		}
+		if !principal.HasScope(scope) {
+			return nil, fmt.Errorf("%w: cannot grant scope %q", identity.ErrForbidden, raw)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func newAPIKeyPrefix() (string, error) {
+	buf := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key prefix: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const (
+	defaultAuditQueryLimit = 100
+	maxAuditQueryLimit     = 1000
+	// auditScanPageSize is how many events chain verification loads at a time
+	auditScanPageSize = 500
+)
+
+// Problems chain verification can detect
+const (
+	// AuditProblemGap means events are missing from the sequence
+	AuditProblemGap = "gap"
+	// AuditProblemBrokenLink means an event does not reference the hash of its predecessor
+	AuditProblemBrokenLink = "broken_link"
+	// AuditProblemTampered means an event's content no longer matches its hash
+	AuditProblemTampered = "tampered"
+)
+
+// AuditRecord describes a completed mutating request
+type AuditRecord struct {
+	// Action and TargetID describe the request itself; they are recorded when
+	// it observed no changes, e.g. because it failed
+	Action    string
+	TargetID  string
+	Status    int
+	RequestID string
+	ClientIP  string
+	// Changes are the changes the request made; each is recorded as an event
+	Changes []audit.Change
+}
+
+// AuditChainProblem is one inconsistency found in an audit chain
+type AuditChainProblem struct {
+	Seq    int64
+	Kind   string
+	Detail string
+}
+
+// AuditVerification is the outcome of verifying a tenant's audit chain. A
+// chain that verifies can still have been truncated or rewritten as a whole;
+// compare LastSeq and LastHash with a previously recorded value to rule that out.
+type AuditVerification struct {
+	TenantID string
+	Events   int64
+	LastSeq  int64
+	LastHash string
+	Problems []AuditChainProblem
+}
+
+// OK reports whether the chain is intact
+func (v *AuditVerification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+type AuditUseCase struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewAuditUseCase(auditRepo repositories.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record appends a completed request to the audit log of its tenant: one
+// event per change it made, or a single event if it made none
+func (uc *AuditUseCase) Record(ctx context.Context, record AuditRecord) error {
+
+	tenantID, err := tenancy.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes := record.Changes
+	if len(changes) == 0 {
+		changes = []audit.Change{{Action: record.Action, TargetID: record.TargetID}}
+	}
+	for _, change := range changes {
+		event := entities.NewAuditEvent(change.Action, change.TargetID)
+		event.TenantID = tenantID
+		if principal, ok := identity.FromContext(ctx); ok {
+			event.ActorID = principal.UserID
+			event.APIKeyID = principal.APIKeyID
+		}
+		if change.ActorID != "" {
+			event.ActorID = change.ActorID
+		}
+		event.BeforeHash = change.BeforeHash
+		event.AfterHash = change.AfterHash
+		event.Status = record.Status
+		event.RequestID = record.RequestID
+		event.ClientIP = record.ClientIP
+
+		if err := uc.auditRepo.Append(ctx, event); err != nil {
+			return fmt.Errorf("failed to record audit event: %w", err)
+		}
+	}
+	return nil
+}
+
+// QueryEvents returns the audit events of the caller's tenant, newest first
+func (uc *AuditUseCase) QueryEvents(ctx context.Context, req dto.AuditQueryRequest) ([]*entities.AuditEvent, error) {
+
+	if _, err := identity.RequireScope(ctx, identity.ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	filter := repositories.AuditFilter{
+		ActorID:   req.ActorID,
+		Action:    req.Action,
+		TargetID:  req.TargetID,
+		BeforeSeq: req.BeforeSeq,
+		Limit:     req.Limit,
+	}
+	var err error
+	if filter.Since, err = parseOptionalTime("since", req.Since); err != nil {
+		return nil, err
+	}
+	if filter.Until, err = parseOptionalTime("until", req.Until); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditQueryLimit
+	}
+	if filter.Limit > maxAuditQueryLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrInvalidInput, maxAuditQueryLimit)
+	}
+
+	events, err := uc.auditRepo.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	return events, nil
+}
+
+// VerifyChain checks the audit chain of the caller's tenant
+func (uc *AuditUseCase) VerifyChain(ctx context.Context) (*AuditVerification, error) {
+
+	if _, err := identity.RequireScope(ctx, identity.ScopeAdmin); err != nil {
+		return nil, err
+	}
+	return uc.Verify(ctx)
+}
+
+// Verify walks the audit chain of the tenant in ctx and reports gaps in the
+// sequence, events that do not link to their predecessor and events whose
+// content was altered. It performs no authorization and is meant for
+// maintenance commands.
+func (uc *AuditUseCase) Verify(ctx context.Context) (*AuditVerification, error) {
+
+	tenantID, err := tenancy.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditVerification{TenantID: tenantID, LastHash: entities.AuditGenesisHash}
+	for {
+		page, err := uc.auditRepo.Scan(ctx, result.LastSeq, auditScanPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit events: %w", err)
+		}
+
+		for _, event := range page {
+			switch {
+			case event.Seq != result.LastSeq+1:
+				result.Problems = append(result.Problems, AuditChainProblem{
+					Seq:    event.Seq,
+					Kind:   AuditProblemGap,
+					Detail: fmt.Sprintf("events %d to %d are missing", result.LastSeq+1, event.Seq-1),
+				})
+			case event.PrevHash != result.LastHash:
+				result.Problems = append(result.Problems, AuditChainProblem{
+					Seq:    event.Seq,
+					Kind:   AuditProblemBrokenLink,
+					Detail: "previous hash does not match the preceding event",
+				})
+			}
+			if event.ComputeHash() != event.Hash {
+				result.Problems = append(result.Problems, AuditChainProblem{
+					Seq:    event.Seq,
+					Kind:   AuditProblemTampered,
+					Detail: "content does not match the event's hash",
+				})
+			}
+
+			result.Events++
+			result.LastSeq = event.Seq
+			result.LastHash = event.Hash
+		}
+
+		if len(page) < auditScanPageSize {
+			return result, nil
+		}
+	}
+}
+
+// VerifyAll verifies the chain of the tenant in ctx or, in a maintenance
+// context made by tenancy.WithAllTenants, the chain of every tenant with events
+func (uc *AuditUseCase) VerifyAll(ctx context.Context) ([]*AuditVerification, error) {
+
+	if _, ok := tenancy.FromContext(ctx); ok {
+		result, err := uc.Verify(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []*AuditVerification{result}, nil
+	}
+
+	tenants, err := uc.auditRepo.Tenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit tenants: %w", err)
+	}
+	results := make([]*AuditVerification, 0, len(tenants))
+	for _, tenantID := range tenants {
+		result, err := uc.Verify(tenancy.WithTenant(ctx, tenantID))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseOptionalTime parses an RFC 3339 query value, returning the zero time for ""
+func parseOptionalTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 time", ErrInvalidInput, name)
+	}
+	return t, nil
+}
@@ -0,0 +1,406 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown username or a wrong password
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUsernameTaken is returned when registering an existing username
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrInvalidRefreshToken is returned for an unknown, expired or revoked refresh token
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented
+	// again; the session it belongs to is revoked
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, session revoked")
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 128
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,64}$`)
+
+// PasswordHasher hashes and verifies user passwords
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) error
+}
+
+// AccessTokenIssuer signs and verifies short-lived access tokens
+type AccessTokenIssuer interface {
+	Issue(principal *identity.Principal) (string, time.Time, error)
+	Verify(token string) (*identity.Principal, error)
+	JWKS() identity.JSONWebKeySet
+}
+
+// LoginResult is the outcome of a successful login or token refresh
+type LoginResult struct {
+	User             *entities.User
+	AccessToken      string
+	ExpiresAt        time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type AuthUseCase struct {
+	userRepo    repositories.UserRepository
+	sessionRepo repositories.SessionRepository
+	refreshRepo repositories.RefreshTokenRepository
+	hasher      PasswordHasher
+	tokens      AccessTokenIssuer
+	sessionTTL  time.Duration
+	admins      map[string]bool
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthUseCase(
+	userRepo repositories.UserRepository,
+	sessionRepo repositories.SessionRepository,
+	refreshRepo repositories.RefreshTokenRepository,
+	hasher PasswordHasher,
+	tokens AccessTokenIssuer,
+	sessionTTL time.Duration,
+	opts ...AuthUseCaseOption,
+) *AuthUseCase {
+	uc := &AuthUseCase{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
+		hasher:      hasher,
+		tokens:      tokens,
+		sessionTTL:  sessionTTL,
+		admins:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// AuthUseCaseOption configures optional AuthUseCase behavior
+type AuthUseCaseOption func(*AuthUseCase)
+
+// WithAdminUsernames grants the admin scope to the named users
+func WithAdminUsernames(usernames ...string) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		for _, username := range usernames {
+			uc.admins[entities.NormalizeUsername(username)] = true
+		}
+	}
+}
+
+func (uc *AuthUseCase) Register(ctx context.Context, req dto.RegisterRequest) (*entities.User, error) {
+
+	username := entities.NormalizeUsername(req.Username)
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("%w: username must be 3-64 characters of a-z, 0-9, '.', '_' or '-'", ErrInvalidInput)
+	}
+	if err := validatePassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := uc.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	created, err := uc.userRepo.Create(ctx, entities.NewUser(username, hash))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserExists) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	audit.ObserveAs(ctx, created.ID, audit.ActionUserRegistered, created.ID, "", audit.Hash(created))
+	return created, nil
+}
+
+func (uc *AuthUseCase) Login(ctx context.Context, req dto.LoginRequest) (*LoginResult, error) {
+
+	user, err := uc.userRepo.GetByUsername(ctx, entities.NormalizeUsername(req.Username))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			// Spend the same time as a real verification so usernames cannot be probed
+			uc.hasher.Verify(req.Password, uc.getDummyHash())
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := uc.hasher.Verify(req.Password, user.PasswordHash); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	session := entities.NewSession(user.ID, uc.sessionTTL)
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	audit.ObserveAs(ctx, user.ID, audit.ActionSessionStarted, session.ID, "", audit.Hash(session))
+	return uc.issueTokens(ctx, user, session)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Each refresh token is single-use: presenting one that was already rotated
+// means it leaked, so the whole session is revoked.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*LoginResult, error) {
+
+	stored, err := uc.refreshRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if err := uc.refreshRepo.MarkUsed(ctx, stored.ID); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenUsed) {
+			if err := uc.revokeSession(ctx, stored.SessionID); err != nil {
+				return nil, err
+			}
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if stored.IsExpired(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, stored.SessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.IsExpired(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	audit.ObserveAs(ctx, user.ID, audit.ActionSessionRefreshed, session.ID, audit.Hash(stored), "")
+	return uc.issueTokens(ctx, user, session)
+}
+
+// Authenticate resolves an access token to the principal it was issued to.
+// The token's session must still exist, so logging out takes effect at once.
+func (uc *AuthUseCase) Authenticate(ctx context.Context, token string) (*identity.Principal, error) {
+
+	principal, err := uc.tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	// The token names its tenant; the session lookup must run there
+	ctx, err = tenancy.Bind(ctx, principal.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", identity.ErrUnauthenticated, err)
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, principal.SessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, identity.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != principal.UserID || session.IsExpired(time.Now()) {
+		return nil, identity.ErrUnauthenticated
+	}
+
+	return principal, nil
+}
+
+// JWKS returns the public keys that verify access tokens
+func (uc *AuthUseCase) JWKS() identity.JSONWebKeySet {
+	return uc.tokens.JWKS()
+}
+
+// Logout ends the caller's current session and its refresh tokens
+func (uc *AuthUseCase) Logout(ctx context.Context) error {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.revokeSession(ctx, principal.SessionID); err != nil {
+		return err
+	}
+
+	audit.Observe(ctx, audit.ActionSessionEnded, principal.SessionID, "", "")
+	return nil
+}
+
+// ChangePassword replaces the caller's password and signs out all of their other sessions.
+// It requires a signed-in session; API keys cannot change passwords.
+func (uc *AuthUseCase) ChangePassword(ctx context.Context, req dto.ChangePasswordRequest) error {
+
+	if err := requireSession(ctx); err != nil {
+		return err
+	}
+
+	user, err := uc.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.hasher.Verify(req.CurrentPassword, user.PasswordHash); err != nil {
+		return ErrInvalidCredentials
+	}
+	if err := validatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	hash, err := uc.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	before := audit.Hash(user)
+	user.ChangePassword(hash)
+	if err := uc.userRepo.UpdatePassword(ctx, user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	principal, _ := identity.FromContext(ctx)
+	if err := uc.refreshRepo.DeleteByUser(ctx, user.ID, principal.SessionID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if err := uc.sessionRepo.DeleteByUser(ctx, user.ID, principal.SessionID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionPasswordChanged, user.ID, before, audit.Hash(user))
+	return nil
+}
+
+// CurrentUser returns the user behind the caller's principal
+func (uc *AuthUseCase) CurrentUser(ctx context.Context) (*entities.User, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, identity.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// UserScopes returns the scopes a user currently holds
+func (uc *AuthUseCase) UserScopes(ctx context.Context, userID string) ([]identity.Scope, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, identity.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return uc.scopesFor(user), nil
+}
+
+// issueTokens issues a fresh access token and the next refresh token of session
+func (uc *AuthUseCase) issueTokens(ctx context.Context, user *entities.User, session *entities.Session) (*LoginResult, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	stored := entities.NewRefreshToken(session, hashToken(refreshToken))
+	if err := uc.refreshRepo.Create(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	tenantID, _ := tenancy.FromContext(ctx)
+	accessToken, expiresAt, err := uc.tokens.Issue(&identity.Principal{
+		UserID:    user.ID,
+		Username:  user.Username,
+		SessionID: session.ID,
+		Scopes:    uc.scopesFor(user),
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	return &LoginResult{
+		User:             user,
+		AccessToken:      accessToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+// scopesFor returns the scopes a signed-in user holds
+func (uc *AuthUseCase) scopesFor(user *entities.User) []identity.Scope {
+	scopes := []identity.Scope{identity.ScopeTodosRead, identity.ScopeTodosWrite}
+	if uc.admins[user.Username] {
+		scopes = append(scopes, identity.ScopeAdmin)
+	}
+	return scopes
+}
+
+// revokeSession deletes a session together with its refresh token family
+func (uc *AuthUseCase) revokeSession(ctx context.Context, sessionID string) error {
+	if err := uc.refreshRepo.DeleteBySession(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if err := uc.sessionRepo.Delete(ctx, sessionID); err != nil && !errors.Is(err, repositories.ErrSessionNotFound) {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (uc *AuthUseCase) getDummyHash() string {
+	uc.dummyHashOnce.Do(func() {
+		uc.dummyHash, _ = uc.hasher.Hash("dummy password for timing equalization")
+	})
+	return uc.dummyHash
+}
+
+// requireSession rejects callers that did not sign in interactively
+func requireSession(ctx context.Context) error {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.SessionID == "" {
+		return fmt.Errorf("%w: this operation requires a signed-in session", identity.ErrForbidden)
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: password must be %d-%d characters", ErrInvalidInput, minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
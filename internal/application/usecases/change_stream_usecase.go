@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// changeLogPageSize is how many log entries are relayed or caught up on at a time
+const changeLogPageSize = 100
+
+// ChangeStreamUseCase streams the changes recorded in the change log to
+// subscribers, each of whom sees the changes to the todos they may view
+type ChangeStreamUseCase struct {
+	changes repositories.ChangeLogRepository
+	bus     *events.Bus
+	access  listAccess
+	// buffer is how many events a subscriber may fall behind before it is
+	// dropped from the bus and catches up from the log instead
+	buffer int
+
+	// mu keeps events on the bus in the order of the log
+	mu sync.Mutex
+	// relayed is the last entry of each tenant's log put on the bus
+	relayed map[string]int64
+}
+
+// NewChangeStreamUseCase creates a new ChangeStreamUseCase
+func NewChangeStreamUseCase(changes repositories.ChangeLogRepository, listRepo repositories.ListRepository, bus *events.Bus, buffer int) *ChangeStreamUseCase {
+	return &ChangeStreamUseCase{
+		changes: changes,
+		bus:     bus,
+		access:  listAccess{lists: listRepo},
+		buffer:  buffer,
+		relayed: make(map[string]int64),
+	}
+}
+
+// Handle implements events.Handler for the outbox. The event only signals
+// that changes were committed: the entries logged since the last relay are
+// delivered to the subscribers, so that the stream carries nothing the log
+// does not, and an event seen again relays nothing new.
+func (uc *ChangeStreamUseCase) Handle(ctx context.Context, _ events.ChangeEvent) error {
+	return uc.relay(ctx)
+}
+
+// relay puts the entries logged in ctx's tenant since the last relay on the
+// bus. Until someone subscribes to a tenant's changes there is nothing to relay.
+func (uc *ChangeStreamUseCase) relay(ctx context.Context) error {
+	tenantID, _ := tenancy.FromContext(ctx)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	last, ok := uc.relayed[tenantID]
+	if !ok {
+		return nil
+	}
+	for {
+		page, err := uc.changes.Since(ctx, last, changeLogPageSize)
+		if err != nil {
+			return err
+		}
+		changeEvents, err := uc.changeEvents(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, event := range changeEvents {
+			uc.bus.Publish(ctx, event)
+		}
+		if len(page) > 0 {
+			last = page[len(page)-1].Seq
+			uc.relayed[tenantID] = last
+		}
+		if len(page) < changeLogPageSize {
+			return nil
+		}
+	}
+}
+
+// changeEvents describes a page of the log as it is streamed, with the
+// changes of every batch that committed in it
+func (uc *ChangeStreamUseCase) changeEvents(ctx context.Context, page []*entities.ChangeEntry) ([]events.ChangeEvent, error) {
+	batches := make(map[string][]*entities.ChangeEntry)
+	for _, batchID := range events.CommittedBatches(page) {
+		batch, err := uc.changes.Batch(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		batches[batchID] = batch
+	}
+	return events.FromChangeEntries(page, batches), nil
+}
+
+// startRelaying relays the changes of ctx's tenant logged from now on
+func (uc *ChangeStreamUseCase) startRelaying(ctx context.Context) error {
+	tenantID, _ := tenancy.FromContext(ctx)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.relayed[tenantID]; ok {
+		return nil
+	}
+	_, newest, err := uc.changes.SeqRange(ctx)
+	if err != nil {
+		return err
+	}
+	uc.relayed[tenantID] = newest
+	return nil
+}
+
+// Subscribe streams the changes visible to the caller. With lastSeq set, the
+// changes logged after it are replayed first. The subscription is bound to
+// ctx's caller and tenant and must be closed.
+func (uc *ChangeStreamUseCase) Subscribe(ctx context.Context, lastSeq int64) (*ChangeSubscription, error) {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, _ := tenancy.FromContext(ctx)
+	if err := uc.startRelaying(ctx); err != nil {
+		return nil, err
+	}
+
+	// Subscribing before reading the log leaves no gap between the two
+	sub := &ChangeSubscription{
+		uc:       uc,
+		ctx:      ctx,
+		userID:   principal.UserID,
+		tenantID: tenantID,
+		live:     uc.bus.Subscribe(uc.buffer),
+	}
+	oldest, newest, err := uc.changes.SeqRange(ctx)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	switch {
+	case lastSeq <= 0:
+		sub.lastSeq = newest
+	case lastSeq < oldest || lastSeq > newest:
+		// Changes after lastSeq were purged, or lastSeq is not from this log
+		sub.lastSeq = newest
+		sub.missed = true
+	default:
+		sub.lastSeq = lastSeq
+		sub.replaying = true
+	}
+	return sub, nil
+}
+
+// ChangeSubscription is one subscriber's stream of change events
+type ChangeSubscription struct {
+	uc *ChangeStreamUseCase
+	// ctx carries the subscriber's identity and tenant
+	ctx      context.Context
+	userID   string
+	tenantID string
+	live     *events.Subscription
+
+	// lastSeq is the last log entry the subscriber was given or skipped
+	lastSeq   int64
+	replaying bool
+	pending   []events.ChangeEvent
+	missed    bool
+}
+
+// MissedChanges reports whether the changes after the requested sequence
+// number can no longer be replayed, in which case the subscriber must reload
+// its todos
+func (s *ChangeSubscription) MissedChanges() bool {
+	return s.missed
+}
+
+// Next returns the next change event visible to the subscriber, waiting until
+// one is published or ctx is done. Changes the subscriber may not see are left
+// out of events, and events left without changes are skipped.
+func (s *ChangeSubscription) Next(ctx context.Context) (events.ChangeEvent, error) {
+	for {
+		if len(s.pending) == 0 && s.replaying {
+			page, err := s.uc.changes.Since(s.ctx, s.lastSeq, changeLogPageSize)
+			if err != nil {
+				return events.ChangeEvent{}, err
+			}
+			if s.pending, err = s.uc.changeEvents(s.ctx, page); err != nil {
+				return events.ChangeEvent{}, err
+			}
+			s.replaying = len(page) == changeLogPageSize
+			if len(page) > 0 {
+				s.lastSeq = page[len(page)-1].Seq
+			}
+		}
+		if len(s.pending) > 0 {
+			event := s.pending[0]
+			s.pending = s.pending[1:]
+			if visible, ok := s.visible(event); ok {
+				return visible, nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return events.ChangeEvent{}, ctx.Err()
+		case event, ok := <-s.live.Events():
+			if !ok {
+				// Dropped by the bus for falling behind
+				slog.WarnContext(s.ctx, "Change stream fell behind, catching up from the change log", "last_seq", s.lastSeq)
+				s.live = s.uc.bus.Subscribe(s.uc.buffer)
+				s.replaying = true
+				continue
+			}
+			if event.Seq <= s.lastSeq {
+				// Already replayed from the log
+				continue
+			}
+			s.lastSeq = event.Seq
+			if visible, ok := s.visible(event); ok {
+				return visible, nil
+			}
+		}
+	}
+}
+
+// visible returns event with only the changes the subscriber may see
+func (s *ChangeSubscription) visible(event events.ChangeEvent) (events.ChangeEvent, bool) {
+	if event.TenantID != s.tenantID {
+		return event, false
+	}
+
+	viewable := make(map[string]bool)
+	changes := make([]events.Change, 0, len(event.Changes))
+	for _, change := range event.Changes {
+		if change.ListID == "" {
+			if change.OwnerID == s.userID {
+				changes = append(changes, change)
+			}
+			continue
+		}
+		canView, ok := viewable[change.ListID]
+		if !ok {
+			_, err := s.uc.access.authorizeList(s.ctx, change.ListID, entities.ListPermissionView)
+			canView = err == nil
+			viewable[change.ListID] = canView
+		}
+		if canView {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return event, false
+	}
+	event.Changes = changes
+	return event, true
+}
+
+// Close ends the subscription
+func (s *ChangeSubscription) Close() {
+	s.live.Close()
+}
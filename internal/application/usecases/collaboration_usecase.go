@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// ErrTooManyConnections is returned when a connection would exceed the connection limits
+var ErrTooManyConnections = errors.New("too many connections")
+
+// CollaborationLimits bounds the open collaboration sessions; zero is unlimited
+type CollaborationLimits struct {
+	MaxConnections        int
+	MaxConnectionsPerUser int
+}
+
+// ListViewer is a user with a list open
+type ListViewer struct {
+	UserID   string
+	Username string
+}
+
+// presenceKey identifies a list across tenants
+type presenceKey struct {
+	tenantID string
+	listID   string
+}
+
+// CollaborationUseCase manages the sessions of collaborative clients: the
+// lists each has open, the changes to those lists and who else is viewing them.
+// Presence is tracked per instance.
+type CollaborationUseCase struct {
+	changeStream *ChangeStreamUseCase
+	access       listAccess
+	limits       CollaborationLimits
+
+	mu          sync.Mutex
+	connections int
+	perUser     map[string]int
+	viewers     map[presenceKey]map[*CollaborationSession]struct{}
+}
+
+// NewCollaborationUseCase creates a new CollaborationUseCase
+func NewCollaborationUseCase(changeStream *ChangeStreamUseCase, listRepo repositories.ListRepository, limits CollaborationLimits) *CollaborationUseCase {
+	return &CollaborationUseCase{
+		changeStream: changeStream,
+		access:       listAccess{lists: listRepo},
+		limits:       limits,
+		perUser:      make(map[string]int),
+		viewers:      make(map[presenceKey]map[*CollaborationSession]struct{}),
+	}
+}
+
+// Connect opens a session for the caller, unless that exceeds the connection
+// limits. The session is bound to ctx's caller and tenant and must be closed.
+func (uc *CollaborationUseCase) Connect(ctx context.Context) (*CollaborationSession, error) {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, _ := tenancy.FromContext(ctx)
+	user := tenantID + "/" + principal.UserID
+
+	uc.mu.Lock()
+	if (uc.limits.MaxConnections > 0 && uc.connections >= uc.limits.MaxConnections) ||
+		(uc.limits.MaxConnectionsPerUser > 0 && uc.perUser[user] >= uc.limits.MaxConnectionsPerUser) {
+		uc.mu.Unlock()
+		return nil, ErrTooManyConnections
+	}
+	uc.connections++
+	uc.perUser[user]++
+	uc.mu.Unlock()
+
+	session := &CollaborationSession{
+		uc:       uc,
+		user:     user,
+		viewer:   ListViewer{UserID: principal.UserID, Username: principal.Username},
+		tenantID: tenantID,
+		lists:    make(map[string]bool),
+		presence: make(map[string]bool),
+		notify:   make(chan struct{}, 1),
+	}
+	session.changes, err = uc.changeStream.Subscribe(ctx, 0)
+	if err != nil {
+		uc.disconnect(session)
+		return nil, err
+	}
+	return session, nil
+}
+
+// disconnect releases the connection of session
+func (uc *CollaborationUseCase) disconnect(session *CollaborationSession) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.connections--
+	if uc.perUser[session.user]--; uc.perUser[session.user] <= 0 {
+		delete(uc.perUser, session.user)
+	}
+}
+
+// join adds session to the viewers of listID and tells them about it
+func (uc *CollaborationUseCase) join(session *CollaborationSession, listID string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	key := presenceKey{tenantID: session.tenantID, listID: listID}
+	if uc.viewers[key] == nil {
+		uc.viewers[key] = make(map[*CollaborationSession]struct{})
+	}
+	uc.viewers[key][session] = struct{}{}
+	uc.presenceChanged(key)
+}
+
+// leave removes session from the viewers of listID and tells the others about it
+func (uc *CollaborationUseCase) leave(session *CollaborationSession, listID string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	key := presenceKey{tenantID: session.tenantID, listID: listID}
+	delete(uc.viewers[key], session)
+	if len(uc.viewers[key]) == 0 {
+		delete(uc.viewers, key)
+		return
+	}
+	uc.presenceChanged(key)
+}
+
+// presenceChanged flags key on the sessions viewing it; uc.mu must be held
+func (uc *CollaborationUseCase) presenceChanged(key presenceKey) {
+	for session := range uc.viewers[key] {
+		session.flagPresence(key.listID)
+	}
+}
+
+// listViewers returns the users viewing listID, each once, sorted by username
+func (uc *CollaborationUseCase) listViewers(tenantID, listID string) []ListViewer {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var viewers []ListViewer
+	for session := range uc.viewers[presenceKey{tenantID: tenantID, listID: listID}] {
+		if !seen[session.viewer.UserID] {
+			seen[session.viewer.UserID] = true
+			viewers = append(viewers, session.viewer)
+		}
+	}
+	sort.Slice(viewers, func(i, j int) bool {
+		if viewers[i].Username != viewers[j].Username {
+			return viewers[i].Username < viewers[j].Username
+		}
+		return viewers[i].UserID < viewers[j].UserID
+	})
+	return viewers
+}
+
+// CollaborationSession is one client connection. Changes are read by one
+// goroutine at a time; the other methods may be called concurrently.
+type CollaborationSession struct {
+	uc       *CollaborationUseCase
+	user     string
+	viewer   ListViewer
+	tenantID string
+	changes  *ChangeSubscription
+
+	mu sync.Mutex
+	// lists are the lists the client subscribed to
+	lists map[string]bool
+	// presence flags the lists whose viewers changed since PresenceUpdates
+	presence map[string]bool
+	notify   chan struct{}
+	closed   bool
+}
+
+// Subscribe opens listID, after which its changes are delivered to the
+// session and the other viewers of the list learn of the client
+func (s *CollaborationSession) Subscribe(ctx context.Context, listID string) error {
+	if _, err := s.uc.access.authorizeList(ctx, listID, entities.ListPermissionView); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed || s.lists[listID] {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lists[listID] = true
+	s.mu.Unlock()
+
+	s.uc.join(s, listID)
+	return nil
+}
+
+// Unsubscribe closes listID
+func (s *CollaborationSession) Unsubscribe(listID string) {
+	s.mu.Lock()
+	subscribed := s.lists[listID]
+	delete(s.lists, listID)
+	delete(s.presence, listID)
+	s.mu.Unlock()
+
+	if subscribed {
+		s.uc.leave(s, listID)
+	}
+}
+
+// NextChange returns the next change event with changes to the subscribed
+// lists, reduced to those changes, waiting until one is published or ctx is done
+func (s *CollaborationSession) NextChange(ctx context.Context) (events.ChangeEvent, error) {
+	for {
+		event, err := s.changes.Next(ctx)
+		if err != nil {
+			return events.ChangeEvent{}, err
+		}
+
+		s.mu.Lock()
+		changes := make([]events.Change, 0, len(event.Changes))
+		for _, change := range event.Changes {
+			if s.lists[change.ListID] {
+				changes = append(changes, change)
+			}
+		}
+		s.mu.Unlock()
+
+		if len(changes) > 0 {
+			event.Changes = changes
+			return event, nil
+		}
+	}
+}
+
+// PresenceChanged is signalled when the viewers of a subscribed list changed
+func (s *CollaborationSession) PresenceChanged() <-chan struct{} {
+	return s.notify
+}
+
+// PresenceUpdates returns the current viewers of each subscribed list whose
+// viewers changed since the last call
+func (s *CollaborationSession) PresenceUpdates() map[string][]ListViewer {
+	s.mu.Lock()
+	listIDs := make([]string, 0, len(s.presence))
+	for listID := range s.presence {
+		if s.lists[listID] {
+			listIDs = append(listIDs, listID)
+		}
+	}
+	s.presence = make(map[string]bool)
+	s.mu.Unlock()
+
+	updates := make(map[string][]ListViewer, len(listIDs))
+	for _, listID := range listIDs {
+		updates[listID] = s.uc.listViewers(s.tenantID, listID)
+	}
+	return updates
+}
+
+// flagPresence records that the viewers of listID changed
+func (s *CollaborationSession) flagPresence(listID string) {
+	s.mu.Lock()
+	s.presence[listID] = true
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// A signal is already pending
+	}
+}
+
+// Close leaves every list and ends the session. NextChange must have returned.
+func (s *CollaborationSession) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	lists := s.lists
+	s.lists = make(map[string]bool)
+	s.mu.Unlock()
+
+	for listID := range lists {
+		s.uc.leave(s, listID)
+	}
+	s.changes.Close()
+	s.uc.disconnect(s)
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const maxCommentLength = 2000
+
+type CommentUseCase struct {
+	todoUseCase *TodoUseCase
+	commentRepo repositories.CommentRepository
+}
+
+// NewCommentUseCase creates comments on the todos todoUseCase gives access to
+func NewCommentUseCase(todoUseCase *TodoUseCase, commentRepo repositories.CommentRepository) *CommentUseCase {
+	return &CommentUseCase{
+		todoUseCase: todoUseCase,
+		commentRepo: commentRepo,
+	}
+}
+
+// AddComment comments on a todo the caller may comment on
+func (uc *CommentUseCase) AddComment(ctx context.Context, todoID string, req dto.CreateCommentRequest) (*entities.Comment, error) {
+
+	if _, err := uc.authorizedTodo(ctx, todoID, entities.ListPermissionComment); err != nil {
+		return nil, err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" || len(body) > maxCommentLength {
+		return nil, fmt.Errorf("%w: comment must be 1-%d characters", ErrInvalidInput, maxCommentLength)
+	}
+	if err := uc.todoUseCase.quotas.reserve(ctx, 0, int64(len(body))); err != nil {
+		return nil, err
+	}
+
+	comment := entities.NewComment(todoID, principal.UserID, body)
+	if err := uc.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionCommentCreated, comment.ID, "", audit.Hash(comment))
+	return comment, nil
+}
+
+// GetComments returns the comments of a todo the caller can see
+func (uc *CommentUseCase) GetComments(ctx context.Context, todoID string) ([]*entities.Comment, error) {
+
+	if _, err := uc.authorizedTodo(ctx, todoID, entities.ListPermissionView); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.commentRepo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (uc *CommentUseCase) authorizedTodo(ctx context.Context, todoID string, permission entities.ListPermission) (*entities.Todo, error) {
+	todo, err := uc.todoUseCase.GetTodoByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.todoUseCase.access.authorizeTodo(ctx, todo, permission); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
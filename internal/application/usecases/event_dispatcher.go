@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// outboxPageSize is how many outbox events a subscriber is relayed at a time
+const outboxPageSize = 100
+
+// EventDispatcher relays the domain events stored in the outbox to its
+// subscribers. Every subscriber sees the events in the order they were
+// stored, at least once: its cursor only moves past an event once its handler
+// returned without error.
+type EventDispatcher struct {
+	outbox repositories.OutboxRepository
+
+	mu          sync.Mutex
+	subscribers []eventSubscriber
+}
+
+type eventSubscriber struct {
+	name    string
+	handler events.Handler
+}
+
+// NewEventDispatcher creates a new EventDispatcher
+func NewEventDispatcher(outbox repositories.OutboxRepository) *EventDispatcher {
+	return &EventDispatcher{
+		outbox: outbox,
+	}
+}
+
+// Subscribe registers handler under name. The name keys the subscriber's
+// cursor, so it must stay the same across restarts.
+func (d *EventDispatcher) Subscribe(name string, handler events.Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribers = append(d.subscribers, eventSubscriber{name: name, handler: handler})
+}
+
+// Dispatch relays the pending events of the database ctx is scoped to and
+// returns how many were handled. A subscriber whose handler fails stops at
+// that event and is relayed it again on the next run; the others carry on.
+func (d *EventDispatcher) Dispatch(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	handled := 0
+	var errs []error
+	for _, subscriber := range d.subscribers {
+		n, err := d.relay(ctx, subscriber)
+		handled += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %s: %w", subscriber.name, err))
+		}
+	}
+	return handled, errors.Join(errs...)
+}
+
+// relay hands subscriber the events past its cursor
+func (d *EventDispatcher) relay(ctx context.Context, subscriber eventSubscriber) (int, error) {
+	cursor, err := d.outbox.Cursor(ctx, subscriber.name)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for {
+		pending, err := d.outbox.Since(ctx, cursor, outboxPageSize)
+		if err != nil {
+			return handled, err
+		}
+		for _, event := range pending {
+			// The outbox of a shared database holds the events of every
+			// tenant; each is handled in its own
+			eventCtx := ctx
+			if event.TenantID != "" {
+				eventCtx = tenancy.WithTenant(ctx, event.TenantID)
+			}
+			if err := subscriber.handler.Handle(eventCtx, event); err != nil {
+				return handled, fmt.Errorf("failed to handle event %s: %w", event.ID, err)
+			}
+			if err := d.outbox.Advance(ctx, subscriber.name, event.Seq); err != nil {
+				return handled, err
+			}
+			cursor = event.Seq
+			handled++
+		}
+		if len(pending) < outboxPageSize {
+			return handled, nil
+		}
+	}
+}
+
+// Purge removes the events every subscriber has handled that occurred before cutoff
+func (d *EventDispatcher) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	d.mu.Lock()
+	names := make([]string, len(d.subscribers))
+	for i, subscriber := range d.subscribers {
+		names[i] = subscriber.name
+	}
+	d.mu.Unlock()
+
+	return d.outbox.DeleteHandledBefore(ctx, names, cutoff)
+}
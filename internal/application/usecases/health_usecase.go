@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthProbe is one of the probes an orchestrator polls
+type HealthProbe string
+
+const (
+	// ProbeLiveness fails when the process is stuck and should be restarted
+	ProbeLiveness HealthProbe = "livez"
+	// ProbeReadiness fails when the instance should not receive requests
+	ProbeReadiness HealthProbe = "readyz"
+	// ProbeStartup fails until the instance has started
+	ProbeStartup HealthProbe = "startupz"
+)
+
+const defaultHealthCheckTimeout = time.Second
+
+// HealthCheck checks one dependency
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Timeout bounds the check; it defaults to one second
+	Timeout time.Duration
+	// CacheTTL reuses a result for this long, so that frequent probes cannot
+	// overload the dependency; zero runs the check on every probe
+	CacheTTL time.Duration
+}
+
+// HealthCheckResult is the outcome of one check
+type HealthCheckResult struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+	// Cached is set when the result was reused from an earlier probe
+	Cached    bool
+	CheckedAt time.Time
+}
+
+// HealthReport is the outcome of a probe
+type HealthReport struct {
+	Results []HealthCheckResult
+}
+
+// OK reports whether every check passed
+func (r HealthReport) OK() bool {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// healthCheck caches the last result of a check, which probes share
+type healthCheck struct {
+	HealthCheck
+
+	// mu is held while the check runs, so that concurrent probes wait for
+	// and share one result instead of piling onto the dependency
+	mu   sync.Mutex
+	last *HealthCheckResult
+}
+
+func (c *healthCheck) run(ctx context.Context) HealthCheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.CacheTTL {
+		cached := *c.last
+		cached.Cached = true
+		return cached
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// A check ignoring its context must not hang the probe
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	c.last = &HealthCheckResult{Name: c.Name, Err: err, Duration: time.Since(start), CheckedAt: start}
+	return *c.last
+}
+
+type HealthUseCase struct {
+	checks map[string]*healthCheck
+	probes map[HealthProbe][]*healthCheck
+}
+
+func NewHealthUseCase() *HealthUseCase {
+	return &HealthUseCase{
+		checks: make(map[string]*healthCheck),
+		probes: make(map[HealthProbe][]*healthCheck),
+	}
+}
+
+// Register adds checks to probe. A check registered with several probes
+// under the same name is run once and its result shared.
+func (uc *HealthUseCase) Register(probe HealthProbe, checks ...HealthCheck) {
+	for _, check := range checks {
+		registered, ok := uc.checks[check.Name]
+		if !ok {
+			registered = &healthCheck{HealthCheck: check}
+			uc.checks[check.Name] = registered
+		}
+		uc.probes[probe] = append(uc.probes[probe], registered)
+	}
+}
+
+// Check runs the checks of probe concurrently, except those named in exclude
+func (uc *HealthUseCase) Check(ctx context.Context, probe HealthProbe, exclude ...string) HealthReport {
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	var checks []*healthCheck
+	for _, check := range uc.probes[probe] {
+		if !excluded[check.Name] {
+			checks = append(checks, check)
+		}
+	}
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *healthCheck) {
+			defer wg.Done()
+			results[i] = check.run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+	return HealthReport{Results: results}
+}
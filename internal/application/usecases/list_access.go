@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+)
+
+// listAccess answers what the caller may do with a list and its todos. It is
+// shared by every use case that touches todos, so REST, batch and any other
+// entry point enforce the same rules.
+type listAccess struct {
+	lists repositories.ListRepository
+}
+
+// role returns the caller's role on listID. Lists the caller is not a member
+// of are reported as ErrListNotFound so that their existence does not leak.
+func (a listAccess) role(ctx context.Context, listID string) (entities.ListRole, error) {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	if a.lists == nil {
+		return "", repositories.ErrListNotFound
+	}
+
+	member, err := a.lists.GetMember(ctx, listID, principal.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrListMemberNotFound) {
+			return "", repositories.ErrListNotFound
+		}
+		return "", fmt.Errorf("failed to get list member: %w", err)
+	}
+	return member.Role, nil
+}
+
+// authorizeList checks that the caller holds permission on listID
+func (a listAccess) authorizeList(ctx context.Context, listID string, permission entities.ListPermission) (entities.ListRole, error) {
+	role, err := a.role(ctx, listID)
+	if err != nil {
+		return "", err
+	}
+	if !role.Can(permission) {
+		return "", fmt.Errorf("%w: %s role cannot %s this list", identity.ErrForbidden, role, permission)
+	}
+	return role, nil
+}
+
+// authorizeTodo checks that the caller holds permission on a todo loaded
+// through the repository. Personal todos are only ever returned to their
+// owner, who may do anything with them.
+func (a listAccess) authorizeTodo(ctx context.Context, todo *entities.Todo, permission entities.ListPermission) error {
+	if todo.ListID == "" {
+		return nil
+	}
+
+	_, err := a.authorizeList(ctx, todo.ListID, permission)
+	if errors.Is(err, repositories.ErrListNotFound) {
+		return repositories.ErrTodoNotFound
+	}
+	return err
+}
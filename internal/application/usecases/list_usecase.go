@@ -0,0 +1,346 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const maxListNameLength = 200
+
+// CreatedListInvite is a new invite together with its token, which is never shown again
+type CreatedListInvite struct {
+	Invite *entities.ListInvite
+	Token  string
+}
+
+type ListUseCase struct {
+	listRepo   repositories.ListRepository
+	inviteRepo repositories.ListInviteRepository
+	access     listAccess
+	inviteTTL  time.Duration
+	todos      *TodoUseCase
+}
+
+// ListUseCaseOption configures optional ListUseCase behaviour
+type ListUseCaseOption func(*ListUseCase)
+
+// WithListTodos deletes the todos of a deleted list through todos, so that
+// each deletion is logged and raises a todo.deleted event like any other
+func WithListTodos(todos *TodoUseCase) ListUseCaseOption {
+	return func(uc *ListUseCase) {
+		uc.todos = todos
+	}
+}
+
+func NewListUseCase(listRepo repositories.ListRepository, inviteRepo repositories.ListInviteRepository, inviteTTL time.Duration, opts ...ListUseCaseOption) *ListUseCase {
+	uc := &ListUseCase{
+		listRepo:   listRepo,
+		inviteRepo: inviteRepo,
+		access:     listAccess{lists: listRepo},
+		inviteTTL:  inviteTTL,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// CreateList creates a list owned by the caller
+func (uc *ListUseCase) CreateList(ctx context.Context, req dto.CreateListRequest) (*entities.ListMembership, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxListNameLength {
+		return nil, fmt.Errorf("%w: list name must be 1-%d characters", ErrInvalidInput, maxListNameLength)
+	}
+
+	list := entities.NewTodoList(name, principal.UserID)
+	if err := uc.listRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionListCreated, list.ID, "", audit.Hash(list))
+	return &entities.ListMembership{List: list, Role: entities.ListRoleOwner}, nil
+}
+
+// GetLists returns every list the caller is a member of
+func (uc *ListUseCase) GetLists(ctx context.Context) ([]*entities.ListMembership, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := uc.listRepo.ListForUser(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetList returns a list the caller may view, with the caller's role
+func (uc *ListUseCase) GetList(ctx context.Context, id string) (*entities.ListMembership, error) {
+
+	role, err := uc.access.authorizeList(ctx, id, entities.ListPermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := uc.listRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrListNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return &entities.ListMembership{List: list, Role: role}, nil
+}
+
+// DeleteList deletes a list with all of its todos
+func (uc *ListUseCase) DeleteList(ctx context.Context, id string) error {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionDelete); err != nil {
+		return err
+	}
+	list, err := uc.listRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrListNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to get list: %w", err)
+	}
+
+	deleteList := func(ctx context.Context) error {
+		return uc.listRepo.Delete(ctx, id)
+	}
+	if uc.todos != nil {
+		err = uc.todos.deleteListTodos(ctx, id, deleteList)
+	} else {
+		err = deleteList(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrListNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionListDeleted, id, audit.Hash(list), "")
+	return nil
+}
+
+// GetMembers returns the members of a list the caller may view
+func (uc *ListUseCase) GetMembers(ctx context.Context, id string) ([]*entities.ListMember, error) {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionView); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.listRepo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a member other than the owner
+func (uc *ListUseCase) UpdateMemberRole(ctx context.Context, id, userID string, req dto.UpdateMemberRoleRequest) error {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionManage); err != nil {
+		return err
+	}
+	role, err := parseShareableRole(req.Role)
+	if err != nil {
+		return err
+	}
+	member, err := uc.requireNonOwnerMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.listRepo.UpdateMemberRole(ctx, id, userID, role); err != nil {
+		if errors.Is(err, repositories.ErrListMemberNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	before := audit.Hash(member)
+	member.Role = role
+	audit.Observe(ctx, audit.ActionMemberUpdated, id+"/"+userID, before, audit.Hash(member))
+	return nil
+}
+
+// RemoveMember removes a member from a list. Members may always leave; only
+// the owner can remove others, and the owner cannot be removed.
+func (uc *ListUseCase) RemoveMember(ctx context.Context, id, userID string) error {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	permission := entities.ListPermissionManage
+	if userID == principal.UserID {
+		permission = entities.ListPermissionView
+	}
+	if _, err := uc.access.authorizeList(ctx, id, permission); err != nil {
+		return err
+	}
+	member, err := uc.requireNonOwnerMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.listRepo.RemoveMember(ctx, id, userID); err != nil {
+		if errors.Is(err, repositories.ErrListMemberNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionMemberRemoved, id+"/"+userID, audit.Hash(member), "")
+	return nil
+}
+
+// CreateInvite issues an invite token granting role on a list
+func (uc *ListUseCase) CreateInvite(ctx context.Context, id string, req dto.CreateInviteRequest) (*CreatedListInvite, error) {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionManage); err != nil {
+		return nil, err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	role, err := parseShareableRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(uc.inviteTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) || req.ExpiresAt.After(expiresAt) {
+			return nil, fmt.Errorf("%w: expiresAt must be in the future and at most %s away", ErrInvalidInput, uc.inviteTTL)
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	invite := entities.NewListInvite(id, principal.UserID, hashToken(token), role, expiresAt)
+	if err := uc.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionInviteCreated, invite.ID, "", audit.Hash(invite))
+	return &CreatedListInvite{Invite: invite, Token: token}, nil
+}
+
+// GetInvites returns the invites of a list the caller manages
+func (uc *ListUseCase) GetInvites(ctx context.Context, id string) ([]*entities.ListInvite, error) {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionManage); err != nil {
+		return nil, err
+	}
+
+	invites, err := uc.inviteRepo.ListByList(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invites: %w", err)
+	}
+	return invites, nil
+}
+
+// RevokeInvite revokes an invite so that it can no longer be accepted
+func (uc *ListUseCase) RevokeInvite(ctx context.Context, id, inviteID string) error {
+
+	if _, err := uc.access.authorizeList(ctx, id, entities.ListPermissionManage); err != nil {
+		return err
+	}
+
+	if err := uc.inviteRepo.Revoke(ctx, id, inviteID); err != nil {
+		if errors.Is(err, repositories.ErrListInviteNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionInviteRevoked, inviteID, "", "")
+	return nil
+}
+
+// AcceptInvite makes the caller a member of the invite's list. Expired,
+// revoked and already accepted invites are all reported as not found.
+func (uc *ListUseCase) AcceptInvite(ctx context.Context, req dto.AcceptInviteRequest) (*entities.ListMembership, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := uc.inviteRepo.GetByHash(ctx, hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrListInviteNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+	if !invite.IsUsable(time.Now()) {
+		return nil, repositories.ErrListInviteNotFound
+	}
+
+	var list *entities.TodoList
+	member := entities.NewListMember(invite.ListID, principal.UserID, invite.Role)
+	err = uc.listRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.listRepo.AddMember(txCtx, member); err != nil {
+			return err
+		}
+		if err := uc.inviteRepo.MarkAccepted(txCtx, invite.ID, principal.UserID); err != nil {
+			return err
+		}
+		list, err = uc.listRepo.GetByID(txCtx, invite.ListID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrListMemberExists) || errors.Is(err, repositories.ErrListInviteNotFound) || errors.Is(err, repositories.ErrListNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to accept invite: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionInviteAccepted, invite.ID, "", audit.Hash(member))
+	return &entities.ListMembership{List: list, Role: invite.Role}, nil
+}
+
+// requireNonOwnerMember returns a member, rejecting changes to the owner's membership
+func (uc *ListUseCase) requireNonOwnerMember(ctx context.Context, listID, userID string) (*entities.ListMember, error) {
+	member, err := uc.listRepo.GetMember(ctx, listID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrListMemberNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get list member: %w", err)
+	}
+	if member.Role == entities.ListRoleOwner {
+		return nil, fmt.Errorf("%w: the owner's membership cannot be changed", ErrInvalidInput)
+	}
+	return member, nil
+}
+
+// parseShareableRole parses a role that can be granted to other users
+func parseShareableRole(value string) (entities.ListRole, error) {
+	role := entities.ListRole(value)
+	if !role.IsValid() || role == entities.ListRoleOwner {
+		return "", fmt.Errorf("%w: role must be editor, commenter or viewer", ErrInvalidInput)
+	}
+	return role, nil
+}
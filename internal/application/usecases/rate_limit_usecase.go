@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// RateLimitRule applies its own policy to the requests it matches
+type RateLimitRule struct {
+	// Method matches every method when empty
+	Method string
+	// Path is a route pattern: ":name" matches one segment and a trailing
+	// "*" matches the rest of the path, if any
+	Path   string
+	Policy entities.RateLimitPolicy
+}
+
+// matches reports whether the rule applies to a request
+func (r RateLimitRule) matches(method, path string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+
+	pattern := strings.Split(strings.Trim(r.Path, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range pattern {
+		if part == "*" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(part, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+// RateLimitRequest identifies a request to rate limit
+type RateLimitRequest struct {
+	Method   string
+	Path     string
+	ClientIP string
+}
+
+// RateLimitBucketStatus is a bucket together with the policy it is filled by
+type RateLimitBucketStatus struct {
+	Bucket *entities.RateLimitBucket
+	Policy entities.RateLimitPolicy
+}
+
+type RateLimitUseCase struct {
+	store    repositories.RateLimitStore
+	fallback entities.RateLimitPolicy
+	rules    []RateLimitRule
+	policies map[string]entities.RateLimitPolicy
+}
+
+// NewRateLimitUseCase limits requests matching one of rules by the rule's
+// policy and every other request by fallback. Policy names must be unique.
+func NewRateLimitUseCase(store repositories.RateLimitStore, fallback entities.RateLimitPolicy, rules ...RateLimitRule) *RateLimitUseCase {
+	policies := map[string]entities.RateLimitPolicy{fallback.Name: fallback}
+	for _, rule := range rules {
+		policies[rule.Policy.Name] = rule.Policy
+	}
+	return &RateLimitUseCase{
+		store:    store,
+		fallback: fallback,
+		rules:    rules,
+		policies: policies,
+	}
+}
+
+// Take spends a token from the caller's bucket under the policy that applies
+// to req. It returns nil when no policy limits the request.
+func (uc *RateLimitUseCase) Take(ctx context.Context, req RateLimitRequest) (*entities.RateLimitDecision, error) {
+
+	policy := uc.fallback
+	for _, rule := range uc.rules {
+		if rule.matches(req.Method, req.Path) {
+			policy = rule.Policy
+			break
+		}
+	}
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	tenantID, _ := tenancy.FromContext(ctx)
+	decision, err := uc.store.Take(ctx, tenantID, rateLimitSubject(ctx, req.ClientIP), policy, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// GetBuckets returns the buckets of the caller's tenant as of now, only those
+// of subject unless it is empty
+func (uc *RateLimitUseCase) GetBuckets(ctx context.Context, subject string) ([]RateLimitBucketStatus, error) {
+
+	if _, err := identity.RequireScope(ctx, identity.ScopeAdmin); err != nil {
+		return nil, err
+	}
+	tenantID, _ := tenancy.FromContext(ctx)
+
+	buckets, err := uc.store.List(ctx, tenantID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit buckets: %w", err)
+	}
+
+	now := time.Now()
+	statuses := make([]RateLimitBucketStatus, 0, len(buckets))
+	for _, bucket := range buckets {
+		policy, ok := uc.policies[bucket.Policy]
+		if !ok {
+			// The policy was removed from the configuration
+			continue
+		}
+		bucket.Refill(policy, now)
+		statuses = append(statuses, RateLimitBucketStatus{Bucket: bucket, Policy: policy})
+	}
+	return statuses, nil
+}
+
+// ResetBuckets refills the buckets of subject in the caller's tenant, only
+// the one under policy unless it is empty, and returns how many were reset
+func (uc *RateLimitUseCase) ResetBuckets(ctx context.Context, subject, policy string) (int64, error) {
+
+	if _, err := identity.RequireScope(ctx, identity.ScopeAdmin); err != nil {
+		return 0, err
+	}
+	if subject == "" {
+		return 0, fmt.Errorf("%w: subject is required", ErrInvalidInput)
+	}
+	tenantID, _ := tenancy.FromContext(ctx)
+
+	reset, err := uc.store.Reset(ctx, tenantID, subject, policy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset rate limit buckets: %w", err)
+	}
+	return reset, nil
+}
+
+// rateLimitSubject names the bucket owner: the API key, else the user, else the client IP
+func rateLimitSubject(ctx context.Context, clientIP string) string {
+	if principal, ok := identity.FromContext(ctx); ok {
+		if principal.APIKeyID != "" {
+			return "key:" + principal.APIKeyID
+		}
+		return "user:" + principal.UserID
+	}
+	return "ip:" + clientIP
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// serviceUserPrefix starts the user ID of every service principal so that
+// services cannot be mistaken for users
+const serviceUserPrefix = "service:"
+
+// ServiceClient is a machine-to-machine caller identified by the subject of
+// its TLS client certificate
+type ServiceClient struct {
+	Subject string
+	Name    string
+	Scopes  []identity.Scope
+	// TenantID pins the service to a tenant; empty uses the request's tenant
+	TenantID string
+}
+
+type ServiceAuthUseCase struct {
+	clients map[string]ServiceClient
+}
+
+// NewServiceAuthUseCase authenticates clients by certificate subject.
+// Subjects, names and scopes are validated.
+func NewServiceAuthUseCase(clients ...ServiceClient) (*ServiceAuthUseCase, error) {
+	bySubject := make(map[string]ServiceClient, len(clients))
+	names := map[string]bool{}
+	for _, client := range clients {
+		if client.Subject == "" || client.Name == "" {
+			return nil, fmt.Errorf("%w: service clients need a subject and a name", ErrInvalidInput)
+		}
+		if _, ok := bySubject[client.Subject]; ok || names[client.Name] {
+			return nil, fmt.Errorf("%w: duplicate service client %q", ErrInvalidInput, client.Name)
+		}
+		for _, scope := range client.Scopes {
+			if !scope.IsKnown() {
+				return nil, fmt.Errorf("%w: service client %q: unknown scope %q", ErrInvalidInput, client.Name, scope)
+			}
+		}
+		if client.TenantID != "" {
+			if err := tenancy.ValidateID(client.TenantID); err != nil {
+				return nil, fmt.Errorf("%w: service client %q: %v", ErrInvalidInput, client.Name, err)
+			}
+		}
+		bySubject[client.Subject] = client
+		names[client.Name] = true
+	}
+	return &ServiceAuthUseCase{clients: bySubject}, nil
+}
+
+// AuthenticateCertificate resolves the subject of a verified client
+// certificate to its service principal. It returns
+// identity.ErrUnauthenticated for subjects of no known service.
+func (uc *ServiceAuthUseCase) AuthenticateCertificate(ctx context.Context, subject string) (*identity.Principal, error) {
+
+	client, ok := uc.clients[subject]
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+
+	tenantID := client.TenantID
+	if tenantID == "" {
+		tenantID, _ = tenancy.FromContext(ctx)
+	}
+	return &identity.Principal{
+		UserID:   serviceUserPrefix + client.Name,
+		Username: client.Name,
+		Scopes:   client.Scopes,
+		TenantID: tenantID,
+	}, nil
+}
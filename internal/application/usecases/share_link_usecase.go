@@ -0,0 +1,201 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const (
+	// maxShareLinkAccesses bounds the access log returned for a share link
+	maxShareLinkAccesses = 100
+	// maxUserAgentLength bounds the user agent stored per access
+	maxUserAgentLength = 256
+)
+
+// ErrShareLinkPassword is returned when a protected share link is opened
+// without its password or with a wrong one
+var ErrShareLinkPassword = errors.New("share link password required or incorrect")
+
+// CreatedShareLink is a new share link together with its token, which is never shown again
+type CreatedShareLink struct {
+	Link  *entities.ShareLink
+	Token string
+}
+
+// ShareLinkVisit describes an anonymous attempt to open a share link
+type ShareLinkVisit struct {
+	Token     string
+	Password  string
+	IPAddress string
+	UserAgent string
+}
+
+// SharedList is what a share link exposes of a list
+type SharedList struct {
+	Name  string
+	Todos []*entities.Todo
+}
+
+type ShareLinkUseCase struct {
+	listRepo repositories.ListRepository
+	linkRepo repositories.ShareLinkRepository
+	hasher   PasswordHasher
+	access   listAccess
+}
+
+func NewShareLinkUseCase(listRepo repositories.ListRepository, linkRepo repositories.ShareLinkRepository, hasher PasswordHasher) *ShareLinkUseCase {
+	return &ShareLinkUseCase{
+		listRepo: listRepo,
+		linkRepo: linkRepo,
+		hasher:   hasher,
+		access:   listAccess{lists: listRepo},
+	}
+}
+
+// CreateShareLink issues a read-only public link to a list the caller manages
+func (uc *ShareLinkUseCase) CreateShareLink(ctx context.Context, listID string, req dto.CreateShareLinkRequest) (*CreatedShareLink, error) {
+
+	if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionManage); err != nil {
+		return nil, err
+	}
+	principal, _ := identity.FromContext(ctx)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiresAt must be in the future", ErrInvalidInput)
+	}
+	if req.Password != "" && (len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength) {
+		return nil, fmt.Errorf("%w: password must be %d-%d characters", ErrInvalidInput, minPasswordLength, maxPasswordLength)
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	link := entities.NewShareLink(listID, principal.UserID, hashToken(token))
+	link.ExpiresAt = req.ExpiresAt
+	link.HideCompleted = req.HideCompleted
+	if req.Password != "" {
+		if link.PasswordHash, err = uc.hasher.Hash(req.Password); err != nil {
+			return nil, fmt.Errorf("failed to hash share link password: %w", err)
+		}
+	}
+
+	if err := uc.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionShareLinkCreated, link.ID, "", audit.Hash(link))
+	return &CreatedShareLink{Link: link, Token: token}, nil
+}
+
+// GetShareLinks returns the share links of a list the caller manages
+func (uc *ShareLinkUseCase) GetShareLinks(ctx context.Context, listID string) ([]*entities.ShareLink, error) {
+
+	if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionManage); err != nil {
+		return nil, err
+	}
+
+	links, err := uc.linkRepo.ListByList(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+	return links, nil
+}
+
+// RevokeShareLink revokes a share link so that it can no longer be opened
+func (uc *ShareLinkUseCase) RevokeShareLink(ctx context.Context, listID, linkID string) error {
+
+	if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionManage); err != nil {
+		return err
+	}
+
+	if err := uc.linkRepo.Revoke(ctx, listID, linkID); err != nil {
+		if errors.Is(err, repositories.ErrShareLinkNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionShareLinkRevoked, linkID, "", "")
+	return nil
+}
+
+// GetShareLinkAccesses returns the latest entries of a share link's access log
+func (uc *ShareLinkUseCase) GetShareLinkAccesses(ctx context.Context, listID, linkID string) ([]*entities.ShareLinkAccess, error) {
+
+	if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionManage); err != nil {
+		return nil, err
+	}
+
+	accesses, err := uc.linkRepo.ListAccesses(ctx, listID, linkID, maxShareLinkAccesses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link accesses: %w", err)
+	}
+	return accesses, nil
+}
+
+// OpenShareLink returns the list behind a share link to an anonymous
+// visitor. Unknown, expired and revoked links are all reported as not found.
+// Every attempt on an existing link is recorded in its access log.
+func (uc *ShareLinkUseCase) OpenShareLink(ctx context.Context, visit ShareLinkVisit) (*SharedList, error) {
+
+	link, err := uc.linkRepo.GetByHash(ctx, hashToken(visit.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrShareLinkNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	outcome := entities.ShareLinkGranted
+	switch {
+	case !link.IsActive(time.Now()):
+		outcome = entities.ShareLinkInactive
+	case link.HasPassword() && (visit.Password == "" || uc.hasher.Verify(visit.Password, link.PasswordHash) != nil):
+		outcome = entities.ShareLinkDenied
+	}
+	userAgent := visit.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	if err := uc.linkRepo.RecordAccess(ctx, entities.NewShareLinkAccess(link, outcome, visit.IPAddress, userAgent)); err != nil {
+		return nil, fmt.Errorf("failed to record share link access: %w", err)
+	}
+
+	switch outcome {
+	case entities.ShareLinkInactive:
+		return nil, repositories.ErrShareLinkNotFound
+	case entities.ShareLinkDenied:
+		return nil, ErrShareLinkPassword
+	}
+
+	list, err := uc.listRepo.GetByID(ctx, link.ListID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrListNotFound) {
+			return nil, repositories.ErrShareLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get shared list: %w", err)
+	}
+	todos, err := uc.linkRepo.SharedTodos(ctx, link.ListID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared todos: %w", err)
+	}
+	if link.HideCompleted {
+		open := todos[:0]
+		for _, todo := range todos {
+			if !todo.Completed {
+				open = append(open, todo)
+			}
+		}
+		todos = open
+	}
+
+	return &SharedList{Name: list.Name, Todos: todos}, nil
+}
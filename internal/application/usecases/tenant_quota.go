@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// QuotaFunc returns the quota of a tenant
+type QuotaFunc func(tenantID string) entities.TenantQuota
+
+// tenantQuotas rejects changes that would take the caller's tenant over its
+// quota. Without a usage repository nothing is limited.
+type tenantQuotas struct {
+	usage  repositories.TenantUsageRepository
+	quotas QuotaFunc
+}
+
+// WithQuotas limits the todos and storage of each tenant
+func WithQuotas(usage repositories.TenantUsageRepository, quotas QuotaFunc) TodoUseCaseOption {
+	return func(uc *TodoUseCase) {
+		uc.quotas = tenantQuotas{usage: usage, quotas: quotas}
+	}
+}
+
+// reserve checks that todos more todos and bytes more bytes of text fit the
+// tenant's quota. Checks made inside a transaction see the changes made
+// earlier in it, so a batch cannot slip past the quota.
+func (q tenantQuotas) reserve(ctx context.Context, todos, bytes int64) error {
+	if q.usage == nil || (todos <= 0 && bytes <= 0) {
+		return nil
+	}
+	tenantID, err := tenancy.Require(ctx)
+	if err != nil {
+		return err
+	}
+	quota := q.quotas(tenantID)
+	if quota.MaxTodos == 0 && quota.MaxStorageBytes == 0 {
+		return nil
+	}
+
+	usage, err := q.usage.Usage(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to measure tenant usage: %w", err)
+	}
+	if !quota.Allows(usage, todos, bytes) {
+		return fmt.Errorf("%w: tenant %s allows %d todos and %d bytes", tenancy.ErrQuotaExceeded, tenantID, quota.MaxTodos, quota.MaxStorageBytes)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+)
+
+// TenantStatus is the usage and quota of a tenant
+type TenantStatus struct {
+	ID    string
+	Usage *entities.TenantUsage
+	Quota entities.TenantQuota
+}
+
+type TenantUseCase struct {
+	usage  repositories.TenantUsageRepository
+	quotas QuotaFunc
+}
+
+func NewTenantUseCase(usage repositories.TenantUsageRepository, quotas QuotaFunc) *TenantUseCase {
+	return &TenantUseCase{
+		usage:  usage,
+		quotas: quotas,
+	}
+}
+
+// GetStatus returns the usage and quota of the caller's tenant
+func (uc *TenantUseCase) GetStatus(ctx context.Context) (*TenantStatus, error) {
+
+	tenantID, err := tenancy.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := uc.usage.Usage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure tenant usage: %w", err)
+	}
+
+	return &TenantStatus{ID: tenantID, Usage: usage, Quota: uc.quotas(tenantID)}, nil
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrBatchRolledBack is returned when an atomic batch fails and none of its operations are applied
+	ErrBatchRolledBack = errors.New("batch rolled back")
+	// ErrBatchAborted marks operations that were not applied because another operation in an atomic batch failed
+	ErrBatchAborted = errors.New("operation not applied because the batch was rolled back")
+)
+
+// BatchItemResult is the outcome of a single batch operation
+type BatchItemResult struct {
+	Index int
+	Op    string
+	ID    string
+	Todo  *entities.Todo
+	Err   error
+}
+
+// ExecuteBatch applies req.Operations in order. In atomic mode every operation runs
+// in one transaction and a single failure rolls back the whole batch; otherwise each
+// operation is applied independently. The operations that were committed are logged
+// as one batch, which is marked committed once every operation has run; the change
+// stream holds the batch back until then and delivers it as one aggregated change
+// event, however many other changes were logged in between.
+func (uc *TodoUseCase) ExecuteBatch(ctx context.Context, req dto.BatchRequest) (_ []BatchItemResult, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.ExecuteBatch")
+	defer func() { endSpan(span, err) }()
+
+	if len(req.Operations) == 0 {
+		return nil, fmt.Errorf("%w: batch must contain at least one operation", ErrInvalidInput)
+	}
+
+	results := make([]BatchItemResult, len(req.Operations))
+	for i, op := range req.Operations {
+		results[i] = BatchItemResult{Index: i, Op: op.Op, ID: op.ID}
+	}
+	ctx = events.WithBatch(ctx, uuid.New().String())
+
+	if req.Atomic {
+		return uc.executeAtomicBatch(ctx, req.Operations, results)
+	}
+
+	applied := false
+	for i, op := range req.Operations {
+		if err := validateBatchOperation(op); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		todo, id, err := uc.applyBatchOperation(ctx, op)
+		results[i].Todo, results[i].Err = todo, err
+		if err == nil {
+			results[i].ID = id
+			applied = true
+		}
+	}
+	if applied {
+		// The operations are committed already, so failing to mark the batch
+		// only keeps it off the change stream
+		if err := uc.commitBatch(ctx); err != nil {
+			slog.ErrorContext(ctx, "Failed to commit batch", "error", err)
+		}
+	}
+	return results, nil
+}
+
+func (uc *TodoUseCase) executeAtomicBatch(ctx context.Context, ops []dto.BatchOperation, results []BatchItemResult) ([]BatchItemResult, error) {
+	// Reject the whole batch up front when any operation is malformed
+	for i, op := range ops {
+		if err := validateBatchOperation(op); err != nil {
+			results[i].Err = err
+			return abortBatch(results), ErrBatchRolledBack
+		}
+	}
+
+	err := uc.todoRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		for i, op := range ops {
+			todo, id, err := uc.applyBatchOperation(txCtx, op)
+			if err != nil {
+				results[i].Err = err
+				return err
+			}
+			results[i].Todo, results[i].ID = todo, id
+		}
+		return uc.commitBatch(txCtx)
+	})
+	if err != nil {
+		return abortBatch(results), ErrBatchRolledBack
+	}
+	return results, nil
+}
+
+// commitBatch marks the batch in ctx committed, and appends an event to the
+// outbox in the same transaction so that its subscribers learn of it
+func (uc *TodoUseCase) commitBatch(ctx context.Context) error {
+	return uc.todoRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.todoRepo.CommitBatch(txCtx); err != nil {
+			return err
+		}
+		if uc.outbox == nil {
+			return nil
+		}
+		batchID, _ := events.BatchFromContext(ctx)
+		event := uc.stamp(ctx, events.NewChangeEvent(events.TodosBatchApplied))
+		event.ID = batchID
+		if err := uc.outbox.Append(txCtx, &event); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+		return nil
+	})
+}
+
+// applyBatchOperation applies op and returns the todo it left, if any, and its ID
+func (uc *TodoUseCase) applyBatchOperation(ctx context.Context, op dto.BatchOperation) (*entities.Todo, string, error) {
+	var todo *entities.Todo
+	var err error
+	switch op.Op {
+	case dto.BatchOpCreate:
+		todo, err = uc.createTodo(ctx, op.ListID, op.Text)
+	case dto.BatchOpUpdate:
+		todo, err = uc.updateTodoText(ctx, op.ID, op.Text)
+	case dto.BatchOpComplete:
+		todo, err = uc.completeTodo(ctx, op.ID)
+	case dto.BatchOpDelete:
+		if _, err = uc.deleteTodo(ctx, op.ID); err != nil {
+			return nil, "", err
+		}
+		return nil, op.ID, nil
+	default:
+		return nil, "", fmt.Errorf("%w: unknown operation %q", ErrInvalidInput, op.Op)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return todo, todo.ID, nil
+}
+
+// abortBatch marks every operation without its own error as not applied
+func abortBatch(results []BatchItemResult) []BatchItemResult {
+	for i := range results {
+		results[i].Todo = nil
+		if results[i].Err == nil {
+			results[i].Err = ErrBatchAborted
+		}
+	}
+	return results
+}
+
+func validateBatchOperation(op dto.BatchOperation) error {
+	switch op.Op {
+	case dto.BatchOpCreate:
+		if op.Text == "" {
+			return fmt.Errorf("%w: todo text cannot be empty", ErrInvalidInput)
+		}
+	case dto.BatchOpUpdate:
+		if op.ID == "" || op.Text == "" {
+			return fmt.Errorf("%w: update requires id and text", ErrInvalidInput)
+		}
+	case dto.BatchOpDelete, dto.BatchOpComplete:
+		if op.ID == "" {
+			return fmt.Errorf("%w: %s requires id", ErrInvalidInput, op.Op)
+		}
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidInput, op.Op)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"todo-backend/internal/application/patch"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/interfaces/dto"
+)
+
+// mutableTodoFields lists the todo members a patch may modify
+var mutableTodoFields = map[string]bool{
+	"text":      true,
+	"completed": true,
+}
+
+// PatchTodo applies a JSON Merge Patch or JSON Patch to the todo with the given ID.
+// The patch is applied to the todo's JSON representation as a whole, so a failing
+// operation, including a failed "test", leaves the todo untouched.
+func (uc *TodoUseCase) PatchTodo(ctx context.Context, id string, req dto.PatchTodoRequest) (_ *entities.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.PatchTodo")
+	defer func() { endSpan(span, err) }()
+
+	todo, err := uc.getEditableTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := todoDocument(todo)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched map[string]interface{}
+	switch req.Format {
+	case dto.PatchFormatMerge:
+		patched, err = patch.ApplyMergePatch(doc, req.Patch, mutableTodoFields)
+	case dto.PatchFormatJSONPatch:
+		patched, err = patch.ApplyJSONPatch(doc, req.Patch, mutableTodoFields)
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch format %q", ErrInvalidInput, req.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	text, ok := patched["text"].(string)
+	if !ok || text == "" {
+		return nil, fmt.Errorf("%w: text must be a non-empty string", patch.ErrInvalidPatch)
+	}
+	completed, ok := patched["completed"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("%w: completed must be a boolean", patch.ErrInvalidPatch)
+	}
+
+	wasCompleted := todo.Completed
+	if text == todo.Text && completed == wasCompleted {
+		return todo, nil
+	}
+
+	before := audit.Hash(todo)
+	if text != todo.Text {
+		if err := uc.quotas.reserve(ctx, 0, int64(len(text)-len(todo.Text))); err != nil {
+			return nil, err
+		}
+		todo.UpdateText(text)
+	}
+	if completed && !wasCompleted {
+		todo.Complete()
+	} else if !completed && wasCompleted {
+		todo.Reopen()
+	}
+
+	action := audit.ActionTodoUpdated
+	if completed && !wasCompleted {
+		action = audit.ActionTodoCompleted
+	}
+	saved, err := uc.saveObservedTodo(ctx, action, todo, before)
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// todoDocument returns the JSON representation patches are applied to
+func todoDocument(todo *entities.Todo) (map[string]interface{}, error) {
+	raw, err := json.Marshal(dto.ToTodoDetailResponse(todo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode todo: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode todo: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,493 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// syncClockSkew is subtracted from the retention when deciding whether
+	// the changes since a sync token are still in the log, for the entries
+	// written while the token was issued
+	syncClockSkew = time.Minute
+	// defaultSyncChanges is the number of log entries read by one sync when
+	// the limits leave it unset
+	defaultSyncChanges = 500
+)
+
+// SyncLimits configures the delta sync
+type SyncLimits struct {
+	// Retention is how long the change log is kept. A client that has not synced
+	// for longer gets a full snapshot.
+	Retention time.Duration
+	// MaxChanges bounds the log entries read by one sync; HasMore tells the
+	// client to sync again for the rest
+	MaxChanges int
+}
+
+// syncLog is the change log the delta sync reads
+type syncLog struct {
+	log    repositories.ChangeLogRepository
+	limits SyncLimits
+}
+
+// WithSync enables Sync on top of the change log
+func WithSync(log repositories.ChangeLogRepository, limits SyncLimits) TodoUseCaseOption {
+	return func(uc *TodoUseCase) {
+		uc.sync = syncLog{log: log, limits: limits}
+	}
+}
+
+// SyncResult is what a client has to apply to its copy to catch up
+type SyncResult struct {
+	Token string
+	// Full is set when Todos is every todo of the caller, which replace the client's copy
+	Full           bool
+	HasMore        bool
+	Todos          []*entities.Todo
+	DeletedTodoIDs []string
+	RemovedListIDs []string
+	Results        []SyncMutationResult
+}
+
+// SyncMutationResult is the outcome of a single sync mutation
+type SyncMutationResult struct {
+	ClientID string
+	TodoID   string
+	// Status is one of the dto.SyncStatus values
+	Status    string
+	Todo      *entities.Todo
+	Deleted   bool
+	Conflicts []FieldConflict
+	Err       error
+}
+
+// FieldConflict is a field a mutation changed that another writer changed too
+type FieldConflict struct {
+	Field       string
+	ClientValue interface{}
+	ServerValue interface{}
+}
+
+// syncToken is the position of a client in the change log: the sequence number
+// of the last entry it has seen, and when it got it
+type syncToken struct {
+	seq      int64
+	issuedAt time.Time
+}
+
+func (t syncToken) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", t.seq, t.issuedAt.Unix())))
+}
+
+func parseSyncToken(s string) (syncToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return syncToken{}, fmt.Errorf("%w: invalid sync token", ErrInvalidInput)
+	}
+	seq, issued, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return syncToken{}, fmt.Errorf("%w: invalid sync token", ErrInvalidInput)
+	}
+	token := syncToken{}
+	if token.seq, err = strconv.ParseInt(seq, 10, 64); err != nil {
+		return syncToken{}, fmt.Errorf("%w: invalid sync token", ErrInvalidInput)
+	}
+	unix, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil {
+		return syncToken{}, fmt.Errorf("%w: invalid sync token", ErrInvalidInput)
+	}
+	token.issuedAt = time.Unix(unix, 0)
+	return token, nil
+}
+
+// Sync applies the mutations an offline client queued, each independently and
+// in order, then returns the changes since req.SyncToken, or every todo of the
+// caller when there is no token or the log no longer reaches back to it.
+//
+// A mutation based on an older version of a todo than the server's is merged
+// per field: a field changed only by the client is applied, a field changed
+// on the server since the base version is left as the server has it and
+// reported as a conflict, unless both ended up with the same value.
+func (uc *TodoUseCase) Sync(ctx context.Context, req dto.SyncRequest) (_ *SyncResult, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.Sync")
+	defer func() { endSpan(span, err) }()
+
+	if uc.sync.log == nil {
+		return nil, errors.New("sync is not enabled")
+	}
+	var token syncToken
+	if req.SyncToken != "" {
+		if token, err = parseSyncToken(req.SyncToken); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &SyncResult{Results: make([]SyncMutationResult, len(req.Mutations))}
+	for i, mutation := range req.Mutations {
+		result.Results[i] = uc.applySyncMutation(ctx, mutation)
+	}
+
+	now := time.Now()
+	expired := uc.sync.limits.Retention > 0 && now.After(token.issuedAt.Add(uc.sync.limits.Retention-syncClockSkew))
+	if req.SyncToken == "" || expired {
+		err = uc.syncSnapshot(ctx, now, result)
+	} else {
+		err = uc.syncChanges(ctx, token, now, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncSnapshot returns every todo of the caller
+func (uc *TodoUseCase) syncSnapshot(ctx context.Context, now time.Time, result *SyncResult) error {
+	// Read the position first: changes made meanwhile are sent again next time
+	_, newest, err := uc.sync.log.SeqRange(ctx)
+	if err != nil {
+		return err
+	}
+	todos, err := uc.todoRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get todos: %w", err)
+	}
+
+	result.Full = true
+	result.Todos = todos
+	result.Token = syncToken{seq: newest, issuedAt: now}.String()
+	return nil
+}
+
+// syncChanges returns the todos changed since token, the todos deleted and
+// the lists left, and the todos of the lists joined
+func (uc *TodoUseCase) syncChanges(ctx context.Context, token syncToken, now time.Time, result *SyncResult) error {
+	limit := uc.sync.limits.MaxChanges
+	if limit <= 0 {
+		limit = defaultSyncChanges
+	}
+	entries, err := uc.sync.log.VisibleSince(ctx, token.seq, limit+1)
+	if err != nil {
+		return err
+	}
+
+	next := syncToken{seq: token.seq, issuedAt: now}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		result.HasMore = true
+		// The entries still to come are only as fresh as the old token
+		next.issuedAt = token.issuedAt
+	}
+	if len(entries) > 0 {
+		next.seq = entries[len(entries)-1].Seq
+	}
+	result.Token = next.String()
+
+	// Only the last entry of every todo and list matters
+	var todoIDs, listIDs []string
+	todos := make(map[string]entities.ChangeKind)
+	lists := make(map[string]entities.ChangeKind)
+	for _, entry := range entries {
+		switch {
+		case entry.IsTodoChange():
+			if _, ok := todos[entry.TodoID]; !ok {
+				todoIDs = append(todoIDs, entry.TodoID)
+			}
+			todos[entry.TodoID] = entry.Kind
+		case entry.Kind == entities.ChangeListJoined || entry.Kind == entities.ChangeListLeft:
+			if _, ok := lists[entry.ListID]; !ok {
+				listIDs = append(listIDs, entry.ListID)
+			}
+			lists[entry.ListID] = entry.Kind
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, listID := range listIDs {
+		if lists[listID] == entities.ChangeListLeft {
+			result.RemovedListIDs = append(result.RemovedListIDs, listID)
+			continue
+		}
+		listTodos, err := uc.todoRepo.GetByList(ctx, listID)
+		if err != nil {
+			return fmt.Errorf("failed to get todos: %w", err)
+		}
+		for _, todo := range listTodos {
+			if !seen[todo.ID] {
+				seen[todo.ID] = true
+				result.Todos = append(result.Todos, todo)
+			}
+		}
+	}
+	for _, todoID := range todoIDs {
+		if seen[todoID] {
+			continue
+		}
+		seen[todoID] = true
+		if todos[todoID] == entities.ChangeTodoDeleted {
+			result.DeletedTodoIDs = append(result.DeletedTodoIDs, todoID)
+			continue
+		}
+		todo, err := uc.todoRepo.GetByID(ctx, todoID)
+		if errors.Is(err, repositories.ErrTodoNotFound) {
+			// Deleted or out of reach since
+			result.DeletedTodoIDs = append(result.DeletedTodoIDs, todoID)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+		result.Todos = append(result.Todos, todo)
+	}
+	return nil
+}
+
+// applySyncMutation applies one mutation
+func (uc *TodoUseCase) applySyncMutation(ctx context.Context, mutation dto.SyncMutation) SyncMutationResult {
+	result := SyncMutationResult{ClientID: mutation.ClientID, TodoID: mutation.TodoID}
+	var err error
+	switch mutation.Op {
+	case dto.SyncOpCreate:
+		err = uc.syncCreate(ctx, mutation, &result)
+	case dto.SyncOpUpdate:
+		err = uc.syncUpdate(ctx, mutation, &result)
+	case dto.SyncOpDelete:
+		err = uc.syncDelete(ctx, mutation, &result)
+	default:
+		err = fmt.Errorf("%w: unknown operation %q", ErrInvalidInput, mutation.Op)
+	}
+	if err != nil {
+		return SyncMutationResult{ClientID: mutation.ClientID, TodoID: mutation.TodoID, Status: dto.SyncStatusRejected, Err: err}
+	}
+	if result.Status == "" {
+		result.Status = dto.SyncStatusApplied
+		if len(result.Conflicts) > 0 {
+			result.Status = dto.SyncStatusConflict
+		}
+	}
+	return result
+}
+
+// syncCreate creates a todo under the ID the client generated. Replaying a
+// create that was already applied changes nothing.
+func (uc *TodoUseCase) syncCreate(ctx context.Context, mutation dto.SyncMutation, result *SyncMutationResult) error {
+	if _, err := uuid.Parse(mutation.TodoID); err != nil {
+		return fmt.Errorf("%w: todoId must be a UUID", ErrInvalidInput)
+	}
+	if mutation.Text == nil || *mutation.Text == "" {
+		return fmt.Errorf("%w: todo text cannot be empty", ErrInvalidInput)
+	}
+
+	existing, err := uc.todoRepo.GetByID(ctx, mutation.TodoID)
+	if err == nil {
+		result.Todo = existing
+		return nil
+	}
+	if !errors.Is(err, repositories.ErrTodoNotFound) {
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+	if deleted, err := uc.tombstoned(ctx, mutation.TodoID); err != nil || deleted {
+		result.Deleted = deleted
+		return err
+	}
+
+	todo := entities.NewTodo(*mutation.Text)
+	todo.ID = mutation.TodoID
+	if mutation.Completed != nil && *mutation.Completed {
+		todo.Complete()
+	}
+	created, err := uc.insertTodo(ctx, mutation.ListID, todo)
+	if err != nil {
+		return err
+	}
+
+	result.Todo = created
+	return nil
+}
+
+// syncUpdate merges the fields a client changed into the todo
+func (uc *TodoUseCase) syncUpdate(ctx context.Context, mutation dto.SyncMutation, result *SyncMutationResult) error {
+	if mutation.BaseVersion < 1 {
+		return fmt.Errorf("%w: baseVersion is required", ErrInvalidInput)
+	}
+	if mutation.Text == nil && mutation.Completed == nil {
+		return fmt.Errorf("%w: update must change text or completed", ErrInvalidInput)
+	}
+	if mutation.Text != nil && *mutation.Text == "" {
+		return fmt.Errorf("%w: todo text cannot be empty", ErrInvalidInput)
+	}
+
+	todo, err := uc.getSyncedTodo(ctx, mutation.TodoID, result)
+	if err != nil || todo == nil {
+		if result.Deleted {
+			result.Status = dto.SyncStatusConflict
+		}
+		return err
+	}
+	changed, err := uc.changedSince(ctx, todo, mutation.BaseVersion)
+	if err != nil {
+		return err
+	}
+
+	wasCompleted := todo.Completed
+	text, completed := todo.Text, todo.Completed
+	if mutation.Text != nil && *mutation.Text != todo.Text {
+		if changed[entities.TodoFieldText] {
+			result.Conflicts = append(result.Conflicts, FieldConflict{Field: entities.TodoFieldText, ClientValue: *mutation.Text, ServerValue: todo.Text})
+		} else {
+			text = *mutation.Text
+		}
+	}
+	if mutation.Completed != nil && *mutation.Completed != todo.Completed {
+		if changed[entities.TodoFieldCompleted] {
+			result.Conflicts = append(result.Conflicts, FieldConflict{Field: entities.TodoFieldCompleted, ClientValue: *mutation.Completed, ServerValue: todo.Completed})
+		} else {
+			completed = *mutation.Completed
+		}
+	}
+	if text == todo.Text && completed == wasCompleted {
+		result.Todo = todo
+		return nil
+	}
+
+	before := audit.Hash(todo)
+	if text != todo.Text {
+		if err := uc.quotas.reserve(ctx, 0, int64(len(text)-len(todo.Text))); err != nil {
+			return err
+		}
+		todo.UpdateText(text)
+	}
+	if completed && !wasCompleted {
+		todo.Complete()
+	} else if !completed && wasCompleted {
+		todo.Reopen()
+	}
+
+	action := audit.ActionTodoUpdated
+	if completed && !wasCompleted {
+		action = audit.ActionTodoCompleted
+	}
+	saved, err := uc.saveObservedTodo(ctx, action, todo, before)
+	if err != nil {
+		return err
+	}
+
+	result.Todo = saved
+	return nil
+}
+
+// syncDelete deletes the todo unless another writer changed it since the
+// client's base version. Deleting a deleted todo changes nothing.
+func (uc *TodoUseCase) syncDelete(ctx context.Context, mutation dto.SyncMutation, result *SyncMutationResult) error {
+	if mutation.BaseVersion < 1 {
+		return fmt.Errorf("%w: baseVersion is required", ErrInvalidInput)
+	}
+
+	todo, err := uc.getSyncedTodo(ctx, mutation.TodoID, result)
+	if err != nil || todo == nil {
+		return err
+	}
+	changed, err := uc.changedSince(ctx, todo, mutation.BaseVersion)
+	if err != nil {
+		return err
+	}
+	if changed[entities.TodoFieldText] {
+		result.Conflicts = append(result.Conflicts, FieldConflict{Field: entities.TodoFieldText, ServerValue: todo.Text})
+	}
+	if changed[entities.TodoFieldCompleted] {
+		result.Conflicts = append(result.Conflicts, FieldConflict{Field: entities.TodoFieldCompleted, ServerValue: todo.Completed})
+	}
+	if len(result.Conflicts) > 0 {
+		result.Todo = todo
+		return nil
+	}
+
+	if _, err := uc.deleteTodo(ctx, todo.ID); err != nil {
+		return err
+	}
+
+	result.Deleted = true
+	return nil
+}
+
+// getSyncedTodo loads the todo a mutation changes. It returns no todo and no
+// error, with result.Deleted set, when the todo was deleted.
+func (uc *TodoUseCase) getSyncedTodo(ctx context.Context, id string, result *SyncMutationResult) (*entities.Todo, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: todo ID cannot be empty", ErrInvalidInput)
+	}
+
+	todo, err := uc.getEditableTodo(ctx, id)
+	if err == nil || !errors.Is(err, repositories.ErrTodoNotFound) {
+		return todo, err
+	}
+	deleted, tombstoneErr := uc.tombstoned(ctx, id)
+	if tombstoneErr != nil {
+		return nil, tombstoneErr
+	}
+	if !deleted {
+		return nil, err
+	}
+	result.Deleted = true
+	return nil, nil
+}
+
+// tombstoned reports whether the change log records that the caller's todo was deleted
+func (uc *TodoUseCase) tombstoned(ctx context.Context, id string) (bool, error) {
+	history, err := uc.sync.log.History(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range history {
+		if entry.Kind == entities.ChangeTodoDeleted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// changedSince returns the fields of todo saved after baseVersion. When the
+// log no longer covers every version since, all fields count as changed.
+func (uc *TodoUseCase) changedSince(ctx context.Context, todo *entities.Todo, baseVersion int64) (map[string]bool, error) {
+	if baseVersion > todo.Version {
+		return nil, fmt.Errorf("%w: baseVersion %d is ahead of the todo's version %d", ErrInvalidInput, baseVersion, todo.Version)
+	}
+	changed := make(map[string]bool)
+	if todo.Version == baseVersion {
+		return changed, nil
+	}
+
+	history, err := uc.sync.log.History(ctx, todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	next := baseVersion + 1
+	for _, entry := range history {
+		if entry.Kind == entities.ChangeTodoDeleted || entry.Version < next {
+			continue
+		}
+		if entry.Version > next {
+			break
+		}
+		for _, field := range entry.Fields {
+			changed[field] = true
+		}
+		next++
+	}
+	if next <= todo.Version {
+		changed[entities.TodoFieldText] = true
+		changed[entities.TodoFieldCompleted] = true
+	}
+	return changed, nil
+}
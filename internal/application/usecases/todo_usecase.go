@@ -4,39 +4,73 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/correlation"
 	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
 	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
 	"todo-backend/internal/interfaces/dto"
 )
 
 // test-driven - no code
 
+var (
+	// ErrInvalidInput is wrapped by errors caused by invalid client input
+	ErrInvalidInput = errors.New("invalid input")
+)
+
 type TodoUseCase struct {
 	todoRepo repositories.TodoRepository
+	access   listAccess
+	quotas   tenantQuotas
+	sync     syncLog
+	outbox   repositories.OutboxRepository
 }
 
-func NewTodoUseCase(todoRepo repositories.TodoRepository) *TodoUseCase {
-	return &TodoUseCase{
-		todoRepo: todoRepo,
+// TodoUseCaseOption configures optional TodoUseCase collaborators
+type TodoUseCaseOption func(*TodoUseCase)
+
+// WithOutbox stores the domain events todos raise in outbox, in the
+// transaction of the change that raised them
+func WithOutbox(outbox repositories.OutboxRepository) TodoUseCaseOption {
+	return func(uc *TodoUseCase) {
+		uc.outbox = outbox
 	}
 }
 
-func (uc *TodoUseCase) CreateTodo(ctx context.Context, req dto.CreateTodoRequest) (*entities.Todo, error) {
+// WithListRepository enables todos on shared lists, authorized by the caller's list role
+func WithListRepository(listRepo repositories.ListRepository) TodoUseCaseOption {
+	return func(uc *TodoUseCase) {
+		uc.access = listAccess{lists: listRepo}
+	}
+}
 
-	if req.Text == "" {
-		return nil, fmt.Errorf("todo text cannot be empty")
+func NewTodoUseCase(todoRepo repositories.TodoRepository, opts ...TodoUseCaseOption) *TodoUseCase {
+	uc := &TodoUseCase{
+		todoRepo: todoRepo,
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
+}
 
-	todo := req.ToEntity()
-	created, err := uc.todoRepo.Create(ctx, todo)
+func (uc *TodoUseCase) CreateTodo(ctx context.Context, req dto.CreateTodoRequest) (_ *entities.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.CreateTodo")
+	defer func() { endSpan(span, err) }()
+
+	created, err := uc.createTodo(ctx, req.ListID, req.Text)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+		return nil, err
 	}
-
 	return created, nil
 }
 
-func (uc *TodoUseCase) GetAllTodos(ctx context.Context) ([]*entities.Todo, error) {
+func (uc *TodoUseCase) GetAllTodos(ctx context.Context) (_ []*entities.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetAllTodos")
+	defer func() { endSpan(span, err) }()
 
 	todos, err := uc.todoRepo.GetAll(ctx)
 	if err != nil {
@@ -46,19 +80,238 @@ func (uc *TodoUseCase) GetAllTodos(ctx context.Context) ([]*entities.Todo, error
 	return todos, nil
 }
 
-func (uc *TodoUseCase) GetTodoByID(ctx context.Context, id string) (*entities.Todo, error) {
+// GetTodosByList returns the todos of a list the caller may view
+func (uc *TodoUseCase) GetTodosByList(ctx context.Context, listID string) (_ []*entities.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetTodosByList")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionView); err != nil {
+		return nil, err
+	}
+
+	todos, err := uc.todoRepo.GetByList(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+func (uc *TodoUseCase) GetTodoByID(ctx context.Context, id string) (_ *entities.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetTodoByID")
+	defer func() { endSpan(span, err) }()
 
 	if id == "" {
-		return nil, fmt.Errorf("todo ID cannot be empty")
+		return nil, fmt.Errorf("%w: todo ID cannot be empty", ErrInvalidInput)
 	}
 
 	todo, err := uc.todoRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrTodoNotFound) {
-			return nil, fmt.Errorf("todo with ID %s not found", id)
+			return nil, fmt.Errorf("todo with ID %s not found: %w", id, repositories.ErrTodoNotFound)
 		}
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
 
 	return todo, nil
 }
+
+func (uc *TodoUseCase) createTodo(ctx context.Context, listID, text string) (*entities.Todo, error) {
+	return uc.insertTodo(ctx, listID, entities.NewTodo(text))
+}
+
+// insertTodo stores a new todo owned by the caller, on listID unless it is empty
+func (uc *TodoUseCase) insertTodo(ctx context.Context, listID string, todo *entities.Todo) (*entities.Todo, error) {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Text == "" {
+		return nil, fmt.Errorf("%w: todo text cannot be empty", ErrInvalidInput)
+	}
+	if listID != "" {
+		if _, err := uc.access.authorizeList(ctx, listID, entities.ListPermissionEdit); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.quotas.reserve(ctx, 1, int64(len(todo.Text))); err != nil {
+		return nil, err
+	}
+
+	todo.OwnerID = principal.UserID
+	todo.ListID = listID
+	created, err := uc.storeWithEvents(ctx, todo, func(ctx context.Context) (*entities.Todo, error) {
+		created, err := uc.todoRepo.Create(ctx, todo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create todo: %w", err)
+		}
+		return created, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Observe(ctx, audit.ActionTodoCreated, created.ID, "", audit.Hash(created))
+	return created, nil
+}
+
+func (uc *TodoUseCase) updateTodoText(ctx context.Context, id, text string) (*entities.Todo, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: todo text cannot be empty", ErrInvalidInput)
+	}
+
+	todo, err := uc.getEditableTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.quotas.reserve(ctx, 0, int64(len(text)-len(todo.Text))); err != nil {
+		return nil, err
+	}
+
+	before := audit.Hash(todo)
+	todo.UpdateText(text)
+	return uc.saveObservedTodo(ctx, audit.ActionTodoUpdated, todo, before)
+}
+
+func (uc *TodoUseCase) completeTodo(ctx context.Context, id string) (*entities.Todo, error) {
+	todo, err := uc.getEditableTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := audit.Hash(todo)
+	todo.Complete()
+	return uc.saveObservedTodo(ctx, audit.ActionTodoCompleted, todo, before)
+}
+
+// deleteTodo deletes a todo and returns it as it was
+func (uc *TodoUseCase) deleteTodo(ctx context.Context, id string) (*entities.Todo, error) {
+	todo, err := uc.getEditableTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	todo.Delete()
+	_, err = uc.storeWithEvents(ctx, todo, func(ctx context.Context) (*entities.Todo, error) {
+		if err := uc.todoRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrTodoNotFound) {
+				return nil, fmt.Errorf("todo with ID %s not found: %w", id, repositories.ErrTodoNotFound)
+			}
+			return nil, fmt.Errorf("failed to delete todo: %w", err)
+		}
+		return todo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Observe(ctx, audit.ActionTodoDeleted, id, audit.Hash(todo), "")
+	return todo, nil
+}
+
+// deleteListTodos deletes the todos of a list, each with its event, and then
+// runs deleteList in the same transaction
+func (uc *TodoUseCase) deleteListTodos(ctx context.Context, listID string, deleteList func(ctx context.Context) error) error {
+	return uc.todoRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		todos, err := uc.todoRepo.GetByList(txCtx, listID)
+		if err != nil {
+			return fmt.Errorf("failed to get todos: %w", err)
+		}
+		for _, todo := range todos {
+			todo.Delete()
+			_, err := uc.storeWithEvents(txCtx, todo, func(ctx context.Context) (*entities.Todo, error) {
+				if err := uc.todoRepo.Delete(ctx, todo.ID); err != nil {
+					return nil, fmt.Errorf("failed to delete todo: %w", err)
+				}
+				return todo, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return deleteList(txCtx)
+	})
+}
+
+// getEditableTodo loads a todo the caller may edit
+func (uc *TodoUseCase) getEditableTodo(ctx context.Context, id string) (*entities.Todo, error) {
+	todo, err := uc.GetTodoByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.access.authorizeTodo(ctx, todo, entities.ListPermissionEdit); err != nil {
+		if errors.Is(err, repositories.ErrTodoNotFound) {
+			return nil, fmt.Errorf("todo with ID %s not found: %w", id, err)
+		}
+		return nil, err
+	}
+	return todo, nil
+}
+
+func (uc *TodoUseCase) saveTodo(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	updated, err := uc.todoRepo.Update(ctx, todo)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTodoNotFound) {
+			return nil, fmt.Errorf("todo with ID %s not found: %w", todo.ID, repositories.ErrTodoNotFound)
+		}
+		return nil, fmt.Errorf("failed to update todo: %w", err)
+	}
+
+	return updated, nil
+}
+
+// saveObservedTodo saves todo and records the change on the audit trail.
+// before is the hash of the todo as it was loaded.
+func (uc *TodoUseCase) saveObservedTodo(ctx context.Context, action string, todo *entities.Todo, before string) (*entities.Todo, error) {
+	saved, err := uc.storeWithEvents(ctx, todo, func(ctx context.Context) (*entities.Todo, error) {
+		return uc.saveTodo(ctx, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Observe(ctx, action, saved.ID, before, audit.Hash(saved))
+	return saved, nil
+}
+
+// storeWithEvents runs write, which stores todo, and appends the domain
+// events todo raised to the outbox in the same transaction. The events carry
+// the todo as write returns it.
+func (uc *TodoUseCase) storeWithEvents(ctx context.Context, todo *entities.Todo, write func(ctx context.Context) (*entities.Todo, error)) (*entities.Todo, error) {
+	if uc.outbox == nil {
+		stored, err := write(ctx)
+		todo.PullEvents()
+		return stored, err
+	}
+
+	var stored *entities.Todo
+	err := uc.todoRepo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if stored, err = write(txCtx); err != nil {
+			return err
+		}
+		for _, raised := range todo.PullEvents() {
+			event := uc.stamp(ctx, events.FromTodoEvent(raised, stored))
+			if err := uc.outbox.Append(txCtx, &event); err != nil {
+				return fmt.Errorf("failed to store event: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// stamp attributes event to the request, trace and tenant in ctx
+func (uc *TodoUseCase) stamp(ctx context.Context, event events.ChangeEvent) events.ChangeEvent {
+	if id, ok := correlation.FromContext(ctx); ok {
+		event.RequestID = id
+	}
+	event.TraceParent = traceParent(ctx)
+	if tenantID, ok := tenancy.FromContext(ctx); ok {
+		event.TenantID = tenantID
+	}
+	return event
+}
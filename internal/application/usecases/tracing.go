@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer uses the globally installed tracer provider, which is a no-op
+// unless tracing is enabled
+var tracer = otel.Tracer("todo-backend/internal/application/usecases")
+
+// startSpan starts a span for a use case method. ctx is left as it is while
+// tracing is disabled.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	spanCtx, span := tracer.Start(ctx, name)
+	if !span.SpanContext().IsValid() {
+		return ctx, span
+	}
+	return spanCtx, span
+}
+
+// endSpan records the error the method returned, if any, and ends span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceParent returns the W3C traceparent header of the span in ctx, or ""
+// when ctx carries none, so that work done later can join the trace
+func traceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
@@ -0,0 +1,425 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+)
+
+const (
+	// webhookSecretMarker starts every webhook secret
+	webhookSecretMarker = "whsec_"
+	maxWebhookURLLength = 2048
+	maxWebhooksPerUser  = 25
+	// maxWebhookDeliveries bounds the deliveries listed at once
+	maxWebhookDeliveries = 100
+	// webhookBatchSize is how many due deliveries one run of the worker claims
+	webhookBatchSize = 20
+)
+
+// webhookEventTypes are the events webhooks can subscribe to
+var webhookEventTypes = map[events.Type]bool{
+	events.TodoCreated:   true,
+	events.TodoUpdated:   true,
+	events.TodoCompleted: true,
+	events.TodoDeleted:   true,
+}
+
+// WebhookSender posts a delivery's payload to its webhook
+type WebhookSender interface {
+	// Send returns the status code the receiver responded with, or an error
+	// when it could not be reached
+	Send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error)
+}
+
+// WebhookLimits configures the delivery of webhooks
+type WebhookLimits struct {
+	// Timeout bounds a single attempt
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery is dead
+	MaxAttempts int
+	// BackoffBase is the wait after the first failed attempt, doubled after
+	// every further one up to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// CreatedWebhook is a new webhook together with its secret, which is never shown again
+type CreatedWebhook struct {
+	Webhook *entities.Webhook
+	Secret  string
+}
+
+// WebhookDeliveryDetail is a delivery with its delivery log
+type WebhookDeliveryDetail struct {
+	Delivery *entities.WebhookDelivery
+	Attempts []*entities.WebhookAttempt
+}
+
+// WebhookUseCase manages webhooks and delivers the todo events they
+// subscribed to. As an outbox subscriber it turns every event into a delivery
+// per webhook, which DeliverDue sends.
+type WebhookUseCase struct {
+	webhookRepo repositories.WebhookRepository
+	sender      WebhookSender
+	limits      WebhookLimits
+	access      listAccess
+}
+
+func NewWebhookUseCase(webhookRepo repositories.WebhookRepository, listRepo repositories.ListRepository, sender WebhookSender, limits WebhookLimits) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		limits:      limits,
+		access:      listAccess{lists: listRepo},
+	}
+}
+
+// CreateWebhook registers a webhook for the caller
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, req dto.CreateWebhookRequest) (*CreatedWebhook, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ListID != "" {
+		if _, err := uc.access.authorizeList(ctx, req.ListID, entities.ListPermissionView); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := uc.webhookRepo.ListByOwner(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("%w: at most %d webhooks per user", ErrInvalidInput, maxWebhooksPerUser)
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	secret := webhookSecretMarker + token
+
+	webhook := entities.NewWebhook(principal.UserID, req.URL, secret, req.ListID, eventTypes)
+	if err := uc.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionWebhookCreated, webhook.ID, "", audit.Hash(webhook))
+	return &CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+// ListWebhooks returns the caller's webhooks
+func (uc *WebhookUseCase) ListWebhooks(ctx context.Context) ([]*entities.Webhook, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := uc.webhookRepo.ListByOwner(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns one of the caller's webhooks
+func (uc *WebhookUseCase) GetWebhook(ctx context.Context, id string) (*entities.Webhook, error) {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := uc.webhookRepo.GetByID(ctx, id, principal.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes one of the caller's webhooks with its deliveries
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, id string) error {
+
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.webhookRepo.Delete(ctx, id, principal.UserID); err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	audit.Observe(ctx, audit.ActionWebhookDeleted, id, "", "")
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of one of the caller's
+// webhooks, limited to status unless it is empty. The dead deliveries form
+// the webhook's dead-letter queue.
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, webhookID, status string) ([]*entities.WebhookDelivery, error) {
+
+	if _, err := uc.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveryStatus := entities.WebhookDeliveryStatus(status)
+	switch deliveryStatus {
+	case "", entities.WebhookDeliveryPending, entities.WebhookDeliveryDelivered, entities.WebhookDeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", ErrInvalidInput, status)
+	}
+
+	deliveries, err := uc.webhookRepo.ListDeliveries(ctx, webhookID, deliveryStatus, maxWebhookDeliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery of one of the caller's webhooks with its attempts
+func (uc *WebhookUseCase) GetDelivery(ctx context.Context, webhookID, id string) (*WebhookDeliveryDetail, error) {
+
+	if _, err := uc.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, webhookID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookDeliveryNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	attempts, err := uc.webhookRepo.ListAttempts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook attempts: %w", err)
+	}
+	return &WebhookDeliveryDetail{Delivery: delivery, Attempts: attempts}, nil
+}
+
+// Redeliver queues a delivery of one of the caller's webhooks again, with a
+// fresh count of attempts. It is how deliveries leave the dead-letter queue.
+func (uc *WebhookUseCase) Redeliver(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error) {
+
+	if _, err := uc.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := uc.webhookRepo.Redeliver(ctx, webhookID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookDeliveryNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// Handle implements events.Handler. A delivery is stored for every webhook
+// subscribed to a change of the event; handling an event again stores none.
+func (uc *WebhookUseCase) Handle(ctx context.Context, event events.ChangeEvent) error {
+	var deliveries []*entities.WebhookDelivery
+	for i, change := range event.Changes {
+		webhooks, err := uc.webhookRepo.Subscribed(ctx, change.OwnerID, change.ListID)
+		if err != nil {
+			return fmt.Errorf("failed to find webhooks: %w", err)
+		}
+
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !webhook.Accepts(string(change.Type)) {
+				continue
+			}
+			if payload == nil {
+				if payload, err = webhookPayload(event, change); err != nil {
+					return err
+				}
+			}
+
+			// Every change of an event is delivered once per webhook
+			eventID := event.ID
+			if len(event.Changes) > 1 {
+				eventID = fmt.Sprintf("%s.%d", event.ID, i)
+			}
+			delivery := entities.NewWebhookDelivery(webhook.ID, eventID, string(change.Type), payload)
+			delivery.RequestID = event.RequestID
+			delivery.TraceParent = event.TraceParent
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	if err := uc.webhookRepo.Enqueue(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// DeliverDue attempts the deliveries that are due and returns how many were
+// attempted. Failed deliveries are retried with exponential backoff until
+// they run out of attempts.
+func (uc *WebhookUseCase) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	// Deliveries are attempted one after the other, so the lease has to
+	// outlast a batch of attempts that all time out
+	lease := now.Add(time.Duration(webhookBatchSize+1) * uc.limits.Timeout)
+
+	deliveries, err := uc.webhookRepo.ClaimDue(ctx, now, lease, webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	for i, delivery := range deliveries {
+		if err := uc.attempt(ctx, delivery); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends delivery once and records the outcome
+func (uc *WebhookUseCase) attempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	// The worker may claim the deliveries of every tenant at once, but the
+	// attempt is logged in the delivery's tenant
+	if delivery.TenantID != "" {
+		ctx = tenancy.WithTenant(ctx, delivery.TenantID)
+	}
+	webhook, err := uc.webhookRepo.GetForDelivery(ctx, delivery.WebhookID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWebhookNotFound) {
+			// Deleted meanwhile, together with its deliveries
+			return nil
+		}
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, uc.limits.Timeout)
+	started := time.Now()
+	statusCode, err := uc.sender.Send(sendCtx, webhook, delivery)
+	cancel()
+	finished := time.Now()
+
+	reason := ""
+	switch {
+	case err != nil:
+		reason = err.Error()
+	case statusCode < 200 || statusCode > 299:
+		reason = fmt.Sprintf("receiver responded with status %d", statusCode)
+	}
+
+	if reason == "" {
+		delivery.Delivered(finished)
+	} else {
+		var retryAt *time.Time
+		if delivery.Attempts+1 < uc.limits.MaxAttempts {
+			next := finished.Add(uc.backoff(delivery.Attempts + 1))
+			retryAt = &next
+		}
+		delivery.Failed(reason, retryAt)
+	}
+
+	attempt := entities.NewWebhookAttempt(delivery.ID, statusCode, reason, finished.Sub(started))
+	if err := uc.webhookRepo.RecordAttempt(ctx, delivery, attempt); err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (uc *WebhookUseCase) backoff(failures int) time.Duration {
+	wait := uc.limits.BackoffBase
+	for i := 1; i < failures && wait < uc.limits.BackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > uc.limits.BackoffMax {
+		wait = uc.limits.BackoffMax
+	}
+	return wait
+}
+
+// PurgeDeliveries removes the finished deliveries created before cutoff
+func (uc *WebhookUseCase) PurgeDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	purged, err := uc.webhookRepo.DeleteFinishedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook deliveries: %w", err)
+	}
+	return purged, nil
+}
+
+// webhookPayload encodes the body delivered for a change of event
+func webhookPayload(event events.ChangeEvent, change events.Change) ([]byte, error) {
+	payload := dto.WebhookEventPayload{
+		ID:         event.ID,
+		Type:       string(change.Type),
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		RequestID:  event.RequestID,
+		TodoID:     change.TodoID,
+		ListID:     change.ListID,
+	}
+	if change.Todo != nil {
+		payload.Todo = dto.ToTodoDetailResponse(change.Todo)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	return raw, nil
+}
+
+func validateWebhookURL(raw string) error {
+	if len(raw) > maxWebhookURLLength {
+		return fmt.Errorf("%w: url must be at most %d characters", ErrInvalidInput, maxWebhookURLLength)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidInput)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("%w: url must not contain credentials", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateWebhookEventTypes rejects unknown event types and drops duplicates
+func validateWebhookEventTypes(requested []string) ([]string, error) {
+	eventTypes := make([]string, 0, len(requested))
+	seen := make(map[string]bool)
+	for _, raw := range requested {
+		if !webhookEventTypes[events.Type(raw)] {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, raw)
+		}
+		if !seen[raw] {
+			seen[raw] = true
+			eventTypes = append(eventTypes, raw)
+		}
+	}
+	return eventTypes, nil
+}
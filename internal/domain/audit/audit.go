@@ -0,0 +1,109 @@
+// Package audit collects what a request changed so that it can be written
+// to the tamper-evident audit log once the request completes.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Change is one resource a request changed. The hashes fingerprint the
+// resource before and after the change; they are empty when the resource did
+// not exist.
+type Change struct {
+	// ActorID is set when the change names its actor itself, e.g. on sign-in
+	ActorID    string
+	Action     string
+	TargetID   string
+	BeforeHash string
+	AfterHash  string
+}
+
+// Trail collects the changes made while handling one request
+type Trail struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+// Changes returns the changes observed so far
+func (t *Trail) Changes() []Change {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Change(nil), t.changes...)
+}
+
+type trailKey struct{}
+
+// WithTrail returns a copy of ctx collecting changes into a new trail
+func WithTrail(ctx context.Context) (context.Context, *Trail) {
+	trail := &Trail{}
+	return context.WithValue(ctx, trailKey{}, trail), trail
+}
+
+// Observe records a change made by the caller on the trail carried by ctx.
+// It does nothing when ctx carries no trail, e.g. outside of request handling.
+func Observe(ctx context.Context, action, targetID, beforeHash, afterHash string) {
+	ObserveAs(ctx, "", action, targetID, beforeHash, afterHash)
+}
+
+// ObserveAs records a change made by actorID, for requests that are not
+// authenticated yet such as sign-ins
+func ObserveAs(ctx context.Context, actorID, action, targetID, beforeHash, afterHash string) {
+	trail, ok := ctx.Value(trailKey{}).(*Trail)
+	if !ok {
+		return
+	}
+	trail.mu.Lock()
+	defer trail.mu.Unlock()
+	trail.changes = append(trail.changes, Change{
+		ActorID:    actorID,
+		Action:     action,
+		TargetID:   targetID,
+		BeforeHash: beforeHash,
+		AfterHash:  afterHash,
+	})
+}
+
+// Hash fingerprints the state of a resource. It returns an empty string for
+// nil, i.e. for a resource that does not exist.
+func Hash(state interface{}) string {
+	if state == nil {
+		return ""
+	}
+	raw, err := json.Marshal(state)
+	if err != nil || string(raw) == "null" {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Actions recorded by the use cases
+const (
+	ActionTodoCreated      = "todo.created"
+	ActionTodoUpdated      = "todo.updated"
+	ActionTodoCompleted    = "todo.completed"
+	ActionTodoDeleted      = "todo.deleted"
+	ActionCommentCreated   = "comment.created"
+	ActionListCreated      = "list.created"
+	ActionListDeleted      = "list.deleted"
+	ActionMemberUpdated    = "list.member_updated"
+	ActionMemberRemoved    = "list.member_removed"
+	ActionInviteCreated    = "list.invite_created"
+	ActionInviteRevoked    = "list.invite_revoked"
+	ActionInviteAccepted   = "list.invite_accepted"
+	ActionShareLinkCreated = "list.share_link_created"
+	ActionShareLinkRevoked = "list.share_link_revoked"
+	ActionUserRegistered   = "user.registered"
+	ActionPasswordChanged  = "user.password_changed"
+	ActionSessionStarted   = "session.started"
+	ActionSessionRefreshed = "session.refreshed"
+	ActionSessionEnded     = "session.ended"
+	ActionAPIKeyCreated    = "api_key.created"
+	ActionAPIKeyRevoked    = "api_key.revoked"
+	ActionWebhookCreated   = "webhook.created"
+	ActionWebhookDeleted   = "webhook.deleted"
+)
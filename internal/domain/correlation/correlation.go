@@ -0,0 +1,18 @@
+// Package correlation carries the ID that ties together everything done on
+// behalf of one request: its logs, audit events, spans and notifications.
+package correlation
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx done on behalf of request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, if any
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+	"todo-backend/internal/domain/identity"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a long-lived credential for scripts and integrations. Only a hash
+// of the key is stored; Prefix identifies the key in listings and lookups.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []identity.Scope
+	ExpiresAt  *time.Time // nil for keys that never expire
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func NewAPIKey(userID, name, prefix, keyHash string, scopes []identity.Scope, expiresAt *time.Time) *APIKey {
+	return &APIKey{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsExpired reports whether the key has passed its expiry
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditGenesisHash is the previous hash of the first event of a chain
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditEvent records who changed what. The events of a tenant form a hash
+// chain: Seq increases without gaps and every event includes the hash of its
+// predecessor, so that removed, reordered or altered events are detected.
+type AuditEvent struct {
+	ID         string
+	Seq        int64
+	TenantID   string
+	ActorID    string // empty for anonymous requests
+	APIKeyID   string // set when the actor used an API key
+	Action     string
+	TargetID   string
+	BeforeHash string
+	AfterHash  string
+	Status     int // HTTP status the request was answered with
+	RequestID  string
+	ClientIP   string
+	OccurredAt time.Time
+	PrevHash   string
+	Hash       string
+}
+
+func NewAuditEvent(action, targetID string) *AuditEvent {
+	return &AuditEvent{
+		ID:         uuid.New().String(),
+		Action:     action,
+		TargetID:   targetID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// ComputeHash returns the hash of the event's content and of PrevHash. Times
+// are hashed at the one-second resolution they are stored with.
+func (e *AuditEvent) ComputeHash() string {
+	raw, _ := json.Marshal([]interface{}{
+		e.ID, e.Seq, e.TenantID, e.ActorID, e.APIKeyID, e.Action, e.TargetID,
+		e.BeforeHash, e.AfterHash, e.Status, e.RequestID, e.ClientIP,
+		e.OccurredAt.Unix(), e.PrevHash,
+	})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal links the event to its predecessor in the chain and computes its hash.
+// prev is nil for the first event of a chain.
+func (e *AuditEvent) Seal(prev *AuditEvent) {
+	e.Seq = 1
+	e.PrevHash = AuditGenesisHash
+	if prev != nil {
+		e.Seq = prev.Seq + 1
+		e.PrevHash = prev.Hash
+	}
+	e.Hash = e.ComputeHash()
+}
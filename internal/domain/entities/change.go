@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+// ChangeKind is what a change log entry records
+type ChangeKind string
+
+const (
+	ChangeTodoCreated   ChangeKind = ChangeKind(TodoCreatedEvent)
+	ChangeTodoUpdated   ChangeKind = ChangeKind(TodoUpdatedEvent)
+	ChangeTodoCompleted ChangeKind = ChangeKind(TodoCompletedEvent)
+	// ChangeTodoDeleted is the todo's tombstone
+	ChangeTodoDeleted ChangeKind = ChangeKind(TodoDeletedEvent)
+	// ChangeListJoined records that a user became a member of a list
+	ChangeListJoined ChangeKind = "list.joined"
+	// ChangeListLeft records that a user stopped being a member of a list
+	ChangeListLeft ChangeKind = "list.left"
+	// ChangeBatchCommitted records that every change of a batch request has
+	// committed, after the last of them
+	ChangeBatchCommitted ChangeKind = "batch.committed"
+)
+
+// TodoKinds are the kinds of entries that change a todo
+var TodoKinds = []ChangeKind{ChangeTodoCreated, ChangeTodoUpdated, ChangeTodoCompleted, ChangeTodoDeleted}
+
+// Fields of a todo that clients change independently
+const (
+	TodoFieldText      = "text"
+	TodoFieldCompleted = "completed"
+)
+
+// ChangeEntry is an entry of the change log, written by the repositories in
+// the same transaction as the change it records
+type ChangeEntry struct {
+	// Seq orders the entries of a tenant
+	Seq      int64
+	TenantID string
+	Kind     ChangeKind
+	TodoID   string
+	ListID   string
+	// OwnerID is the owner of the todo
+	OwnerID string
+	// UserID is the user who joined or left the list
+	UserID string
+	// Todo is the todo as the change left it; nil once deleted
+	Todo *Todo
+	// Version is the todo's version after the change
+	Version int64
+	// Fields are the fields the change set, all of them when the todo was created
+	Fields []string
+	// RequestID is the request that made the change, if any
+	RequestID string
+	// BatchID groups the changes made by one batch request
+	BatchID   string
+	ChangedAt time.Time
+}
+
+// IsTodoChange reports whether the entry changed a todo, rather than a list membership
+func (e *ChangeEntry) IsTodoChange() bool {
+	for _, kind := range TodoKinds {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
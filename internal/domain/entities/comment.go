@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a remark left on a todo by a member of its list
+type Comment struct {
+	ID        string
+	TodoID    string
+	AuthorID  string
+	Body      string
+	CreatedAt time.Time
+}
+
+func NewComment(todoID, authorID, body string) *Comment {
+	return &Comment{
+		ID:        uuid.New().String(),
+		TodoID:    todoID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+}
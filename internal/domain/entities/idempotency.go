@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+// IdempotencyStatus describes the lifecycle of an idempotency key
+type IdempotencyStatus string
+
+const (
+	IdempotencyInFlight  IdempotencyStatus = "in_flight"
+	IdempotencyCompleted IdempotencyStatus = "completed"
+)
+
+// IdempotencyRecord stores the outcome of a request made with an Idempotency-Key header
+type IdempotencyRecord struct {
+	Key          string
+	Scope        string
+	Fingerprint  string
+	Status       IdempotencyStatus
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	// LeaseExpiresAt ends the reservation of an in-flight record whose request
+	// stopped renewing it, such as one cut short by a crash
+	LeaseExpiresAt time.Time
+}
+
+// NewIdempotencyRecord creates an in-flight record that expires after ttl,
+// and is given up after lease unless renewed
+func NewIdempotencyRecord(key, scope, fingerprint string, ttl, lease time.Duration) *IdempotencyRecord {
+	now := time.Now()
+	return &IdempotencyRecord{
+		Key:            key,
+		Scope:          scope,
+		Fingerprint:    fingerprint,
+		Status:         IdempotencyInFlight,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+		LeaseExpiresAt: now.Add(lease),
+	}
+}
+
+// IsExpired reports whether the record has outlived its TTL
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// IsAbandoned reports whether the record is in flight past its lease
+func (r *IdempotencyRecord) IsAbandoned(now time.Time) bool {
+	return r.Status == IdempotencyInFlight && !now.Before(r.LeaseExpiresAt)
+}
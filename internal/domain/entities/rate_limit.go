@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimitPolicy is a token bucket: a client may burst up to Burst requests
+// and regains Requests tokens every Period
+type RateLimitPolicy struct {
+	Name     string
+	Requests int
+	Period   time.Duration
+	// Burst is the bucket's capacity; zero means Requests
+	Burst int
+}
+
+// Enabled reports whether the policy limits anything
+func (p RateLimitPolicy) Enabled() bool {
+	return p.Requests > 0 && p.Period > 0
+}
+
+// Capacity returns the number of tokens a full bucket holds
+func (p RateLimitPolicy) Capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Requests
+}
+
+// refillRate returns the tokens regained per second
+func (p RateLimitPolicy) refillRate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// RateLimitBucket holds the tokens left to one client under one policy
+type RateLimitBucket struct {
+	Tenant    string
+	Policy    string
+	Subject   string
+	Tokens    float64
+	UpdatedAt time.Time
+	// FullAt is when the bucket will have refilled completely; from then on
+	// it is indistinguishable from a new bucket and can be dropped
+	FullAt time.Time
+}
+
+// NewRateLimitBucket creates a full bucket for subject under policy
+func NewRateLimitBucket(tenant string, policy RateLimitPolicy, subject string, now time.Time) *RateLimitBucket {
+	return &RateLimitBucket{
+		Tenant:    tenant,
+		Policy:    policy.Name,
+		Subject:   subject,
+		Tokens:    float64(policy.Capacity()),
+		UpdatedAt: now,
+		FullAt:    now,
+	}
+}
+
+// Refill adds the tokens regained since the bucket was last updated
+func (b *RateLimitBucket) Refill(policy RateLimitPolicy, now time.Time) {
+	if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(float64(policy.Capacity()), b.Tokens+elapsed*policy.refillRate())
+		b.UpdatedAt = now
+	}
+}
+
+// Take refills the bucket and spends one token on a request, if one is left
+func (b *RateLimitBucket) Take(policy RateLimitPolicy, now time.Time) RateLimitDecision {
+	b.Refill(policy, now)
+
+	decision := RateLimitDecision{Policy: policy, Allowed: b.Tokens >= 1}
+	if decision.Allowed {
+		b.Tokens--
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - b.Tokens) / policy.refillRate())
+	}
+	decision.Remaining = int(math.Floor(b.Tokens))
+	decision.Reset = secondsToDuration((float64(policy.Capacity()) - b.Tokens) / policy.refillRate())
+	b.FullAt = now.Add(decision.Reset)
+	return decision
+}
+
+// RateLimitDecision is the outcome of taking a token for a request
+type RateLimitDecision struct {
+	Policy    RateLimitPolicy
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long a rejected client has to wait for the next token
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
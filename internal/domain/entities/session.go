@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device of a user. It groups the family of refresh
+// tokens issued from one login; deleting it revokes the whole family.
+type Session struct {
+	ID        string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewSession(userID string, ttl time.Duration) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsExpired reports whether the session has outlived its TTL
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// RefreshToken is a single-use token that can be exchanged for a new access
+// token. Only a hash of the token is stored.
+type RefreshToken struct {
+	ID        string
+	SessionID string
+	UserID    string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt is set once the token has been rotated; presenting it again signals theft
+	UsedAt *time.Time
+}
+
+func NewRefreshToken(session *Session, tokenHash string) *RefreshToken {
+	return &RefreshToken{
+		ID:        uuid.New().String(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: tokenHash,
+		CreatedAt: time.Now(),
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+// IsExpired reports whether the refresh token has outlived its TTL
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
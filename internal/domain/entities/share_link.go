@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink gives anyone holding its token anonymous, read-only access to a
+// list. Only hashes of the token and of the optional password are stored.
+type ShareLink struct {
+	ID            string
+	ListID        string
+	TokenHash     string
+	PasswordHash  string // empty when the link is not password protected
+	HideCompleted bool
+	CreatedBy     string
+	CreatedAt     time.Time
+	ExpiresAt     *time.Time // nil for links that never expire
+	RevokedAt     *time.Time
+}
+
+func NewShareLink(listID, createdBy, tokenHash string) *ShareLink {
+	return &ShareLink{
+		ID:        uuid.New().String(),
+		ListID:    listID,
+		TokenHash: tokenHash,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsActive reports whether the link can still be opened
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && (l.ExpiresAt == nil || now.Before(*l.ExpiresAt))
+}
+
+// HasPassword reports whether visitors must enter a password
+func (l *ShareLink) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+// ShareLinkOutcome is the result of an attempt to open a share link
+type ShareLinkOutcome string
+
+const (
+	ShareLinkGranted ShareLinkOutcome = "granted"
+	// ShareLinkDenied is recorded for a missing or wrong password
+	ShareLinkDenied ShareLinkOutcome = "denied"
+	// ShareLinkInactive is recorded for an expired or revoked link
+	ShareLinkInactive ShareLinkOutcome = "inactive"
+)
+
+// ShareLinkAccess is an entry in the access log of a share link
+type ShareLinkAccess struct {
+	ID         string
+	LinkID     string
+	ListID     string
+	Outcome    ShareLinkOutcome
+	IPAddress  string
+	UserAgent  string
+	AccessedAt time.Time
+}
+
+func NewShareLinkAccess(link *ShareLink, outcome ShareLinkOutcome, ipAddress, userAgent string) *ShareLinkAccess {
+	return &ShareLinkAccess{
+		ID:         uuid.New().String(),
+		LinkID:     link.ID,
+		ListID:     link.ListID,
+		Outcome:    outcome,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		AccessedAt: time.Now(),
+	}
+}
@@ -0,0 +1,25 @@
+package entities
+
+// TenantUsage measures what a tenant stores
+type TenantUsage struct {
+	Todos int64
+	// StorageBytes counts the text of the tenant's todos and comments
+	StorageBytes int64
+}
+
+// TenantQuota limits what a tenant may store. Zero means unlimited.
+type TenantQuota struct {
+	MaxTodos        int64
+	MaxStorageBytes int64
+}
+
+// Allows reports whether usage grown by todos and bytes stays within the quota
+func (q TenantQuota) Allows(usage *TenantUsage, todos, bytes int64) bool {
+	if q.MaxTodos > 0 && todos > 0 && usage.Todos+todos > q.MaxTodos {
+		return false
+	}
+	if q.MaxStorageBytes > 0 && bytes > 0 && usage.StorageBytes+bytes > q.MaxStorageBytes {
+		return false
+	}
+	return true
+}
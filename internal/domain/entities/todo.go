@@ -9,16 +9,52 @@ import (
 type Todo struct {
 	ID        string    `json:"id"`
 	Text      string    `json:"text"`
+	Completed bool      `json:"completed"`
+	Version   int64     `json:"version"` // starts at 1, incremented on every save
+	OwnerID   string    `json:"ownerId"`
+	ListID    string    `json:"listId"` // empty for the owner's personal todos
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+
+	// events are the domain events raised since the todo was created or loaded
+	events []TodoEvent
 }
 
 func NewTodo(text string) *Todo {
 	now := time.Now()
-	return &Todo{
+	todo := &Todo{
 		ID:        uuid.New().String(),
 		Text:      text,
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	todo.raise(TodoCreatedEvent, now)
+	return todo
+}
+
+// UpdateText replaces the todo text
+func (t *Todo) UpdateText(text string) {
+	t.Text = text
+	t.UpdatedAt = time.Now()
+	t.raise(TodoUpdatedEvent, t.UpdatedAt)
+}
+
+// Complete marks the todo as done
+func (t *Todo) Complete() {
+	t.Completed = true
+	t.UpdatedAt = time.Now()
+	t.raise(TodoCompletedEvent, t.UpdatedAt)
+}
+
+// Reopen marks a completed todo as not done
+func (t *Todo) Reopen() {
+	t.Completed = false
+	t.UpdatedAt = time.Now()
+	t.raise(TodoUpdatedEvent, t.UpdatedAt)
+}
+
+// Delete records that the todo is being deleted. Removing it is up to the repository.
+func (t *Todo) Delete() {
+	t.raise(TodoDeletedEvent, time.Now())
 }
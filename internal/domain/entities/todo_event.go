@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoEventType names what happened to a todo
+type TodoEventType string
+
+const (
+	TodoCreatedEvent   TodoEventType = "todo.created"
+	TodoUpdatedEvent   TodoEventType = "todo.updated"
+	TodoCompletedEvent TodoEventType = "todo.completed"
+	TodoDeletedEvent   TodoEventType = "todo.deleted"
+)
+
+// TodoEvent is a domain event raised by a todo when it changes. The events
+// are stored with the change, in the same transaction, and relayed from there.
+type TodoEvent struct {
+	ID         string
+	Type       TodoEventType
+	OccurredAt time.Time
+}
+
+func (t *Todo) raise(eventType TodoEventType, at time.Time) {
+	t.events = append(t.events, TodoEvent{ID: uuid.New().String(), Type: eventType, OccurredAt: at})
+}
+
+// PullEvents returns the events raised since the last call, oldest first,
+// and forgets them
+func (t *Todo) PullEvents() []TodoEvent {
+	raised := t.events
+	t.events = nil
+	return raised
+}
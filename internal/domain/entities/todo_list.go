@@ -0,0 +1,133 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListRole is a member's role on a shared todo list
+type ListRole string
+
+const (
+	ListRoleOwner     ListRole = "owner"
+	ListRoleEditor    ListRole = "editor"
+	ListRoleCommenter ListRole = "commenter"
+	ListRoleViewer    ListRole = "viewer"
+)
+
+// ListPermission is an action a list member may be allowed to perform
+type ListPermission string
+
+const (
+	// ListPermissionView allows reading the list, its todos, comments and members
+	ListPermissionView ListPermission = "view"
+	// ListPermissionComment allows commenting on the list's todos
+	ListPermissionComment ListPermission = "comment"
+	// ListPermissionEdit allows creating, changing and deleting the list's todos
+	ListPermissionEdit ListPermission = "edit"
+	// ListPermissionManage allows inviting, re-assigning and removing members
+	ListPermissionManage ListPermission = "manage"
+	// ListPermissionDelete allows deleting the list itself
+	ListPermissionDelete ListPermission = "delete"
+)
+
+// listRolePermissions is the permission matrix of list roles
+var listRolePermissions = map[ListRole][]ListPermission{
+	ListRoleOwner:     {ListPermissionView, ListPermissionComment, ListPermissionEdit, ListPermissionManage, ListPermissionDelete},
+	ListRoleEditor:    {ListPermissionView, ListPermissionComment, ListPermissionEdit},
+	ListRoleCommenter: {ListPermissionView, ListPermissionComment},
+	ListRoleViewer:    {ListPermissionView},
+}
+
+// IsValid reports whether r is a known role
+func (r ListRole) IsValid() bool {
+	_, ok := listRolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants permission
+func (r ListRole) Can(permission ListPermission) bool {
+	for _, granted := range listRolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// TodoList groups todos that can be shared with other users
+type TodoList struct {
+	ID        string
+	Name      string
+	OwnerID   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewTodoList(name, ownerID string) *TodoList {
+	now := time.Now()
+	return &TodoList{
+		ID:        uuid.New().String(),
+		Name:      name,
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ListMember grants a user a role on a list
+type ListMember struct {
+	ListID    string
+	UserID    string
+	Username  string // filled in when members are listed
+	Role      ListRole
+	CreatedAt time.Time
+}
+
+func NewListMember(listID, userID string, role ListRole) *ListMember {
+	return &ListMember{
+		ListID:    listID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ListMembership is a list together with the caller's role on it
+type ListMembership struct {
+	List *TodoList
+	Role ListRole
+}
+
+// ListInvite lets whoever holds its token join a list once. Only a hash of the
+// token is stored.
+type ListInvite struct {
+	ID         string
+	ListID     string
+	TokenHash  string
+	Role       ListRole
+	CreatedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	AcceptedAt *time.Time
+	AcceptedBy string
+}
+
+func NewListInvite(listID, createdBy, tokenHash string, role ListRole, expiresAt time.Time) *ListInvite {
+	return &ListInvite{
+		ID:        uuid.New().String(),
+		ListID:    listID,
+		TokenHash: tokenHash,
+		Role:      role,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsUsable reports whether the invite can still be accepted
+func (i *ListInvite) IsUsable(now time.Time) bool {
+	return i.RevokedAt == nil && i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
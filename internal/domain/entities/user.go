@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func NewUser(username, passwordHash string) *User {
+	now := time.Now()
+	return &User{
+		ID:           uuid.New().String(),
+		Username:     NormalizeUsername(username),
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// ChangePassword replaces the stored password hash
+func (u *User) ChangePassword(passwordHash string) {
+	u.PasswordHash = passwordHash
+	u.UpdatedAt = time.Now()
+}
+
+// NormalizeUsername returns the canonical form used to store and look up usernames
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
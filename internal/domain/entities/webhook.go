@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is a URL a user registered to be told about todo events. Deliveries
+// are signed with Secret, which is shown to the user once.
+type Webhook struct {
+	ID      string
+	OwnerID string
+	URL     string
+	Secret  string
+	// EventTypes are the event types delivered; empty delivers all
+	EventTypes []string
+	// ListID limits deliveries to the todos of a list; empty delivers the
+	// events of every todo the owner can access
+	ListID    string
+	CreatedAt time.Time
+}
+
+func NewWebhook(ownerID, url, secret, listID string, eventTypes []string) *Webhook {
+	return &Webhook{
+		ID:         uuid.New().String(),
+		OwnerID:    ownerID,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		ListID:     listID,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Accepts reports whether the webhook subscribed to events of eventType
+func (w *Webhook) Accepts(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is where a delivery is in its lifecycle
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending deliveries are attempted when NextAttemptAt is due
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered deliveries were accepted by the receiver
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead deliveries ran out of attempts; they form the
+	// dead-letter queue until redelivered
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event to be delivered to one webhook
+type WebhookDelivery struct {
+	ID        string
+	WebhookID string
+	// TenantID is the tenant the delivery was stored in
+	TenantID string
+	// EventID identifies the event; a webhook receives every event once
+	EventID   string
+	EventType string
+	// Payload is the request body, fixed when the event occurred
+	Payload []byte
+	// RequestID and TraceParent tie the delivery to the request that caused
+	// the event, if any
+	RequestID     string
+	TraceParent   string
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+func NewWebhookDelivery(webhookID, eventID, eventType string, payload []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// Delivered records a successful attempt at at
+func (d *WebhookDelivery) Delivered(at time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+// Failed records a failed attempt. The delivery is retried at retryAt, or
+// moves to the dead-letter queue when retryAt is nil.
+func (d *WebhookDelivery) Failed(reason string, retryAt *time.Time) {
+	d.Attempts++
+	d.LastError = reason
+	if retryAt == nil {
+		d.Status = WebhookDeliveryDead
+		return
+	}
+	d.NextAttemptAt = *retryAt
+}
+
+// WebhookAttempt is an entry of the delivery log: one request to a webhook
+type WebhookAttempt struct {
+	ID         string
+	DeliveryID string
+	// StatusCode is the receiver's response status, zero when there was no response
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+func NewWebhookAttempt(deliveryID string, statusCode int, errorMessage string, duration time.Duration) *WebhookAttempt {
+	return &WebhookAttempt{
+		ID:          uuid.New().String(),
+		DeliveryID:  deliveryID,
+		StatusCode:  statusCode,
+		Error:       errorMessage,
+		Duration:    duration,
+		AttemptedAt: time.Now(),
+	}
+}
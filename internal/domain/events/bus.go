@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Bus delivers published events to the subscribers in this process. A
+// subscriber that falls further behind than its buffer is dropped rather
+// than allowed to hold up publishers.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*Subscription]struct{})}
+}
+
+// Publish delivers event to every subscriber. It never blocks.
+func (b *Bus) Publish(_ context.Context, event ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe receives the events published from now on. Up to buffer events
+// are queued for the subscriber.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{bus: b, events: make(chan ChangeEvent, buffer)}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Subscribers returns how many subscribers the bus has
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove unsubscribes sub; b.mu must be held
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription is a subscriber of a Bus
+type Subscription struct {
+	bus    *Bus
+	events chan ChangeEvent
+}
+
+// Events returns the events published to the subscriber. The channel is
+// closed when the subscriber is dropped for falling behind, and on Close.
+func (s *Subscription) Events() <-chan ChangeEvent {
+	return s.events
+}
+
+// Close unsubscribes
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
@@ -0,0 +1,164 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"todo-backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies the kind of change an event describes
+type Type string
+
+const (
+	TodoCreated   Type = "todo.created"
+	TodoUpdated   Type = "todo.updated"
+	TodoCompleted Type = "todo.completed"
+	TodoDeleted   Type = "todo.deleted"
+	// TodosBatchApplied aggregates all changes made by one batch request
+	TodosBatchApplied Type = "todos.batch_applied"
+)
+
+// Change describes a single todo affected by an event
+type Change struct {
+	Type   Type   `json:"type"`
+	TodoID string `json:"todoId"`
+	// OwnerID and ListID tell who may see the change
+	OwnerID string `json:"ownerId,omitempty"`
+	ListID  string `json:"listId,omitempty"`
+	// Todo is the todo as the change left it; nil once deleted
+	Todo *entities.Todo `json:"todo,omitempty"`
+}
+
+// TodoChange describes a change of the given type to todo
+func TodoChange(changeType Type, todo *entities.Todo) Change {
+	change := Change{Type: changeType, TodoID: todo.ID, OwnerID: todo.OwnerID, ListID: todo.ListID}
+	if changeType != TodoDeleted {
+		snapshot := *todo
+		change.Todo = &snapshot
+	}
+	return change
+}
+
+// FromTodoEvent describes a domain event raised by a todo, as stored by the
+// change that raised it
+func FromTodoEvent(event entities.TodoEvent, todo *entities.Todo) ChangeEvent {
+	changeType := Type(event.Type)
+	return ChangeEvent{
+		ID:         event.ID,
+		Type:       changeType,
+		Changes:    []Change{TodoChange(changeType, todo)},
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+// FromChangeEntries describes logged changes as they are streamed: one event
+// per todo change, except for the changes of a batch request. These are held
+// back until the batch commits, and then make a single TodosBatchApplied
+// event at the sequence number of its commit, from the changes batches holds
+// for it. Entries that record no todo change are skipped.
+func FromChangeEntries(entries []*entities.ChangeEntry, batches map[string][]*entities.ChangeEntry) []ChangeEvent {
+	var changeEvents []ChangeEvent
+	for _, entry := range entries {
+		switch {
+		case entry.Kind == entities.ChangeBatchCommitted:
+			var changes []Change
+			for _, batched := range batches[entry.BatchID] {
+				changes = append(changes, changeFromEntry(batched))
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			changeEvents = append(changeEvents, ChangeEvent{
+				ID:         entry.BatchID,
+				Seq:        entry.Seq,
+				Type:       TodosBatchApplied,
+				Changes:    changes,
+				OccurredAt: entry.ChangedAt,
+				TenantID:   entry.TenantID,
+				RequestID:  entry.RequestID,
+			})
+		case entry.IsTodoChange() && entry.BatchID == "":
+			changeEvents = append(changeEvents, ChangeEvent{
+				ID:         strconv.FormatInt(entry.Seq, 10),
+				Seq:        entry.Seq,
+				Type:       Type(entry.Kind),
+				Changes:    []Change{changeFromEntry(entry)},
+				OccurredAt: entry.ChangedAt,
+				TenantID:   entry.TenantID,
+				RequestID:  entry.RequestID,
+			})
+		}
+	}
+	return changeEvents
+}
+
+// CommittedBatches returns the batches whose commit is among entries
+func CommittedBatches(entries []*entities.ChangeEntry) []string {
+	var batchIDs []string
+	for _, entry := range entries {
+		if entry.Kind == entities.ChangeBatchCommitted {
+			batchIDs = append(batchIDs, entry.BatchID)
+		}
+	}
+	return batchIDs
+}
+
+func changeFromEntry(entry *entities.ChangeEntry) Change {
+	return Change{Type: Type(entry.Kind), TodoID: entry.TodoID, OwnerID: entry.OwnerID, ListID: entry.ListID, Todo: entry.Todo}
+}
+
+type batchKey struct{}
+
+// WithBatch returns a copy of ctx whose changes are logged as one batch
+func WithBatch(ctx context.Context, batchID string) context.Context {
+	return context.WithValue(ctx, batchKey{}, batchID)
+}
+
+// BatchFromContext returns the batch the changes made with ctx belong to, if any
+func BatchFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(batchKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ChangeEvent is published after changes to todos have been committed
+type ChangeEvent struct {
+	ID string `json:"id"`
+	// Seq orders the events of a tenant once they are in the change log; a
+	// batch has the sequence number of its last change
+	Seq        int64     `json:"seq,omitempty"`
+	Type       Type      `json:"type"`
+	Changes    []Change  `json:"changes"`
+	OccurredAt time.Time `json:"occurredAt"`
+	TenantID   string    `json:"tenantId,omitempty"`
+	// RequestID is the request that made the changes, if any
+	RequestID string `json:"requestId,omitempty"`
+	// TraceParent is the W3C trace context of the request, if it was traced
+	TraceParent string `json:"traceParent,omitempty"`
+}
+
+// NewChangeEvent creates an event of the given type covering changes
+func NewChangeEvent(eventType Type, changes ...Change) ChangeEvent {
+	return ChangeEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Changes:    changes,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Handler reacts to the events relayed from the outbox. Events are relayed at
+// least once, so a handler must tolerate seeing an event again.
+type Handler interface {
+	Handle(ctx context.Context, event ChangeEvent) error
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(ctx context.Context, event ChangeEvent) error
+
+// Handle implements Handler
+func (f HandlerFunc) Handle(ctx context.Context, event ChangeEvent) error {
+	return f(ctx, event)
+}
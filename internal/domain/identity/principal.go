@@ -0,0 +1,68 @@
+// Package identity carries the authenticated caller through request contexts.
+package identity
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnauthenticated is returned when an operation requires a principal and none is present
+var ErrUnauthenticated = errors.New("authentication required")
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID   string
+	Username string
+	// SessionID is set for callers signed in with an access token
+	SessionID string
+	// APIKeyID is set for callers authenticated with an API key
+	APIKeyID string
+	Scopes   []Scope
+	// TenantID is the tenant the principal was authenticated in
+	TenantID string
+}
+
+// HasScope reports whether the principal was granted scope, directly or through ScopeAdmin
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope returns the principal carried by ctx if it holds scope.
+// It returns ErrUnauthenticated without a principal and ErrForbidden without the scope.
+func RequireScope(ctx context.Context, scope Scope) (*Principal, error) {
+	principal, err := Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.HasScope(scope) {
+		return nil, ErrForbidden
+	}
+	return principal, nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal carried by ctx, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// Require returns the principal carried by ctx or ErrUnauthenticated
+func Require(ctx context.Context) (*Principal, error) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
@@ -0,0 +1,51 @@
+package identity
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrForbidden is returned when the principal lacks a scope an operation requires
+var ErrForbidden = errors.New("insufficient permissions")
+
+// Scope is a permission granted to a principal
+type Scope string
+
+const (
+	ScopeTodosRead  Scope = "todos:read"
+	ScopeTodosWrite Scope = "todos:write"
+	// ScopeAdmin grants every other scope as well
+	ScopeAdmin Scope = "admin"
+)
+
+// KnownScopes lists every scope a principal can hold
+var KnownScopes = []Scope{ScopeTodosRead, ScopeTodosWrite, ScopeAdmin}
+
+// IsKnown reports whether s is one of KnownScopes
+func (s Scope) IsKnown() bool {
+	for _, known := range KnownScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes splits a space separated scope list (RFC 6749 section 3.3)
+func ParseScopes(value string) []Scope {
+	fields := strings.Fields(value)
+	scopes := make([]Scope, 0, len(fields))
+	for _, field := range fields {
+		scopes = append(scopes, Scope(field))
+	}
+	return scopes
+}
+
+// FormatScopes joins scopes into a space separated list
+func FormatScopes(scopes []Scope) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+
+	GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+
+	ListByUser(ctx context.Context, userID string) ([]*entities.APIKey, error)
+
+	// Delete removes a key of userID, returning ErrAPIKeyNotFound for keys of other users
+	Delete(ctx context.Context, id, userID string) error
+
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+// AuditFilter selects audit events. Zero fields do not filter.
+type AuditFilter struct {
+	ActorID  string
+	Action   string
+	TargetID string
+	Since    time.Time
+	Until    time.Time
+	// BeforeSeq pages backwards: only events older than BeforeSeq are returned
+	BeforeSeq int64
+	Limit     int
+}
+
+// AuditRepository is the append-only store of the audit log. Events are
+// never updated or deleted.
+type AuditRepository interface {
+	// Append seals event onto the end of its tenant's chain and stores it
+	Append(ctx context.Context, event *entities.AuditEvent) error
+
+	// Query returns the events matching filter, newest first
+	Query(ctx context.Context, filter AuditFilter) ([]*entities.AuditEvent, error)
+
+	// Scan returns up to limit events with a sequence number above afterSeq, oldest first
+	Scan(ctx context.Context, afterSeq int64, limit int) ([]*entities.AuditEvent, error)
+
+	// Tenants returns the tenants that have audit events in the database ctx operates on
+	Tenants(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+// ChangeLogRepository reads the change log, which the todo and list
+// repositories append to in the transaction of every change they make: todos
+// saved or deleted and list members joining or leaving. Clients of the change
+// stream resume from it where they left off, and offline clients sync from
+// it; the deleted entries are the tombstones they need to learn of deletions.
+type ChangeLogRepository interface {
+	// Since returns up to limit entries with a sequence number above afterSeq, oldest first
+	Since(ctx context.Context, afterSeq int64, limit int) ([]*entities.ChangeEntry, error)
+
+	// VisibleSince is Since limited to the entries the caller in ctx may see:
+	// the entries of their personal todos and of the lists they are a member
+	// of, and their own list memberships
+	VisibleSince(ctx context.Context, afterSeq int64, limit int) ([]*entities.ChangeEntry, error)
+
+	// History returns the entries of a todo the caller in ctx may see, oldest first
+	History(ctx context.Context, todoID string) ([]*entities.ChangeEntry, error)
+
+	// Batch returns the todo changes made by a batch request, oldest first
+	Batch(ctx context.Context, batchID string) ([]*entities.ChangeEntry, error)
+
+	// SeqRange returns the sequence numbers of the oldest and the newest entry
+	// kept, both zero when there is none
+	SeqRange(ctx context.Context) (oldest, newest int64, err error)
+
+	// DeleteBefore removes the entries made before cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+var (
+	ErrIdempotencyKeyExists   = errors.New("idempotency key already exists")
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores record as in flight. When an unexpired record with the same
+	// key and scope exists, it is returned together with ErrIdempotencyKeyExists;
+	// expired records and abandoned in-flight ones are replaced.
+	Reserve(ctx context.Context, record *entities.IdempotencyRecord) (*entities.IdempotencyRecord, error)
+
+	// Renew extends the lease of an in-flight key until leaseExpiresAt
+	Renew(ctx context.Context, key, scope string, leaseExpiresAt time.Time) error
+
+	Complete(ctx context.Context, key, scope string, statusCode int, contentType string, body []byte) error
+
+	Release(ctx context.Context, key, scope string) error
+
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"todo-backend/internal/domain/entities"
+)
+
+var (
+	ErrListNotFound       = errors.New("list not found")
+	ErrListMemberNotFound = errors.New("list member not found")
+	ErrListMemberExists   = errors.New("user is already a member of this list")
+	ErrListInviteNotFound = errors.New("invite not found")
+	ErrShareLinkNotFound  = errors.New("share link not found")
+)
+
+// ListRepository stores todo lists and their members. It does not check
+// permissions; the use cases do.
+type ListRepository interface {
+	// Create stores a list together with its owner's membership
+	Create(ctx context.Context, list *entities.TodoList) error
+
+	GetByID(ctx context.Context, id string) (*entities.TodoList, error)
+
+	// ListForUser returns every list userID is a member of, with their role
+	ListForUser(ctx context.Context, userID string) ([]*entities.ListMembership, error)
+
+	// Delete removes a list with its todos, members, invites and share links
+	Delete(ctx context.Context, id string) error
+
+	GetMember(ctx context.Context, listID, userID string) (*entities.ListMember, error)
+
+	ListMembers(ctx context.Context, listID string) ([]*entities.ListMember, error)
+
+	// AddMember returns ErrListMemberExists if the user already is a member
+	AddMember(ctx context.Context, member *entities.ListMember) error
+
+	UpdateMemberRole(ctx context.Context, listID, userID string, role entities.ListRole) error
+
+	RemoveMember(ctx context.Context, listID, userID string) error
+
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type ListInviteRepository interface {
+	Create(ctx context.Context, invite *entities.ListInvite) error
+
+	GetByHash(ctx context.Context, tokenHash string) (*entities.ListInvite, error)
+
+	ListByList(ctx context.Context, listID string) ([]*entities.ListInvite, error)
+
+	// Revoke marks an invite of listID as revoked
+	Revoke(ctx context.Context, listID, id string) error
+
+	// MarkAccepted atomically consumes a usable invite, returning
+	// ErrListInviteNotFound if it was already accepted or revoked
+	MarkAccepted(ctx context.Context, id, userID string) error
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *entities.Comment) error
+
+	ListByTodo(ctx context.Context, todoID string) ([]*entities.Comment, error)
+}
+
+// ShareLinkRepository stores public share links and their access log
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *entities.ShareLink) error
+
+	GetByHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error)
+
+	ListByList(ctx context.Context, listID string) ([]*entities.ShareLink, error)
+
+	// Revoke marks a link of listID as revoked
+	Revoke(ctx context.Context, listID, id string) error
+
+	// SharedTodos returns the todos of a list without regard to the caller,
+	// who is an anonymous visitor holding a share link
+	SharedTodos(ctx context.Context, listID string) ([]*entities.Todo, error)
+
+	RecordAccess(ctx context.Context, access *entities.ShareLinkAccess) error
+
+	// ListAccesses returns the latest limit accesses of a link of listID, newest first
+	ListAccesses(ctx context.Context, listID, linkID string, limit int) ([]*entities.ShareLinkAccess, error)
+}
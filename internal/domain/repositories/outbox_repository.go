@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"todo-backend/internal/domain/events"
+)
+
+// OutboxRepository stores the domain events raised by changes, in the
+// transaction of the change, until every subscriber has handled them. Each
+// subscriber's progress is kept as the sequence number of the last event it
+// handled.
+type OutboxRepository interface {
+	// Append stores event and sets its Seq, which is larger than that of any
+	// event appended before it
+	Append(ctx context.Context, event *events.ChangeEvent) error
+
+	// Since returns up to limit events with a sequence number above afterSeq, oldest first
+	Since(ctx context.Context, afterSeq int64, limit int) ([]events.ChangeEvent, error)
+
+	// Cursor returns the sequence number of the last event subscriber handled,
+	// zero when it has handled none
+	Cursor(ctx context.Context, subscriber string) (int64, error)
+
+	// Advance records that subscriber handled the events up to seq
+	Advance(ctx context.Context, subscriber string, seq int64) error
+
+	// DeleteHandledBefore removes the events that occurred before cutoff and
+	// that every one of subscribers has handled
+	DeleteHandledBefore(ctx context.Context, subscribers []string, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+// RateLimitStore holds the token buckets of rate limited clients. A bucket
+// is identified by tenant, policy and subject. Take must be atomic per
+// bucket so that concurrent requests, from any node sharing the store,
+// cannot spend the same token twice.
+type RateLimitStore interface {
+	// Take spends a token from the subject's bucket under policy, creating a full bucket first if needed
+	Take(ctx context.Context, tenant, subject string, policy entities.RateLimitPolicy, now time.Time) (entities.RateLimitDecision, error)
+
+	// List returns the buckets of a tenant, only those of subject unless it is empty
+	List(ctx context.Context, tenant, subject string) ([]*entities.RateLimitBucket, error)
+
+	// Reset drops the buckets of subject, only the one under policy unless it is empty
+	Reset(ctx context.Context, tenant, subject, policy string) (int64, error)
+
+	// DeleteFull drops buckets that have refilled completely by now
+	DeleteFull(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"todo-backend/internal/domain/entities"
+)
+
+// TenantUsageRepository measures the tenant carried by the context
+type TenantUsageRepository interface {
+	Usage(ctx context.Context) (*entities.TenantUsage, error)
+}
@@ -9,12 +9,35 @@ import (
 var (
 	ErrTodoNotFound = errors.New("todo not found")
 	ErrTodoExists   = errors.New("todo already exists")
+	// ErrVersionConflict is returned when a todo was modified since it was read
+	ErrVersionConflict = errors.New("todo was modified concurrently")
 )
 
+// TodoRepository stores todos. Every query is limited to the todos the
+// caller in the context can access: their personal todos and the todos of
+// the lists they are a member of.
 type TodoRepository interface {
 	Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error)
-	
+
 	GetAll(ctx context.Context) ([]*entities.Todo, error)
-	
+
+	GetByList(ctx context.Context, listID string) ([]*entities.Todo, error)
+
 	GetByID(ctx context.Context, id string) (*entities.Todo, error)
-} 
\ No newline at end of file
+
+	// Update saves todo if its stored version still equals todo.Version and
+	// returns the saved todo with its incremented version
+	Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error)
+
+	Delete(ctx context.Context, id string) error
+
+	// WithinTransaction runs fn in a single transaction. Repository calls made
+	// with the context passed to fn join that transaction, so a batch of
+	// operations either commits or rolls back as a whole.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// CommitBatch records in the change log that the changes of the batch in
+	// ctx have all been made, so that they are streamed as one. It does
+	// nothing when ctx carries no batch.
+	CommitBatch(ctx context.Context) error
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"todo-backend/internal/domain/entities"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrUserExists      = errors.New("user already exists")
+	ErrSessionNotFound = errors.New("session not found")
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenUsed is returned when a refresh token has already been rotated
+	ErrRefreshTokenUsed = errors.New("refresh token already used")
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *entities.User) (*entities.User, error)
+
+	GetByID(ctx context.Context, id string) (*entities.User, error)
+
+	GetByUsername(ctx context.Context, username string) (*entities.User, error)
+
+	UpdatePassword(ctx context.Context, user *entities.User) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *entities.Session) error
+
+	GetByID(ctx context.Context, id string) (*entities.Session, error)
+
+	Delete(ctx context.Context, id string) error
+
+	// DeleteByUser removes every session of userID except keepID
+	DeleteByUser(ctx context.Context, userID, keepID string) error
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+
+	// MarkUsed atomically flags an unused token as rotated, returning
+	// ErrRefreshTokenUsed if it was already used
+	MarkUsed(ctx context.Context, id string) error
+
+	DeleteBySession(ctx context.Context, sessionID string) error
+
+	// DeleteByUser removes the refresh tokens of every session of userID except keepSessionID
+	DeleteByUser(ctx context.Context, userID, keepSessionID string) error
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WebhookRepository stores webhooks and their deliveries, which form the
+// outbox the delivery worker drains
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.Webhook) error
+
+	// GetByID returns a webhook of ownerID, ErrWebhookNotFound for webhooks of other users
+	GetByID(ctx context.Context, id, ownerID string) (*entities.Webhook, error)
+
+	// GetForDelivery returns a webhook whoever owns it, for the delivery worker
+	GetForDelivery(ctx context.Context, id string) (*entities.Webhook, error)
+
+	ListByOwner(ctx context.Context, ownerID string) ([]*entities.Webhook, error)
+
+	// Delete removes a webhook of ownerID with its deliveries
+	Delete(ctx context.Context, id, ownerID string) error
+
+	// Subscribed returns the webhooks whose owners can access a todo owned by
+	// ownerID on listID (empty for personal todos) and that did not limit
+	// themselves to another list
+	Subscribed(ctx context.Context, ownerID, listID string) ([]*entities.Webhook, error)
+
+	// Enqueue stores deliveries; a webhook's delivery of an event already
+	// stored is skipped
+	Enqueue(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+
+	// ClaimDue returns up to limit pending deliveries due at now, oldest
+	// first, and postpones them to leaseUntil so that no other worker picks
+	// them up meanwhile
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error)
+
+	// RecordAttempt saves the delivery's new state together with the attempt
+	RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error
+
+	GetDelivery(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error)
+
+	// ListDeliveries returns the deliveries of a webhook, newest first,
+	// limited to status unless it is empty
+	ListDeliveries(ctx context.Context, webhookID string, status entities.WebhookDeliveryStatus, limit int) ([]*entities.WebhookDelivery, error)
+
+	// ListAttempts returns the attempts of a delivery, oldest first
+	ListAttempts(ctx context.Context, deliveryID string) ([]*entities.WebhookAttempt, error)
+
+	// Redeliver makes a delivery pending again, due at once with a fresh
+	// count of attempts
+	Redeliver(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error)
+
+	// DeleteFinishedBefore removes the delivered and dead deliveries, with
+	// their attempts, created before cutoff
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,89 @@
+// Package tenancy carries the workspace (tenant) a request operates in.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrTenantRequired is returned when tenant scoped data is accessed without a tenant
+	ErrTenantRequired = errors.New("tenant required")
+	// ErrUnknownTenant is returned for tenant IDs that are malformed or not configured
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrQuotaExceeded is returned when a change would exceed the tenant's quota
+	ErrQuotaExceeded = errors.New("tenant quota exceeded")
+)
+
+// tenantIDPattern keeps tenant IDs usable as subdomains and file names
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidateID checks that id is a well-formed tenant ID
+func ValidateID(id string) error {
+	if !tenantIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q is not a valid tenant ID", ErrUnknownTenant, id)
+	}
+	return nil
+}
+
+type tenantKey struct{}
+
+type fallbackTenantKey struct{}
+
+type allTenantsKey struct{}
+
+// WithTenant returns a copy of ctx operating in tenant id
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// WithFallbackTenant returns a copy of ctx operating in tenant id unless a
+// token names another tenant. It is used when a request names no tenant.
+func WithFallbackTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, fallbackTenantKey{}, id)
+}
+
+// FromContext returns the tenant carried by ctx, if any
+func FromContext(ctx context.Context) (string, bool) {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id, true
+	}
+	id, ok := ctx.Value(fallbackTenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Require returns the tenant carried by ctx or ErrTenantRequired
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrTenantRequired
+	}
+	return id, nil
+}
+
+// Bind returns a copy of ctx operating in tenant id, as named by a token.
+// It fails if the request explicitly named a different tenant, so that a
+// token issued for one tenant cannot be replayed against another.
+func Bind(ctx context.Context, id string) (context.Context, error) {
+	if id == "" {
+		return ctx, nil
+	}
+	if current, ok := ctx.Value(tenantKey{}).(string); ok && current != "" && current != id {
+		return nil, fmt.Errorf("%w: token was issued for tenant %s", ErrUnknownTenant, id)
+	}
+	return WithTenant(ctx, id), nil
+}
+
+// WithAllTenants returns a copy of ctx that may deliberately operate across
+// all tenants. It is meant for maintenance jobs, never for request handling.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+// AllTenants reports whether ctx was created by WithAllTenants
+func AllTenants(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsKey{}).(bool)
+	return all
+}
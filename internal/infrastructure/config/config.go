@@ -3,27 +3,138 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	// Environment selects the profile merged over config.yaml, e.g.
+	// config.production.yaml for "production"
+	Environment string            `mapstructure:"environment"`
+	Server      ServerConfig      `mapstructure:"server"`
+	HTTP        HTTPConfig        `mapstructure:"http"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Logging     LoggingConfig     `mapstructure:"logging"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Batch       BatchConfig       `mapstructure:"batch"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Sharing     SharingConfig     `mapstructure:"sharing"`
+	Tenancy     TenancyConfig     `mapstructure:"tenancy"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Tracing     TracingConfig     `mapstructure:"tracing"`
+	Stream      StreamConfig      `mapstructure:"stream"`
+	WebSocket   WebSocketConfig   `mapstructure:"websocket"`
+	Sync        SyncConfig        `mapstructure:"sync"`
+	Webhooks    WebhooksConfig    `mapstructure:"webhooks"`
+	Outbox      OutboxConfig      `mapstructure:"outbox"`
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
+	// Socket serves on this Unix domain socket instead of Host and Port
+	Socket string    `mapstructure:"socket"`
+	TLS    TLSConfig `mapstructure:"tls"`
+	// DrainDelay keeps serving after SIGTERM failed readiness, until load
+	// balancers stopped routing new requests here
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+	// ShutdownTimeout bounds how long requests in flight may take to complete
+	// on shutdown, and then how long background workers may take to stop
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
-// DatabaseConfig holds database configuration  
+// TLSConfig holds HTTPS serving configuration
+type TLSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CertFile and KeyFile are PEM encoded; they are reloaded when they change
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// MinVersion is "1.2" or "1.3"
+	MinVersion string `mapstructure:"min_version"`
+	// CipherSuites restricts the TLS 1.2 cipher suites by their standard
+	// names, e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256; empty uses Go's
+	// secure defaults. TLS 1.3 suites are not configurable.
+	CipherSuites []string `mapstructure:"cipher_suites"`
+	// ClientCAFile enables mutual TLS: client certificates must be issued by
+	// one of its PEM encoded CAs
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// ClientAuth is "verify" to verify client certificates when presented, or
+	// "require" to refuse connections without one
+	ClientAuth string `mapstructure:"client_auth"`
+	// ServiceClients authenticates machine-to-machine callers by the subject
+	// of their client certificate
+	ServiceClients []ServiceClientConfig `mapstructure:"service_clients"`
+}
+
+// ServiceClientConfig maps a client certificate subject to a service principal
+type ServiceClientConfig struct {
+	// Subject is the certificate's distinguished name, e.g. "CN=billing,O=Acme"
+	Subject string   `mapstructure:"subject"`
+	Name    string   `mapstructure:"name"`
+	Scopes  []string `mapstructure:"scopes"`
+	// Tenant pins the service to a tenant; empty uses the request's tenant
+	Tenant string `mapstructure:"tenant"`
+}
+
+// HTTPConfig holds CORS, security header and request size configuration
+type HTTPConfig struct {
+	CORS            CORSConfig            `mapstructure:"cors"`
+	SecurityHeaders SecurityHeadersConfig `mapstructure:"security_headers"`
+	// MaxBodyBytes bounds every request body; larger requests are refused
+	// while they are read
+	MaxBodyBytes int `mapstructure:"max_body_bytes"`
+	// MaxJSONBytes bounds JSON request bodies before they are parsed. It
+	// caps batch.max_payload_bytes.
+	MaxJSONBytes int `mapstructure:"max_json_bytes"`
+	// MaxHeaderBytes bounds the request line and headers
+	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
+	// ProxyHeader carries the client IP, e.g. X-Forwarded-For, behind a
+	// reverse proxy. It is only read on requests from TrustedProxies.
+	ProxyHeader string `mapstructure:"proxy_header"`
+	// TrustedProxies are the IPs and CIDR ranges of the reverse proxies
+	// whose ProxyHeader is believed
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// CORSConfig holds the browser origins allowed to call the API
+type CORSConfig struct {
+	// AllowOrigins accepts any origin when it is ["*"], which cannot be
+	// combined with AllowCredentials
+	AllowOrigins     []string      `mapstructure:"allow_origins"`
+	AllowMethods     []string      `mapstructure:"allow_methods"`
+	AllowHeaders     []string      `mapstructure:"allow_headers"`
+	ExposeHeaders    []string      `mapstructure:"expose_headers"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"`
+}
+
+// SecurityHeadersConfig holds the security headers sent with every response
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy string `mapstructure:"content_security_policy"`
+	// HSTSMaxAge is only sent over HTTPS; zero disables HSTS
+	HSTSMaxAge            time.Duration `mapstructure:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `mapstructure:"hsts_include_subdomains"`
+	HSTSPreload           bool          `mapstructure:"hsts_preload"`
+	FrameOptions          string        `mapstructure:"frame_options"`
+	ReferrerPolicy        string        `mapstructure:"referrer_policy"`
+}
+
+// DatabaseConfig holds database configuration
 type DatabaseConfig struct {
 	Type string `mapstructure:"type"`
 	File string `mapstructure:"file"`
+	// Mode is how todos are stored: "state" keeps their current state,
+	// "eventsourced" keeps every change in an append-only event stream and
+	// projects the current state from it
+	Mode string `mapstructure:"mode"`
+	// SnapshotEvery is how many events of a todo's stream are replayed at
+	// most before its state is snapshotted, in eventsourced mode
+	SnapshotEvery int `mapstructure:"snapshot_every"`
 	// PostgreSQL fields (kept for backwards compatibility)
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
@@ -35,10 +146,263 @@ type DatabaseConfig struct {
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level  string `mapstructure:"level"`
+	// Level is debug, info, warn or error; it can be changed at runtime
+	// through /api/admin/log-level
+	Level string `mapstructure:"level"`
+	// Format is json or text
 	Format string `mapstructure:"format"`
+	// SlowQueryThreshold logs slower database queries as warnings; zero
+	// disables it. Every query is logged at debug level.
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+}
+
+// IdempotencyConfig holds Idempotency-Key handling configuration
+type IdempotencyConfig struct {
+	TTL time.Duration `mapstructure:"ttl"`
+	// Lease is how long a key stays reserved for a request in flight unless
+	// the request renews it, so that a crash does not block retries for TTL
+	Lease time.Duration `mapstructure:"lease"`
+}
+
+// BatchConfig holds limits for POST /api/todos/batch
+type BatchConfig struct {
+	MaxOperations   int `mapstructure:"max_operations"`
+	MaxPayloadBytes int `mapstructure:"max_payload_bytes"`
+}
+
+// AuthConfig holds authentication configuration
+type AuthConfig struct {
+	// SessionTTL bounds the lifetime of a login, i.e. of its refresh token family
+	SessionTTL time.Duration `mapstructure:"session_ttl"`
+	Argon2     Argon2Config  `mapstructure:"argon2"`
+	JWT        JWTConfig     `mapstructure:"jwt"`
+	// AdminUsernames are granted the admin scope
+	AdminUsernames []string `mapstructure:"admin_usernames"`
+}
+
+// JWTConfig holds access token signing configuration
+type JWTConfig struct {
+	Issuer    string        `mapstructure:"issuer"`
+	Audience  string        `mapstructure:"audience"`
+	Algorithm string        `mapstructure:"algorithm"` // HS256 or EdDSA
+	AccessTTL time.Duration `mapstructure:"access_ttl"`
+	// ActiveKeyID selects the key new tokens are signed with; the other keys
+	// remain valid for verification so that they can be rotated out gracefully
+	ActiveKeyID string         `mapstructure:"active_key_id"`
+	Keys        []JWTKeyConfig `mapstructure:"keys"`
+	// RotationInterval rotates generated keys when no keys are configured
+	RotationInterval time.Duration `mapstructure:"rotation_interval"`
+}
+
+// JWTKeyConfig describes one signing key
+type JWTKeyConfig struct {
+	ID        string `mapstructure:"id"`
+	Algorithm string `mapstructure:"algorithm"` // defaults to JWTConfig.Algorithm
+	// Secret is the HS256 shared secret (at least 32 bytes)
+	Secret string `mapstructure:"secret"`
+	// PrivateKeyFile is a PKCS#8 PEM encoded Ed25519 private key
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+}
+
+// SharingConfig holds shared list configuration
+type SharingConfig struct {
+	// InviteTTL is the default and maximum lifetime of a list invite
+	InviteTTL time.Duration `mapstructure:"invite_ttl"`
+	// LinkRateLimit is how often one client IP may open share links per
+	// LinkRateWindow; zero disables the limit
+	LinkRateLimit  int           `mapstructure:"link_rate_limit"`
+	LinkRateWindow time.Duration `mapstructure:"link_rate_window"`
+}
+
+// RateLimitConfig holds per-client rate limiting configuration. Clients are
+// identified by API key, else by user, else by IP.
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store is "memory" for a single node or "database" to share buckets
+	// between nodes using the same database
+	Store string `mapstructure:"store"`
+	// Default limits every request no route policy matches
+	Default RateLimitPolicyConfig `mapstructure:"default"`
+	// Routes limit matching requests by their own policy; the first match wins
+	Routes []RateLimitRouteConfig `mapstructure:"routes"`
+}
+
+// RateLimitPolicyConfig is a token bucket refilling Requests tokens every
+// Period and holding at most Burst tokens (Requests when zero). Zero
+// Requests disables the limit.
+type RateLimitPolicyConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// RateLimitRouteConfig applies a named policy to the requests matching Method and Path
+type RateLimitRouteConfig struct {
+	Name string `mapstructure:"name"`
+	// Method matches every method when empty
+	Method string `mapstructure:"method"`
+	// Path is a route pattern such as /api/todos/:id or /api/lists/*
+	Path                  string `mapstructure:"path"`
+	RateLimitPolicyConfig `mapstructure:",squash"`
+}
+
+// TenancyConfig holds workspace (tenant) configuration
+type TenancyConfig struct {
+	// Enabled resolves the tenant of each request from the header, the
+	// subdomain or the access token. When disabled every request runs in
+	// DefaultTenant.
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultTenant is used when a request names no tenant; leave it empty
+	// to reject such requests
+	DefaultTenant string `mapstructure:"default_tenant"`
+	Header        string `mapstructure:"header"`
+	// BaseDomain resolves acme.<base_domain> to tenant acme
+	BaseDomain string `mapstructure:"base_domain"`
+	// Tenants restricts the accepted tenant IDs; empty accepts any valid ID
+	Tenants []string `mapstructure:"tenants"`
+	// DatabasePerTenant stores each tenant in its own SQLite file under DataDir
+	DatabasePerTenant bool                   `mapstructure:"database_per_tenant"`
+	DataDir           string                 `mapstructure:"data_dir"`
+	Quota             QuotaConfig            `mapstructure:"quota"`
+	TenantQuotas      map[string]QuotaConfig `mapstructure:"tenant_quotas"`
+}
+
+// QuotaConfig limits what a tenant may store. Zero means unlimited.
+type QuotaConfig struct {
+	MaxTodos        int64 `mapstructure:"max_todos"`
+	MaxStorageBytes int64 `mapstructure:"max_storage_bytes"`
 }
 
+// QuotaFor returns the quota of tenant, falling back to the default quota
+func (c TenancyConfig) QuotaFor(tenant string) QuotaConfig {
+	if quota, ok := c.TenantQuotas[tenant]; ok {
+		return quota
+	}
+	return c.Quota
+}
+
+// HealthConfig holds the checks behind the /livez, /readyz and /startupz probes
+type HealthConfig struct {
+	// Timeout bounds each check
+	Timeout time.Duration `mapstructure:"timeout"`
+	// CacheTTL reuses the results of database checks for this long, so that
+	// probes cannot overload the database
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// MinFreeDiskBytes fails readiness when the database's file system has
+	// less space available; zero disables the check
+	MinFreeDiskBytes uint64 `mapstructure:"min_free_disk_bytes"`
+}
+
+// MetricsConfig holds the Prometheus metrics configuration
+type MetricsConfig struct {
+	// Enabled serves /metrics
+	Enabled bool `mapstructure:"enabled"`
+	// Address is the host:port of the internal listener /metrics is served
+	// on. The metrics cover every tenant, so they are not served on the API port.
+	Address string `mapstructure:"address"`
+	// TodoCountInterval is how often todos are counted by state
+	TodoCountInterval time.Duration `mapstructure:"todo_count_interval"`
+}
+
+// TracingConfig holds the OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	ServiceName string `mapstructure:"service_name"`
+	// Exporter is otlp, stdout or file
+	Exporter string `mapstructure:"exporter"`
+	// OTLPEndpoint is the host:port of an OTLP/HTTP collector; unset uses
+	// OTEL_EXPORTER_OTLP_ENDPOINT, else localhost:4318
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
+	OTLPInsecure bool   `mapstructure:"otlp_insecure"`
+	// File receives spans as JSON with the file exporter
+	File string `mapstructure:"file"`
+	// SampleRatio is the share of new traces that are sampled. Requests
+	// carrying a traceparent keep their caller's decision.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// StreamConfig holds the configuration of the change stream on /api/todos/stream
+type StreamConfig struct {
+	// HeartbeatInterval is how often an idle stream sends a comment, which
+	// keeps proxies from closing it
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	// Buffer is how many events a client may fall behind before it catches
+	// up from the change log instead
+	Buffer int `mapstructure:"buffer"`
+	// Retention is how long changes are kept for clients resuming with
+	// Last-Event-ID. The change log is kept for the longer of this and the
+	// sync retention.
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// WebSocketConfig holds the configuration of the collaboration WebSocket on /api/ws
+type WebSocketConfig struct {
+	// MaxConnections bounds the open connections of this instance; 0 is unlimited
+	MaxConnections int `mapstructure:"max_connections"`
+	// MaxConnectionsPerUser bounds the open connections of each user; 0 is unlimited
+	MaxConnectionsPerUser int `mapstructure:"max_connections_per_user"`
+	// PingInterval is how often the server pings an idle connection
+	PingInterval time.Duration `mapstructure:"ping_interval"`
+	// PongTimeout is how long after a ping the client must have answered
+	// before the connection is considered dead
+	PongTimeout time.Duration `mapstructure:"pong_timeout"`
+	// MaxMessageBytes bounds the size of a message from a client
+	MaxMessageBytes int64 `mapstructure:"max_message_bytes"`
+}
+
+// SyncConfig holds the configuration of the offline sync on /api/sync
+type SyncConfig struct {
+	// Retention is how long the change log and its tombstones are kept for
+	// offline clients. Clients that have not synced for longer get a full snapshot.
+	Retention time.Duration `mapstructure:"retention"`
+	// MaxChanges bounds the log entries returned by one sync
+	MaxChanges int `mapstructure:"max_changes"`
+	// MaxMutations bounds the mutations a client may send with one sync
+	MaxMutations int `mapstructure:"max_mutations"`
+}
+
+// WebhooksConfig holds the configuration of outgoing webhooks
+type WebhooksConfig struct {
+	// Timeout bounds a single delivery attempt
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxAttempts is the number of attempts before a delivery moves to the dead-letter queue
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// BackoffBase is the wait after the first failed attempt, doubled after
+	// every further one up to BackoffMax
+	BackoffBase time.Duration `mapstructure:"backoff_base"`
+	BackoffMax  time.Duration `mapstructure:"backoff_max"`
+	// PollInterval is how often due deliveries are sent
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// Retention is how long delivered and dead deliveries are kept
+	Retention time.Duration `mapstructure:"retention"`
+	// AllowPrivateNetworks lets webhooks point at loopback and private
+	// addresses. Leave it off unless every user is trusted.
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+}
+
+// OutboxConfig holds the configuration of the domain event outbox
+type OutboxConfig struct {
+	// PollInterval is how often stored events are relayed to their subscribers
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// Retention is how long events every subscriber handled are kept
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// Argon2Config holds argon2id password hashing cost parameters
+type Argon2Config struct {
+	MemoryKiB   uint32 `mapstructure:"memory_kib"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+}
+
+var (
+	defaultCORSMethods       = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders       = []string{"Origin", "Content-Type", "Accept", "Authorization", "Idempotency-Key", "X-Tenant-ID", "X-Share-Password", "X-Request-ID", "Last-Event-ID"}
+	defaultCORSExposeHeaders = []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"}
+	// The API only serves JSON, so its responses may not load or embed anything
+	defaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+)
+
 // Load loads configuration from file and environment variables
 func Load() (*Config, error) {
 	// Set config file name and paths
@@ -49,14 +413,92 @@ func Load() (*Config, error) {
 	viper.AddConfigPath("../../configs")
 
 	// Set default values
+	viper.SetDefault("environment", "development")
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", 8081)
+	viper.SetDefault("server.drain_delay", "5s")
+	viper.SetDefault("server.shutdown_timeout", "20s")
+	viper.SetDefault("server.tls.min_version", "1.2")
+	viper.SetDefault("server.tls.client_auth", "verify")
+	viper.SetDefault("http.cors.allow_origins", []string{"*"})
+	viper.SetDefault("http.cors.allow_methods", defaultCORSMethods)
+	viper.SetDefault("http.cors.allow_headers", defaultCORSHeaders)
+	viper.SetDefault("http.cors.expose_headers", defaultCORSExposeHeaders)
+	viper.SetDefault("http.cors.max_age", "10m")
+	viper.SetDefault("http.security_headers.content_security_policy", defaultContentSecurityPolicy)
+	viper.SetDefault("http.security_headers.hsts_max_age", "8760h")
+	viper.SetDefault("http.security_headers.hsts_include_subdomains", true)
+	viper.SetDefault("http.max_body_bytes", 4<<20)
+	viper.SetDefault("http.max_json_bytes", 1<<20)
+	viper.SetDefault("http.max_header_bytes", 8<<10)
+	viper.SetDefault("http.proxy_header", "")
+	viper.SetDefault("http.trusted_proxies", []string{})
 	viper.SetDefault("database.type", "sqlite")
 	viper.SetDefault("database.file", "todo.db")
+	viper.SetDefault("database.mode", "state")
+	viper.SetDefault("database.snapshot_every", 50)
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
+	viper.SetDefault("logging.slow_query_threshold", "200ms")
+	viper.SetDefault("idempotency.ttl", "24h")
+	viper.SetDefault("idempotency.lease", "30s")
+	viper.SetDefault("batch.max_operations", 100)
+	viper.SetDefault("batch.max_payload_bytes", 1<<20)
+	viper.SetDefault("auth.session_ttl", "720h")
+	viper.SetDefault("auth.argon2.memory_kib", 64*1024)
+	viper.SetDefault("auth.argon2.iterations", 3)
+	viper.SetDefault("auth.argon2.parallelism", 4)
+	viper.SetDefault("auth.jwt.issuer", "todo-backend")
+	viper.SetDefault("auth.jwt.audience", "todo-api")
+	viper.SetDefault("auth.jwt.algorithm", "EdDSA")
+	viper.SetDefault("auth.jwt.access_ttl", "15m")
+	viper.SetDefault("auth.jwt.rotation_interval", "24h")
+	viper.SetDefault("sharing.invite_ttl", "168h")
+	viper.SetDefault("sharing.link_rate_limit", 30)
+	viper.SetDefault("sharing.link_rate_window", "1m")
+	viper.SetDefault("tenancy.enabled", false)
+	viper.SetDefault("tenancy.default_tenant", "default")
+	viper.SetDefault("tenancy.header", "X-Tenant-ID")
+	viper.SetDefault("tenancy.data_dir", "tenants")
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.store", "memory")
+	viper.SetDefault("rate_limit.default.requests", 300)
+	viper.SetDefault("rate_limit.default.period", "1m")
+	viper.SetDefault("rate_limit.default.burst", 60)
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.service_name", "todo-backend")
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.file", "traces.json")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("stream.heartbeat_interval", "15s")
+	viper.SetDefault("stream.buffer", 64)
+	viper.SetDefault("stream.retention", "24h")
+	viper.SetDefault("websocket.max_connections", 10000)
+	viper.SetDefault("websocket.max_connections_per_user", 10)
+	viper.SetDefault("websocket.ping_interval", "30s")
+	viper.SetDefault("websocket.pong_timeout", "10s")
+	viper.SetDefault("websocket.max_message_bytes", 64<<10)
+	viper.SetDefault("sync.retention", "720h")
+	viper.SetDefault("sync.max_changes", 500)
+	viper.SetDefault("sync.max_mutations", 100)
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("webhooks.max_attempts", 8)
+	viper.SetDefault("webhooks.backoff_base", "30s")
+	viper.SetDefault("webhooks.backoff_max", "1h")
+	viper.SetDefault("webhooks.poll_interval", "5s")
+	viper.SetDefault("webhooks.retention", "720h")
+	viper.SetDefault("webhooks.allow_private_networks", false)
+	viper.SetDefault("outbox.poll_interval", "1s")
+	viper.SetDefault("outbox.retention", "72h")
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.address", "127.0.0.1:9090")
+	viper.SetDefault("metrics.todo_count_interval", "30s")
+	viper.SetDefault("health.timeout", "2s")
+	viper.SetDefault("health.cache_ttl", "5s")
+	viper.SetDefault("health.min_free_disk_bytes", 100<<20)
 
 	// Enable environment variable reading
+	_ = viper.BindEnv("environment", "GO_ENV", "ENVIRONMENT")
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
@@ -68,6 +510,16 @@ func Load() (*Config, error) {
 		// Config file not found; rely on defaults and environment variables
 	}
 
+	// Merge the environment's profile, e.g. configs/config.production.yaml
+	if environment := viper.GetString("environment"); environment != "" {
+		viper.SetConfigName("config." + environment)
+		if err := viper.MergeInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				return nil, fmt.Errorf("failed to read %s config profile: %w", environment, err)
+			}
+		}
+	}
+
 	// Unmarshal config
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
@@ -77,6 +529,140 @@ func Load() (*Config, error) {
 	return &config, nil
 }
 
+// Default returns the configuration used when no config file can be loaded
+func Default() *Config {
+	return &Config{
+		Environment: "development",
+		Server: ServerConfig{
+			Host: "0.0.0.0",
+			Port: 8083,
+			TLS: TLSConfig{
+				MinVersion: "1.2",
+				ClientAuth: "verify",
+			},
+			DrainDelay:      5 * time.Second,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		HTTP: HTTPConfig{
+			CORS: CORSConfig{
+				AllowOrigins:  []string{"*"},
+				AllowMethods:  defaultCORSMethods,
+				AllowHeaders:  defaultCORSHeaders,
+				ExposeHeaders: defaultCORSExposeHeaders,
+				MaxAge:        10 * time.Minute,
+			},
+			SecurityHeaders: SecurityHeadersConfig{
+				ContentSecurityPolicy: defaultContentSecurityPolicy,
+				HSTSMaxAge:            365 * 24 * time.Hour,
+				HSTSIncludeSubdomains: true,
+			},
+			MaxBodyBytes:   4 << 20,
+			MaxJSONBytes:   1 << 20,
+			MaxHeaderBytes: 8 << 10,
+			TrustedProxies: []string{},
+		},
+		Database: DatabaseConfig{
+			Type:          "sqlite",
+			File:          "todo.db",
+			Mode:          "state",
+			SnapshotEvery: 50,
+		},
+		Logging: LoggingConfig{
+			Level:              "info",
+			Format:             "json",
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:   24 * time.Hour,
+			Lease: 30 * time.Second,
+		},
+		Batch: BatchConfig{
+			MaxOperations:   100,
+			MaxPayloadBytes: 1 << 20,
+		},
+		Auth: AuthConfig{
+			SessionTTL: 30 * 24 * time.Hour,
+			Argon2: Argon2Config{
+				MemoryKiB:   64 * 1024,
+				Iterations:  3,
+				Parallelism: 4,
+			},
+			JWT: JWTConfig{
+				Issuer:           "todo-backend",
+				Audience:         "todo-api",
+				Algorithm:        "EdDSA",
+				AccessTTL:        15 * time.Minute,
+				RotationInterval: 24 * time.Hour,
+			},
+		},
+		Sharing: SharingConfig{
+			InviteTTL:      7 * 24 * time.Hour,
+			LinkRateLimit:  30,
+			LinkRateWindow: time.Minute,
+		},
+		Tenancy: TenancyConfig{
+			DefaultTenant: "default",
+			Header:        "X-Tenant-ID",
+			DataDir:       "tenants",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Default: RateLimitPolicyConfig{Requests: 300, Period: time.Minute, Burst: 60},
+			Routes: []RateLimitRouteConfig{
+				{Name: "login", Method: "POST", Path: "/api/auth/login", RateLimitPolicyConfig: RateLimitPolicyConfig{Requests: 10, Period: time.Minute, Burst: 5}},
+				{Name: "register", Method: "POST", Path: "/api/auth/register", RateLimitPolicyConfig: RateLimitPolicyConfig{Requests: 5, Period: time.Hour, Burst: 5}},
+				{Name: "create-todo", Method: "POST", Path: "/api/todos", RateLimitPolicyConfig: RateLimitPolicyConfig{Requests: 60, Period: time.Minute, Burst: 20}},
+			},
+		},
+		Tracing: TracingConfig{
+			ServiceName: "todo-backend",
+			Exporter:    "otlp",
+			File:        "traces.json",
+			SampleRatio: 1,
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: 15 * time.Second,
+			Buffer:            64,
+			Retention:         24 * time.Hour,
+		},
+		WebSocket: WebSocketConfig{
+			MaxConnections:        10000,
+			MaxConnectionsPerUser: 10,
+			PingInterval:          30 * time.Second,
+			PongTimeout:           10 * time.Second,
+			MaxMessageBytes:       64 << 10,
+		},
+		Sync: SyncConfig{
+			Retention:    30 * 24 * time.Hour,
+			MaxChanges:   500,
+			MaxMutations: 100,
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:      10 * time.Second,
+			MaxAttempts:  8,
+			BackoffBase:  30 * time.Second,
+			BackoffMax:   time.Hour,
+			PollInterval: 5 * time.Second,
+			Retention:    30 * 24 * time.Hour,
+		},
+		Outbox: OutboxConfig{
+			PollInterval: time.Second,
+			Retention:    72 * time.Hour,
+		},
+		Metrics: MetricsConfig{
+			Enabled:           true,
+			Address:           "127.0.0.1:9090",
+			TodoCountInterval: 30 * time.Second,
+		},
+		Health: HealthConfig{
+			Timeout:          2 * time.Second,
+			CacheTTL:         5 * time.Second,
+			MinFreeDiskBytes: 100 << 20,
+		},
+	}
+}
+
 // GetServerAddress returns the full server address
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -96,4 +682,4 @@ func (c *Config) GetDatabaseDSN() string {
 		c.Database.Name,
 		c.Database.SSLMode,
 	)
-} 
\ No newline at end of file
+}
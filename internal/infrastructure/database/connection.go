@@ -1,38 +1,86 @@
 package database
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/logging"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // NewConnection creates a new database connection
 func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 	dsn := cfg.GetDatabaseDSN()
-	
-	// Configure GORM logger
-	logLevel := logger.Error
-	if cfg.Logging.Level == "info" {
-		logLevel = logger.Info
+
+	// Queries are logged through the application logger, at its current level
+	gormLogger := logging.NewGormLogger(slog.Default(), cfg.Logging.SlowQueryThreshold)
+	open := func(path string) (*gorm.DB, error) {
+		return gorm.Open(sqlite.Open(SQLiteDSN(path)), &gorm.Config{
+			Logger: gormLogger,
+		})
 	}
 
 	// Use SQLite as default database
-	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
-	})
+	db, err := open(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to SQLite database: %w", err)
 	}
 
-	// Auto-migrate the schema - use SQLiteTodoModel
-	if err := db.AutoMigrate(&SQLiteTodoModel{}); err != nil {
+	// Auto-migrate the schema
+	if err := Migrate(db); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	log.Printf("✅ SQLite database connected: %s", dsn)
+	// Scope every repository query to the request's tenant
+	var databases *TenantDatabases
+	if cfg.Tenancy.DatabasePerTenant {
+		databases = NewTenantDatabases(cfg.Tenancy.DataDir, open)
+		slog.Info("One SQLite database per tenant", "dir", cfg.Tenancy.DataDir)
+	}
+	if err := db.Use(NewTenantScoping(databases)); err != nil {
+		return nil, fmt.Errorf("failed to enable tenant scoping: %w", err)
+	}
+
+	slog.Info("SQLite database connected", "dsn", dsn)
 	return db, nil
-} 
\ No newline at end of file
+}
+
+// sqliteLockParams make transactions take the write lock when they begin and
+// wait up to 5s for it, so that requests and the workers writing beside them
+// queue for the lock rather than fail with "database is locked"
+const sqliteLockParams = "_txlock=immediate&_busy_timeout=5000"
+
+// SQLiteDSN returns the DSN NewConnection opens the SQLite database at path
+// with, which adds sqliteLockParams
+func SQLiteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + sqliteLockParams
+	}
+	return path + "?" + sqliteLockParams
+}
+
+// Close closes the database, and every tenant database opened through it
+func Close(db *gorm.DB) error {
+	var errs []error
+	if plugin, ok := db.Config.Plugins[tenantScopingPlugin].(*TenantScoping); ok && plugin.databases != nil {
+		if err := plugin.databases.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package database
+
+import "errors"
+
+// CheckFreeSpace is not supported on this platform
+func CheckFreeSpace(dir string, minFree uint64) error {
+	return errors.New("free space cannot be checked on this platform")
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package database
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// CheckFreeSpace checks that the file system holding dir has at least
+// minFree bytes available, so that SQLite can keep writing
+func CheckFreeSpace(dir string, minFree uint64) error {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return fmt.Errorf("failed to stat file system: %w", err)
+	}
+	free := uint64(stat.Bavail) * uint64(stat.Bsize)
+	if free < minFree {
+		return fmt.Errorf("%d bytes free, below %d", free, minFree)
+	}
+	return nil
+}
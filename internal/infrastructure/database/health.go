@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+	"todo-backend/internal/domain/tenancy"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteHealthProbeModel is written by the write probe, one row per node
+type SQLiteHealthProbeModel struct {
+	Node      string `gorm:"primaryKey;type:text"`
+	CheckedAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteHealthProbeModel
+func (SQLiteHealthProbeModel) TableName() string {
+	return "health_probes"
+}
+
+// Ping checks that the database answers
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// ProbeWrite checks that the database accepts writes, which a ping does not
+// tell for a read-only or full SQLite file
+func ProbeWrite(ctx context.Context, db *gorm.DB) error {
+	node, err := os.Hostname()
+	if err != nil {
+		node = "unknown"
+	}
+	probe := SQLiteHealthProbeModel{Node: node, CheckedAt: time.Now().UnixMilli()}
+	return db.WithContext(ctx).Save(&probe).Error
+}
+
+// CheckMigrations checks that every table and column of the schema exists
+func CheckMigrations(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(tenancy.WithAllTenants(ctx))
+	migrator := db.Migrator()
+	models := append(Models(), &SQLiteRateLimitBucketModel{}, &SQLiteHealthProbeModel{})
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		if !migrator.HasTable(model) {
+			return fmt.Errorf("table %s is missing", stmt.Schema.Table)
+		}
+		for _, column := range stmt.Schema.DBNames {
+			if !migrator.HasColumn(model, column) {
+				return fmt.Errorf("column %s.%s is missing", stmt.Schema.Table, column)
+			}
+		}
+	}
+	return nil
+}
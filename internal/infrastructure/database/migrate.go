@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"todo-backend/internal/domain/tenancy"
+
+	"gorm.io/gorm"
+)
+
+// Models lists every database model managed by the application
+func Models() []interface{} {
+	return []interface{}{
+		&SQLiteTodoModel{},
+		&SQLiteIdempotencyModel{},
+		&SQLiteUserModel{},
+		&SQLiteSessionModel{},
+		&SQLiteRefreshTokenModel{},
+		&SQLiteAPIKeyModel{},
+		&SQLiteListModel{},
+		&SQLiteListMemberModel{},
+		&SQLiteListInviteModel{},
+		&SQLiteCommentModel{},
+		&SQLiteShareLinkModel{},
+		&SQLiteShareLinkAccessModel{},
+		&SQLiteAuditEventModel{},
+		&SQLiteChangeModel{},
+		&SQLiteWebhookModel{},
+		&SQLiteWebhookDeliveryModel{},
+		&SQLiteWebhookAttemptModel{},
+		&SQLiteOutboxEventModel{},
+		&SQLiteTodoEventModel{},
+		&SQLiteTodoSnapshotModel{},
+	}
+}
+
+// Migrate auto-migrates the schema for all application models
+func Migrate(db *gorm.DB) error {
+	// Schema changes span all tenants
+	db = db.WithContext(tenancy.WithAllTenants(context.Background()))
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+
+	// Rate limit buckets are keyed by tenant rather than scoped to one, and
+	// health probes and outbox cursors belong to no tenant, so they are kept
+	// out of Models()
+	if err := db.AutoMigrate(&SQLiteRateLimitBucketModel{}, &SQLiteHealthProbeModel{}, &SQLiteOutboxCursorModel{}); err != nil {
+		return err
+	}
+
+	for _, trigger := range auditAppendOnlyTriggers {
+		if err := db.Exec(trigger).Error; err != nil {
+			return err
+		}
+	}
+
+	// The sync log was merged into the change log
+	if err := db.Migrator().DropTable("todo_sync_log"); err != nil {
+		return err
+	}
+
+	// Usernames used to be unique across the whole database; they are now unique per tenant
+	if db.Migrator().HasIndex(&SQLiteUserModel{}, "idx_users_username") {
+		return db.Migrator().DropIndex(&SQLiteUserModel{}, "idx_users_username")
+	}
+	return nil
+}
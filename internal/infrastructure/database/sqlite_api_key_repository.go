@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteAPIKeyRepository implements APIKeyRepository using SQLite
+type SQLiteAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteAPIKeyRepository creates a new SQLite API key repository
+func NewSQLiteAPIKeyRepository(db *gorm.DB) repositories.APIKeyRepository {
+	return &SQLiteAPIKeyRepository{
+		db: db,
+	}
+}
+
+// SQLiteAPIKeyModel represents the database model for API keys
+type SQLiteAPIKeyModel struct {
+	ID         string `gorm:"primaryKey;type:text"`
+	TenantID   string `gorm:"not null;default:'';index;type:text"`
+	UserID     string `gorm:"not null;index;type:text"`
+	Name       string `gorm:"not null;type:text"`
+	Prefix     string `gorm:"not null;uniqueIndex;type:text"`
+	KeyHash    string `gorm:"not null;type:text"`
+	Scopes     string `gorm:"not null;type:text"` // space separated
+	ExpiresAt  *int64
+	LastUsedAt *int64
+	CreatedAt  int64 `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteAPIKeyModel
+func (SQLiteAPIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+// ToEntity converts SQLiteAPIKeyModel to domain entity
+func (m *SQLiteAPIKeyModel) ToEntity() *entities.APIKey {
+	return &entities.APIKey{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		Scopes:     identity.ParseScopes(m.Scopes),
+		ExpiresAt:  optionalTimeFromUnix(m.ExpiresAt),
+		LastUsedAt: optionalTimeFromUnix(m.LastUsedAt),
+		CreatedAt:  timeFromUnix(m.CreatedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteAPIKeyModel
+func (m *SQLiteAPIKeyModel) FromEntity(key *entities.APIKey) {
+	m.ID = key.ID
+	m.UserID = key.UserID
+	m.Name = key.Name
+	m.Prefix = key.Prefix
+	m.KeyHash = key.KeyHash
+	m.Scopes = identity.FormatScopes(key.Scopes)
+	m.ExpiresAt = optionalTimeToUnix(key.ExpiresAt)
+	m.LastUsedAt = optionalTimeToUnix(key.LastUsedAt)
+	m.CreatedAt = key.CreatedAt.Unix()
+}
+
+// Create stores a new API key
+func (r *SQLiteAPIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	model := &SQLiteAPIKeyModel{}
+	model.FromEntity(key)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetByPrefix retrieves an API key by its public prefix
+func (r *SQLiteAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	var model SQLiteAPIKeyModel
+	if err := conn(ctx, r.db).Where("prefix = ?", prefix).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListByUser retrieves the API keys of a user, newest first
+func (r *SQLiteAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*entities.APIKey, error) {
+	var models []SQLiteAPIKeyModel
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]*entities.APIKey, len(models))
+	for i := range models {
+		keys[i] = models[i].ToEntity()
+	}
+	return keys, nil
+}
+
+// Delete removes an API key owned by userID
+func (r *SQLiteAPIKeyRepository) Delete(ctx context.Context, id, userID string) error {
+	result := conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&SQLiteAPIKeyModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete api key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed records when a key was last used
+func (r *SQLiteAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	if err := conn(ctx, r.db).Model(&SQLiteAPIKeyModel{}).Where("id = ?", id).Update("last_used_at", at.Unix()).Error; err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	return nil
+}
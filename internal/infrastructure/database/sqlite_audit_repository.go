@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteAuditRepository implements AuditRepository using SQLite
+type SQLiteAuditRepository struct {
+	db *gorm.DB
+	// mu serializes appends so that concurrent requests do not race for the
+	// same sequence number
+	mu sync.Mutex
+}
+
+// NewSQLiteAuditRepository creates a new SQLite audit repository
+func NewSQLiteAuditRepository(db *gorm.DB) repositories.AuditRepository {
+	return &SQLiteAuditRepository{
+		db: db,
+	}
+}
+
+// SQLiteAuditEventModel represents the database model for audit events
+type SQLiteAuditEventModel struct {
+	ID         string `gorm:"primaryKey;type:text"`
+	TenantID   string `gorm:"not null;default:'';uniqueIndex:idx_audit_events_tenant_seq,priority:1;type:text"`
+	Seq        int64  `gorm:"not null;uniqueIndex:idx_audit_events_tenant_seq,priority:2"`
+	ActorID    string `gorm:"not null;default:'';index;type:text"`
+	APIKeyID   string `gorm:"not null;default:'';type:text"`
+	Action     string `gorm:"not null;index;type:text"`
+	TargetID   string `gorm:"not null;default:'';index;type:text"`
+	BeforeHash string `gorm:"not null;default:'';type:text"`
+	AfterHash  string `gorm:"not null;default:'';type:text"`
+	Status     int    `gorm:"not null;default:0"`
+	RequestID  string `gorm:"not null;default:'';type:text"`
+	ClientIP   string `gorm:"not null;default:'';type:text"`
+	OccurredAt int64  `gorm:"not null;index"`
+	PrevHash   string `gorm:"not null;type:text"`
+	Hash       string `gorm:"not null;type:text"`
+}
+
+// TableName returns the table name for SQLiteAuditEventModel
+func (SQLiteAuditEventModel) TableName() string {
+	return "audit_events"
+}
+
+// ToEntity converts SQLiteAuditEventModel to domain entity
+func (m *SQLiteAuditEventModel) ToEntity() *entities.AuditEvent {
+	return &entities.AuditEvent{
+		ID:         m.ID,
+		Seq:        m.Seq,
+		TenantID:   m.TenantID,
+		ActorID:    m.ActorID,
+		APIKeyID:   m.APIKeyID,
+		Action:     m.Action,
+		TargetID:   m.TargetID,
+		BeforeHash: m.BeforeHash,
+		AfterHash:  m.AfterHash,
+		Status:     m.Status,
+		RequestID:  m.RequestID,
+		ClientIP:   m.ClientIP,
+		OccurredAt: timeFromUnix(m.OccurredAt),
+		PrevHash:   m.PrevHash,
+		Hash:       m.Hash,
+	}
+}
+
+// FromEntity converts domain entity to SQLiteAuditEventModel
+func (m *SQLiteAuditEventModel) FromEntity(event *entities.AuditEvent) {
+	m.ID = event.ID
+	m.Seq = event.Seq
+	m.TenantID = event.TenantID
+	m.ActorID = event.ActorID
+	m.APIKeyID = event.APIKeyID
+	m.Action = event.Action
+	m.TargetID = event.TargetID
+	m.BeforeHash = event.BeforeHash
+	m.AfterHash = event.AfterHash
+	m.Status = event.Status
+	m.RequestID = event.RequestID
+	m.ClientIP = event.ClientIP
+	m.OccurredAt = event.OccurredAt.Unix()
+	m.PrevHash = event.PrevHash
+	m.Hash = event.Hash
+}
+
+// auditAppendOnlyTriggers make the database itself refuse to change or
+// remove audit events
+var auditAppendOnlyTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS audit_events_no_update BEFORE UPDATE ON audit_events
+	BEGIN SELECT RAISE(ABORT, 'audit_events is append-only'); END`,
+	`CREATE TRIGGER IF NOT EXISTS audit_events_no_delete BEFORE DELETE ON audit_events
+	BEGIN SELECT RAISE(ABORT, 'audit_events is append-only'); END`,
+}
+
+// Append seals event onto the chain of its tenant and stores it
+func (r *SQLiteAuditRepository) Append(ctx context.Context, event *entities.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		var last SQLiteAuditEventModel
+		var prev *entities.AuditEvent
+		err := conn(ctx, r.db).Order("seq DESC").First(&last).Error
+		switch {
+		case err == nil:
+			prev = last.ToEntity()
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get last audit event: %w", err)
+		}
+
+		event.Seal(prev)
+		model := &SQLiteAuditEventModel{}
+		model.FromEntity(event)
+		if err := conn(ctx, r.db).Create(model).Error; err != nil {
+			return fmt.Errorf("failed to append audit event: %w", err)
+		}
+		return nil
+	})
+}
+
+// Query retrieves audit events matching filter, newest first
+func (r *SQLiteAuditRepository) Query(ctx context.Context, filter repositories.AuditFilter) ([]*entities.AuditEvent, error) {
+	query := conn(ctx, r.db)
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.Since.Unix())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("occurred_at <= ?", filter.Until.Unix())
+	}
+	if filter.BeforeSeq > 0 {
+		query = query.Where("seq < ?", filter.BeforeSeq)
+	}
+
+	var models []SQLiteAuditEventModel
+	if err := query.Order("seq DESC").Limit(filter.Limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	return auditEventsToEntities(models), nil
+}
+
+// Scan retrieves a page of the chain, oldest first
+func (r *SQLiteAuditRepository) Scan(ctx context.Context, afterSeq int64, limit int) ([]*entities.AuditEvent, error) {
+	var models []SQLiteAuditEventModel
+	if err := conn(ctx, r.db).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to scan audit events: %w", err)
+	}
+	return auditEventsToEntities(models), nil
+}
+
+// Tenants retrieves the distinct tenants of the stored events
+func (r *SQLiteAuditRepository) Tenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	if err := conn(ctx, r.db).Model(&SQLiteAuditEventModel{}).Distinct().Order("tenant_id").Pluck("tenant_id", &tenants).Error; err != nil {
+		return nil, fmt.Errorf("failed to list audit tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+func auditEventsToEntities(models []SQLiteAuditEventModel) []*entities.AuditEvent {
+	events := make([]*entities.AuditEvent, len(models))
+	for i := range models {
+		events[i] = models[i].ToEntity()
+	}
+	return events
+}
@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/correlation"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteChangeLogRepository implements ChangeLogRepository using SQLite
+type SQLiteChangeLogRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteChangeLogRepository creates a new SQLite change log repository
+func NewSQLiteChangeLogRepository(db *gorm.DB) repositories.ChangeLogRepository {
+	return &SQLiteChangeLogRepository{
+		db: db,
+	}
+}
+
+// SQLiteChangeModel represents the database model for change log entries
+type SQLiteChangeModel struct {
+	Seq      int64  `gorm:"primaryKey;autoIncrement"`
+	TenantID string `gorm:"not null;default:'';index;type:text"`
+	Kind     string `gorm:"not null;type:text"`
+	TodoID   string `gorm:"not null;default:'';index;type:text"`
+	ListID   string `gorm:"not null;default:'';type:text"`
+	OwnerID  string `gorm:"not null;default:'';type:text"`
+	UserID   string `gorm:"not null;default:'';type:text"`
+	// Todo is the todo as the change left it, as JSON; empty once deleted
+	Todo      []byte
+	Version   int64  `gorm:"not null;default:0"`
+	Fields    string `gorm:"not null;default:'';type:text"`
+	RequestID string `gorm:"not null;default:'';type:text"`
+	BatchID   string `gorm:"not null;default:'';index;type:text"`
+	ChangedAt int64  `gorm:"not null;index"`
+}
+
+// TableName returns the table name for SQLiteChangeModel
+func (SQLiteChangeModel) TableName() string {
+	return "todo_changes"
+}
+
+// ToEntity converts SQLiteChangeModel to domain entity
+func (m *SQLiteChangeModel) ToEntity() (*entities.ChangeEntry, error) {
+	entry := &entities.ChangeEntry{
+		Seq:       m.Seq,
+		TenantID:  m.TenantID,
+		Kind:      entities.ChangeKind(m.Kind),
+		TodoID:    m.TodoID,
+		ListID:    m.ListID,
+		OwnerID:   m.OwnerID,
+		UserID:    m.UserID,
+		Version:   m.Version,
+		RequestID: m.RequestID,
+		BatchID:   m.BatchID,
+		ChangedAt: timeFromUnix(m.ChangedAt),
+	}
+	if m.Fields != "" {
+		entry.Fields = strings.Split(m.Fields, ",")
+	}
+	if len(m.Todo) > 0 {
+		if err := json.Unmarshal(m.Todo, &entry.Todo); err != nil {
+			return nil, fmt.Errorf("failed to decode todo of change %d: %w", m.Seq, err)
+		}
+	}
+	return entry, nil
+}
+
+// FromEntity converts domain entity to SQLiteChangeModel
+func (m *SQLiteChangeModel) FromEntity(entry *entities.ChangeEntry) error {
+	m.Kind = string(entry.Kind)
+	m.TodoID = entry.TodoID
+	m.ListID = entry.ListID
+	m.OwnerID = entry.OwnerID
+	m.UserID = entry.UserID
+	m.Version = entry.Version
+	m.Fields = strings.Join(entry.Fields, ",")
+	m.RequestID = entry.RequestID
+	m.BatchID = entry.BatchID
+	m.ChangedAt = entry.ChangedAt.Unix()
+	m.Todo = nil
+	if entry.Todo != nil {
+		raw, err := json.Marshal(entry.Todo)
+		if err != nil {
+			return fmt.Errorf("failed to encode todo: %w", err)
+		}
+		m.Todo = raw
+	}
+	return nil
+}
+
+// Since retrieves a page of the log, oldest first
+func (r *SQLiteChangeLogRepository) Since(ctx context.Context, afterSeq int64, limit int) ([]*entities.ChangeEntry, error) {
+	var models []SQLiteChangeModel
+	if err := conn(ctx, r.db).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return toChangeEntries(models)
+}
+
+// VisibleSince retrieves a page of the entries the caller may see, oldest first
+func (r *SQLiteChangeLogRepository) VisibleSince(ctx context.Context, afterSeq int64, limit int) ([]*entities.ChangeEntry, error) {
+	scope, err := visibleChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []SQLiteChangeModel
+	if err := conn(ctx, r.db).Scopes(scope).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return toChangeEntries(models)
+}
+
+// History retrieves the entries of a todo the caller may see, oldest first
+func (r *SQLiteChangeLogRepository) History(ctx context.Context, todoID string) ([]*entities.ChangeEntry, error) {
+	scope, err := visibleChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []SQLiteChangeModel
+	if err := conn(ctx, r.db).Scopes(scope).Where("todo_id = ?", todoID).Order("seq ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return toChangeEntries(models)
+}
+
+// Batch retrieves the todo changes of a batch, oldest first
+func (r *SQLiteChangeLogRepository) Batch(ctx context.Context, batchID string) ([]*entities.ChangeEntry, error) {
+	var models []SQLiteChangeModel
+	if err := conn(ctx, r.db).Where("batch_id = ? AND kind IN ?", batchID, entities.TodoKinds).Order("seq ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return toChangeEntries(models)
+}
+
+// SeqRange retrieves the sequence numbers at both ends of the log
+func (r *SQLiteChangeLogRepository) SeqRange(ctx context.Context) (int64, int64, error) {
+	var bounds struct {
+		Oldest int64
+		Newest int64
+	}
+	err := conn(ctx, r.db).Model(&SQLiteChangeModel{}).
+		Select("COALESCE(MIN(seq), 0) AS oldest, COALESCE(MAX(seq), 0) AS newest").
+		Scan(&bounds).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return bounds.Oldest, bounds.Newest, nil
+}
+
+// DeleteBefore purges the entries made before cutoff
+func (r *SQLiteChangeLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("changed_at < ?", cutoff.Unix()).Delete(&SQLiteChangeModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge change log: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// appendChange records entry in the change log, attributed to the request and
+// batch in ctx. It is called by the repositories inside the transaction of
+// the change it records.
+func appendChange(ctx context.Context, db *gorm.DB, entry *entities.ChangeEntry) error {
+	if entry.ChangedAt.IsZero() {
+		entry.ChangedAt = time.Now()
+	}
+	if id, ok := correlation.FromContext(ctx); ok {
+		entry.RequestID = id
+	}
+	if id, ok := events.BatchFromContext(ctx); ok {
+		entry.BatchID = id
+	}
+
+	model := &SQLiteChangeModel{}
+	if err := model.FromEntity(entry); err != nil {
+		return err
+	}
+	if err := conn(ctx, db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to append to change log: %w", err)
+	}
+	entry.Seq = model.Seq
+	entry.TenantID = model.TenantID
+	return nil
+}
+
+// commitBatch records that the batch in ctx has made all its changes
+func commitBatch(ctx context.Context, db *gorm.DB) error {
+	if _, ok := events.BatchFromContext(ctx); !ok {
+		return nil
+	}
+	return appendChange(ctx, db, &entities.ChangeEntry{Kind: entities.ChangeBatchCommitted})
+}
+
+// todoChange describes a change of the given kind that set fields of todo
+// and left it as it is
+func todoChange(kind entities.ChangeKind, todo *entities.Todo, fields ...string) *entities.ChangeEntry {
+	entry := &entities.ChangeEntry{
+		Kind:    kind,
+		TodoID:  todo.ID,
+		ListID:  todo.ListID,
+		OwnerID: todo.OwnerID,
+		Version: todo.Version,
+		Fields:  fields,
+	}
+	if kind != entities.ChangeTodoDeleted {
+		entry.Todo = todo
+	}
+	return entry
+}
+
+// visibleChanges limits a change log query to the caller's own list
+// memberships and the entries of the todos they can access, like
+// accessibleTodos, including the deleted ones
+func visibleChanges(ctx context.Context) (func(*gorm.DB) *gorm.DB, error) {
+	user, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		memberships := db.Session(&gorm.Session{NewDB: true}).
+			Model(&SQLiteListMemberModel{}).Select("list_id").Where("user_id = ?", user)
+		return db.Where("((kind IN ? AND ((list_id = '' AND owner_id = ?) OR list_id IN (?))) OR (kind IN ? AND user_id = ?))",
+			entities.TodoKinds, user, memberships,
+			[]entities.ChangeKind{entities.ChangeListJoined, entities.ChangeListLeft}, user)
+	}, nil
+}
+
+func toChangeEntries(models []SQLiteChangeModel) ([]*entities.ChangeEntry, error) {
+	entries := make([]*entities.ChangeEntry, len(models))
+	for i := range models {
+		entry, err := models[i].ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteCommentRepository implements CommentRepository using SQLite
+type SQLiteCommentRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteCommentRepository creates a new SQLite comment repository
+func NewSQLiteCommentRepository(db *gorm.DB) repositories.CommentRepository {
+	return &SQLiteCommentRepository{
+		db: db,
+	}
+}
+
+// SQLiteCommentModel represents the database model for todo comments
+type SQLiteCommentModel struct {
+	ID        string `gorm:"primaryKey;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	TodoID    string `gorm:"not null;index;type:text"`
+	AuthorID  string `gorm:"not null;type:text"`
+	Body      string `gorm:"not null;type:text"`
+	CreatedAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteCommentModel
+func (SQLiteCommentModel) TableName() string {
+	return "todo_comments"
+}
+
+// ToEntity converts SQLiteCommentModel to domain entity
+func (m *SQLiteCommentModel) ToEntity() *entities.Comment {
+	return &entities.Comment{
+		ID:        m.ID,
+		TodoID:    m.TodoID,
+		AuthorID:  m.AuthorID,
+		Body:      m.Body,
+		CreatedAt: timeFromUnix(m.CreatedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteCommentModel
+func (m *SQLiteCommentModel) FromEntity(comment *entities.Comment) {
+	m.ID = comment.ID
+	m.TodoID = comment.TodoID
+	m.AuthorID = comment.AuthorID
+	m.Body = comment.Body
+	m.CreatedAt = comment.CreatedAt.Unix()
+}
+
+// Create stores a new comment
+func (r *SQLiteCommentRepository) Create(ctx context.Context, comment *entities.Comment) error {
+	model := &SQLiteCommentModel{}
+	model.FromEntity(comment)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+// ListByTodo retrieves the comments of a todo, oldest first
+func (r *SQLiteCommentRepository) ListByTodo(ctx context.Context, todoID string) ([]*entities.Comment, error) {
+	var models []SQLiteCommentModel
+	if err := conn(ctx, r.db).Where("todo_id = ?", todoID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	comments := make([]*entities.Comment, len(models))
+	for i := range models {
+		comments[i] = models[i].ToEntity()
+	}
+	return comments, nil
+}
@@ -0,0 +1,487 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultSnapshotEvery is used when no snapshot interval is configured
+const defaultSnapshotEvery = 50
+
+// todoImportedEvent starts or resets the stream of a todo with the state of
+// its row, for todos stored or changed in state mode
+const todoImportedEvent entities.TodoEventType = "todo.imported"
+
+// SQLiteEventSourcedTodoRepository implements TodoRepository on an
+// append-only stream of events per todo. A todo's state is replayed from its
+// latest snapshot and the events after it. The todos table is kept as a
+// projection of the streams for list queries and access checks, in the
+// transaction of every change.
+//
+// Rows written in state mode - stored before the database was switched to
+// this mode, or changed while it was back in state mode - are newer than
+// their stream. They are read as they are, and imported into their stream
+// the next time the todo changes.
+type SQLiteEventSourcedTodoRepository struct {
+	db            *gorm.DB
+	projection    *SQLiteTodoProjection
+	snapshotEvery int64
+}
+
+// NewSQLiteEventSourcedTodoRepository creates a new event-sourced todo
+// repository snapshotting the state of a todo every snapshotEvery events
+func NewSQLiteEventSourcedTodoRepository(db *gorm.DB, snapshotEvery int) repositories.TodoRepository {
+	if snapshotEvery <= 0 {
+		snapshotEvery = defaultSnapshotEvery
+	}
+	return &SQLiteEventSourcedTodoRepository{
+		db:            db,
+		projection:    NewSQLiteTodoProjection(db),
+		snapshotEvery: int64(snapshotEvery),
+	}
+}
+
+// SQLiteTodoEventModel represents the database model for the events of todo streams
+type SQLiteTodoEventModel struct {
+	Seq      int64  `gorm:"primaryKey;autoIncrement"`
+	TenantID string `gorm:"not null;default:'';index;type:text"`
+	TodoID   string `gorm:"not null;uniqueIndex:idx_todo_events_stream;type:text"`
+	// Position numbers the events of a stream from 1
+	Position int64 `gorm:"not null;uniqueIndex:idx_todo_events_stream"`
+	// Version is the version of the todo the event results in
+	Version    int64  `gorm:"not null"`
+	Type       string `gorm:"not null;type:text"`
+	Data       []byte `gorm:"not null"`
+	OccurredAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteTodoEventModel
+func (SQLiteTodoEventModel) TableName() string {
+	return "todo_events"
+}
+
+// SQLiteTodoSnapshotModel represents the database model for the latest
+// snapshot of a todo stream
+type SQLiteTodoSnapshotModel struct {
+	TodoID   string `gorm:"primaryKey;type:text"`
+	TenantID string `gorm:"not null;default:'';index;type:text"`
+	// Position is the position of the last event the snapshot covers
+	Position int64  `gorm:"not null"`
+	State    []byte `gorm:"not null"`
+	TakenAt  int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteTodoSnapshotModel
+func (SQLiteTodoSnapshotModel) TableName() string {
+	return "todo_snapshots"
+}
+
+// todoState is a todo as replayed from its stream
+type todoState struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Completed bool   `json:"completed"`
+	Version   int64  `json:"version"`
+	OwnerID   string `json:"ownerId"`
+	ListID    string `json:"listId"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+	Deleted   bool   `json:"deleted,omitempty"`
+	// Position is the position of the last event applied
+	Position int64 `json:"position"`
+}
+
+// todoEventData holds what an event of a todo stream changed
+type todoEventData struct {
+	Text      *string `json:"text,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+	// OwnerID, ListID and CreatedAt are set by the event creating the todo
+	OwnerID   string `json:"ownerId,omitempty"`
+	ListID    string `json:"listId,omitempty"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+}
+
+// apply moves the state past event
+func (s *todoState) apply(event *SQLiteTodoEventModel) error {
+	var data todoEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return fmt.Errorf("failed to decode event %d of todo %s: %w", event.Version, event.TodoID, err)
+	}
+
+	switch entities.TodoEventType(event.Type) {
+	case entities.TodoCreatedEvent, todoImportedEvent:
+		*s = todoState{ID: event.TodoID, OwnerID: data.OwnerID, ListID: data.ListID, CreatedAt: data.CreatedAt}
+	case entities.TodoDeletedEvent:
+		s.Deleted = true
+		s.Version = event.Version
+		s.Position = event.Position
+		return nil
+	}
+	if data.Text != nil {
+		s.Text = *data.Text
+	}
+	if data.Completed != nil {
+		s.Completed = *data.Completed
+	}
+	s.Version = event.Version
+	s.Position = event.Position
+	s.UpdatedAt = event.OccurredAt
+	return nil
+}
+
+// supersededBy reports whether row was written in state mode after the
+// stream's last event: the todo has no live stream, or its row has a later
+// version. State mode bumps the version of every change it stores.
+func (s *todoState) supersededBy(row *SQLiteTodoModel) bool {
+	return s.Deleted || row.Version > s.Version
+}
+
+// todoStateFromModel takes the state of a todo from its row
+func todoStateFromModel(model *SQLiteTodoModel) *todoState {
+	return &todoState{
+		ID:        model.ID,
+		Text:      model.Text,
+		Completed: model.Completed,
+		Version:   model.Version,
+		OwnerID:   model.OwnerID,
+		ListID:    model.ListID,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+// importTodoRow appends a todoImportedEvent making row the state of its
+// stream, which is at state
+func importTodoRow(ctx context.Context, db *gorm.DB, state *todoState, row *SQLiteTodoModel) error {
+	raw, err := json.Marshal(todoEventData{
+		Text:      &row.Text,
+		Completed: &row.Completed,
+		OwnerID:   row.OwnerID,
+		ListID:    row.ListID,
+		CreatedAt: row.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode todo event: %w", err)
+	}
+
+	model := &SQLiteTodoEventModel{
+		TodoID:     row.ID,
+		Position:   state.Position + 1,
+		Version:    row.Version,
+		Type:       string(todoImportedEvent),
+		Data:       raw,
+		OccurredAt: row.UpdatedAt,
+	}
+	if err := conn(ctx, db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to import todo %s: %w", row.ID, err)
+	}
+	return state.apply(model)
+}
+
+// endTodoStream appends a TodoDeletedEvent to the live stream of a todo,
+// if it has one, for todos deleted in state mode. It keeps rebuilding the
+// projection from bringing them back.
+func endTodoStream(ctx context.Context, db *gorm.DB, row *SQLiteTodoModel) error {
+	state, ok, err := loadTodoStream(ctx, db, row.ID)
+	if err != nil || !ok || state.Deleted {
+		return err
+	}
+
+	model := &SQLiteTodoEventModel{
+		TodoID:     row.ID,
+		Position:   state.Position + 1,
+		Version:    row.Version + 1,
+		Type:       string(entities.TodoDeletedEvent),
+		Data:       []byte("{}"),
+		OccurredAt: time.Now().Unix(),
+	}
+	if err := conn(ctx, db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to end the stream of todo %s: %w", row.ID, err)
+	}
+	return nil
+}
+
+// model returns the row projecting the state
+func (s *todoState) model() *SQLiteTodoModel {
+	return &SQLiteTodoModel{
+		ID:        s.ID,
+		Text:      s.Text,
+		Completed: s.Completed,
+		Version:   s.Version,
+		OwnerID:   s.OwnerID,
+		ListID:    s.ListID,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// loadTodoStream replays the stream of the todo id from its latest snapshot.
+// It reports false for todos without a stream.
+func loadTodoStream(ctx context.Context, db *gorm.DB, id string) (*todoState, bool, error) {
+	state := &todoState{}
+	found := false
+
+	var snapshots []SQLiteTodoSnapshotModel
+	if err := conn(ctx, db).Where("todo_id = ?", id).Limit(1).Find(&snapshots).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to get todo snapshot: %w", err)
+	}
+	if len(snapshots) > 0 {
+		if err := json.Unmarshal(snapshots[0].State, state); err != nil {
+			return nil, false, fmt.Errorf("failed to decode snapshot of todo %s: %w", id, err)
+		}
+		found = true
+	}
+
+	var models []SQLiteTodoEventModel
+	if err := conn(ctx, db).Where("todo_id = ? AND position > ?", id, state.Position).Order("position ASC").Find(&models).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to get todo events: %w", err)
+	}
+	for i := range models {
+		if err := state.apply(&models[i]); err != nil {
+			return nil, false, err
+		}
+		found = true
+	}
+	return state, found, nil
+}
+
+// saveTodoSnapshot replaces the snapshot of the todo's stream with state
+func saveTodoSnapshot(ctx context.Context, db *gorm.DB, state *todoState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode todo snapshot: %w", err)
+	}
+	model := &SQLiteTodoSnapshotModel{TodoID: state.ID, Position: state.Position, State: raw, TakenAt: time.Now().Unix()}
+	err = conn(ctx, db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "todo_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "state", "taken_at"}),
+	}).Create(model).Error
+	if err != nil {
+		return fmt.Errorf("failed to save todo snapshot: %w", err)
+	}
+	return nil
+}
+
+// Create starts the stream of a new todo owned by the caller
+func (r *SQLiteEventSourcedTodoRepository) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	owner, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &todoState{ID: todo.ID}
+	data := todoEventData{
+		Text:      &todo.Text,
+		Completed: &todo.Completed,
+		OwnerID:   owner,
+		ListID:    todo.ListID,
+		CreatedAt: todo.CreatedAt.Unix(),
+	}
+
+	err = withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		if err := r.append(ctx, state, entities.TodoCreatedEvent, data, todo.Version, todo.UpdatedAt); err != nil {
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				return repositories.ErrTodoExists
+			}
+			return err
+		}
+		if err := r.projection.insert(ctx, state); err != nil {
+			return err
+		}
+
+		created, err := state.model().ToEntity()
+		if err != nil {
+			return err
+		}
+		return appendChange(ctx, r.db, todoChange(entities.ChangeTodoCreated, created, entities.TodoFieldText, entities.TodoFieldCompleted))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state.model().ToEntity()
+}
+
+// GetAll retrieves all todos the caller can access from the projection
+func (r *SQLiteEventSourcedTodoRepository) GetAll(ctx context.Context) ([]*entities.Todo, error) {
+	return r.projection.find(ctx)
+}
+
+// GetByList retrieves the todos of a list the caller is a member of from the projection
+func (r *SQLiteEventSourcedTodoRepository) GetByList(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	return r.projection.find(ctx, "list_id = ?", listID)
+}
+
+// GetByID replays the stream of a todo the caller can access
+func (r *SQLiteEventSourcedTodoRepository) GetByID(ctx context.Context, id string) (*entities.Todo, error) {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.current(ctx, scope, id, false)
+	if err != nil {
+		return nil, err
+	}
+	return state.model().ToEntity()
+}
+
+// Update appends the changes made to todo to its stream if its stream is
+// still at todo.Version, and returns the todo at its incremented version
+func (r *SQLiteEventSourcedTodoRepository) Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *entities.Todo
+	err = withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		state, err := r.current(ctx, scope, todo.ID, true)
+		if err != nil {
+			return err
+		}
+		if state.Version != todo.Version {
+			return repositories.ErrVersionConflict
+		}
+
+		eventType := entities.TodoUpdatedEvent
+		var data todoEventData
+		var fields []string
+		if todo.Text != state.Text {
+			data.Text = &todo.Text
+			fields = append(fields, entities.TodoFieldText)
+		}
+		if todo.Completed != state.Completed {
+			data.Completed = &todo.Completed
+			fields = append(fields, entities.TodoFieldCompleted)
+			if todo.Completed {
+				eventType = entities.TodoCompletedEvent
+			}
+		}
+		if err := r.append(ctx, state, eventType, data, state.Version+1, todo.UpdatedAt); err != nil {
+			return err
+		}
+		if err := r.projection.update(ctx, state); err != nil {
+			return err
+		}
+
+		if updated, err = state.model().ToEntity(); err != nil {
+			return err
+		}
+		return appendChange(ctx, r.db, todoChange(entities.ChangeKind(eventType), updated, fields...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete ends the stream of a todo the caller can access and removes it from
+// the projection, together with its comments, leaving a tombstone in the
+// change log
+func (r *SQLiteEventSourcedTodoRepository) Delete(ctx context.Context, id string) error {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		state, err := r.current(ctx, scope, id, true)
+		if err != nil {
+			return err
+		}
+		if err := r.append(ctx, state, entities.TodoDeletedEvent, todoEventData{}, state.Version+1, time.Now()); err != nil {
+			return err
+		}
+		if err := r.projection.remove(ctx, state); err != nil {
+			return err
+		}
+
+		if err := conn(ctx, r.db).Where("todo_id = ?", id).Delete(&SQLiteCommentModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		deleted, err := state.model().ToEntity()
+		if err != nil {
+			return err
+		}
+		return appendChange(ctx, r.db, todoChange(entities.ChangeTodoDeleted, deleted))
+	})
+}
+
+// WithinTransaction runs fn in a database transaction shared by all repository calls made with its context
+func (r *SQLiteEventSourcedTodoRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, r.db, fn)
+}
+
+// CommitBatch marks the end of the batch in ctx in the change log
+func (r *SQLiteEventSourcedTodoRepository) CommitBatch(ctx context.Context) error {
+	return commitBatch(ctx, r.db)
+}
+
+// current replays the stream of a todo the caller can access. A row written
+// in state mode supersedes its stream: with adopt set it is imported into the
+// stream, so that a change can be appended to it, and otherwise it is
+// returned as it is.
+func (r *SQLiteEventSourcedTodoRepository) current(ctx context.Context, scope func(*gorm.DB) *gorm.DB, id string, adopt bool) (*todoState, error) {
+	row, err := r.projection.get(ctx, scope, id)
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := loadTodoStream(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if !state.supersededBy(row) {
+		return state, nil
+	}
+	if !adopt {
+		return todoStateFromModel(row), nil
+	}
+	if err := importTodoRow(ctx, r.db, state, row); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// append stores the next event of state's stream, resulting in version, and
+// applies it to state. It fails with ErrVersionConflict when the stream has
+// moved past state.
+func (r *SQLiteEventSourcedTodoRepository) append(ctx context.Context, state *todoState, eventType entities.TodoEventType, data todoEventData, version int64, at time.Time) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode todo event: %w", err)
+	}
+
+	model := &SQLiteTodoEventModel{
+		TodoID:     state.ID,
+		Position:   state.Position + 1,
+		Version:    version,
+		Type:       string(eventType),
+		Data:       raw,
+		OccurredAt: at.Unix(),
+	}
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to append todo event: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrVersionConflict
+	}
+
+	if err := state.apply(model); err != nil {
+		return err
+	}
+	if model.Position%r.snapshotEvery == 0 && !state.Deleted {
+		return saveTodoSnapshot(ctx, r.db, state)
+	}
+	return nil
+}
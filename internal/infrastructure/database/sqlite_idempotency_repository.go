@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteIdempotencyRepository implements IdempotencyRepository using SQLite
+type SQLiteIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteIdempotencyRepository creates a new SQLite idempotency repository
+func NewSQLiteIdempotencyRepository(db *gorm.DB) repositories.IdempotencyRepository {
+	return &SQLiteIdempotencyRepository{
+		db: db,
+	}
+}
+
+// SQLiteIdempotencyModel represents the database model for idempotency keys
+type SQLiteIdempotencyModel struct {
+	Key          string `gorm:"primaryKey;type:text"`
+	Scope        string `gorm:"primaryKey;type:text"`
+	TenantID     string `gorm:"not null;default:'';index;type:text"`
+	Fingerprint  string `gorm:"not null;type:text"`
+	Status       string `gorm:"not null;type:text"`
+	StatusCode   int
+	ContentType  string `gorm:"type:text"`
+	ResponseBody []byte
+	CreatedAt    int64 `gorm:"not null"`
+	ExpiresAt    int64 `gorm:"not null;index"`
+	// Keys reserved before leases existed have none, and are given up at once
+	LeaseExpiresAt int64 `gorm:"not null;default:0"`
+}
+
+// TableName returns the table name for SQLiteIdempotencyModel
+func (SQLiteIdempotencyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+// ToEntity converts SQLiteIdempotencyModel to domain entity
+func (m *SQLiteIdempotencyModel) ToEntity() *entities.IdempotencyRecord {
+	return &entities.IdempotencyRecord{
+		Key:          m.Key,
+		Scope:        m.Scope,
+		Fingerprint:  m.Fingerprint,
+		Status:       entities.IdempotencyStatus(m.Status),
+		StatusCode:   m.StatusCode,
+		ContentType:  m.ContentType,
+		ResponseBody: m.ResponseBody,
+		CreatedAt:    timeFromUnix(m.CreatedAt),
+		ExpiresAt:    timeFromUnix(m.ExpiresAt),
+		// A lease is kept to the millisecond, as it is much shorter than the TTL
+		LeaseExpiresAt: time.UnixMilli(m.LeaseExpiresAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteIdempotencyModel
+func (m *SQLiteIdempotencyModel) FromEntity(record *entities.IdempotencyRecord) {
+	m.Key = record.Key
+	m.Scope = record.Scope
+	m.Fingerprint = record.Fingerprint
+	m.Status = string(record.Status)
+	m.StatusCode = record.StatusCode
+	m.ContentType = record.ContentType
+	m.ResponseBody = record.ResponseBody
+	m.CreatedAt = record.CreatedAt.Unix()
+	m.ExpiresAt = record.ExpiresAt.Unix()
+	m.LeaseExpiresAt = record.LeaseExpiresAt.UnixMilli()
+}
+
+// Reserve inserts record unless an unexpired record with the same key and
+// scope exists whose request, if still in flight, holds its lease
+func (r *SQLiteIdempotencyRepository) Reserve(ctx context.Context, record *entities.IdempotencyRecord) (*entities.IdempotencyRecord, error) {
+	model := &SQLiteIdempotencyModel{}
+	model.FromEntity(record)
+
+	var existing *entities.IdempotencyRecord
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Expired and abandoned keys may be reused, so clear them before inserting
+		if err := tx.Where("key = ? AND scope = ?", record.Key, record.Scope).Scopes(reusableIdempotencyKeys(time.Now())).
+			Delete(&SQLiteIdempotencyModel{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		var current SQLiteIdempotencyModel
+		if err := tx.Where("key = ? AND scope = ?", record.Key, record.Scope).First(&current).Error; err != nil {
+			return err
+		}
+		existing = current.ToEntity()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if existing != nil {
+		return existing, repositories.ErrIdempotencyKeyExists
+	}
+
+	return model.ToEntity(), nil
+}
+
+// Complete stores the response for an in-flight key
+func (r *SQLiteIdempotencyRepository) Complete(ctx context.Context, key, scope string, statusCode int, contentType string, body []byte) error {
+	result := r.db.WithContext(ctx).Model(&SQLiteIdempotencyModel{}).
+		Where("key = ? AND scope = ?", key, scope).
+		Updates(map[string]interface{}{
+			"status":        string(entities.IdempotencyCompleted),
+			"status_code":   statusCode,
+			"content_type":  contentType,
+			"response_body": body,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// Renew extends the lease of an in-flight key
+func (r *SQLiteIdempotencyRepository) Renew(ctx context.Context, key, scope string, leaseExpiresAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&SQLiteIdempotencyModel{}).
+		Where("key = ? AND scope = ? AND status = ?", key, scope, string(entities.IdempotencyInFlight)).
+		Update("lease_expires_at", leaseExpiresAt.UnixMilli())
+	if result.Error != nil {
+		return fmt.Errorf("failed to renew idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// Release removes an in-flight key so that the request can be retried
+func (r *SQLiteIdempotencyRepository) Release(ctx context.Context, key, scope string) error {
+	if err := r.db.WithContext(ctx).
+		Where("key = ? AND scope = ? AND status = ?", key, scope, string(entities.IdempotencyInFlight)).
+		Delete(&SQLiteIdempotencyModel{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes all keys that expired, or were abandoned in flight, before now
+func (r *SQLiteIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Scopes(reusableIdempotencyKeys(now)).Delete(&SQLiteIdempotencyModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// reusableIdempotencyKeys selects the keys past their TTL at now, and the
+// in-flight keys whose lease ran out
+func reusableIdempotencyKeys(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("expires_at <= ? OR (status = ? AND lease_expires_at <= ?)",
+			now.Unix(), string(entities.IdempotencyInFlight), now.UnixMilli())
+	}
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteListInviteRepository implements ListInviteRepository using SQLite
+type SQLiteListInviteRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteListInviteRepository creates a new SQLite list invite repository
+func NewSQLiteListInviteRepository(db *gorm.DB) repositories.ListInviteRepository {
+	return &SQLiteListInviteRepository{
+		db: db,
+	}
+}
+
+// SQLiteListInviteModel represents the database model for list invites
+type SQLiteListInviteModel struct {
+	ID         string `gorm:"primaryKey;type:text"`
+	TenantID   string `gorm:"not null;default:'';index;type:text"`
+	ListID     string `gorm:"not null;index;type:text"`
+	TokenHash  string `gorm:"not null;uniqueIndex;type:text"`
+	Role       string `gorm:"not null;type:text"`
+	CreatedBy  string `gorm:"not null;type:text"`
+	CreatedAt  int64  `gorm:"not null"`
+	ExpiresAt  int64  `gorm:"not null"`
+	RevokedAt  *int64
+	AcceptedAt *int64
+	AcceptedBy string `gorm:"not null;default:'';type:text"`
+}
+
+// TableName returns the table name for SQLiteListInviteModel
+func (SQLiteListInviteModel) TableName() string {
+	return "list_invites"
+}
+
+// ToEntity converts SQLiteListInviteModel to domain entity
+func (m *SQLiteListInviteModel) ToEntity() *entities.ListInvite {
+	return &entities.ListInvite{
+		ID:         m.ID,
+		ListID:     m.ListID,
+		TokenHash:  m.TokenHash,
+		Role:       entities.ListRole(m.Role),
+		CreatedBy:  m.CreatedBy,
+		CreatedAt:  timeFromUnix(m.CreatedAt),
+		ExpiresAt:  timeFromUnix(m.ExpiresAt),
+		RevokedAt:  optionalTimeFromUnix(m.RevokedAt),
+		AcceptedAt: optionalTimeFromUnix(m.AcceptedAt),
+		AcceptedBy: m.AcceptedBy,
+	}
+}
+
+// FromEntity converts domain entity to SQLiteListInviteModel
+func (m *SQLiteListInviteModel) FromEntity(invite *entities.ListInvite) {
+	m.ID = invite.ID
+	m.ListID = invite.ListID
+	m.TokenHash = invite.TokenHash
+	m.Role = string(invite.Role)
+	m.CreatedBy = invite.CreatedBy
+	m.CreatedAt = invite.CreatedAt.Unix()
+	m.ExpiresAt = invite.ExpiresAt.Unix()
+	m.RevokedAt = optionalTimeToUnix(invite.RevokedAt)
+	m.AcceptedAt = optionalTimeToUnix(invite.AcceptedAt)
+	m.AcceptedBy = invite.AcceptedBy
+}
+
+// Create stores a new invite
+func (r *SQLiteListInviteRepository) Create(ctx context.Context, invite *entities.ListInvite) error {
+	model := &SQLiteListInviteModel{}
+	model.FromEntity(invite)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
+
+// GetByHash retrieves an invite by the hash of its token
+func (r *SQLiteListInviteRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.ListInvite, error) {
+	var model SQLiteListInviteModel
+	if err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrListInviteNotFound
+		}
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListByList retrieves the invites of a list, newest first
+func (r *SQLiteListInviteRepository) ListByList(ctx context.Context, listID string) ([]*entities.ListInvite, error) {
+	var models []SQLiteListInviteModel
+	if err := conn(ctx, r.db).Where("list_id = ?", listID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+
+	invites := make([]*entities.ListInvite, len(models))
+	for i := range models {
+		invites[i] = models[i].ToEntity()
+	}
+	return invites, nil
+}
+
+// Revoke marks an invite as revoked
+func (r *SQLiteListInviteRepository) Revoke(ctx context.Context, listID, id string) error {
+	result := conn(ctx, r.db).Model(&SQLiteListInviteModel{}).
+		Where("id = ? AND list_id = ? AND revoked_at IS NULL", id, listID).
+		Update("revoked_at", time.Now().Unix())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke invite: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrListInviteNotFound
+	}
+	return nil
+}
+
+// MarkAccepted consumes an invite that is neither accepted nor revoked
+func (r *SQLiteListInviteRepository) MarkAccepted(ctx context.Context, id, userID string) error {
+	result := conn(ctx, r.db).Model(&SQLiteListInviteModel{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"accepted_at": time.Now().Unix(),
+			"accepted_by": userID,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to accept invite: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrListInviteNotFound
+	}
+	return nil
+}
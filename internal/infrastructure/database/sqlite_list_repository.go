@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteListRepository implements ListRepository using SQLite
+type SQLiteListRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteListRepository creates a new SQLite list repository
+func NewSQLiteListRepository(db *gorm.DB) repositories.ListRepository {
+	return &SQLiteListRepository{
+		db: db,
+	}
+}
+
+// SQLiteListModel represents the database model for todo lists
+type SQLiteListModel struct {
+	ID        string `gorm:"primaryKey;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	Name      string `gorm:"not null;type:text"`
+	OwnerID   string `gorm:"not null;index;type:text"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	UpdatedAt int64  `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for SQLiteListModel
+func (SQLiteListModel) TableName() string {
+	return "lists"
+}
+
+// ToEntity converts SQLiteListModel to domain entity
+func (m *SQLiteListModel) ToEntity() *entities.TodoList {
+	return &entities.TodoList{
+		ID:        m.ID,
+		Name:      m.Name,
+		OwnerID:   m.OwnerID,
+		CreatedAt: timeFromUnix(m.CreatedAt),
+		UpdatedAt: timeFromUnix(m.UpdatedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteListModel
+func (m *SQLiteListModel) FromEntity(list *entities.TodoList) {
+	m.ID = list.ID
+	m.Name = list.Name
+	m.OwnerID = list.OwnerID
+	m.CreatedAt = list.CreatedAt.Unix()
+	m.UpdatedAt = list.UpdatedAt.Unix()
+}
+
+// SQLiteListMemberModel represents the database model for list memberships
+type SQLiteListMemberModel struct {
+	ListID    string `gorm:"primaryKey;type:text"`
+	UserID    string `gorm:"primaryKey;index;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	Role      string `gorm:"not null;type:text"`
+	CreatedAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteListMemberModel
+func (SQLiteListMemberModel) TableName() string {
+	return "list_members"
+}
+
+// ToEntity converts SQLiteListMemberModel to domain entity
+func (m *SQLiteListMemberModel) ToEntity() *entities.ListMember {
+	return &entities.ListMember{
+		ListID:    m.ListID,
+		UserID:    m.UserID,
+		Role:      entities.ListRole(m.Role),
+		CreatedAt: timeFromUnix(m.CreatedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteListMemberModel
+func (m *SQLiteListMemberModel) FromEntity(member *entities.ListMember) {
+	m.ListID = member.ListID
+	m.UserID = member.UserID
+	m.Role = string(member.Role)
+	m.CreatedAt = member.CreatedAt.Unix()
+}
+
+// Create stores a list and makes its owner a member
+func (r *SQLiteListRepository) Create(ctx context.Context, list *entities.TodoList) error {
+	model := &SQLiteListModel{}
+	model.FromEntity(list)
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		if err := conn(ctx, r.db).Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create list: %w", err)
+		}
+		return r.AddMember(ctx, entities.NewListMember(list.ID, list.OwnerID, entities.ListRoleOwner))
+	})
+}
+
+// GetByID retrieves a list by its ID
+func (r *SQLiteListRepository) GetByID(ctx context.Context, id string) (*entities.TodoList, error) {
+	var model SQLiteListModel
+	if err := conn(ctx, r.db).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListForUser retrieves the lists userID is a member of, oldest first
+func (r *SQLiteListRepository) ListForUser(ctx context.Context, userID string) ([]*entities.ListMembership, error) {
+	var rows []struct {
+		SQLiteListModel
+		Role string
+	}
+	err := conn(ctx, r.db).Table("lists").
+		Select("lists.*, list_members.role").
+		Joins("JOIN list_members ON list_members.list_id = lists.id").
+		Where("list_members.user_id = ?", userID).
+		Order("lists.created_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lists: %w", err)
+	}
+
+	memberships := make([]*entities.ListMembership, len(rows))
+	for i := range rows {
+		memberships[i] = &entities.ListMembership{
+			List: rows[i].SQLiteListModel.ToEntity(),
+			Role: entities.ListRole(rows[i].Role),
+		}
+	}
+	return memberships, nil
+}
+
+// Delete removes a list with its todos, their comments, members, invites and share links
+func (r *SQLiteListRepository) Delete(ctx context.Context, id string) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		// The members lose the list, and with it its todos
+		var members []string
+		if err := db.Model(&SQLiteListMemberModel{}).Where("list_id = ?", id).Pluck("user_id", &members).Error; err != nil {
+			return fmt.Errorf("failed to get list members: %w", err)
+		}
+		for _, userID := range members {
+			if err := appendChange(ctx, r.db, &entities.ChangeEntry{Kind: entities.ChangeListLeft, ListID: id, UserID: userID}); err != nil {
+				return err
+			}
+		}
+
+		todoIDs := db.Session(&gorm.Session{NewDB: true}).Model(&SQLiteTodoModel{}).Select("id").Where("list_id = ?", id)
+		if err := db.Where("todo_id IN (?)", todoIDs).Delete(&SQLiteCommentModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		// In eventsourced mode the todos go with their streams, so that
+		// rebuilding the projection does not bring them back
+		for _, model := range []interface{}{&SQLiteTodoEventModel{}, &SQLiteTodoSnapshotModel{}} {
+			if err := db.Where("todo_id IN (?)", todoIDs).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete todo streams: %w", err)
+			}
+		}
+		for _, model := range []interface{}{&SQLiteTodoModel{}, &SQLiteListMemberModel{}, &SQLiteListInviteModel{}, &SQLiteShareLinkModel{}, &SQLiteShareLinkAccessModel{}} {
+			if err := conn(ctx, r.db).Where("list_id = ?", id).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete list contents: %w", err)
+			}
+		}
+
+		result := conn(ctx, r.db).Where("id = ?", id).Delete(&SQLiteListModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete list: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrListNotFound
+		}
+		return nil
+	})
+}
+
+// GetMember retrieves the membership of userID in a list
+func (r *SQLiteListRepository) GetMember(ctx context.Context, listID, userID string) (*entities.ListMember, error) {
+	var model SQLiteListMemberModel
+	if err := conn(ctx, r.db).Where("list_id = ? AND user_id = ?", listID, userID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrListMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get list member: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListMembers retrieves the members of a list with their usernames
+func (r *SQLiteListRepository) ListMembers(ctx context.Context, listID string) ([]*entities.ListMember, error) {
+	var rows []struct {
+		SQLiteListMemberModel
+		Username string
+	}
+	err := conn(ctx, r.db).Table("list_members").
+		Select("list_members.*, users.username").
+		Joins("LEFT JOIN users ON users.id = list_members.user_id").
+		Where("list_members.list_id = ?", listID).
+		Order("list_members.created_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	members := make([]*entities.ListMember, len(rows))
+	for i := range rows {
+		members[i] = rows[i].SQLiteListMemberModel.ToEntity()
+		members[i].Username = rows[i].Username
+	}
+	return members, nil
+}
+
+// AddMember adds a user to a list
+func (r *SQLiteListRepository) AddMember(ctx context.Context, member *entities.ListMember) error {
+	model := &SQLiteListMemberModel{}
+	model.FromEntity(member)
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return fmt.Errorf("failed to add list member: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrListMemberExists
+		}
+		return appendChange(ctx, r.db, &entities.ChangeEntry{Kind: entities.ChangeListJoined, ListID: member.ListID, UserID: member.UserID})
+	})
+}
+
+// UpdateMemberRole changes the role of a member
+func (r *SQLiteListRepository) UpdateMemberRole(ctx context.Context, listID, userID string, role entities.ListRole) error {
+	result := conn(ctx, r.db).Model(&SQLiteListMemberModel{}).
+		Where("list_id = ? AND user_id = ?", listID, userID).
+		Update("role", string(role))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update list member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrListMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a list
+func (r *SQLiteListRepository) RemoveMember(ctx context.Context, listID, userID string) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		result := conn(ctx, r.db).Where("list_id = ? AND user_id = ?", listID, userID).Delete(&SQLiteListMemberModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to remove list member: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrListMemberNotFound
+		}
+		return appendChange(ctx, r.db, &entities.ChangeEntry{Kind: entities.ChangeListLeft, ListID: listID, UserID: userID})
+	})
+}
+
+// WithinTransaction runs fn in a database transaction shared by all repository calls made with its context
+func (r *SQLiteListRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, r.db, fn)
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteOutboxRepository implements OutboxRepository using SQLite
+type SQLiteOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteOutboxRepository creates a new SQLite outbox repository
+func NewSQLiteOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &SQLiteOutboxRepository{
+		db: db,
+	}
+}
+
+// SQLiteOutboxEventModel represents the database model for outbox events
+type SQLiteOutboxEventModel struct {
+	Seq       int64  `gorm:"primaryKey;autoIncrement"`
+	EventID   string `gorm:"not null;uniqueIndex;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	Type      string `gorm:"not null;type:text"`
+	Changes   []byte `gorm:"not null"`
+	RequestID string `gorm:"not null;default:'';type:text"`
+	// TraceParent is the W3C trace context of the request, if it was traced
+	TraceParent string `gorm:"not null;default:'';type:text"`
+	OccurredAt  int64  `gorm:"not null;index"`
+}
+
+// TableName returns the table name for SQLiteOutboxEventModel
+func (SQLiteOutboxEventModel) TableName() string {
+	return "outbox_events"
+}
+
+// ToEntity converts SQLiteOutboxEventModel to a change event
+func (m *SQLiteOutboxEventModel) ToEntity() (events.ChangeEvent, error) {
+	event := events.ChangeEvent{
+		ID:          m.EventID,
+		Seq:         m.Seq,
+		Type:        events.Type(m.Type),
+		TenantID:    m.TenantID,
+		RequestID:   m.RequestID,
+		TraceParent: m.TraceParent,
+		OccurredAt:  timeFromUnix(m.OccurredAt),
+	}
+	if err := json.Unmarshal(m.Changes, &event.Changes); err != nil {
+		return events.ChangeEvent{}, fmt.Errorf("failed to decode changes of outbox event %d: %w", m.Seq, err)
+	}
+	return event, nil
+}
+
+// FromEntity converts a change event to SQLiteOutboxEventModel
+func (m *SQLiteOutboxEventModel) FromEntity(event *events.ChangeEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+	m.EventID = event.ID
+	m.TenantID = event.TenantID
+	m.Type = string(event.Type)
+	m.Changes = changes
+	m.RequestID = event.RequestID
+	m.TraceParent = event.TraceParent
+	m.OccurredAt = event.OccurredAt.Unix()
+	return nil
+}
+
+// SQLiteOutboxCursorModel represents the database model for the position of
+// an outbox subscriber. Cursors follow the sequence numbers of the database
+// they are in rather than a tenant, so they carry no TenantID.
+type SQLiteOutboxCursorModel struct {
+	Subscriber string `gorm:"primaryKey;type:text"`
+	LastSeq    int64  `gorm:"not null;default:0"`
+	UpdatedAt  int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteOutboxCursorModel
+func (SQLiteOutboxCursorModel) TableName() string {
+	return "outbox_cursors"
+}
+
+// Append stores event at the end of the outbox
+func (r *SQLiteOutboxRepository) Append(ctx context.Context, event *events.ChangeEvent) error {
+	model := &SQLiteOutboxEventModel{}
+	if err := model.FromEntity(event); err != nil {
+		return err
+	}
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to append outbox event: %w", err)
+	}
+	event.Seq = model.Seq
+	return nil
+}
+
+// Since retrieves a page of the outbox, oldest first
+func (r *SQLiteOutboxRepository) Since(ctx context.Context, afterSeq int64, limit int) ([]events.ChangeEvent, error) {
+	var models []SQLiteOutboxEventModel
+	if err := conn(ctx, r.db).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	pending := make([]events.ChangeEvent, len(models))
+	for i := range models {
+		event, err := models[i].ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		pending[i] = event
+	}
+	return pending, nil
+}
+
+// Cursor retrieves the position of a subscriber
+func (r *SQLiteOutboxRepository) Cursor(ctx context.Context, subscriber string) (int64, error) {
+	var models []SQLiteOutboxCursorModel
+	if err := conn(ctx, r.db).Where("subscriber = ?", subscriber).Limit(1).Find(&models).Error; err != nil {
+		return 0, fmt.Errorf("failed to read outbox cursor: %w", err)
+	}
+	if len(models) == 0 {
+		return 0, nil
+	}
+	return models[0].LastSeq, nil
+}
+
+// Advance moves the position of a subscriber forward to seq
+func (r *SQLiteOutboxRepository) Advance(ctx context.Context, subscriber string, seq int64) error {
+	model := &SQLiteOutboxCursorModel{Subscriber: subscriber, LastSeq: seq, UpdatedAt: time.Now().Unix()}
+	err := conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subscriber"}},
+		// A cursor never moves back
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "last_seq"}, Value: gorm.Expr("MAX(last_seq, excluded.last_seq)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("excluded.updated_at")},
+		},
+	}).Create(model).Error
+	if err != nil {
+		return fmt.Errorf("failed to advance outbox cursor: %w", err)
+	}
+	return nil
+}
+
+// DeleteHandledBefore purges the events handled by every subscriber that occurred before cutoff
+func (r *SQLiteOutboxRepository) DeleteHandledBefore(ctx context.Context, subscribers []string, cutoff time.Time) (int64, error) {
+	query := conn(ctx, r.db).Where("occurred_at < ?", cutoff.Unix())
+	if len(subscribers) > 0 {
+		var bounds struct {
+			Cursors int64
+			Lowest  int64
+		}
+		err := conn(ctx, r.db).Model(&SQLiteOutboxCursorModel{}).
+			Select("COUNT(*) AS cursors, COALESCE(MIN(last_seq), 0) AS lowest").
+			Where("subscriber IN ?", subscribers).
+			Scan(&bounds).Error
+		if err != nil {
+			return 0, fmt.Errorf("failed to read outbox cursors: %w", err)
+		}
+		// A subscriber without a cursor has handled nothing yet
+		if bounds.Cursors < int64(len(subscribers)) {
+			return 0, nil
+		}
+		query = query.Where("seq <= ?", bounds.Lowest)
+	}
+
+	result := query.Delete(&SQLiteOutboxEventModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
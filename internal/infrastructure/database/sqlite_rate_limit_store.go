@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteRateLimitStore implements RateLimitStore using SQLite so that every
+// node using the same database shares its buckets. Buckets always live in
+// the main database, also with one database per tenant: the tenant is part
+// of a bucket's key rather than a scope applied by the tenancy plugin.
+type SQLiteRateLimitStore struct {
+	db *gorm.DB
+	// mu serializes takes within this node; other nodes are serialized by
+	// the transaction
+	mu sync.Mutex
+}
+
+// NewSQLiteRateLimitStore creates a new SQLite rate limit store
+func NewSQLiteRateLimitStore(db *gorm.DB) repositories.RateLimitStore {
+	return &SQLiteRateLimitStore{
+		db: db,
+	}
+}
+
+// SQLiteRateLimitBucketModel represents the database model for token buckets.
+// Times are Unix milliseconds because buckets refill continuously.
+type SQLiteRateLimitBucketModel struct {
+	Tenant    string  `gorm:"primaryKey;type:text"`
+	Subject   string  `gorm:"primaryKey;type:text"`
+	Policy    string  `gorm:"primaryKey;type:text"`
+	Tokens    float64 `gorm:"not null"`
+	UpdatedAt int64   `gorm:"not null;autoUpdateTime:false"`
+	FullAt    int64   `gorm:"not null;index"`
+}
+
+// TableName returns the table name for SQLiteRateLimitBucketModel
+func (SQLiteRateLimitBucketModel) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// ToEntity converts SQLiteRateLimitBucketModel to domain entity
+func (m *SQLiteRateLimitBucketModel) ToEntity() *entities.RateLimitBucket {
+	return &entities.RateLimitBucket{
+		Tenant:    m.Tenant,
+		Policy:    m.Policy,
+		Subject:   m.Subject,
+		Tokens:    m.Tokens,
+		UpdatedAt: time.UnixMilli(m.UpdatedAt),
+		FullAt:    time.UnixMilli(m.FullAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteRateLimitBucketModel
+func (m *SQLiteRateLimitBucketModel) FromEntity(bucket *entities.RateLimitBucket) {
+	m.Tenant = bucket.Tenant
+	m.Policy = bucket.Policy
+	m.Subject = bucket.Subject
+	m.Tokens = bucket.Tokens
+	m.UpdatedAt = bucket.UpdatedAt.UnixMilli()
+	m.FullAt = bucket.FullAt.UnixMilli()
+}
+
+// Take spends a token from the subject's bucket
+func (s *SQLiteRateLimitStore) Take(ctx context.Context, tenant, subject string, policy entities.RateLimitPolicy, now time.Time) (entities.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var decision entities.RateLimitDecision
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model SQLiteRateLimitBucketModel
+		result := tx.Where("tenant = ? AND subject = ? AND policy = ?", tenant, subject, policy.Name).Limit(1).Find(&model)
+		if result.Error != nil {
+			return result.Error
+		}
+		bucket := entities.NewRateLimitBucket(tenant, policy, subject, now)
+		if result.RowsAffected > 0 {
+			bucket = model.ToEntity()
+		}
+
+		decision = bucket.Take(policy, now)
+		model.FromEntity(bucket)
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&model).Error
+	})
+	if err != nil {
+		return entities.RateLimitDecision{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	return decision, nil
+}
+
+// List retrieves a tenant's buckets ordered by subject and policy
+func (s *SQLiteRateLimitStore) List(ctx context.Context, tenant, subject string) ([]*entities.RateLimitBucket, error) {
+	query := s.db.WithContext(ctx).Where("tenant = ?", tenant)
+	if subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+
+	var models []SQLiteRateLimitBucketModel
+	if err := query.Order("subject, policy").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list rate limit buckets: %w", err)
+	}
+	buckets := make([]*entities.RateLimitBucket, len(models))
+	for i := range models {
+		buckets[i] = models[i].ToEntity()
+	}
+	return buckets, nil
+}
+
+// Reset deletes the buckets of subject
+func (s *SQLiteRateLimitStore) Reset(ctx context.Context, tenant, subject, policy string) (int64, error) {
+	query := s.db.WithContext(ctx).Where("tenant = ? AND subject = ?", tenant, subject)
+	if policy != "" {
+		query = query.Where("policy = ?", policy)
+	}
+
+	result := query.Delete(&SQLiteRateLimitBucketModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to reset rate limit buckets: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteFull deletes buckets that have refilled completely
+func (s *SQLiteRateLimitStore) DeleteFull(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("full_at <= ?", now.UnixMilli()).Delete(&SQLiteRateLimitBucketModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete full rate limit buckets: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
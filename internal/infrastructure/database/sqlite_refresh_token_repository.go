@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteRefreshTokenRepository implements RefreshTokenRepository using SQLite
+type SQLiteRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteRefreshTokenRepository creates a new SQLite refresh token repository
+func NewSQLiteRefreshTokenRepository(db *gorm.DB) repositories.RefreshTokenRepository {
+	return &SQLiteRefreshTokenRepository{
+		db: db,
+	}
+}
+
+// SQLiteRefreshTokenModel represents the database model for refresh tokens
+type SQLiteRefreshTokenModel struct {
+	ID        string `gorm:"primaryKey;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	SessionID string `gorm:"not null;index;type:text"`
+	UserID    string `gorm:"not null;index;type:text"`
+	TokenHash string `gorm:"not null;uniqueIndex;type:text"`
+	CreatedAt int64  `gorm:"not null"`
+	ExpiresAt int64  `gorm:"not null"`
+	UsedAt    *int64
+}
+
+// TableName returns the table name for SQLiteRefreshTokenModel
+func (SQLiteRefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+// ToEntity converts SQLiteRefreshTokenModel to domain entity
+func (m *SQLiteRefreshTokenModel) ToEntity() *entities.RefreshToken {
+	token := &entities.RefreshToken{
+		ID:        m.ID,
+		SessionID: m.SessionID,
+		UserID:    m.UserID,
+		TokenHash: m.TokenHash,
+		CreatedAt: timeFromUnix(m.CreatedAt),
+		ExpiresAt: timeFromUnix(m.ExpiresAt),
+	}
+	if m.UsedAt != nil {
+		usedAt := timeFromUnix(*m.UsedAt)
+		token.UsedAt = &usedAt
+	}
+	return token
+}
+
+// FromEntity converts domain entity to SQLiteRefreshTokenModel
+func (m *SQLiteRefreshTokenModel) FromEntity(token *entities.RefreshToken) {
+	m.ID = token.ID
+	m.SessionID = token.SessionID
+	m.UserID = token.UserID
+	m.TokenHash = token.TokenHash
+	m.CreatedAt = token.CreatedAt.Unix()
+	m.ExpiresAt = token.ExpiresAt.Unix()
+	m.UsedAt = nil
+	if token.UsedAt != nil {
+		usedAt := token.UsedAt.Unix()
+		m.UsedAt = &usedAt
+	}
+}
+
+// Create stores a new refresh token
+func (r *SQLiteRefreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	model := &SQLiteRefreshTokenModel{}
+	model.FromEntity(token)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetByHash retrieves a refresh token by its hash
+func (r *SQLiteRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	var model SQLiteRefreshTokenModel
+	if err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// MarkUsed flags an unused refresh token as rotated
+func (r *SQLiteRefreshTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Model(&SQLiteRefreshTokenModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark refresh token used: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+// DeleteBySession removes every refresh token of a session
+func (r *SQLiteRefreshTokenRepository) DeleteBySession(ctx context.Context, sessionID string) error {
+	if err := conn(ctx, r.db).Where("session_id = ?", sessionID).Delete(&SQLiteRefreshTokenModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteByUser removes the refresh tokens of every session of userID except keepSessionID
+func (r *SQLiteRefreshTokenRepository) DeleteByUser(ctx context.Context, userID, keepSessionID string) error {
+	if err := conn(ctx, r.db).Where("user_id = ? AND session_id <> ?", userID, keepSessionID).Delete(&SQLiteRefreshTokenModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteSessionRepository implements SessionRepository using SQLite
+type SQLiteSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteSessionRepository creates a new SQLite session repository
+func NewSQLiteSessionRepository(db *gorm.DB) repositories.SessionRepository {
+	return &SQLiteSessionRepository{
+		db: db,
+	}
+}
+
+// SQLiteSessionModel represents the database model for sessions
+type SQLiteSessionModel struct {
+	ID        string `gorm:"primaryKey;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
+	UserID    string `gorm:"not null;index;type:text"`
+	CreatedAt int64  `gorm:"not null"`
+	ExpiresAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteSessionModel
+func (SQLiteSessionModel) TableName() string {
+	return "sessions"
+}
+
+// ToEntity converts SQLiteSessionModel to domain entity
+func (m *SQLiteSessionModel) ToEntity() *entities.Session {
+	return &entities.Session{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		CreatedAt: timeFromUnix(m.CreatedAt),
+		ExpiresAt: timeFromUnix(m.ExpiresAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteSessionModel
+func (m *SQLiteSessionModel) FromEntity(session *entities.Session) {
+	m.ID = session.ID
+	m.UserID = session.UserID
+	m.CreatedAt = session.CreatedAt.Unix()
+	m.ExpiresAt = session.ExpiresAt.Unix()
+}
+
+// Create stores a new session
+func (r *SQLiteSessionRepository) Create(ctx context.Context, session *entities.Session) error {
+	model := &SQLiteSessionModel{}
+	model.FromEntity(session)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a session by its ID
+func (r *SQLiteSessionRepository) GetByID(ctx context.Context, id string) (*entities.Session, error) {
+	var model SQLiteSessionModel
+	if err := conn(ctx, r.db).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// Delete removes a session by its ID
+func (r *SQLiteSessionRepository) Delete(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&SQLiteSessionModel{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteByUser removes every session of userID except keepID
+func (r *SQLiteSessionRepository) DeleteByUser(ctx context.Context, userID, keepID string) error {
+	if err := conn(ctx, r.db).Where("user_id = ? AND id <> ?", userID, keepID).Delete(&SQLiteSessionModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteShareLinkRepository implements ShareLinkRepository using SQLite
+type SQLiteShareLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteShareLinkRepository creates a new SQLite share link repository
+func NewSQLiteShareLinkRepository(db *gorm.DB) repositories.ShareLinkRepository {
+	return &SQLiteShareLinkRepository{
+		db: db,
+	}
+}
+
+// SQLiteShareLinkModel represents the database model for public share links
+type SQLiteShareLinkModel struct {
+	ID            string `gorm:"primaryKey;type:text"`
+	TenantID      string `gorm:"not null;default:'';index;type:text"`
+	ListID        string `gorm:"not null;index;type:text"`
+	TokenHash     string `gorm:"not null;uniqueIndex;type:text"`
+	PasswordHash  string `gorm:"not null;default:'';type:text"`
+	HideCompleted bool   `gorm:"not null;default:false"`
+	CreatedBy     string `gorm:"not null;type:text"`
+	CreatedAt     int64  `gorm:"not null"`
+	ExpiresAt     *int64
+	RevokedAt     *int64
+}
+
+// TableName returns the table name for SQLiteShareLinkModel
+func (SQLiteShareLinkModel) TableName() string {
+	return "share_links"
+}
+
+// ToEntity converts SQLiteShareLinkModel to domain entity
+func (m *SQLiteShareLinkModel) ToEntity() *entities.ShareLink {
+	return &entities.ShareLink{
+		ID:            m.ID,
+		ListID:        m.ListID,
+		TokenHash:     m.TokenHash,
+		PasswordHash:  m.PasswordHash,
+		HideCompleted: m.HideCompleted,
+		CreatedBy:     m.CreatedBy,
+		CreatedAt:     timeFromUnix(m.CreatedAt),
+		ExpiresAt:     optionalTimeFromUnix(m.ExpiresAt),
+		RevokedAt:     optionalTimeFromUnix(m.RevokedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteShareLinkModel
+func (m *SQLiteShareLinkModel) FromEntity(link *entities.ShareLink) {
+	m.ID = link.ID
+	m.ListID = link.ListID
+	m.TokenHash = link.TokenHash
+	m.PasswordHash = link.PasswordHash
+	m.HideCompleted = link.HideCompleted
+	m.CreatedBy = link.CreatedBy
+	m.CreatedAt = link.CreatedAt.Unix()
+	m.ExpiresAt = optionalTimeToUnix(link.ExpiresAt)
+	m.RevokedAt = optionalTimeToUnix(link.RevokedAt)
+}
+
+// SQLiteShareLinkAccessModel represents the database model for the share link access log
+type SQLiteShareLinkAccessModel struct {
+	ID         string `gorm:"primaryKey;type:text"`
+	TenantID   string `gorm:"not null;default:'';index;type:text"`
+	LinkID     string `gorm:"not null;index;type:text"`
+	ListID     string `gorm:"not null;index;type:text"`
+	Outcome    string `gorm:"not null;type:text"`
+	IPAddress  string `gorm:"not null;default:'';type:text"`
+	UserAgent  string `gorm:"not null;default:'';type:text"`
+	AccessedAt int64  `gorm:"not null;index"`
+}
+
+// TableName returns the table name for SQLiteShareLinkAccessModel
+func (SQLiteShareLinkAccessModel) TableName() string {
+	return "share_link_accesses"
+}
+
+// ToEntity converts SQLiteShareLinkAccessModel to domain entity
+func (m *SQLiteShareLinkAccessModel) ToEntity() *entities.ShareLinkAccess {
+	return &entities.ShareLinkAccess{
+		ID:         m.ID,
+		LinkID:     m.LinkID,
+		ListID:     m.ListID,
+		Outcome:    entities.ShareLinkOutcome(m.Outcome),
+		IPAddress:  m.IPAddress,
+		UserAgent:  m.UserAgent,
+		AccessedAt: timeFromUnix(m.AccessedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteShareLinkAccessModel
+func (m *SQLiteShareLinkAccessModel) FromEntity(access *entities.ShareLinkAccess) {
+	m.ID = access.ID
+	m.LinkID = access.LinkID
+	m.ListID = access.ListID
+	m.Outcome = string(access.Outcome)
+	m.IPAddress = access.IPAddress
+	m.UserAgent = access.UserAgent
+	m.AccessedAt = access.AccessedAt.Unix()
+}
+
+// Create stores a new share link
+func (r *SQLiteShareLinkRepository) Create(ctx context.Context, link *entities.ShareLink) error {
+	model := &SQLiteShareLinkModel{}
+	model.FromEntity(link)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+// GetByHash retrieves a share link by the hash of its token
+func (r *SQLiteShareLinkRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error) {
+	var model SQLiteShareLinkModel
+	if err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrShareLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListByList retrieves the share links of a list, newest first
+func (r *SQLiteShareLinkRepository) ListByList(ctx context.Context, listID string) ([]*entities.ShareLink, error) {
+	var models []SQLiteShareLinkModel
+	if err := conn(ctx, r.db).Where("list_id = ?", listID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+
+	links := make([]*entities.ShareLink, len(models))
+	for i := range models {
+		links[i] = models[i].ToEntity()
+	}
+	return links, nil
+}
+
+// Revoke marks a share link as revoked
+func (r *SQLiteShareLinkRepository) Revoke(ctx context.Context, listID, id string) error {
+	result := conn(ctx, r.db).Model(&SQLiteShareLinkModel{}).
+		Where("id = ? AND list_id = ? AND revoked_at IS NULL", id, listID).
+		Update("revoked_at", time.Now().Unix())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke share link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// SharedTodos retrieves the todos of a list, newest first
+func (r *SQLiteShareLinkRepository) SharedTodos(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	var models []SQLiteTodoModel
+	if err := conn(ctx, r.db).Where("list_id = ?", listID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get shared todos: %w", err)
+	}
+
+	todos := make([]*entities.Todo, len(models))
+	for i := range models {
+		todo, err := models[i].ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert todo model: %w", err)
+		}
+		todos[i] = todo
+	}
+	return todos, nil
+}
+
+// RecordAccess appends an entry to the access log of a share link
+func (r *SQLiteShareLinkRepository) RecordAccess(ctx context.Context, access *entities.ShareLinkAccess) error {
+	model := &SQLiteShareLinkAccessModel{}
+	model.FromEntity(access)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to record share link access: %w", err)
+	}
+	return nil
+}
+
+// ListAccesses retrieves the latest accesses of a share link, newest first
+func (r *SQLiteShareLinkRepository) ListAccesses(ctx context.Context, listID, linkID string, limit int) ([]*entities.ShareLinkAccess, error) {
+	var models []SQLiteShareLinkAccessModel
+	err := conn(ctx, r.db).
+		Where("list_id = ? AND link_id = ?", listID, linkID).
+		Order("accessed_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share link accesses: %w", err)
+	}
+
+	accesses := make([]*entities.ShareLinkAccess, len(models))
+	for i := range models {
+		accesses[i] = models[i].ToEntity()
+	}
+	return accesses, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteTenantUsageRepository implements TenantUsageRepository using SQLite
+type SQLiteTenantUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteTenantUsageRepository creates a new SQLite tenant usage repository
+func NewSQLiteTenantUsageRepository(db *gorm.DB) repositories.TenantUsageRepository {
+	return &SQLiteTenantUsageRepository{
+		db: db,
+	}
+}
+
+// Usage counts the todos of the tenant in ctx and the bytes of text they and their comments hold
+func (r *SQLiteTenantUsageRepository) Usage(ctx context.Context) (*entities.TenantUsage, error) {
+	var todos struct {
+		Count int64
+		Bytes int64
+	}
+	err := conn(ctx, r.db).Model(&SQLiteTodoModel{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(LENGTH(CAST(text AS BLOB))), 0) AS bytes").
+		Scan(&todos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure todos: %w", err)
+	}
+
+	var commentBytes int64
+	err = conn(ctx, r.db).Model(&SQLiteCommentModel{}).
+		Select("COALESCE(SUM(LENGTH(CAST(body AS BLOB))), 0)").
+		Scan(&commentBytes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure comments: %w", err)
+	}
+
+	return &entities.TenantUsage{
+		Todos:        todos.Count,
+		StorageBytes: todos.Bytes + commentBytes,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteTodoProjection keeps the todos table, the read model of the todo
+// streams, in step with the streams
+type SQLiteTodoProjection struct {
+	db *gorm.DB
+}
+
+// NewSQLiteTodoProjection creates a new todo projection
+func NewSQLiteTodoProjection(db *gorm.DB) *SQLiteTodoProjection {
+	return &SQLiteTodoProjection{
+		db: db,
+	}
+}
+
+// insert projects a todo whose stream just started
+func (p *SQLiteTodoProjection) insert(ctx context.Context, state *todoState) error {
+	result := conn(ctx, p.db).Clauses(clause.OnConflict{DoNothing: true}).Create(state.model())
+	if result.Error != nil {
+		return fmt.Errorf("failed to project todo: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrTodoExists
+	}
+	return nil
+}
+
+// update projects the latest state of a todo
+func (p *SQLiteTodoProjection) update(ctx context.Context, state *todoState) error {
+	err := conn(ctx, p.db).Model(&SQLiteTodoModel{}).Where("id = ?", state.ID).Updates(map[string]interface{}{
+		"text":       state.Text,
+		"completed":  state.Completed,
+		"version":    state.Version,
+		"updated_at": state.UpdatedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to project todo: %w", err)
+	}
+	return nil
+}
+
+// remove drops a deleted todo from the projection
+func (p *SQLiteTodoProjection) remove(ctx context.Context, state *todoState) error {
+	if err := conn(ctx, p.db).Where("id = ?", state.ID).Delete(&SQLiteTodoModel{}).Error; err != nil {
+		return fmt.Errorf("failed to project todo: %w", err)
+	}
+	return nil
+}
+
+// get retrieves the projected row of a todo within scope
+func (p *SQLiteTodoProjection) get(ctx context.Context, scope func(*gorm.DB) *gorm.DB, id string) (*SQLiteTodoModel, error) {
+	var model SQLiteTodoModel
+	if err := conn(ctx, p.db).Scopes(scope).Where("id = ?", id).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+	return &model, nil
+}
+
+// find retrieves the projected todos the caller can access, newest first
+func (p *SQLiteTodoProjection) find(ctx context.Context, conds ...interface{}) ([]*entities.Todo, error) {
+	return (&SQLiteTodoRepository{db: p.db}).find(ctx, conds...)
+}
+
+// Rebuild replaces the projection of the tenant in ctx with the state
+// replayed from the todo streams, and returns how many todos it holds. Rows
+// without a live stream, or newer than theirs, were written in state mode:
+// they are imported into their streams first, so that no todo is lost. In a
+// maintenance context made by tenancy.WithAllTenants, every tenant with todos
+// is rebuilt in turn.
+func (p *SQLiteTodoProjection) Rebuild(ctx context.Context) (int, error) {
+	if _, ok := tenancy.FromContext(ctx); ok || !tenancy.AllTenants(ctx) {
+		return p.rebuild(ctx)
+	}
+
+	tenants := map[string]bool{}
+	for _, model := range []interface{}{&SQLiteTodoModel{}, &SQLiteTodoEventModel{}, &SQLiteTodoSnapshotModel{}} {
+		var found []string
+		if err := conn(ctx, p.db).Model(model).Distinct().Pluck("tenant_id", &found).Error; err != nil {
+			return 0, fmt.Errorf("failed to list todo tenants: %w", err)
+		}
+		for _, tenantID := range found {
+			tenants[tenantID] = true
+		}
+	}
+
+	projected := 0
+	var errs []error
+	for _, tenantID := range sortedKeys(tenants) {
+		n, err := p.rebuild(tenancy.WithTenant(ctx, tenantID))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenantID, err))
+		}
+		projected += n
+	}
+	return projected, errors.Join(errs...)
+}
+
+// rebuild rebuilds the projection of a single tenant in one transaction
+func (p *SQLiteTodoProjection) rebuild(ctx context.Context) (int, error) {
+	projected := 0
+	err := withinTransaction(ctx, p.db, func(ctx context.Context) error {
+		var rows []SQLiteTodoModel
+		if err := conn(ctx, p.db).Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to get todos: %w", err)
+		}
+		for i := range rows {
+			state, _, err := loadTodoStream(ctx, p.db, rows[i].ID)
+			if err != nil {
+				return err
+			}
+			if state.supersededBy(&rows[i]) {
+				if err := importTodoRow(ctx, p.db, state, &rows[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		streams := map[string]bool{}
+		for _, model := range []interface{}{&SQLiteTodoEventModel{}, &SQLiteTodoSnapshotModel{}} {
+			var ids []string
+			if err := conn(ctx, p.db).Model(model).Distinct().Pluck("todo_id", &ids).Error; err != nil {
+				return fmt.Errorf("failed to list todo streams: %w", err)
+			}
+			for _, id := range ids {
+				streams[id] = true
+			}
+		}
+
+		if err := conn(ctx, p.db).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&SQLiteTodoModel{}).Error; err != nil {
+			return fmt.Errorf("failed to clear todo projection: %w", err)
+		}
+		for _, id := range sortedKeys(streams) {
+			state, _, err := loadTodoStream(ctx, p.db, id)
+			if err != nil {
+				return err
+			}
+			if state.Deleted {
+				continue
+			}
+			if err := p.insert(ctx, state); err != nil {
+				return fmt.Errorf("failed to project todo %s: %w", id, err)
+			}
+			projected++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return projected, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
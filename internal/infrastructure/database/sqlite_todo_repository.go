@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
 	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/config"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SQLiteTodoRepository implements TodoRepository using SQLite
@@ -21,10 +24,36 @@ func NewSQLiteTodoRepository(db *gorm.DB) repositories.TodoRepository {
 	}
 }
 
+// Todo storage modes, selected by database.mode
+const (
+	// TodoModeState stores the current state of every todo
+	TodoModeState = "state"
+	// TodoModeEventSourced stores an event stream per todo and projects
+	// their current state into the todos table
+	TodoModeEventSourced = "eventsourced"
+)
+
+// NewTodoRepository creates the todo repository of the configured storage mode
+func NewTodoRepository(db *gorm.DB, cfg config.DatabaseConfig) (repositories.TodoRepository, error) {
+	switch cfg.Mode {
+	case "", TodoModeState:
+		return NewSQLiteTodoRepository(db), nil
+	case TodoModeEventSourced:
+		return NewSQLiteEventSourcedTodoRepository(db, cfg.SnapshotEvery), nil
+	default:
+		return nil, fmt.Errorf("unknown database mode %q", cfg.Mode)
+	}
+}
+
 // SQLiteTodoModel represents the database model for SQLite todos
 type SQLiteTodoModel struct {
 	ID        string `gorm:"primaryKey;type:text"`
+	TenantID  string `gorm:"not null;default:'';index;type:text"`
 	Text      string `gorm:"not null;type:text"`
+	Completed bool   `gorm:"not null;default:false"`
+	Version   int64  `gorm:"not null;default:1"`
+	OwnerID   string `gorm:"not null;default:'';index;type:text"`
+	ListID    string `gorm:"not null;default:'';index;type:text"`
 	CreatedAt int64  `gorm:"autoCreateTime"`
 	UpdatedAt int64  `gorm:"autoUpdateTime"`
 }
@@ -39,6 +68,10 @@ func (tm *SQLiteTodoModel) ToEntity() (*entities.Todo, error) {
 	todo := &entities.Todo{
 		ID:        tm.ID,
 		Text:      tm.Text,
+		Completed: tm.Completed,
+		Version:   tm.Version,
+		OwnerID:   tm.OwnerID,
+		ListID:    tm.ListID,
 		CreatedAt: timeFromUnix(tm.CreatedAt),
 		UpdatedAt: timeFromUnix(tm.UpdatedAt),
 	}
@@ -50,26 +83,70 @@ func (tm *SQLiteTodoModel) ToEntity() (*entities.Todo, error) {
 func (tm *SQLiteTodoModel) FromEntity(todo *entities.Todo) {
 	tm.ID = todo.ID
 	tm.Text = todo.Text
+	tm.Completed = todo.Completed
+	tm.Version = todo.Version
+	tm.OwnerID = todo.OwnerID
+	tm.ListID = todo.ListID
 	tm.CreatedAt = todo.CreatedAt.Unix()
 	tm.UpdatedAt = todo.UpdatedAt.Unix()
 }
 
-// Create creates a new todo
+// Create creates a new todo owned by the caller
 func (r *SQLiteTodoRepository) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	owner, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	model := &SQLiteTodoModel{}
 	model.FromEntity(todo)
+	model.OwnerID = owner
+
+	err = withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create todo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrTodoExists
+		}
 
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+		created, err := model.ToEntity()
+		if err != nil {
+			return err
+		}
+		return appendChange(ctx, r.db, todoChange(entities.ChangeTodoCreated, created, entities.TodoFieldText, entities.TodoFieldCompleted))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return model.ToEntity()
 }
 
-// GetAll retrieves all todos
+// GetAll retrieves all todos the caller can access
 func (r *SQLiteTodoRepository) GetAll(ctx context.Context) ([]*entities.Todo, error) {
+	return r.find(ctx)
+}
+
+// GetByList retrieves the todos of a list the caller is a member of
+func (r *SQLiteTodoRepository) GetByList(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	return r.find(ctx, "list_id = ?", listID)
+}
+
+func (r *SQLiteTodoRepository) find(ctx context.Context, conds ...interface{}) ([]*entities.Todo, error) {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := conn(ctx, r.db).Scopes(scope)
+	if len(conds) > 0 {
+		query = query.Where(conds[0], conds[1:]...)
+	}
+
 	var models []SQLiteTodoModel
-	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&models).Error; err != nil {
+	if err := query.Order("created_at DESC").Find(&models).Error; err != nil {
 		return nil, fmt.Errorf("failed to get todos: %w", err)
 	}
 
@@ -85,10 +162,15 @@ func (r *SQLiteTodoRepository) GetAll(ctx context.Context) ([]*entities.Todo, er
 	return todos, nil
 }
 
-// GetByID retrieves a todo by its ID
+// GetByID retrieves a todo the caller can access by its ID
 func (r *SQLiteTodoRepository) GetByID(ctx context.Context, id string) (*entities.Todo, error) {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var model SQLiteTodoModel
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(scope).Where("id = ?", id).First(&model).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, repositories.ErrTodoNotFound
 		}
@@ -97,3 +179,168 @@ func (r *SQLiteTodoRepository) GetByID(ctx context.Context, id string) (*entitie
 
 	return model.ToEntity()
 }
+
+// Update saves changes to an existing todo using optimistic locking on its version
+func (r *SQLiteTodoRepository) Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &SQLiteTodoModel{}
+	model.FromEntity(todo)
+
+	var updated *entities.Todo
+	err = withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		var before SQLiteTodoModel
+		if err := conn(ctx, r.db).Scopes(scope).Where("id = ?", todo.ID).First(&before).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return repositories.ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+
+		result := conn(ctx, r.db).Model(&SQLiteTodoModel{}).Scopes(scope).
+			Where("id = ? AND version = ?", todo.ID, todo.Version).
+			Updates(map[string]interface{}{
+				"text":       model.Text,
+				"completed":  model.Completed,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": model.UpdatedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update todo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrVersionConflict
+		}
+
+		if updated, err = r.GetByID(ctx, todo.ID); err != nil {
+			return err
+		}
+
+		var fields []string
+		if before.Text != updated.Text {
+			fields = append(fields, entities.TodoFieldText)
+		}
+		if before.Completed != updated.Completed {
+			fields = append(fields, entities.TodoFieldCompleted)
+		}
+		kind := entities.ChangeTodoUpdated
+		if updated.Completed && !before.Completed {
+			kind = entities.ChangeTodoCompleted
+		}
+		return appendChange(ctx, r.db, todoChange(kind, updated, fields...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete removes a todo the caller can access, together with its comments,
+// leaving a tombstone in the change log
+func (r *SQLiteTodoRepository) Delete(ctx context.Context, id string) error {
+	scope, err := accessibleTodos(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		var model SQLiteTodoModel
+		if err := conn(ctx, r.db).Scopes(scope).Where("id = ?", id).First(&model).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return repositories.ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+
+		result := conn(ctx, r.db).Scopes(scope).Where("id = ?", id).Delete(&SQLiteTodoModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete todo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrTodoNotFound
+		}
+
+		if err := conn(ctx, r.db).Where("todo_id = ?", id).Delete(&SQLiteCommentModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		// A todo stored in eventsourced mode before must not come back when
+		// its projection is rebuilt
+		if err := endTodoStream(ctx, r.db, &model); err != nil {
+			return err
+		}
+		deleted, err := model.ToEntity()
+		if err != nil {
+			return err
+		}
+		return appendChange(ctx, r.db, todoChange(entities.ChangeTodoDeleted, deleted))
+	})
+}
+
+// WithinTransaction runs fn in a database transaction shared by all repository calls made with its context
+func (r *SQLiteTodoRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, r.db, fn)
+}
+
+// CommitBatch marks the end of the batch in ctx in the change log
+func (r *SQLiteTodoRepository) CommitBatch(ctx context.Context) error {
+	return commitBatch(ctx, r.db)
+}
+
+// ownerID returns the user every todo query is scoped to. Queries without an
+// authenticated principal are refused rather than run unscoped.
+func ownerID(ctx context.Context) (string, error) {
+	principal, err := identity.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	return principal.UserID, nil
+}
+
+// accessibleTodos limits a todo query to the caller's personal todos and the
+// todos of the lists the caller is a member of
+func accessibleTodos(ctx context.Context) (func(*gorm.DB) *gorm.DB, error) {
+	user, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		memberships := db.Session(&gorm.Session{NewDB: true}).
+			Model(&SQLiteListMemberModel{}).Select("list_id").Where("user_id = ?", user)
+		return db.Where("((list_id = '' AND owner_id = ?) OR list_id IN (?))", user, memberships)
+	}, nil
+}
+
+// CountTodosByState counts the todos of every tenant, completed and pending
+func CountTodosByState(ctx context.Context, db *gorm.DB) (map[string]int64, error) {
+	counts := map[string]int64{"completed": 0, "pending": 0}
+	err := ForEachTenant(ctx, db, func(ctx context.Context) error {
+		var rows []struct {
+			Completed bool
+			Count     int64
+		}
+		err := conn(ctx, db).Model(&SQLiteTodoModel{}).
+			Select("completed, count(*) AS count").
+			Group("completed").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if row.Completed {
+				counts["completed"] += row.Count
+			} else {
+				counts["pending"] += row.Count
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+	return counts, nil
+}
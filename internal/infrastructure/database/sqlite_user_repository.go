@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteUserRepository implements UserRepository using SQLite
+type SQLiteUserRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteUserRepository creates a new SQLite user repository
+func NewSQLiteUserRepository(db *gorm.DB) repositories.UserRepository {
+	return &SQLiteUserRepository{
+		db: db,
+	}
+}
+
+// SQLiteUserModel represents the database model for users
+type SQLiteUserModel struct {
+	ID           string `gorm:"primaryKey;type:text"`
+	TenantID     string `gorm:"not null;default:'';uniqueIndex:idx_users_tenant_username;type:text"`
+	Username     string `gorm:"not null;uniqueIndex:idx_users_tenant_username;type:text"`
+	PasswordHash string `gorm:"not null;type:text"`
+	CreatedAt    int64  `gorm:"autoCreateTime"`
+	UpdatedAt    int64  `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for SQLiteUserModel
+func (SQLiteUserModel) TableName() string {
+	return "users"
+}
+
+// ToEntity converts SQLiteUserModel to domain entity
+func (m *SQLiteUserModel) ToEntity() *entities.User {
+	return &entities.User{
+		ID:           m.ID,
+		Username:     m.Username,
+		PasswordHash: m.PasswordHash,
+		CreatedAt:    timeFromUnix(m.CreatedAt),
+		UpdatedAt:    timeFromUnix(m.UpdatedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteUserModel
+func (m *SQLiteUserModel) FromEntity(user *entities.User) {
+	m.ID = user.ID
+	m.Username = user.Username
+	m.PasswordHash = user.PasswordHash
+	m.CreatedAt = user.CreatedAt.Unix()
+	m.UpdatedAt = user.UpdatedAt.Unix()
+}
+
+// Create creates a new user
+func (r *SQLiteUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	model := &SQLiteUserModel{}
+	model.FromEntity(user)
+
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, repositories.ErrUserExists
+	}
+
+	return model.ToEntity(), nil
+}
+
+// GetByID retrieves a user by its ID
+func (r *SQLiteUserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	return r.getWhere(ctx, "id = ?", id)
+}
+
+// GetByUsername retrieves a user by its normalized username
+func (r *SQLiteUserRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	return r.getWhere(ctx, "username = ?", username)
+}
+
+// UpdatePassword stores a new password hash
+func (r *SQLiteUserRepository) UpdatePassword(ctx context.Context, user *entities.User) error {
+	result := conn(ctx, r.db).Model(&SQLiteUserModel{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"password_hash": user.PasswordHash,
+		"updated_at":    user.UpdatedAt.Unix(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update password: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLiteUserRepository) getWhere(ctx context.Context, query string, arg interface{}) (*entities.User, error) {
+	var model SQLiteUserModel
+	if err := conn(ctx, r.db).Where(query, arg).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
@@ -0,0 +1,416 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteWebhookRepository implements WebhookRepository using SQLite
+type SQLiteWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLiteWebhookRepository creates a new SQLite webhook repository
+func NewSQLiteWebhookRepository(db *gorm.DB) repositories.WebhookRepository {
+	return &SQLiteWebhookRepository{
+		db: db,
+	}
+}
+
+// SQLiteWebhookModel represents the database model for webhooks
+type SQLiteWebhookModel struct {
+	ID         string `gorm:"primaryKey;type:text"`
+	TenantID   string `gorm:"not null;default:'';index;type:text"`
+	OwnerID    string `gorm:"not null;index;type:text"`
+	URL        string `gorm:"not null;type:text"`
+	Secret     string `gorm:"not null;type:text"`
+	EventTypes string `gorm:"not null;default:'';type:text"` // comma separated
+	ListID     string `gorm:"not null;default:'';index;type:text"`
+	CreatedAt  int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteWebhookModel
+func (SQLiteWebhookModel) TableName() string {
+	return "webhooks"
+}
+
+// ToEntity converts SQLiteWebhookModel to domain entity
+func (m *SQLiteWebhookModel) ToEntity() *entities.Webhook {
+	webhook := &entities.Webhook{
+		ID:        m.ID,
+		OwnerID:   m.OwnerID,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		ListID:    m.ListID,
+		CreatedAt: timeFromUnix(m.CreatedAt),
+	}
+	if m.EventTypes != "" {
+		webhook.EventTypes = strings.Split(m.EventTypes, ",")
+	}
+	return webhook
+}
+
+// FromEntity converts domain entity to SQLiteWebhookModel
+func (m *SQLiteWebhookModel) FromEntity(webhook *entities.Webhook) {
+	m.ID = webhook.ID
+	m.OwnerID = webhook.OwnerID
+	m.URL = webhook.URL
+	m.Secret = webhook.Secret
+	m.EventTypes = strings.Join(webhook.EventTypes, ",")
+	m.ListID = webhook.ListID
+	m.CreatedAt = webhook.CreatedAt.Unix()
+}
+
+// SQLiteWebhookDeliveryModel represents the database model for webhook deliveries
+type SQLiteWebhookDeliveryModel struct {
+	ID            string `gorm:"primaryKey;type:text"`
+	TenantID      string `gorm:"not null;default:'';index;type:text"`
+	WebhookID     string `gorm:"not null;uniqueIndex:idx_webhook_deliveries_event;type:text"`
+	EventID       string `gorm:"not null;uniqueIndex:idx_webhook_deliveries_event;type:text"`
+	EventType     string `gorm:"not null;type:text"`
+	Payload       []byte `gorm:"not null"`
+	RequestID     string `gorm:"not null;default:'';type:text"`
+	TraceParent   string `gorm:"not null;default:'';type:text"`
+	Status        string `gorm:"not null;index:idx_webhook_deliveries_due;type:text"`
+	Attempts      int    `gorm:"not null;default:0"`
+	NextAttemptAt int64  `gorm:"not null;index:idx_webhook_deliveries_due"`
+	LastError     string `gorm:"not null;default:'';type:text"`
+	CreatedAt     int64  `gorm:"not null;index"`
+	DeliveredAt   *int64
+}
+
+// TableName returns the table name for SQLiteWebhookDeliveryModel
+func (SQLiteWebhookDeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
+
+// ToEntity converts SQLiteWebhookDeliveryModel to domain entity
+func (m *SQLiteWebhookDeliveryModel) ToEntity() *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:            m.ID,
+		WebhookID:     m.WebhookID,
+		TenantID:      m.TenantID,
+		EventID:       m.EventID,
+		EventType:     m.EventType,
+		Payload:       m.Payload,
+		RequestID:     m.RequestID,
+		TraceParent:   m.TraceParent,
+		Status:        entities.WebhookDeliveryStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: timeFromUnix(m.NextAttemptAt),
+		LastError:     m.LastError,
+		CreatedAt:     timeFromUnix(m.CreatedAt),
+		DeliveredAt:   optionalTimeFromUnix(m.DeliveredAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteWebhookDeliveryModel
+func (m *SQLiteWebhookDeliveryModel) FromEntity(delivery *entities.WebhookDelivery) {
+	m.ID = delivery.ID
+	m.WebhookID = delivery.WebhookID
+	m.EventID = delivery.EventID
+	m.EventType = delivery.EventType
+	m.Payload = delivery.Payload
+	m.RequestID = delivery.RequestID
+	m.TraceParent = delivery.TraceParent
+	m.Status = string(delivery.Status)
+	m.Attempts = delivery.Attempts
+	m.NextAttemptAt = delivery.NextAttemptAt.Unix()
+	m.LastError = delivery.LastError
+	m.CreatedAt = delivery.CreatedAt.Unix()
+	m.DeliveredAt = optionalTimeToUnix(delivery.DeliveredAt)
+}
+
+// SQLiteWebhookAttemptModel represents the database model for the webhook delivery log
+type SQLiteWebhookAttemptModel struct {
+	ID          string `gorm:"primaryKey;type:text"`
+	TenantID    string `gorm:"not null;default:'';index;type:text"`
+	DeliveryID  string `gorm:"not null;index;type:text"`
+	StatusCode  int    `gorm:"not null;default:0"`
+	Error       string `gorm:"not null;default:'';type:text"`
+	DurationMs  int64  `gorm:"not null;default:0"`
+	AttemptedAt int64  `gorm:"not null"`
+}
+
+// TableName returns the table name for SQLiteWebhookAttemptModel
+func (SQLiteWebhookAttemptModel) TableName() string {
+	return "webhook_attempts"
+}
+
+// ToEntity converts SQLiteWebhookAttemptModel to domain entity
+func (m *SQLiteWebhookAttemptModel) ToEntity() *entities.WebhookAttempt {
+	return &entities.WebhookAttempt{
+		ID:          m.ID,
+		DeliveryID:  m.DeliveryID,
+		StatusCode:  m.StatusCode,
+		Error:       m.Error,
+		Duration:    time.Duration(m.DurationMs) * time.Millisecond,
+		AttemptedAt: timeFromUnix(m.AttemptedAt),
+	}
+}
+
+// FromEntity converts domain entity to SQLiteWebhookAttemptModel
+func (m *SQLiteWebhookAttemptModel) FromEntity(attempt *entities.WebhookAttempt) {
+	m.ID = attempt.ID
+	m.DeliveryID = attempt.DeliveryID
+	m.StatusCode = attempt.StatusCode
+	m.Error = attempt.Error
+	m.DurationMs = attempt.Duration.Milliseconds()
+	m.AttemptedAt = attempt.AttemptedAt.Unix()
+}
+
+// Create stores a new webhook
+func (r *SQLiteWebhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	model := &SQLiteWebhookModel{}
+	model.FromEntity(webhook)
+
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a webhook owned by ownerID
+func (r *SQLiteWebhookRepository) GetByID(ctx context.Context, id, ownerID string) (*entities.Webhook, error) {
+	var model SQLiteWebhookModel
+	if err := conn(ctx, r.db).Where("id = ? AND owner_id = ?", id, ownerID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// GetForDelivery retrieves a webhook regardless of its owner
+func (r *SQLiteWebhookRepository) GetForDelivery(ctx context.Context, id string) (*entities.Webhook, error) {
+	var model SQLiteWebhookModel
+	if err := conn(ctx, r.db).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListByOwner retrieves the webhooks of a user, newest first
+func (r *SQLiteWebhookRepository) ListByOwner(ctx context.Context, ownerID string) ([]*entities.Webhook, error) {
+	var models []SQLiteWebhookModel
+	if err := conn(ctx, r.db).Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return toWebhooks(models), nil
+}
+
+// Delete removes a webhook owned by ownerID, together with its deliveries
+func (r *SQLiteWebhookRepository) Delete(ctx context.Context, id, ownerID string) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		result := conn(ctx, r.db).Where("id = ? AND owner_id = ?", id, ownerID).Delete(&SQLiteWebhookModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrWebhookNotFound
+		}
+
+		db := conn(ctx, r.db)
+		deliveryIDs := db.Session(&gorm.Session{NewDB: true}).Model(&SQLiteWebhookDeliveryModel{}).Select("id").Where("webhook_id = ?", id)
+		if err := db.Where("delivery_id IN (?)", deliveryIDs).Delete(&SQLiteWebhookAttemptModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook attempts: %w", err)
+		}
+		if err := conn(ctx, r.db).Where("webhook_id = ?", id).Delete(&SQLiteWebhookDeliveryModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// Subscribed retrieves the webhooks told about changes to a todo of ownerID on listID
+func (r *SQLiteWebhookRepository) Subscribed(ctx context.Context, ownerID, listID string) ([]*entities.Webhook, error) {
+	db := conn(ctx, r.db)
+	query := db.Where("list_id = '' AND owner_id = ?", ownerID)
+	if listID != "" {
+		members := db.Session(&gorm.Session{NewDB: true}).Model(&SQLiteListMemberModel{}).Select("user_id").Where("list_id = ?", listID)
+		query = db.Where("list_id IN ('', ?) AND owner_id IN (?)", listID, members)
+	}
+
+	var models []SQLiteWebhookModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	return toWebhooks(models), nil
+}
+
+// Enqueue stores new deliveries, skipping those already stored
+func (r *SQLiteWebhookRepository) Enqueue(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	models := make([]SQLiteWebhookDeliveryModel, len(deliveries))
+	for i, delivery := range deliveries {
+		models[i].FromEntity(delivery)
+	}
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&models).Error; err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue retrieves the deliveries due at now and leases them until leaseUntil
+func (r *SQLiteWebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	var models []SQLiteWebhookDeliveryModel
+	err := conn(ctx, r.db).
+		Where("status = ? AND next_attempt_at <= ?", string(entities.WebhookDeliveryPending), now.Unix()).
+		Order("next_attempt_at ASC").Limit(limit).Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due webhook deliveries: %w", err)
+	}
+
+	claimed := make([]*entities.WebhookDelivery, 0, len(models))
+	for i := range models {
+		// Another worker may have claimed the delivery since it was read
+		result := conn(ctx, r.db).Model(&SQLiteWebhookDeliveryModel{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", models[i].ID, models[i].Status, models[i].NextAttemptAt).
+			Update("next_attempt_at", leaseUntil.Unix())
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim webhook delivery: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			models[i].NextAttemptAt = leaseUntil.Unix()
+			claimed = append(claimed, models[i].ToEntity())
+		}
+	}
+	return claimed, nil
+}
+
+// RecordAttempt saves a delivery after an attempt and logs the attempt
+func (r *SQLiteWebhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error {
+	model := &SQLiteWebhookDeliveryModel{}
+	model.FromEntity(delivery)
+	log := &SQLiteWebhookAttemptModel{}
+	log.FromEntity(attempt)
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		err := conn(ctx, r.db).Model(&SQLiteWebhookDeliveryModel{}).Where("id = ?", delivery.ID).
+			Updates(map[string]interface{}{
+				"status":          model.Status,
+				"attempts":        model.Attempts,
+				"next_attempt_at": model.NextAttemptAt,
+				"last_error":      model.LastError,
+				"delivered_at":    model.DeliveredAt,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		if err := conn(ctx, r.db).Create(log).Error; err != nil {
+			return fmt.Errorf("failed to log webhook attempt: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetDelivery retrieves a delivery of a webhook
+func (r *SQLiteWebhookRepository) GetDelivery(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error) {
+	var model SQLiteWebhookDeliveryModel
+	if err := conn(ctx, r.db).Where("id = ? AND webhook_id = ?", id, webhookID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListDeliveries retrieves the deliveries of a webhook, newest first
+func (r *SQLiteWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, status entities.WebhookDeliveryStatus, limit int) ([]*entities.WebhookDelivery, error) {
+	query := conn(ctx, r.db).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+
+	var models []SQLiteWebhookDeliveryModel
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, len(models))
+	for i := range models {
+		deliveries[i] = models[i].ToEntity()
+	}
+	return deliveries, nil
+}
+
+// ListAttempts retrieves the attempts of a delivery, oldest first
+func (r *SQLiteWebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]*entities.WebhookAttempt, error) {
+	var models []SQLiteWebhookAttemptModel
+	if err := conn(ctx, r.db).Where("delivery_id = ?", deliveryID).Order("attempted_at ASC, rowid ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook attempts: %w", err)
+	}
+
+	attempts := make([]*entities.WebhookAttempt, len(models))
+	for i := range models {
+		attempts[i] = models[i].ToEntity()
+	}
+	return attempts, nil
+}
+
+// Redeliver makes a delivery of a webhook pending again
+func (r *SQLiteWebhookRepository) Redeliver(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error) {
+	result := conn(ctx, r.db).Model(&SQLiteWebhookDeliveryModel{}).
+		Where("id = ? AND webhook_id = ?", id, webhookID).
+		Updates(map[string]interface{}{
+			"status":          string(entities.WebhookDeliveryPending),
+			"attempts":        0,
+			"next_attempt_at": time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook delivery: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, repositories.ErrWebhookDeliveryNotFound
+	}
+	return r.GetDelivery(ctx, webhookID, id)
+}
+
+// DeleteFinishedBefore purges the finished deliveries created before cutoff
+func (r *SQLiteWebhookRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+	err := withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		finished := []string{string(entities.WebhookDeliveryDelivered), string(entities.WebhookDeliveryDead)}
+		db := conn(ctx, r.db)
+		deliveryIDs := db.Session(&gorm.Session{NewDB: true}).Model(&SQLiteWebhookDeliveryModel{}).
+			Select("id").Where("status IN ? AND created_at < ?", finished, cutoff.Unix())
+		if err := db.Where("delivery_id IN (?)", deliveryIDs).Delete(&SQLiteWebhookAttemptModel{}).Error; err != nil {
+			return fmt.Errorf("failed to purge webhook attempts: %w", err)
+		}
+
+		result := conn(ctx, r.db).Where("status IN ? AND created_at < ?", finished, cutoff.Unix()).Delete(&SQLiteWebhookDeliveryModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge webhook deliveries: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+func toWebhooks(models []SQLiteWebhookModel) []*entities.Webhook {
+	webhooks := make([]*entities.Webhook, len(models))
+	for i := range models {
+		webhooks[i] = models[i].ToEntity()
+	}
+	return webhooks
+}
@@ -0,0 +1,286 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"todo-backend/internal/domain/tenancy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	tenantScopingPlugin = "tenancy:scoping"
+	tenantField         = "TenantID"
+)
+
+// TenantScoping is a GORM plugin that confines every statement on a model
+// with a TenantID field to the tenant carried by the statement's context:
+// queries, updates and deletes are filtered by tenant_id and creates have it
+// set. A statement without a tenant fails with tenancy.ErrTenantRequired
+// instead of running unscoped. So do raw SQL statements, which cannot be
+// scoped, unless the context was made by tenancy.WithAllTenants.
+type TenantScoping struct {
+	// databases routes each tenant to its own database when set
+	databases *TenantDatabases
+}
+
+// NewTenantScoping creates the plugin. With databases set, repositories built
+// on the database the plugin is registered with use each tenant's own database.
+func NewTenantScoping(databases *TenantDatabases) *TenantScoping {
+	return &TenantScoping{databases: databases}
+}
+
+// Name implements gorm.Plugin
+func (p *TenantScoping) Name() string {
+	return tenantScopingPlugin
+}
+
+// Initialize implements gorm.Plugin
+func (p *TenantScoping) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register("tenancy:create", p.scopeCreate),
+		callbacks.Query().Before("gorm:query").Register("tenancy:query", p.scopeWhere),
+		callbacks.Update().Before("gorm:update").Register("tenancy:update", p.scopeWhere),
+		callbacks.Delete().Before("gorm:delete").Register("tenancy:delete", p.scopeWhere),
+		callbacks.Row().Before("gorm:row").Register("tenancy:row", p.scopeWhere),
+		callbacks.Raw().Before("gorm:raw").Register("tenancy:raw", p.scopeWhere),
+	}
+	return errors.Join(registrations...)
+}
+
+// scopeWhere filters the statement by the tenant in its context
+func (p *TenantScoping) scopeWhere(db *gorm.DB) {
+	field, tenant, ok := statementTenant(db)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenant},
+	}})
+}
+
+// scopeCreate stamps the tenant in the statement's context on created rows
+func (p *TenantScoping) scopeCreate(db *gorm.DB) {
+	field, tenant, ok := statementTenant(db)
+	if !ok {
+		if db.Error == nil && db.Statement.Schema != nil && db.Statement.Schema.LookUpField(tenantField) != nil {
+			// Only reachable through tenancy.WithAllTenants
+			_ = db.AddError(fmt.Errorf("%w: rows cannot be created across all tenants", tenancy.ErrTenantRequired))
+		}
+		return
+	}
+
+	ctx := db.Statement.Context
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := field.Set(ctx, reflect.Indirect(value.Index(i)), tenant); err != nil {
+				_ = db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(ctx, value, tenant); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+}
+
+// statementTenant returns the tenant field and tenant a statement must be
+// scoped to. It records tenancy.ErrTenantRequired on statements that cannot
+// be scoped to a tenant, unless they were deliberately made across all
+// tenants.
+func statementTenant(db *gorm.DB) (*schema.Field, string, bool) {
+	if db.Error != nil {
+		return nil, "", false
+	}
+	stmt := db.Statement
+	allTenants := tenancy.AllTenants(stmt.Context)
+
+	// Raw SQL and statements without a model cannot be scoped
+	if stmt.SQL.Len() > 0 || stmt.Schema == nil {
+		if !allTenants {
+			_ = db.AddError(fmt.Errorf("%w: raw statements cannot be scoped to a tenant", tenancy.ErrTenantRequired))
+		}
+		return nil, "", false
+	}
+
+	field := stmt.Schema.LookUpField(tenantField)
+	if field == nil {
+		return nil, "", false
+	}
+	if tenant, ok := tenancy.FromContext(stmt.Context); ok {
+		return field, tenant, true
+	}
+	if !allTenants {
+		_ = db.AddError(fmt.Errorf("%w: unscoped statement on %s", tenancy.ErrTenantRequired, stmt.Schema.Table))
+	}
+	return nil, "", false
+}
+
+// tenantDatabase returns the database holding the tenant carried by ctx: the
+// tenant's own database with one database per tenant, db otherwise
+func tenantDatabase(ctx context.Context, db *gorm.DB) *gorm.DB {
+	plugin, ok := db.Config.Plugins[tenantScopingPlugin].(*TenantScoping)
+	if !ok || plugin.databases == nil {
+		return db
+	}
+	tenant, ok := tenancy.FromContext(ctx)
+	if !ok {
+		return db
+	}
+
+	tenantDB, err := plugin.databases.Get(tenant)
+	if err != nil {
+		failed := db.Session(&gorm.Session{NewDB: true})
+		_ = failed.AddError(err)
+		return failed
+	}
+	return tenantDB
+}
+
+// ForEachTenant runs a maintenance job against every tenant's data. With one
+// database per tenant fn runs once per tenant database; otherwise it runs
+// once, across all tenants.
+func ForEachTenant(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	plugin, ok := db.Config.Plugins[tenantScopingPlugin].(*TenantScoping)
+	if !ok || plugin.databases == nil {
+		return fn(tenancy.WithAllTenants(ctx))
+	}
+
+	tenants, err := plugin.databases.Tenants()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, tenant := range tenants {
+		if err := fn(tenancy.WithTenant(ctx, tenant)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AdoptUnscopedRows assigns rows stored before tenancy was introduced to
+// tenant, and returns how many rows it assigned. It is a one-off upgrade
+// step run by the adopt-rows command, not part of every migration.
+func AdoptUnscopedRows(ctx context.Context, db *gorm.DB, tenant string) (int64, error) {
+	ctx = tenancy.WithAllTenants(ctx)
+	var adopted int64
+	for _, model := range Models() {
+		result := db.WithContext(ctx).Model(model).Where("tenant_id = ''").Update("tenant_id", tenant)
+		if result.Error != nil {
+			return adopted, fmt.Errorf("failed to assign existing rows to tenant %s: %w", tenant, result.Error)
+		}
+		adopted += result.RowsAffected
+	}
+	return adopted, nil
+}
+
+// TenantDatabases opens one SQLite database per tenant under a directory
+type TenantDatabases struct {
+	dir  string
+	open func(path string) (*gorm.DB, error)
+
+	mu        sync.Mutex
+	databases map[string]*gorm.DB
+}
+
+// NewTenantDatabases stores tenant databases in dir, opening them with open
+func NewTenantDatabases(dir string, open func(path string) (*gorm.DB, error)) *TenantDatabases {
+	return &TenantDatabases{
+		dir:       dir,
+		open:      open,
+		databases: make(map[string]*gorm.DB),
+	}
+}
+
+// Get returns the database of tenant, creating and migrating it on first use
+func (t *TenantDatabases) Get(tenant string) (*gorm.DB, error) {
+	if err := tenancy.ValidateID(tenant); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if db, ok := t.databases[tenant]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(t.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create tenant database directory: %w", err)
+	}
+	db, err := t.open(filepath.Join(t.dir, tenant+".db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database of tenant %s: %w", tenant, err)
+	}
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database of tenant %s: %w", tenant, err)
+	}
+	if err := db.Use(NewTenantScoping(nil)); err != nil {
+		return nil, fmt.Errorf("failed to scope database of tenant %s: %w", tenant, err)
+	}
+
+	t.databases[tenant] = db
+	return db, nil
+}
+
+// Tenants lists the tenants that have a database, whether open or not
+func (t *TenantDatabases) Tenants() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(t.dir, "*.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant databases: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	t.mu.Lock()
+	for tenant := range t.databases {
+		seen[tenant] = true
+	}
+	t.mu.Unlock()
+	for _, path := range paths {
+		tenant := strings.TrimSuffix(filepath.Base(path), ".db")
+		if tenancy.ValidateID(tenant) == nil {
+			seen[tenant] = true
+		}
+	}
+
+	tenants := make([]string, 0, len(seen))
+	for tenant := range seen {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// Close closes every open tenant database
+func (t *TenantDatabases) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for tenant, db := range t.databases {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+		delete(t.databases, tenant)
+	}
+	return errors.Join(errs...)
+}
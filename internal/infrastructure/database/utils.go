@@ -12,4 +12,21 @@ func timeFromUnix(unix int64) time.Time {
 // timeToUnix converts time.Time to Unix timestamp
 func timeToUnix() int64 {
 	return time.Now().Unix()
-} 
\ No newline at end of file
+} 
+// optionalTimeFromUnix converts a nullable Unix timestamp to *time.Time
+func optionalTimeFromUnix(unix *int64) *time.Time {
+	if unix == nil {
+		return nil
+	}
+	t := timeFromUnix(*unix)
+	return &t
+}
+
+// optionalTimeToUnix converts *time.Time to a nullable Unix timestamp
+func optionalTimeToUnix(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
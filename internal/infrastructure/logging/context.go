@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"todo-backend/internal/domain/correlation"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/tenancy"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type attrsKey struct{}
+
+// WithAttrs returns a context whose log records carry attrs, in addition to
+// those already in ctx
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+	return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// contextHandler adds the request-scoped fields of the context to every
+// record: the attributes of WithAttrs, the request ID, the caller, the tenant
+// and the trace
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if id, ok := correlation.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if principal, ok := identity.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("user", principal.UserID))
+	}
+	if tenantID, ok := tenancy.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("tenant", tenantID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
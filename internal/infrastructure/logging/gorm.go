@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger writes GORM's logs to a slog logger: failed queries as errors,
+// slow queries as warnings and every other query at debug level. Queries
+// are logged without their parameters, which may hold secrets.
+type GormLogger struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+	// minLevel is raised by LogMode, e.g. for gorm.Session{Logger: ...Silent}
+	minLevel slog.Level
+}
+
+// NewGormLogger bridges GORM's logger into logger. Queries taking longer
+// than slowThreshold are logged as warnings; zero disables them.
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{logger: logger, slowThreshold: slowThreshold, minLevel: slog.LevelDebug}
+}
+
+// LogMode returns a logger that logs from the slog level matching level up
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	bridged := *l
+	switch level {
+	case gormlogger.Silent:
+		bridged.minLevel = slog.LevelError + 1
+	case gormlogger.Error:
+		bridged.minLevel = slog.LevelError
+	case gormlogger.Warn:
+		bridged.minLevel = slog.LevelWarn
+	default:
+		bridged.minLevel = slog.LevelDebug
+	}
+	return &bridged
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(msg, data...))
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(msg, data...))
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(msg, data...))
+}
+
+// Trace logs a query once it has run
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	level, msg := slog.LevelDebug, "Query"
+	switch {
+	case err != nil && !errors.Is(err, gormlogger.ErrRecordNotFound):
+		level, msg = slog.LevelError, "Query failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level, msg = slog.LevelWarn, "Slow query"
+	}
+	if level < l.minLevel || !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		Milliseconds("duration_ms", elapsed),
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter drops the query parameters from logged queries
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *GormLogger) log(ctx context.Context, level slog.Level, msg string) {
+	if level >= l.minLevel {
+		l.logger.Log(ctx, level, msg)
+	}
+}
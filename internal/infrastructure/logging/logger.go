@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+	"todo-backend/internal/infrastructure/config"
+)
+
+// New builds the application logger from cfg, writing JSON or text to w.
+// Its level can be changed at runtime through the returned LevelVar.
+func New(cfg config.LoggingConfig, w io.Writer) (*slog.Logger, *slog.LevelVar, error) {
+	level := new(slog.LevelVar)
+	if cfg.Level != "" {
+		parsed, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		level.Set(parsed)
+	}
+
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json", "":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q, expected json or text", cfg.Format)
+	}
+	return slog.New(&contextHandler{Handler: handler}), level, nil
+}
+
+// ParseLevel parses debug, info, warn or error, in any case
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	switch strings.ToLower(s) {
+	case "debug":
+		level = slog.LevelDebug
+	case "info":
+		level = slog.LevelInfo
+	case "warn", "warning":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		return level, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// Milliseconds logs d as fractional milliseconds
+func Milliseconds(key string, d time.Duration) slog.Attr {
+	return slog.Float64(key, float64(d)/float64(time.Millisecond))
+}
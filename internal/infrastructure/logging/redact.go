@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of secrets
+const Redacted = "[REDACTED]"
+
+// secretKeys are parts of attribute keys whose values are never logged
+var secretKeys = []string{"password", "secret", "token", "authorization", "cookie", "api_key", "apikey", "private_key"}
+
+// secretValues match credentials embedded in logged strings: bearer tokens
+// and API keys
+var secretValues = regexp.MustCompile(`(?i)(bearer\s+)[^\s"',]+|\btdk_[A-Za-z0-9_-]+`)
+
+// redact hides the values of secret attributes, and credentials within
+// string values
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(attr.Key, Redacted)
+		}
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(RedactString(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = slog.StringValue(RedactString(err.Error()))
+		}
+	}
+	return attr
+}
+
+// RedactString hides the bearer tokens and API keys within s
+func RedactString(s string) string {
+	return secretValues.ReplaceAllStringFunc(s, func(match string) string {
+		if prefix := secretValues.FindStringSubmatch(match)[1]; prefix != "" {
+			return prefix + Redacted
+		}
+		return Redacted
+	})
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-backend/internal/infrastructure/server"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "todo"
+
+// Metrics holds the service's Prometheus metrics, in a registry of their own
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests           *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+	todos              *prometheus.GaugeVec
+}
+
+// New creates the metrics, along with the Go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route template and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Time taken by repository methods.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"repository", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_errors_total",
+			Help:      "Repository method calls that returned an error, including not found and conflict errors.",
+		}, []string{"repository", "method"}),
+		todos: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "items",
+			Help:      "Todos stored across all tenants, by state, as of the last count.",
+		}, []string{"state"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.repositoryDuration,
+		m.repositoryErrors,
+		m.todos,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest records a handled HTTP request. route is the template the
+// request matched, such as /api/todos/:id, which keeps the labels bounded.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	m.requests.With(labels).Inc()
+	m.requestDuration.With(labels).Observe(duration.Seconds())
+}
+
+func (m *Metrics) observeRepository(repository, method string, start time.Time, err error) {
+	m.repositoryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.repositoryErrors.WithLabelValues(repository, method).Inc()
+	}
+}
+
+// SetTodoCounts replaces the todo counts by state
+func (m *Metrics) SetTodoCounts(counts map[string]int64) {
+	m.todos.Reset()
+	for state, count := range counts {
+		m.todos.WithLabelValues(state).Set(float64(count))
+	}
+}
+
+// RegisterDB exports the connection pool statistics of db
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterWorkers exports how far each periodic background job is behind
+// its schedule
+func (m *Metrics) RegisterWorkers(workers *server.Workers) error {
+	return m.registry.Register(&jobLagCollector{workers: workers})
+}
+
+var jobLagDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "background_job", "lag_seconds"),
+	"Time since the background job last ran, beyond its interval. It grows while the job is stuck.",
+	[]string{"job"}, nil,
+)
+
+// jobLagCollector reads the workers' heartbeats at scrape time
+type jobLagCollector struct {
+	workers *server.Workers
+}
+
+func (c *jobLagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jobLagDesc
+}
+
+func (c *jobLagCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, heartbeat := range c.workers.Heartbeats() {
+		lag := now.Sub(heartbeat.Last) - heartbeat.Interval
+		if lag < 0 {
+			lag = 0
+		}
+		ch <- prometheus.MustNewConstMetric(jobLagDesc, prometheus.GaugeValue, lag.Seconds(), heartbeat.Name)
+	}
+}
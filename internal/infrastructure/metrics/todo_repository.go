@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+)
+
+// todoRepository times every call to the decorated repository and counts
+// its errors
+type todoRepository struct {
+	next    repositories.TodoRepository
+	metrics *Metrics
+}
+
+// NewTodoRepository decorates next with metrics
+func NewTodoRepository(next repositories.TodoRepository, metrics *Metrics) repositories.TodoRepository {
+	return &todoRepository{next: next, metrics: metrics}
+}
+
+func (r *todoRepository) observe(method string, start time.Time, err error) {
+	r.metrics.observeRepository("todo", method, start, err)
+}
+
+func (r *todoRepository) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	start := time.Now()
+	created, err := r.next.Create(ctx, todo)
+	r.observe("Create", start, err)
+	return created, err
+}
+
+func (r *todoRepository) GetAll(ctx context.Context) ([]*entities.Todo, error) {
+	start := time.Now()
+	todos, err := r.next.GetAll(ctx)
+	r.observe("GetAll", start, err)
+	return todos, err
+}
+
+func (r *todoRepository) GetByList(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	start := time.Now()
+	todos, err := r.next.GetByList(ctx, listID)
+	r.observe("GetByList", start, err)
+	return todos, err
+}
+
+func (r *todoRepository) GetByID(ctx context.Context, id string) (*entities.Todo, error) {
+	start := time.Now()
+	todo, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return todo, err
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	start := time.Now()
+	updated, err := r.next.Update(ctx, todo)
+	r.observe("Update", start, err)
+	return updated, err
+}
+
+func (r *todoRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("Delete", start, err)
+	return err
+}
+
+func (r *todoRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := r.next.WithinTransaction(ctx, fn)
+	r.observe("WithinTransaction", start, err)
+	return err
+}
+
+func (r *todoRepository) CommitBatch(ctx context.Context) error {
+	start := time.Now()
+	err := r.next.CommitBatch(ctx)
+	r.observe("CommitBatch", start, err)
+	return err
+}
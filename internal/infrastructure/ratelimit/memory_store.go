@@ -0,0 +1,98 @@
+// Package ratelimit provides the in-memory token bucket store for single-node
+// deployments. Clustered deployments share buckets through the database
+// instead, see database.NewSQLiteRateLimitStore.
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+)
+
+type bucketKey struct {
+	tenant  string
+	policy  string
+	subject string
+}
+
+// MemoryStore implements RateLimitStore in process memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[bucketKey]*entities.RateLimitBucket
+}
+
+// NewMemoryStore creates an empty in-memory rate limit store
+func NewMemoryStore() repositories.RateLimitStore {
+	return &MemoryStore{
+		buckets: make(map[bucketKey]*entities.RateLimitBucket),
+	}
+}
+
+// Take spends a token from the subject's bucket
+func (s *MemoryStore) Take(ctx context.Context, tenant, subject string, policy entities.RateLimitPolicy, now time.Time) (entities.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := bucketKey{tenant: tenant, policy: policy.Name, subject: subject}
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = entities.NewRateLimitBucket(tenant, policy, subject, now)
+		s.buckets[key] = bucket
+	}
+	return bucket.Take(policy, now), nil
+}
+
+// List returns copies of a tenant's buckets ordered by subject and policy
+func (s *MemoryStore) List(ctx context.Context, tenant, subject string) ([]*entities.RateLimitBucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buckets []*entities.RateLimitBucket
+	for key, bucket := range s.buckets {
+		if key.tenant != tenant || (subject != "" && key.subject != subject) {
+			continue
+		}
+		copied := *bucket
+		buckets = append(buckets, &copied)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Subject != buckets[j].Subject {
+			return buckets[i].Subject < buckets[j].Subject
+		}
+		return buckets[i].Policy < buckets[j].Policy
+	})
+	return buckets, nil
+}
+
+// Reset drops the buckets of subject
+func (s *MemoryStore) Reset(ctx context.Context, tenant, subject, policy string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var dropped int64
+	for key := range s.buckets {
+		if key.tenant == tenant && key.subject == subject && (policy == "" || key.policy == policy) {
+			delete(s.buckets, key)
+			dropped++
+		}
+	}
+	return dropped, nil
+}
+
+// DeleteFull drops buckets that have refilled completely
+func (s *MemoryStore) DeleteFull(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var dropped int64
+	for key, bucket := range s.buckets {
+		if !bucket.FullAt.After(now) {
+			delete(s.buckets, key)
+			dropped++
+		}
+	}
+	return dropped, nil
+}
@@ -0,0 +1,372 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/infrastructure/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// AlgorithmHS256 signs tokens with a shared HMAC-SHA256 secret
+	AlgorithmHS256 = "HS256"
+	// AlgorithmEdDSA signs tokens with an Ed25519 private key
+	AlgorithmEdDSA = "EdDSA"
+
+	minHS256SecretLength = 32
+)
+
+// ErrUnknownSigningKey is returned when a token names a key the manager does not hold
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// SigningKey is one access token signing key
+type SigningKey struct {
+	ID        string
+	Algorithm string
+
+	secret     []byte
+	privateKey ed25519.PrivateKey
+	createdAt  time.Time
+}
+
+// NewHS256Key creates an HMAC-SHA256 key from a shared secret
+func NewHS256Key(id string, secret []byte) (*SigningKey, error) {
+	if len(secret) < minHS256SecretLength {
+		return nil, fmt.Errorf("HS256 key %q: secret must be at least %d bytes", id, minHS256SecretLength)
+	}
+	return &SigningKey{ID: id, Algorithm: AlgorithmHS256, secret: secret, createdAt: time.Now()}, nil
+}
+
+// NewEdDSAKey creates an Ed25519 key
+func NewEdDSAKey(id string, privateKey ed25519.PrivateKey) *SigningKey {
+	return &SigningKey{ID: id, Algorithm: AlgorithmEdDSA, privateKey: privateKey, createdAt: time.Now()}
+}
+
+// LoadEdDSAKeyFile reads a PKCS#8 PEM encoded Ed25519 private key
+func LoadEdDSAKeyFile(id, path string) (*SigningKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("key file %s: no PEM data", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key file %s: %w", path, err)
+	}
+	privateKey, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key file %s: not an Ed25519 key", path)
+	}
+	return NewEdDSAKey(id, privateKey), nil
+}
+
+// GenerateSigningKey creates a random key for algorithm
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	id := uuid.New().String()
+	switch algorithm {
+	case AlgorithmHS256:
+		secret := make([]byte, minHS256SecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		return NewHS256Key(id, secret)
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		return NewEdDSAKey(id, privateKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodHS256
+}
+
+func (k *SigningKey) signingKey() interface{} {
+	if k.Algorithm == AlgorithmEdDSA {
+		return k.privateKey
+	}
+	return k.secret
+}
+
+func (k *SigningKey) verificationKey() interface{} {
+	if k.Algorithm == AlgorithmEdDSA {
+		return k.privateKey.Public()
+	}
+	return k.secret
+}
+
+// accessClaims are the claims carried by an access token
+type accessClaims struct {
+	SessionID string `json:"sid"`
+	Username  string `json:"name"`
+	Scope     string `json:"scope,omitempty"`
+	TenantID  string `json:"tid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTManager issues and verifies access tokens. It signs with the active key
+// and accepts tokens signed by any key it still holds, so that a rotated key
+// keeps verifying the tokens it issued until they have expired.
+type JWTManager struct {
+	issuer           string
+	audience         string
+	algorithm        string
+	accessTTL        time.Duration
+	rotationInterval time.Duration
+
+	mu     sync.RWMutex
+	active *SigningKey
+	keys   map[string]*SigningKey
+	// retiring maps rotated-out keys to the time they stop verifying
+	retiring map[string]time.Time
+}
+
+// NewJWTManager builds a manager from configuration. Without configured keys
+// it generates one and rotates it every cfg.RotationInterval.
+func NewJWTManager(cfg config.JWTConfig) (*JWTManager, error) {
+	if cfg.AccessTTL <= 0 {
+		return nil, errors.New("auth.jwt.access_ttl must be positive")
+	}
+
+	m := &JWTManager{
+		issuer:    cfg.Issuer,
+		audience:  cfg.Audience,
+		algorithm: cfg.Algorithm,
+		accessTTL: cfg.AccessTTL,
+		keys:      make(map[string]*SigningKey),
+		retiring:  make(map[string]time.Time),
+	}
+
+	if len(cfg.Keys) == 0 {
+		key, err := GenerateSigningKey(cfg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		m.rotationInterval = cfg.RotationInterval
+		m.active = key
+		m.keys[key.ID] = key
+		return m, nil
+	}
+
+	for _, keyCfg := range cfg.Keys {
+		key, err := loadSigningKey(keyCfg, cfg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := m.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		m.keys[key.ID] = key
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" && len(cfg.Keys) == 1 {
+		activeID = cfg.Keys[0].ID
+	}
+	active, ok := m.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("auth.jwt.active_key_id %q does not name a configured key", activeID)
+	}
+	m.active = active
+	return m, nil
+}
+
+func loadSigningKey(cfg config.JWTKeyConfig, defaultAlgorithm string) (*SigningKey, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("signing key without id")
+	}
+
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = defaultAlgorithm
+	}
+	switch algorithm {
+	case AlgorithmHS256:
+		return NewHS256Key(cfg.ID, []byte(cfg.Secret))
+	case AlgorithmEdDSA:
+		return LoadEdDSAKeyFile(cfg.ID, cfg.PrivateKeyFile)
+	default:
+		return nil, fmt.Errorf("signing key %q: unsupported algorithm %q", cfg.ID, algorithm)
+	}
+}
+
+// AccessTTL returns the lifetime of issued access tokens
+func (m *JWTManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// Issue signs an access token for principal
+func (m *JWTManager) Issue(principal *identity.Principal) (string, time.Time, error) {
+	key, err := m.signingKeyFor(time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(m.accessTTL)
+	claims := accessClaims{
+		SessionID: principal.SessionID,
+		Username:  principal.Username,
+		Scope:     identity.FormatScopes(principal.Scopes),
+		TenantID:  principal.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   principal.UserID,
+			Issuer:    m.issuer,
+			Audience:  jwt.ClaimStrings{m.audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.signingKey())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify checks an access token and returns the principal it was issued to.
+// Every failure is reported as identity.ErrUnauthenticated.
+func (m *JWTManager) Verify(token string) (*identity.Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, m.keyFunc,
+		jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithAudience(m.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", identity.ErrUnauthenticated, err)
+	}
+	if claims.Subject == "" || claims.SessionID == "" {
+		return nil, fmt.Errorf("%w: token without subject or session", identity.ErrUnauthenticated)
+	}
+
+	return &identity.Principal{
+		UserID:    claims.Subject,
+		Username:  claims.Username,
+		SessionID: claims.SessionID,
+		Scopes:    identity.ParseScopes(claims.Scope),
+		TenantID:  claims.TenantID,
+	}, nil
+}
+
+// keyFunc resolves the verification key named by the token's kid header and
+// refuses tokens whose alg does not match that key, so an EdDSA public key
+// can never be used as an HMAC secret
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("token algorithm %s does not match key %s", token.Method.Alg(), key.ID)
+	}
+	return key.verificationKey(), nil
+}
+
+// Rotate makes a freshly generated key active. The previous key keeps
+// verifying for one access token lifetime.
+func (m *JWTManager) Rotate() error {
+	key, err := GenerateSigningKey(m.algorithm)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotateLocked(key, time.Now())
+	return nil
+}
+
+func (m *JWTManager) rotateLocked(key *SigningKey, now time.Time) {
+	m.retiring[m.active.ID] = now.Add(m.accessTTL)
+	m.active = key
+	m.keys[key.ID] = key
+}
+
+// signingKeyFor returns the active key, rotating it first when it is due and
+// dropping retired keys whose tokens have all expired
+func (m *JWTManager) signingKeyFor(now time.Time) (*SigningKey, error) {
+	m.mu.RLock()
+	active := m.active
+	due := m.rotationInterval > 0 && now.Sub(active.createdAt) >= m.rotationInterval
+	retiring := len(m.retiring) > 0
+	m.mu.RUnlock()
+	if !due && !retiring {
+		return active, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, until := range m.retiring {
+		if !now.Before(until) {
+			delete(m.retiring, id)
+			delete(m.keys, id)
+		}
+	}
+	if m.rotationInterval > 0 && now.Sub(m.active.createdAt) >= m.rotationInterval {
+		key, err := GenerateSigningKey(m.algorithm)
+		if err != nil {
+			return nil, err
+		}
+		m.rotateLocked(key, now)
+	}
+	return m.active, nil
+}
+
+// JWKS returns the public keys that verify access tokens. HS256 keys are
+// symmetric and therefore never published.
+func (m *JWTManager) JWKS() identity.JSONWebKeySet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := identity.JSONWebKeySet{Keys: []identity.JSONWebKey{}}
+	for _, key := range m.keys {
+		if key.Algorithm != AlgorithmEdDSA {
+			continue
+		}
+		set.Keys = append(set.Keys, identity.JSONWebKey{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(key.privateKey.Public().(ed25519.PublicKey)),
+			KeyID:     key.ID,
+			Algorithm: AlgorithmEdDSA,
+			Use:       "sig",
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
@@ -0,0 +1,158 @@
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"todo-backend/internal/infrastructure/config"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// CertificateReloader serves a certificate loaded from files and reloads it
+// when they change, so that certificates can be renewed without a restart
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertificateReloader loads the PEM encoded certificate and key
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate and key again. The current certificate is
+// kept when they cannot be loaded, e.g. while only one of them was replaced.
+func (r *CertificateReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate returns the current certificate; it is used as tls.Config.GetCertificate
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Watch reloads the certificate whenever its files change until ctx is done.
+// The directories are watched rather than the files, which tools renewing
+// certificates usually replace instead of writing to. Failed reloads are
+// passed to onError.
+func (r *CertificateReloader) Watch(ctx context.Context, onError func(error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch TLS certificate: %w", err)
+	}
+	defer watcher.Close()
+
+	watched := map[string]bool{}
+	for _, file := range []string{r.certFile, r.keyFile} {
+		dir := filepath.Dir(file)
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch TLS certificate: %w", err)
+		}
+		watched[dir] = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			if !r.concerns(event.Name) || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				onError(err)
+			}
+		case err := <-watcher.Errors:
+			onError(err)
+		}
+	}
+}
+
+// concerns reports whether a change to path may change the certificate. Any
+// change in a directory holding Kubernetes style ..data symlinks counts.
+func (r *CertificateReloader) concerns(path string) bool {
+	path = filepath.Clean(path)
+	for _, file := range []string{r.certFile, r.keyFile} {
+		if path == filepath.Clean(file) || (filepath.Base(path) == "..data" && filepath.Dir(path) == filepath.Dir(file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTLSConfig builds the server TLS configuration serving certs. Client
+// certificates are verified against cfg.ClientCAFile when it is set.
+func NewTLSConfig(cfg config.TLSConfig, certs *CertificateReloader) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+	}
+
+	switch cfg.MinVersion {
+	case "", "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS version %q", cfg.MinVersion)
+	}
+
+	if len(cfg.CipherSuites) > 0 {
+		suites := map[string]uint16{}
+		for _, suite := range tls.CipherSuites() {
+			suites[suite.Name] = suite.ID
+		}
+		for _, name := range cfg.CipherSuites {
+			id, ok := suites[name]
+			if !ok {
+				return nil, fmt.Errorf("unsupported or insecure TLS cipher suite %q", name)
+			}
+			tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+		}
+	}
+
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+	raw, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	if !tlsConfig.ClientCAs.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("client CA file %s: no PEM certificates", cfg.ClientCAFile)
+	}
+	switch cfg.ClientAuth {
+	case "", "verify":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported TLS client auth %q", cfg.ClientAuth)
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,67 @@
+// Package server opens the listeners the HTTP server is served on.
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+)
+
+// SocketMode lets the owner and group of a Unix domain socket connect to it
+const SocketMode fs.FileMode = 0o660
+
+// ListenOptions selects where the server listens
+type ListenOptions struct {
+	// Address is a TCP host:port
+	Address string
+	// Socket is a Unix domain socket path; it takes precedence over Address
+	Socket string
+	// TLS serves HTTPS when set
+	TLS *tls.Config
+}
+
+// Listen opens the listener described by opts. A socket file left over by a
+// previous run is replaced.
+func Listen(opts ListenOptions) (net.Listener, error) {
+	var ln net.Listener
+	var err error
+	if opts.Socket != "" {
+		ln, err = listenUnix(opts.Socket)
+	} else {
+		ln, err = net.Listen("tcp", opts.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.TLS != nil {
+		ln = tls.NewListener(ln, opts.TLS)
+	}
+	return ln, nil
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to stat socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, SocketMode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return ln, nil
+}
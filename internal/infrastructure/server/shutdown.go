@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Readiness tells load balancers whether this instance accepts new requests
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// SetReady marks the instance ready or not
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Ready reports whether the instance accepts new requests
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+// Check fails while the instance does not accept new requests
+func (r *Readiness) Check(context.Context) error {
+	if !r.Ready() {
+		return errors.New("not serving: starting or shutting down")
+	}
+	return nil
+}
+
+// ShutdownOptions configures graceful shutdown
+type ShutdownOptions struct {
+	// Readiness is ready while serving and fails as soon as shutdown starts
+	Readiness *Readiness
+	// DrainDelay keeps serving new requests after readiness failed, so that
+	// load balancers notice and stop routing here before the listener closes
+	DrainDelay time.Duration
+	// Timeout bounds how long requests in flight may take to complete, and
+	// then how long cleanup may take; zero waits indefinitely
+	Timeout time.Duration
+}
+
+// Serve serves app on ln until ctx is done, then shuts it down gracefully:
+// readiness fails, the listener closes after opts.DrainDelay and requests in
+// flight are allowed to complete. The cleanup functions run in order once no
+// request is left, e.g. to stop background workers and then close the
+// database.
+func Serve(ctx context.Context, app *fiber.App, ln net.Listener, opts ShutdownOptions, cleanup ...func(ctx context.Context) error) error {
+	readiness := opts.Readiness
+	if readiness == nil {
+		readiness = &Readiness{}
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Listener(ln)
+	}()
+	readiness.SetReady(true)
+
+	select {
+	case err := <-served:
+		readiness.SetReady(false)
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	readiness.SetReady(false)
+	if opts.DrainDelay > 0 {
+		time.Sleep(opts.DrainDelay)
+	}
+
+	var errs []error
+	drainCtx, cancel := withTimeout(opts.Timeout)
+	defer cancel()
+	if err := app.ShutdownWithContext(drainCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+	}
+	if err := <-served; err != nil {
+		errs = append(errs, fmt.Errorf("failed to serve: %w", err))
+	}
+
+	cleanupCtx, cancel := withTimeout(opts.Timeout)
+	defer cancel()
+	for _, fn := range cleanup {
+		if err := fn(cleanupCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func withTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
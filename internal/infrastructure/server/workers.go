@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// staleHeartbeats is how many intervals a periodic worker may miss before it
+// is reported as stuck
+const staleHeartbeats = 3
+
+// Workers runs background workers, such as periodic purges, until they are
+// stopped on shutdown
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.Mutex
+	heartbeats map[string]*heartbeat
+}
+
+// heartbeat records when a periodic worker last completed a tick
+type heartbeat struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// NewWorkers creates an empty set of workers
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel, heartbeats: make(map[string]*heartbeat)}
+}
+
+// Go runs run in the background. run must return once ctx is done.
+func (w *Workers) Go(run func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		run(w.ctx)
+	}()
+}
+
+// Every runs tick every interval until the workers are stopped. A tick in
+// progress is allowed to finish, with a done context. Each completed tick is
+// a heartbeat of the worker called name.
+func (w *Workers) Every(name string, interval time.Duration, tick func(ctx context.Context)) {
+	w.beat(name, interval)
+	w.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tick(ctx)
+				w.beat(name, interval)
+			}
+		}
+	})
+}
+
+func (w *Workers) beat(name string, interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.heartbeats[name] = &heartbeat{interval: interval, last: time.Now()}
+}
+
+// Heartbeat is when a periodic worker last completed a tick
+type Heartbeat struct {
+	Name     string
+	Interval time.Duration
+	Last     time.Time
+}
+
+// Heartbeats returns the last heartbeat of every periodic worker, by name
+func (w *Workers) Heartbeats() []Heartbeat {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	heartbeats := make([]Heartbeat, 0, len(w.heartbeats))
+	for name, beat := range w.heartbeats {
+		heartbeats = append(heartbeats, Heartbeat{Name: name, Interval: beat.interval, Last: beat.last})
+	}
+	sort.Slice(heartbeats, func(i, j int) bool { return heartbeats[i].Name < heartbeats[j].Name })
+	return heartbeats
+}
+
+// CheckHeartbeats reports the periodic workers that missed several
+// heartbeats, e.g. because a tick is stuck
+func (w *Workers) CheckHeartbeats(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	var stale []string
+	for name, beat := range w.heartbeats {
+		if now.Sub(beat.last) > staleHeartbeats*beat.interval {
+			stale = append(stale, fmt.Sprintf("%s (last heartbeat %s ago)", name, now.Sub(beat.last).Round(time.Second)))
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return fmt.Errorf("stuck workers: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// Stop tells the workers to stop and waits until they have, or until ctx is done
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background workers did not stop: %w", ctx.Err())
+	}
+}
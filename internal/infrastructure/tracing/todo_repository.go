@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("todo-backend/internal/infrastructure/tracing")
+
+// todoRepository wraps every call to the decorated repository in a span
+type todoRepository struct {
+	next repositories.TodoRepository
+}
+
+// NewTodoRepository decorates next with tracing
+func NewTodoRepository(next repositories.TodoRepository) repositories.TodoRepository {
+	return &todoRepository{next: next}
+}
+
+func (r *todoRepository) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "TodoRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.system", "sqlite"))...),
+	)
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *todoRepository) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	ctx, span := r.start(ctx, "Create")
+	created, err := r.next.Create(ctx, todo)
+	end(span, err)
+	return created, err
+}
+
+func (r *todoRepository) GetAll(ctx context.Context) ([]*entities.Todo, error) {
+	ctx, span := r.start(ctx, "GetAll")
+	todos, err := r.next.GetAll(ctx)
+	span.SetAttributes(attribute.Int("todo.count", len(todos)))
+	end(span, err)
+	return todos, err
+}
+
+func (r *todoRepository) GetByList(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	ctx, span := r.start(ctx, "GetByList", attribute.String("todo.list_id", listID))
+	todos, err := r.next.GetByList(ctx, listID)
+	span.SetAttributes(attribute.Int("todo.count", len(todos)))
+	end(span, err)
+	return todos, err
+}
+
+func (r *todoRepository) GetByID(ctx context.Context, id string) (*entities.Todo, error) {
+	ctx, span := r.start(ctx, "GetByID", attribute.String("todo.id", id))
+	todo, err := r.next.GetByID(ctx, id)
+	end(span, err)
+	return todo, err
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	ctx, span := r.start(ctx, "Update", attribute.String("todo.id", todo.ID))
+	updated, err := r.next.Update(ctx, todo)
+	end(span, err)
+	return updated, err
+}
+
+func (r *todoRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := r.start(ctx, "Delete", attribute.String("todo.id", id))
+	err := r.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *todoRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := r.start(ctx, "WithinTransaction")
+	err := r.next.WithinTransaction(ctx, fn)
+	end(span, err)
+	return err
+}
+
+func (r *todoRepository) CommitBatch(ctx context.Context) error {
+	ctx, span := r.start(ctx, "CommitBatch")
+	err := r.next.CommitBatch(ctx)
+	end(span, err)
+	return err
+}
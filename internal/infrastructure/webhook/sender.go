@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+const (
+	// SignatureHeader carries the timestamp and signature of a delivery
+	SignatureHeader = "X-Webhook-Signature"
+	// EventIDHeader identifies the event; redeliveries carry the same ID, so
+	// receivers can drop duplicates
+	EventIDHeader = "X-Webhook-ID"
+	// EventTypeHeader names the event type
+	EventTypeHeader = "X-Webhook-Event"
+	// RequestIDHeader carries the ID of the request that caused the event
+	RequestIDHeader = "X-Request-ID"
+	// TraceParentHeader continues the trace of the request that caused the event
+	TraceParentHeader = "traceparent"
+
+	userAgent = "todo-backend-webhooks/1.0"
+	// maxResponseBody bounds what is read of a receiver's response
+	maxResponseBody = 64 << 10
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify for deliveries that were not
+	// signed with the secret, or whose timestamp is out of tolerance
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrPrivateAddress is returned for webhooks resolving to addresses
+	// outside the public internet
+	ErrPrivateAddress = errors.New("webhook address is not public")
+)
+
+// HTTPSender POSTs deliveries to webhooks, signed with their secrets
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a sender giving up on a receiver after timeout. Unless
+// allowPrivateNetworks is set, it refuses to connect to loopback, private and
+// link-local addresses, so that webhooks cannot reach internal services.
+func NewHTTPSender(timeout time.Duration, allowPrivateNetworks bool) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = refusePrivateAddresses
+	}
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// A proxy would connect on our behalf, past the address check
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// Redirects are reported as failures rather than followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send implements usecases.WebhookSender
+func (s *HTTPSender) Send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, time.Now(), delivery.Payload))
+	if delivery.RequestID != "" {
+		req.Header.Set(RequestIDHeader, delivery.RequestID)
+	}
+	if delivery.TraceParent != "" {
+		req.Header.Set(TraceParentHeader, delivery.TraceParent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header of body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">"
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+// Verify checks a signature header against body. Deliveries signed more than
+// tolerance away from now are rejected, so that a captured delivery cannot
+// be replayed later.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, candidate := range signatures {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// refusePrivateAddresses is a net.Dialer Control function. It runs after
+// name resolution, so it also catches public names pointing inwards.
+func refusePrivateAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyResponse describes an API key without its secret
+type APIKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expiresAt,omitempty"`
+	LastUsedAt string   `json:"lastUsedAt,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+// CreatedAPIKeyResponse is returned once, when the key is created
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ToAPIKeyResponse converts entity to its public representation
+func ToAPIKeyResponse(key *entities.APIKey) APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	response := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		CreatedAt: formatTimeForContract(key.CreatedAt),
+	}
+	if key.ExpiresAt != nil {
+		response.ExpiresAt = formatTimeForContract(*key.ExpiresAt)
+	}
+	if key.LastUsedAt != nil {
+		response.LastUsedAt = formatTimeForContract(*key.LastUsedAt)
+	}
+	return response
+}
+
+// ToAPIKeyResponses converts a slice of keys
+func ToAPIKeyResponses(keys []*entities.APIKey) []APIKeyResponse {
+	responses := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = ToAPIKeyResponse(key)
+	}
+	return responses
+}
@@ -0,0 +1,71 @@
+package dto
+
+import "todo-backend/internal/domain/entities"
+
+// AuditQueryRequest filters GET /api/admin/audit
+type AuditQueryRequest struct {
+	ActorID  string `query:"actor"`
+	Action   string `query:"action"`
+	TargetID string `query:"target"`
+	// Since and Until are RFC 3339 times
+	Since string `query:"since"`
+	Until string `query:"until"`
+	// BeforeSeq pages backwards from the seq of the oldest event already seen
+	BeforeSeq int64 `query:"before"`
+	Limit     int   `query:"limit"`
+}
+
+type AuditEventResponse struct {
+	Seq        int64  `json:"seq"`
+	ID         string `json:"id"`
+	ActorID    string `json:"actorId,omitempty"`
+	APIKeyID   string `json:"apiKeyId,omitempty"`
+	Action     string `json:"action"`
+	TargetID   string `json:"targetId,omitempty"`
+	BeforeHash string `json:"beforeHash,omitempty"`
+	AfterHash  string `json:"afterHash,omitempty"`
+	Status     int    `json:"status"`
+	RequestID  string `json:"requestId"`
+	ClientIP   string `json:"clientIp"`
+	OccurredAt string `json:"occurredAt"`
+	PrevHash   string `json:"prevHash"`
+	Hash       string `json:"hash"`
+}
+
+type AuditChainProblemResponse struct {
+	Seq    int64  `json:"seq"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+type AuditVerificationResponse struct {
+	OK       bool                        `json:"ok"`
+	Events   int64                       `json:"events"`
+	LastSeq  int64                       `json:"lastSeq"`
+	LastHash string                      `json:"lastHash"`
+	Problems []AuditChainProblemResponse `json:"problems"`
+}
+
+// ToAuditEventResponses converts a slice of audit events
+func ToAuditEventResponses(events []*entities.AuditEvent) []AuditEventResponse {
+	responses := make([]AuditEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = AuditEventResponse{
+			Seq:        event.Seq,
+			ID:         event.ID,
+			ActorID:    event.ActorID,
+			APIKeyID:   event.APIKeyID,
+			Action:     event.Action,
+			TargetID:   event.TargetID,
+			BeforeHash: event.BeforeHash,
+			AfterHash:  event.AfterHash,
+			Status:     event.Status,
+			RequestID:  event.RequestID,
+			ClientIP:   event.ClientIP,
+			OccurredAt: formatTimeForContract(event.OccurredAt),
+			PrevHash:   event.PrevHash,
+			Hash:       event.Hash,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+type RegisterRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=64"`
+	Password string `json:"password" validate:"required,min=8,max=128"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=128"`
+}
+
+// UserResponse is the public representation of a user
+type UserResponse struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// RefreshRequest exchanges a refresh token for a new token pair
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// LoginResponse carries the token pair issued at login or refresh
+type LoginResponse struct {
+	AccessToken      string       `json:"accessToken"`
+	TokenType        string       `json:"tokenType"`
+	ExpiresAt        string       `json:"expiresAt"`
+	RefreshToken     string       `json:"refreshToken"`
+	RefreshExpiresAt string       `json:"refreshExpiresAt"`
+	User             UserResponse `json:"user"`
+}
+
+// ToUserResponse converts entity to the public user representation
+func ToUserResponse(user *entities.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		CreatedAt: formatTimeForContract(user.CreatedAt),
+	}
+}
+
+// ToLoginResponse builds the response for a freshly issued token pair
+func ToLoginResponse(user *entities.User, accessToken string, expiresAt time.Time, refreshToken string, refreshExpiresAt time.Time) LoginResponse {
+	return LoginResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        formatTimeForContract(expiresAt),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: formatTimeForContract(refreshExpiresAt),
+		User:             ToUserResponse(user),
+	}
+}
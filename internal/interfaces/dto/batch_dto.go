@@ -0,0 +1,42 @@
+package dto
+
+// Batch operation names accepted by POST /api/todos/batch
+const (
+	BatchOpCreate   = "create"
+	BatchOpUpdate   = "update"
+	BatchOpDelete   = "delete"
+	BatchOpComplete = "complete"
+)
+
+// BatchRequest is an ordered list of todo operations
+type BatchRequest struct {
+	// Atomic applies all operations in one transaction; it is set from the query string
+	Atomic     bool             `json:"-"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is a single create, update, delete or complete operation
+type BatchOperation struct {
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Text   string `json:"text,omitempty"`
+	ListID string `json:"listId,omitempty"` // create only
+}
+
+// BatchItemResult reports the outcome of one operation
+type BatchItemResult struct {
+	Index  int                 `json:"index"`
+	Op     string              `json:"op"`
+	ID     string              `json:"id,omitempty"`
+	Status int                 `json:"status"`
+	Todo   *TodoDetailResponse `json:"todo,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// BatchResponse reports the outcome of a whole batch
+type BatchResponse struct {
+	Atomic    bool              `json:"atomic"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
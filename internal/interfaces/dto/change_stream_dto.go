@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"todo-backend/internal/domain/events"
+)
+
+// ChangeEventResponse is the data of an event on the change stream
+type ChangeEventResponse struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Changes    []ChangeResponse `json:"changes"`
+	OccurredAt string           `json:"occurredAt"`
+	RequestID  string           `json:"requestId,omitempty"`
+}
+
+// ChangeResponse is one todo changed by a change stream event
+type ChangeResponse struct {
+	Type   string `json:"type"`
+	TodoID string `json:"todoId"`
+	ListID string `json:"listId,omitempty"`
+	// Todo is omitted once the todo was deleted
+	Todo *TodoDetailResponse `json:"todo,omitempty"`
+}
+
+// ToChangeEventResponse converts a change event to its stream representation
+func ToChangeEventResponse(event events.ChangeEvent) ChangeEventResponse {
+	changes := make([]ChangeResponse, len(event.Changes))
+	for i, change := range event.Changes {
+		changes[i] = ChangeResponse{
+			Type:   string(change.Type),
+			TodoID: change.TodoID,
+			ListID: change.ListID,
+		}
+		if change.Todo != nil {
+			changes[i].Todo = ToTodoDetailResponse(change.Todo)
+		}
+	}
+	return ChangeEventResponse{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		Changes:    changes,
+		OccurredAt: formatTimeForContract(event.OccurredAt),
+		RequestID:  event.RequestID,
+	}
+}
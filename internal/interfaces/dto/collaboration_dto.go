@@ -0,0 +1,57 @@
+package dto
+
+import "encoding/json"
+
+// Types of the messages clients send on /api/ws
+const (
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+	MessageCreate      = "create"
+	MessagePatch       = "patch"
+	MessageBatch       = "batch"
+)
+
+// Types of the messages the server sends on /api/ws
+const (
+	MessageResult   = "result"
+	MessageError    = "error"
+	MessageChange   = "change"
+	MessagePresence = "presence"
+)
+
+// ClientMessage is a message from a collaborative client. Its fields depend
+// on its type.
+type ClientMessage struct {
+	// ID is echoed in the result or error answering the message
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	ListID string `json:"listId,omitempty"`
+	TodoID string `json:"todoId,omitempty"`
+	Text   string `json:"text,omitempty"`
+	// Patch is a JSON Merge Patch object or a JSON Patch array
+	Patch      json.RawMessage  `json:"patch,omitempty"`
+	Atomic     bool             `json:"atomic,omitempty"`
+	Operations []BatchOperation `json:"operations,omitempty"`
+}
+
+// ServerMessage is a message to a collaborative client. Its fields depend
+// on its type.
+type ServerMessage struct {
+	Type string `json:"type"`
+	// ID is the ID of the client message answered
+	ID      string               `json:"id,omitempty"`
+	ListID  string               `json:"listId,omitempty"`
+	Todo    *TodoDetailResponse  `json:"todo,omitempty"`
+	Batch   *BatchResponse       `json:"batch,omitempty"`
+	Change  *ChangeEventResponse `json:"change,omitempty"`
+	Viewers []ViewerResponse     `json:"viewers,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	// Status is the HTTP status code the error would have had
+	Status int `json:"status,omitempty"`
+}
+
+// ViewerResponse is a user viewing a list
+type ViewerResponse struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+}
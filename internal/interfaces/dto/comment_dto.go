@@ -0,0 +1,35 @@
+package dto
+
+import "todo-backend/internal/domain/entities"
+
+type CreateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
+
+type CommentResponse struct {
+	ID        string `json:"id"`
+	TodoID    string `json:"todoId"`
+	AuthorID  string `json:"authorId"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// ToCommentResponse converts entity to its public representation
+func ToCommentResponse(comment *entities.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		TodoID:    comment.TodoID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		CreatedAt: formatTimeForContract(comment.CreatedAt),
+	}
+}
+
+// ToCommentResponses converts a slice of comments
+func ToCommentResponses(comments []*entities.Comment) []CommentResponse {
+	responses := make([]CommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = ToCommentResponse(comment)
+	}
+	return responses
+}
@@ -0,0 +1,17 @@
+package dto
+
+// HealthResponse is the body of the /livez, /readyz and /startupz probes
+type HealthResponse struct {
+	Status string `json:"status"`
+	// Checks is only listed with ?verbose
+	Checks []HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+	Cached     bool    `json:"cached"`
+	CheckedAt  string  `json:"checkedAt"`
+}
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+type CreateListRequest struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor commenter viewer"`
+}
+
+type CreateInviteRequest struct {
+	Role      string     `json:"role" validate:"required,oneof=editor commenter viewer"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type AcceptInviteRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ListResponse describes a list together with the caller's role on it
+type ListResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	OwnerID   string `json:"ownerId"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type ListMemberResponse struct {
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// InviteResponse describes an invite without its token
+type InviteResponse struct {
+	ID         string `json:"id"`
+	Role       string `json:"role"`
+	CreatedBy  string `json:"createdBy"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	RevokedAt  string `json:"revokedAt,omitempty"`
+	AcceptedAt string `json:"acceptedAt,omitempty"`
+	AcceptedBy string `json:"acceptedBy,omitempty"`
+}
+
+// CreatedInviteResponse is returned once, when the invite is created
+type CreatedInviteResponse struct {
+	InviteResponse
+	Token string `json:"token"`
+}
+
+// ToListResponse converts a membership to its public representation
+func ToListResponse(membership *entities.ListMembership) ListResponse {
+	return ListResponse{
+		ID:        membership.List.ID,
+		Name:      membership.List.Name,
+		OwnerID:   membership.List.OwnerID,
+		Role:      string(membership.Role),
+		CreatedAt: formatTimeForContract(membership.List.CreatedAt),
+	}
+}
+
+// ToListResponses converts a slice of memberships
+func ToListResponses(memberships []*entities.ListMembership) []ListResponse {
+	responses := make([]ListResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = ToListResponse(membership)
+	}
+	return responses
+}
+
+// ToListMemberResponses converts a slice of members
+func ToListMemberResponses(members []*entities.ListMember) []ListMemberResponse {
+	responses := make([]ListMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = ListMemberResponse{
+			UserID:    member.UserID,
+			Username:  member.Username,
+			Role:      string(member.Role),
+			CreatedAt: formatTimeForContract(member.CreatedAt),
+		}
+	}
+	return responses
+}
+
+// ToInviteResponse converts entity to its public representation
+func ToInviteResponse(invite *entities.ListInvite) InviteResponse {
+	response := InviteResponse{
+		ID:         invite.ID,
+		Role:       string(invite.Role),
+		CreatedBy:  invite.CreatedBy,
+		CreatedAt:  formatTimeForContract(invite.CreatedAt),
+		ExpiresAt:  formatTimeForContract(invite.ExpiresAt),
+		AcceptedBy: invite.AcceptedBy,
+	}
+	if invite.RevokedAt != nil {
+		response.RevokedAt = formatTimeForContract(*invite.RevokedAt)
+	}
+	if invite.AcceptedAt != nil {
+		response.AcceptedAt = formatTimeForContract(*invite.AcceptedAt)
+	}
+	return response
+}
+
+// ToInviteResponses converts a slice of invites
+func ToInviteResponses(invites []*entities.ListInvite) []InviteResponse {
+	responses := make([]InviteResponse, len(invites))
+	for i, invite := range invites {
+		responses[i] = ToInviteResponse(invite)
+	}
+	return responses
+}
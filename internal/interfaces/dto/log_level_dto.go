@@ -0,0 +1,10 @@
+package dto
+
+// LogLevelRequest changes the log level to debug, info, warn or error
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
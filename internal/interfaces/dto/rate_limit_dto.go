@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"math"
+	"todo-backend/internal/domain/entities"
+)
+
+type RateLimitBucketResponse struct {
+	Policy    string `json:"policy"`
+	Subject   string `json:"subject"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	UpdatedAt string `json:"updatedAt"`
+	FullAt    string `json:"fullAt"`
+}
+
+type RateLimitResetResponse struct {
+	Reset int64 `json:"reset"`
+}
+
+// ToRateLimitBucketResponse converts a bucket filled by policy
+func ToRateLimitBucketResponse(bucket *entities.RateLimitBucket, policy entities.RateLimitPolicy) RateLimitBucketResponse {
+	return RateLimitBucketResponse{
+		Policy:    bucket.Policy,
+		Subject:   bucket.Subject,
+		Limit:     policy.Capacity(),
+		Remaining: int(math.Floor(bucket.Tokens)),
+		UpdatedAt: formatTimeForContract(bucket.UpdatedAt),
+		FullAt:    formatTimeForContract(bucket.FullAt),
+	}
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+	"todo-backend/internal/domain/entities"
+)
+
+type CreateShareLinkRequest struct {
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	Password      string     `json:"password,omitempty" validate:"omitempty,min=8,max=128"`
+	HideCompleted bool       `json:"hideCompleted"`
+}
+
+// ShareLinkResponse describes a share link without its token or password
+type ShareLinkResponse struct {
+	ID                string `json:"id"`
+	CreatedBy         string `json:"createdBy"`
+	CreatedAt         string `json:"createdAt"`
+	ExpiresAt         string `json:"expiresAt,omitempty"`
+	RevokedAt         string `json:"revokedAt,omitempty"`
+	PasswordProtected bool   `json:"passwordProtected"`
+	HideCompleted     bool   `json:"hideCompleted"`
+}
+
+// CreatedShareLinkResponse is returned once, when the link is created
+type CreatedShareLinkResponse struct {
+	ShareLinkResponse
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+type ShareLinkAccessResponse struct {
+	Outcome    string `json:"outcome"`
+	IPAddress  string `json:"ipAddress"`
+	UserAgent  string `json:"userAgent"`
+	AccessedAt string `json:"accessedAt"`
+}
+
+// SharedListResponse is what anonymous visitors of a share link see. It
+// deliberately carries no IDs, owners or members.
+type SharedListResponse struct {
+	Name  string               `json:"name"`
+	Todos []SharedTodoResponse `json:"todos"`
+}
+
+type SharedTodoResponse struct {
+	Text      string `json:"text"`
+	Completed bool   `json:"completed"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// ToShareLinkResponse converts entity to its public representation
+func ToShareLinkResponse(link *entities.ShareLink) ShareLinkResponse {
+	response := ShareLinkResponse{
+		ID:                link.ID,
+		CreatedBy:         link.CreatedBy,
+		CreatedAt:         formatTimeForContract(link.CreatedAt),
+		PasswordProtected: link.HasPassword(),
+		HideCompleted:     link.HideCompleted,
+	}
+	if link.ExpiresAt != nil {
+		response.ExpiresAt = formatTimeForContract(*link.ExpiresAt)
+	}
+	if link.RevokedAt != nil {
+		response.RevokedAt = formatTimeForContract(*link.RevokedAt)
+	}
+	return response
+}
+
+// ToShareLinkResponses converts a slice of share links
+func ToShareLinkResponses(links []*entities.ShareLink) []ShareLinkResponse {
+	responses := make([]ShareLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = ToShareLinkResponse(link)
+	}
+	return responses
+}
+
+// ToShareLinkAccessResponses converts a slice of access log entries
+func ToShareLinkAccessResponses(accesses []*entities.ShareLinkAccess) []ShareLinkAccessResponse {
+	responses := make([]ShareLinkAccessResponse, len(accesses))
+	for i, access := range accesses {
+		responses[i] = ShareLinkAccessResponse{
+			Outcome:    string(access.Outcome),
+			IPAddress:  access.IPAddress,
+			UserAgent:  access.UserAgent,
+			AccessedAt: formatTimeForContract(access.AccessedAt),
+		}
+	}
+	return responses
+}
+
+// ToSharedListResponse converts a shared list to what its visitors see
+func ToSharedListResponse(name string, todos []*entities.Todo) SharedListResponse {
+	response := SharedListResponse{
+		Name:  name,
+		Todos: make([]SharedTodoResponse, len(todos)),
+	}
+	for i, todo := range todos {
+		response.Todos[i] = SharedTodoResponse{
+			Text:      todo.Text,
+			Completed: todo.Completed,
+			CreatedAt: formatTimeForContract(todo.CreatedAt),
+		}
+	}
+	return response
+}
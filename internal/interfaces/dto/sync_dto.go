@@ -0,0 +1,72 @@
+package dto
+
+// Mutation operations accepted by POST /api/sync
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+// Outcomes of a sync mutation
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"
+	SyncStatusRejected = "rejected"
+)
+
+// SyncRequest is what an offline client sends when it comes back online
+type SyncRequest struct {
+	// SyncToken is the token of the client's last sync, empty on the first one
+	SyncToken string         `json:"syncToken,omitempty"`
+	Mutations []SyncMutation `json:"mutations,omitempty"`
+}
+
+// SyncMutation is a change the client made while offline
+type SyncMutation struct {
+	// ClientID identifies the mutation in the client's queue and is echoed in its result
+	ClientID string `json:"clientId"`
+	Op       string `json:"op"`
+	// TodoID is generated by the client for a create
+	TodoID string `json:"todoId"`
+	ListID string `json:"listId,omitempty"` // create only
+	// BaseVersion is the version of the todo the client changed (update and delete)
+	BaseVersion int64   `json:"baseVersion,omitempty"`
+	Text        *string `json:"text,omitempty"`
+	Completed   *bool   `json:"completed,omitempty"`
+}
+
+// SyncResponse carries the changes the client missed and the outcome of its mutations
+type SyncResponse struct {
+	// SyncToken is sent with the next sync
+	SyncToken string `json:"syncToken"`
+	// Full is set when Todos is every todo of the caller rather than the
+	// changed ones, so the client replaces its copy
+	Full bool `json:"full"`
+	// HasMore is set when more changes are waiting; sync again right away
+	HasMore        bool                  `json:"hasMore"`
+	Todos          []*TodoDetailResponse `json:"todos"`
+	DeletedTodoIDs []string              `json:"deletedTodoIds"`
+	// RemovedListIDs are the lists the caller lost access to, with their todos
+	RemovedListIDs []string             `json:"removedListIds"`
+	Results        []SyncMutationResult `json:"results"`
+}
+
+// SyncMutationResult reports the outcome of one mutation
+type SyncMutationResult struct {
+	ClientID string `json:"clientId"`
+	TodoID   string `json:"todoId,omitempty"`
+	Status   string `json:"status"`
+	// Todo is the todo as the server has it after the mutation
+	Todo *TodoDetailResponse `json:"todo,omitempty"`
+	// Deleted is set when the todo no longer exists on the server
+	Deleted   bool                `json:"deleted,omitempty"`
+	Conflicts []SyncFieldConflict `json:"conflicts,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// SyncFieldConflict is a field the client and another writer both changed
+type SyncFieldConflict struct {
+	Field       string      `json:"field"`
+	ClientValue interface{} `json:"clientValue"`
+	ServerValue interface{} `json:"serverValue"`
+}
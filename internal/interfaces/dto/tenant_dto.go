@@ -0,0 +1,36 @@
+package dto
+
+import "todo-backend/internal/domain/entities"
+
+// TenantResponse describes the caller's tenant, its usage and its quota.
+// A zero limit means unlimited.
+type TenantResponse struct {
+	ID    string              `json:"id"`
+	Usage TenantUsageResponse `json:"usage"`
+	Quota TenantQuotaResponse `json:"quota"`
+}
+
+type TenantUsageResponse struct {
+	Todos        int64 `json:"todos"`
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+type TenantQuotaResponse struct {
+	MaxTodos        int64 `json:"maxTodos"`
+	MaxStorageBytes int64 `json:"maxStorageBytes"`
+}
+
+// ToTenantResponse converts a tenant's usage and quota to their public representation
+func ToTenantResponse(id string, usage *entities.TenantUsage, quota entities.TenantQuota) TenantResponse {
+	return TenantResponse{
+		ID: id,
+		Usage: TenantUsageResponse{
+			Todos:        usage.Todos,
+			StorageBytes: usage.StorageBytes,
+		},
+		Quota: TenantQuotaResponse{
+			MaxTodos:        quota.MaxTodos,
+			MaxStorageBytes: quota.MaxStorageBytes,
+		},
+	}
+}
@@ -6,11 +6,36 @@ import (
 
 type CreateTodoRequest struct {
 	Text string `json:"text" validate:"required,min=1,max=500"`
+	// ListID places the todo on a shared list instead of the caller's personal todos
+	ListID string `json:"listId,omitempty"`
 }
 
 // Removed: TodoResponse and TodoListResponse structs
 // These are replaced by ContractTodoResponse in contract_dto.go for better contract compliance
 
+// TodoDetailResponse is the full todo representation used by endpoints outside the frontend contract
+type TodoDetailResponse struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Completed bool   `json:"completed"`
+	Version   int64  `json:"version"`
+	ListID    string `json:"listId,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// Patch formats accepted by PATCH /api/todos/:id
+const (
+	PatchFormatMerge     = "merge-patch"
+	PatchFormatJSONPatch = "json-patch"
+)
+
+// PatchTodoRequest carries a raw patch document and its format
+type PatchTodoRequest struct {
+	Format string
+	Patch  []byte
+}
+
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
@@ -25,6 +50,19 @@ func (req *CreateTodoRequest) ToEntity() *entities.Todo {
 	return entities.NewTodo(req.Text)
 }
 
+// ToTodoDetailResponse converts entity to the full response representation
+func ToTodoDetailResponse(todo *entities.Todo) *TodoDetailResponse {
+	return &TodoDetailResponse{
+		ID:        todo.ID,
+		Text:      todo.Text,
+		Completed: todo.Completed,
+		Version:   todo.Version,
+		ListID:    todo.ListID,
+		CreatedAt: formatTimeForContract(todo.CreatedAt),
+		UpdatedAt: formatTimeForContract(todo.UpdatedAt),
+	}
+}
+
 func SuccessResponse(data interface{}, message string) APIResponse {
 	return APIResponse{
 		Success: true,
@@ -38,4 +76,4 @@ func ErrorResponse(err string) APIResponse {
 		Success: false,
 		Error:   err,
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"todo-backend/internal/domain/entities"
+)
+
+type CreateWebhookRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048"`
+	// EventTypes limits the deliveries to these event types; empty delivers all
+	EventTypes []string `json:"eventTypes,omitempty"`
+	// ListID limits the deliveries to the todos of a list
+	ListID string `json:"listId,omitempty"`
+}
+
+// WebhookResponse describes a webhook without its secret
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	ListID     string   `json:"listId,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+// CreatedWebhookResponse is returned once, when the webhook is created
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID            string `json:"id"`
+	EventID       string `json:"eventId"`
+	EventType     string `json:"eventType"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt string `json:"nextAttemptAt,omitempty"`
+	LastError     string `json:"lastError,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+	DeliveredAt   string `json:"deliveredAt,omitempty"`
+}
+
+type WebhookAttemptResponse struct {
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"durationMs"`
+	AttemptedAt string `json:"attemptedAt"`
+}
+
+// WebhookDeliveryDetailResponse is a delivery with its payload and delivery log
+type WebhookDeliveryDetailResponse struct {
+	WebhookDeliveryResponse
+	Payload string                   `json:"payload"`
+	Log     []WebhookAttemptResponse `json:"log"`
+}
+
+// WebhookEventPayload is the body POSTed to webhooks
+type WebhookEventPayload struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	OccurredAt string              `json:"occurredAt"`
+	RequestID  string              `json:"requestId,omitempty"`
+	TodoID     string              `json:"todoId"`
+	ListID     string              `json:"listId,omitempty"`
+	Todo       *TodoDetailResponse `json:"todo,omitempty"`
+}
+
+// ToWebhookResponse converts entity to its public representation
+func ToWebhookResponse(webhook *entities.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: append([]string{}, webhook.EventTypes...),
+		ListID:     webhook.ListID,
+		CreatedAt:  formatTimeForContract(webhook.CreatedAt),
+	}
+}
+
+// ToWebhookResponses converts a slice of webhooks
+func ToWebhookResponses(webhooks []*entities.Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = ToWebhookResponse(webhook)
+	}
+	return responses
+}
+
+// ToWebhookDeliveryResponse converts entity to its public representation
+func ToWebhookDeliveryResponse(delivery *entities.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Status:    string(delivery.Status),
+		Attempts:  delivery.Attempts,
+		LastError: delivery.LastError,
+		CreatedAt: formatTimeForContract(delivery.CreatedAt),
+	}
+	if delivery.Status == entities.WebhookDeliveryPending {
+		response.NextAttemptAt = formatTimeForContract(delivery.NextAttemptAt)
+	}
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = formatTimeForContract(*delivery.DeliveredAt)
+	}
+	return response
+}
+
+// ToWebhookDeliveryResponses converts a slice of deliveries
+func ToWebhookDeliveryResponses(deliveries []*entities.WebhookDelivery) []WebhookDeliveryResponse {
+	responses := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = ToWebhookDeliveryResponse(delivery)
+	}
+	return responses
+}
+
+// ToWebhookDeliveryDetailResponse converts a delivery and its attempts
+func ToWebhookDeliveryDetailResponse(delivery *entities.WebhookDelivery, attempts []*entities.WebhookAttempt) WebhookDeliveryDetailResponse {
+	response := WebhookDeliveryDetailResponse{
+		WebhookDeliveryResponse: ToWebhookDeliveryResponse(delivery),
+		Payload:                 string(delivery.Payload),
+		Log:                     make([]WebhookAttemptResponse, len(attempts)),
+	}
+	for i, attempt := range attempts {
+		response.Log[i] = WebhookAttemptResponse{
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMs:  attempt.Duration.Milliseconds(),
+			AttemptedAt: formatTimeForContract(attempt.AttemptedAt),
+		}
+	}
+	return response
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCase *usecases.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase *usecases.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// CreateAPIKey handles POST /api/keys
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	created, err := h.apiKeyUseCase.CreateAPIKey(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreatedAPIKeyResponse{
+		APIKeyResponse: dto.ToAPIKeyResponse(created.Key),
+		Key:            created.Secret,
+	})
+}
+
+// ListAPIKeys handles GET /api/keys
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	keys, err := h.apiKeyUseCase.ListAPIKeys(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToAPIKeyResponses(keys))
+}
+
+// RevokeAPIKey handles DELETE /api/keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.apiKeyUseCase.RevokeAPIKey(ctx, c.Params("id")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditUseCase *usecases.AuditUseCase
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(auditUseCase *usecases.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// GetAuditEvents handles GET /api/admin/audit
+func (h *AuditHandler) GetAuditEvents(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.AuditQueryRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid query parameters"),
+		)
+	}
+
+	events, err := h.auditUseCase.QueryEvents(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToAuditEventResponses(events))
+}
+
+// VerifyAuditChain handles GET /api/admin/audit/verify
+func (h *AuditHandler) VerifyAuditChain(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	verification, err := h.auditUseCase.VerifyChain(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	response := dto.AuditVerificationResponse{
+		OK:       verification.OK(),
+		Events:   verification.Events,
+		LastSeq:  verification.LastSeq,
+		LastHash: verification.LastHash,
+		Problems: make([]dto.AuditChainProblemResponse, len(verification.Problems)),
+	}
+	for i, problem := range verification.Problems {
+		response.Problems[i] = dto.AuditChainProblemResponse{Seq: problem.Seq, Kind: problem.Kind, Detail: problem.Detail}
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
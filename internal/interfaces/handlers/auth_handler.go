@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandler struct {
+	authUseCase *usecases.AuthUseCase
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(authUseCase *usecases.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// Register handles POST /api/auth/register
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.RegisterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	user, err := h.authUseCase.Register(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToUserResponse(user))
+}
+
+// Login handles POST /api/auth/login
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	result, err := h.authUseCase.Login(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toLoginResponse(result))
+}
+
+// Refresh handles POST /api/auth/refresh
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.RefreshRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	result, err := h.authUseCase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toLoginResponse(result))
+}
+
+// JWKS handles GET /.well-known/jwks.json
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.authUseCase.JWKS())
+}
+
+// Logout handles POST /api/auth/logout
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.authUseCase.Logout(ctx); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ChangePassword handles PUT /api/auth/password
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	if err := h.authUseCase.ChangePassword(ctx, req); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Me handles GET /api/auth/me
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	user, err := h.authUseCase.CurrentUser(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToUserResponse(user))
+}
+
+func toLoginResponse(result *usecases.LoginResult) dto.LoginResponse {
+	return dto.ToLoginResponse(result.User, result.AccessToken, result.ExpiresAt, result.RefreshToken, result.RefreshExpiresAt)
+}
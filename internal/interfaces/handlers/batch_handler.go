@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BatchLimits bounds the size of a batch request
+type BatchLimits struct {
+	MaxOperations   int
+	MaxPayloadBytes int
+}
+
+type BatchHandler struct {
+	todoUseCase *usecases.TodoUseCase
+	limits      BatchLimits
+}
+
+// NewBatchHandler creates a new BatchHandler
+func NewBatchHandler(todoUseCase *usecases.TodoUseCase, limits BatchLimits) *BatchHandler {
+	return &BatchHandler{
+		todoUseCase: todoUseCase,
+		limits:      limits,
+	}
+}
+
+// ExecuteBatch handles POST /api/todos/batch
+func (h *BatchHandler) ExecuteBatch(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if h.limits.MaxPayloadBytes > 0 && len(c.Body()) > h.limits.MaxPayloadBytes {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(
+			dto.ErrorResponse(fmt.Sprintf("Batch payload exceeds %d bytes", h.limits.MaxPayloadBytes)),
+		)
+	}
+
+	// Parse request body
+	var req dto.BatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+	req.Atomic = c.QueryBool("atomic")
+
+	if h.limits.MaxOperations > 0 && len(req.Operations) > h.limits.MaxOperations {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(
+			dto.ErrorResponse(fmt.Sprintf("Batch exceeds %d operations", h.limits.MaxOperations)),
+		)
+	}
+
+	results, err := h.todoUseCase.ExecuteBatch(ctx, req)
+	if err != nil && !errors.Is(err, usecases.ErrBatchRolledBack) {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	response := toBatchResponse(req.Atomic, results)
+	switch {
+	case err != nil:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(response)
+	case req.Atomic:
+		return c.Status(fiber.StatusOK).JSON(response)
+	default:
+		return c.Status(fiber.StatusMultiStatus).JSON(response)
+	}
+}
+
+func toBatchResponse(atomic bool, results []usecases.BatchItemResult) dto.BatchResponse {
+	response := dto.BatchResponse{
+		Atomic:  atomic,
+		Results: make([]dto.BatchItemResult, len(results)),
+	}
+
+	for i, result := range results {
+		item := dto.BatchItemResult{
+			Index:  result.Index,
+			Op:     result.Op,
+			ID:     result.ID,
+			Status: batchItemStatus(result),
+		}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		if result.Todo != nil {
+			item.Todo = dto.ToTodoDetailResponse(result.Todo)
+		}
+		response.Results[i] = item
+	}
+
+	return response
+}
+
+func batchItemStatus(result usecases.BatchItemResult) int {
+	switch {
+	case result.Err != nil:
+		return errorStatus(result.Err)
+	case result.Op == dto.BatchOpCreate:
+		return fiber.StatusCreated
+	case result.Op == dto.BatchOpDelete:
+		return fiber.StatusNoContent
+	default:
+		return fiber.StatusOK
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// lastEventIDHeader is sent by EventSource clients when they reconnect
+	lastEventIDHeader = "Last-Event-ID"
+	// streamRetry is how long clients wait before reconnecting
+	streamRetry = 3 * time.Second
+	// resetEvent tells a client that changes it missed can no longer be
+	// replayed, so it must reload its todos
+	resetEvent = "reset"
+)
+
+// ChangeStreamHandler streams todo changes as Server-Sent Events
+type ChangeStreamHandler struct {
+	changeStream *usecases.ChangeStreamUseCase
+	// heartbeat is how often an idle stream sends a comment, which keeps
+	// proxies from timing it out and detects clients that went away
+	heartbeat time.Duration
+	// done ends every open stream, e.g. on shutdown, after which clients
+	// reconnect and resume where they left off
+	done <-chan struct{}
+}
+
+// NewChangeStreamHandler creates a new ChangeStreamHandler
+func NewChangeStreamHandler(changeStream *usecases.ChangeStreamUseCase, heartbeat time.Duration, done <-chan struct{}) *ChangeStreamHandler {
+	return &ChangeStreamHandler{
+		changeStream: changeStream,
+		heartbeat:    heartbeat,
+		done:         done,
+	}
+}
+
+// StreamChanges handles GET /api/todos/stream. Each event carries its change
+// log sequence number as its ID; clients resume after it by sending it back
+// in the Last-Event-ID header, or the lastEventId query parameter.
+func (h *ChangeStreamHandler) StreamChanges(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	lastEventID := c.Get(lastEventIDHeader, c.Query("lastEventId"))
+	var lastSeq int64
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				dto.ErrorResponse("Invalid Last-Event-ID"),
+			)
+		}
+		lastSeq = seq
+	}
+
+	sub, err := h.changeStream.Subscribe(ctx, lastSeq)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Keeps reverse proxies from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+		h.stream(ctx, w, sub)
+	})
+	return nil
+}
+
+// stream writes events to w until the client goes away or the handler is done
+func (h *ChangeStreamHandler) stream(ctx context.Context, w *bufio.Writer, sub *usecases.ChangeSubscription) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-h.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if sub.MissedChanges() {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", resetEvent)
+	}
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	for {
+		next, cancelNext := context.WithTimeout(ctx, h.heartbeat)
+		event, err := sub.Next(next)
+		cancelNext()
+
+		switch {
+		case err == nil:
+			data, err := json.Marshal(dto.ToChangeEventResponse(event))
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to encode change event", "event_id", event.ID, "error", err)
+				return
+			}
+			if event.Seq > 0 {
+				fmt.Fprintf(w, "id: %d\n", event.Seq)
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		default:
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Change stream failed", "error", err)
+			}
+			return
+		}
+
+		// Fails once the client has gone away
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// collaborationSessionLocal and collaborationContextLocal hand the session
+	// and the request's user context over to the upgraded connection
+	collaborationSessionLocal = "collaborationSession"
+	collaborationContextLocal = "collaborationContext"
+	// outboxSize is how many messages may queue for a client before the
+	// senders wait for it
+	outboxSize = 16
+	// writeTimeout bounds every write to a client
+	writeTimeout = 10 * time.Second
+)
+
+// WebSocketSettings configures the connections of collaborative clients
+type WebSocketSettings struct {
+	// PingInterval is how often the server pings the client
+	PingInterval time.Duration
+	// PongTimeout is how long the client may take to answer a ping before
+	// the connection is closed
+	PongTimeout time.Duration
+	// MaxMessageBytes bounds the size of a client message
+	MaxMessageBytes int64
+	// Batch bounds the batch messages
+	Batch BatchLimits
+}
+
+// CollaborationHandler serves collaborative clients over a WebSocket: they
+// subscribe to lists, receive their changes and who else is viewing them, and
+// send mutations, each answered with a result or an error carrying its ID
+type CollaborationHandler struct {
+	collaboration *usecases.CollaborationUseCase
+	todoUseCase   *usecases.TodoUseCase
+	settings      WebSocketSettings
+	// done closes every connection, e.g. on shutdown
+	done    <-chan struct{}
+	upgrade fiber.Handler
+}
+
+// NewCollaborationHandler creates a new CollaborationHandler
+func NewCollaborationHandler(collaboration *usecases.CollaborationUseCase, todoUseCase *usecases.TodoUseCase, settings WebSocketSettings, done <-chan struct{}) *CollaborationHandler {
+	h := &CollaborationHandler{
+		collaboration: collaboration,
+		todoUseCase:   todoUseCase,
+		settings:      settings,
+		done:          done,
+	}
+	h.upgrade = websocket.New(h.serve)
+	return h
+}
+
+// Connect handles GET /api/ws. The connection counts against the caller's
+// connection limit until it is closed.
+func (h *CollaborationHandler) Connect(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(
+			dto.ErrorResponse("WebSocket upgrade required"),
+		)
+	}
+	ctx := c.UserContext()
+
+	session, err := h.collaboration.Connect(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	c.Locals(collaborationSessionLocal, session)
+	c.Locals(collaborationContextLocal, ctx)
+	if err := h.upgrade(c); err != nil {
+		session.Close()
+		return err
+	}
+	return nil
+}
+
+// serve runs an upgraded connection until the client or the server closes it
+func (h *CollaborationHandler) serve(conn *websocket.Conn) {
+	session := conn.Locals(collaborationSessionLocal).(*usecases.CollaborationSession)
+	ctx, cancel := context.WithCancel(conn.Locals(collaborationContextLocal).(context.Context))
+	out := make(chan dto.ServerMessage, outboxSize)
+
+	// Whichever goroutine stops first stops the others: the writer closes
+	// the connection, which ends read
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		h.write(ctx, conn, out)
+	}()
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		h.sendChanges(ctx, session, out)
+	}()
+	go func() {
+		defer wg.Done()
+		h.sendPresence(ctx, session, out)
+	}()
+
+	h.read(ctx, conn, session, out)
+	cancel()
+	wg.Wait()
+	session.Close()
+}
+
+// read handles the client's messages in order until the connection fails
+func (h *CollaborationHandler) read(ctx context.Context, conn *websocket.Conn, session *usecases.CollaborationSession, out chan<- dto.ServerMessage) {
+	conn.SetReadLimit(h.settings.MaxMessageBytes)
+	// Every message or pong proves the client alive until the next ping is due
+	alive := func() {
+		_ = conn.SetReadDeadline(time.Now().Add(h.settings.PingInterval + h.settings.PongTimeout))
+	}
+	alive()
+	conn.SetPongHandler(func(string) error {
+		alive()
+		return nil
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil && websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.DebugContext(ctx, "Collaboration connection lost", "error", err)
+			}
+			return
+		}
+		alive()
+
+		reply := h.handle(ctx, session, data)
+		select {
+		case out <- reply:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// write sends the queued messages and pings to the client
+func (h *CollaborationHandler) write(ctx context.Context, conn *websocket.Conn, out <-chan dto.ServerMessage) {
+	defer conn.Close()
+	ping := time.NewTicker(h.settings.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-out:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-h.done:
+			closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, "Server shutting down")
+			_ = conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(writeTimeout))
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendChanges queues the changes to the subscribed lists
+func (h *CollaborationHandler) sendChanges(ctx context.Context, session *usecases.CollaborationSession, out chan<- dto.ServerMessage) {
+	for {
+		event, err := session.NextChange(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Collaboration change stream failed", "error", err)
+			}
+			return
+		}
+
+		change := dto.ToChangeEventResponse(event)
+		select {
+		case out <- dto.ServerMessage{Type: dto.MessageChange, Change: &change}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendPresence queues the viewers of the subscribed lists whenever they change
+func (h *CollaborationHandler) sendPresence(ctx context.Context, session *usecases.CollaborationSession, out chan<- dto.ServerMessage) {
+	for {
+		select {
+		case <-session.PresenceChanged():
+			for listID, viewers := range session.PresenceUpdates() {
+				select {
+				case out <- toPresenceMessage(listID, viewers):
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handle answers one client message
+func (h *CollaborationHandler) handle(ctx context.Context, session *usecases.CollaborationSession, data []byte) dto.ServerMessage {
+	var msg dto.ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return errorMessage("", fiber.StatusBadRequest, "Invalid message")
+	}
+
+	reply := dto.ServerMessage{Type: dto.MessageResult, ID: msg.ID}
+	var err error
+	switch msg.Type {
+	case dto.MessageSubscribe:
+		reply.ListID = msg.ListID
+		err = session.Subscribe(ctx, msg.ListID)
+	case dto.MessageUnsubscribe:
+		reply.ListID = msg.ListID
+		session.Unsubscribe(msg.ListID)
+	case dto.MessageCreate:
+		reply.Todo, err = h.create(ctx, msg)
+	case dto.MessagePatch:
+		reply.Todo, err = h.patch(ctx, msg)
+	case dto.MessageBatch:
+		reply.Batch, err = h.batch(ctx, msg)
+	default:
+		return errorMessage(msg.ID, fiber.StatusBadRequest, fmt.Sprintf("Unknown message type %q", msg.Type))
+	}
+	if err != nil {
+		return errorMessage(msg.ID, errorStatus(err), err.Error())
+	}
+	return reply
+}
+
+// create creates a todo like POST /api/todos
+func (h *CollaborationHandler) create(ctx context.Context, msg dto.ClientMessage) (*dto.TodoDetailResponse, error) {
+	if _, err := identity.RequireScope(ctx, identity.ScopeTodosWrite); err != nil {
+		return nil, err
+	}
+	if msg.Text == "" {
+		return nil, fmt.Errorf("%w: text is required", usecases.ErrInvalidInput)
+	}
+
+	todo, err := h.todoUseCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: msg.Text, ListID: msg.ListID})
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToTodoDetailResponse(todo), nil
+}
+
+// patch patches a todo like PATCH /api/todos/:id. An array is applied as a
+// JSON Patch, anything else as a JSON Merge Patch.
+func (h *CollaborationHandler) patch(ctx context.Context, msg dto.ClientMessage) (*dto.TodoDetailResponse, error) {
+	if _, err := identity.RequireScope(ctx, identity.ScopeTodosWrite); err != nil {
+		return nil, err
+	}
+
+	req := dto.PatchTodoRequest{Patch: msg.Patch, Format: dto.PatchFormatMerge}
+	if bytes.HasPrefix(bytes.TrimSpace(msg.Patch), []byte("[")) {
+		req.Format = dto.PatchFormatJSONPatch
+	}
+	todo, err := h.todoUseCase.PatchTodo(ctx, msg.TodoID, req)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToTodoDetailResponse(todo), nil
+}
+
+// batch applies operations like POST /api/todos/batch
+func (h *CollaborationHandler) batch(ctx context.Context, msg dto.ClientMessage) (*dto.BatchResponse, error) {
+	if _, err := identity.RequireScope(ctx, identity.ScopeTodosWrite); err != nil {
+		return nil, err
+	}
+	if limit := h.settings.Batch.MaxOperations; limit > 0 && len(msg.Operations) > limit {
+		return nil, fmt.Errorf("%w: batch exceeds %d operations", usecases.ErrInvalidInput, limit)
+	}
+
+	req := dto.BatchRequest{Atomic: msg.Atomic, Operations: msg.Operations}
+	results, err := h.todoUseCase.ExecuteBatch(ctx, req)
+	if err != nil && !errors.Is(err, usecases.ErrBatchRolledBack) {
+		return nil, err
+	}
+	response := toBatchResponse(req.Atomic, results)
+	return &response, nil
+}
+
+func errorMessage(id string, status int, message string) dto.ServerMessage {
+	return dto.ServerMessage{Type: dto.MessageError, ID: id, Status: status, Error: message}
+}
+
+func toPresenceMessage(listID string, viewers []usecases.ListViewer) dto.ServerMessage {
+	response := make([]dto.ViewerResponse, len(viewers))
+	for i, viewer := range viewers {
+		response[i] = dto.ViewerResponse{UserID: viewer.UserID, Username: viewer.Username}
+	}
+	return dto.ServerMessage{Type: dto.MessagePresence, ListID: listID, Viewers: response}
+}
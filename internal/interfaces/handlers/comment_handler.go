@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentHandler struct {
+	commentUseCase *usecases.CommentUseCase
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(commentUseCase *usecases.CommentUseCase) *CommentHandler {
+	return &CommentHandler{
+		commentUseCase: commentUseCase,
+	}
+}
+
+// GetComments handles GET /api/todos/:id/comments
+func (h *CommentHandler) GetComments(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	comments, err := h.commentUseCase.GetComments(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToCommentResponses(comments))
+}
+
+// AddComment handles POST /api/todos/:id/comments
+func (h *CommentHandler) AddComment(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	comment, err := h.commentUseCase.AddComment(ctx, c.Params("id"), req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToCommentResponse(comment))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"todo-backend/internal/application/patch"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorStatus maps use case errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated), errors.Is(err, usecases.ErrInvalidCredentials):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, usecases.ErrInvalidRefreshToken), errors.Is(err, usecases.ErrRefreshTokenReused):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, usecases.ErrShareLinkPassword):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, identity.ErrForbidden), errors.Is(err, tenancy.ErrQuotaExceeded):
+		return fiber.StatusForbidden
+	case errors.Is(err, tenancy.ErrTenantRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, tenancy.ErrUnknownTenant):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecases.ErrUsernameTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, usecases.ErrInvalidInput):
+		return fiber.StatusBadRequest
+	case errors.Is(err, repositories.ErrTodoNotFound), errors.Is(err, repositories.ErrAPIKeyNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, repositories.ErrListNotFound), errors.Is(err, repositories.ErrListMemberNotFound),
+		errors.Is(err, repositories.ErrListInviteNotFound), errors.Is(err, repositories.ErrShareLinkNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, repositories.ErrWebhookNotFound), errors.Is(err, repositories.ErrWebhookDeliveryNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, repositories.ErrListMemberExists), errors.Is(err, repositories.ErrTodoExists):
+		return fiber.StatusConflict
+	case errors.Is(err, repositories.ErrVersionConflict), errors.Is(err, patch.ErrTestFailed):
+		return fiber.StatusConflict
+	case errors.Is(err, patch.ErrInvalidPatch), errors.Is(err, patch.ErrFieldNotAllowed):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, usecases.ErrBatchAborted):
+		return fiber.StatusFailedDependency
+	case errors.Is(err, usecases.ErrTooManyConnections):
+		return fiber.StatusTooManyRequests
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthHandler struct {
+	healthUseCase *usecases.HealthUseCase
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(healthUseCase *usecases.HealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// Live handles GET /livez
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return h.probe(c, usecases.ProbeLiveness)
+}
+
+// Ready handles GET /readyz
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	return h.probe(c, usecases.ProbeReadiness)
+}
+
+// Startup handles GET /startupz
+func (h *HealthHandler) Startup(c *fiber.Ctx) error {
+	return h.probe(c, usecases.ProbeStartup)
+}
+
+// probe answers 200 when every check of probe passes and 503 otherwise.
+// ?verbose lists the checks and ?exclude=a,b skips some of them.
+func (h *HealthHandler) probe(c *fiber.Ctx, probe usecases.HealthProbe) error {
+	ctx := c.UserContext()
+
+	var exclude []string
+	if value := c.Query("exclude"); value != "" {
+		exclude = strings.Split(value, ",")
+	}
+	report := h.healthUseCase.Check(ctx, probe, exclude...)
+
+	response := dto.HealthResponse{Status: "ok"}
+	status := fiber.StatusOK
+	if !report.OK() {
+		response.Status = "failed"
+		status = fiber.StatusServiceUnavailable
+	}
+	if c.Request().URI().QueryArgs().Has("verbose") {
+		response.Checks = make([]dto.HealthCheckResponse, len(report.Results))
+		for i, result := range report.Results {
+			check := dto.HealthCheckResponse{
+				Name:       result.Name,
+				Status:     "ok",
+				DurationMs: float64(result.Duration) / float64(time.Millisecond),
+				Cached:     result.Cached,
+				CheckedAt:  result.CheckedAt.UTC().Format(time.RFC3339Nano),
+			}
+			if result.Err != nil {
+				check.Status = "failed"
+				check.Error = result.Err.Error()
+			}
+			response.Checks[i] = check
+		}
+	}
+	return c.Status(status).JSON(response)
+}
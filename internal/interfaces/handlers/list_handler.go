@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListHandler struct {
+	listUseCase *usecases.ListUseCase
+}
+
+// NewListHandler creates a new ListHandler
+func NewListHandler(listUseCase *usecases.ListUseCase) *ListHandler {
+	return &ListHandler{
+		listUseCase: listUseCase,
+	}
+}
+
+// GetLists handles GET /api/lists
+func (h *ListHandler) GetLists(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	lists, err := h.listUseCase.GetLists(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToListResponses(lists))
+}
+
+// CreateList handles POST /api/lists
+func (h *ListHandler) CreateList(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	list, err := h.listUseCase.CreateList(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToListResponse(list))
+}
+
+// GetList handles GET /api/lists/:id
+func (h *ListHandler) GetList(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	list, err := h.listUseCase.GetList(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToListResponse(list))
+}
+
+// DeleteList handles DELETE /api/lists/:id
+func (h *ListHandler) DeleteList(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.listUseCase.DeleteList(ctx, c.Params("id")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetMembers handles GET /api/lists/:id/members
+func (h *ListHandler) GetMembers(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	members, err := h.listUseCase.GetMembers(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToListMemberResponses(members))
+}
+
+// UpdateMemberRole handles PUT /api/lists/:id/members/:userId
+func (h *ListHandler) UpdateMemberRole(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.UpdateMemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	if err := h.listUseCase.UpdateMemberRole(ctx, c.Params("id"), c.Params("userId"), req); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveMember handles DELETE /api/lists/:id/members/:userId
+func (h *ListHandler) RemoveMember(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.listUseCase.RemoveMember(ctx, c.Params("id"), c.Params("userId")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateInvite handles POST /api/lists/:id/invites
+func (h *ListHandler) CreateInvite(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	created, err := h.listUseCase.CreateInvite(ctx, c.Params("id"), req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreatedInviteResponse{
+		InviteResponse: dto.ToInviteResponse(created.Invite),
+		Token:          created.Token,
+	})
+}
+
+// GetInvites handles GET /api/lists/:id/invites
+func (h *ListHandler) GetInvites(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	invites, err := h.listUseCase.GetInvites(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToInviteResponses(invites))
+}
+
+// RevokeInvite handles DELETE /api/lists/:id/invites/:inviteId
+func (h *ListHandler) RevokeInvite(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.listUseCase.RevokeInvite(ctx, c.Params("id"), c.Params("inviteId")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AcceptInvite handles POST /api/invites/accept
+func (h *ListHandler) AcceptInvite(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.AcceptInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	list, err := h.listUseCase.AcceptInvite(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToListResponse(list))
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"log/slog"
+	"strings"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LogLevelHandler struct {
+	level *slog.LevelVar
+}
+
+// NewLogLevelHandler creates a new LogLevelHandler changing level
+func NewLogLevelHandler(level *slog.LevelVar) *LogLevelHandler {
+	return &LogLevelHandler{
+		level: level,
+	}
+}
+
+// GetLogLevel handles GET /api/admin/log-level
+func (h *LogLevelHandler) GetLogLevel(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(dto.LogLevelResponse{Level: levelName(h.level.Level())})
+}
+
+// SetLogLevel handles PUT /api/admin/log-level. The level applies at once
+// and until the next restart.
+func (h *LogLevelHandler) SetLogLevel(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.LogLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	var level slog.Level
+	switch strings.ToLower(req.Level) {
+	case "debug":
+		level = slog.LevelDebug
+	case "info":
+		level = slog.LevelInfo
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("level must be debug, info, warn or error"),
+		)
+	}
+
+	previous := h.level.Level()
+	h.level.Set(level)
+	slog.InfoContext(ctx, "Log level changed", "from", levelName(previous), "to", levelName(level))
+
+	return c.Status(fiber.StatusOK).JSON(dto.LogLevelResponse{Level: levelName(level)})
+}
+
+func levelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RateLimitHandler struct {
+	rateLimitUseCase *usecases.RateLimitUseCase
+}
+
+// NewRateLimitHandler creates a new RateLimitHandler
+func NewRateLimitHandler(rateLimitUseCase *usecases.RateLimitUseCase) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimitUseCase: rateLimitUseCase,
+	}
+}
+
+// GetBuckets handles GET /api/admin/rate-limits
+func (h *RateLimitHandler) GetBuckets(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	statuses, err := h.rateLimitUseCase.GetBuckets(ctx, c.Query("subject"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	response := make([]dto.RateLimitBucketResponse, len(statuses))
+	for i, status := range statuses {
+		response[i] = dto.ToRateLimitBucketResponse(status.Bucket, status.Policy)
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ResetBuckets handles DELETE /api/admin/rate-limits
+func (h *RateLimitHandler) ResetBuckets(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	reset, err := h.rateLimitUseCase.ResetBuckets(ctx, c.Query("subject"), c.Query("policy"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.RateLimitResetResponse{Reset: reset})
+}
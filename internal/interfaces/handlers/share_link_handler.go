@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SharePasswordHeader carries the password of a protected share link
+const SharePasswordHeader = "X-Share-Password"
+
+type ShareLinkHandler struct {
+	shareLinkUseCase *usecases.ShareLinkUseCase
+}
+
+// NewShareLinkHandler creates a new ShareLinkHandler
+func NewShareLinkHandler(shareLinkUseCase *usecases.ShareLinkUseCase) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		shareLinkUseCase: shareLinkUseCase,
+	}
+}
+
+// CreateShareLink handles POST /api/lists/:id/share-links
+func (h *ShareLinkHandler) CreateShareLink(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateShareLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	created, err := h.shareLinkUseCase.CreateShareLink(ctx, c.Params("id"), req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreatedShareLinkResponse{
+		ShareLinkResponse: dto.ToShareLinkResponse(created.Link),
+		Token:             created.Token,
+		Path:              "/s/" + created.Token,
+	})
+}
+
+// GetShareLinks handles GET /api/lists/:id/share-links
+func (h *ShareLinkHandler) GetShareLinks(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	links, err := h.shareLinkUseCase.GetShareLinks(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToShareLinkResponses(links))
+}
+
+// RevokeShareLink handles DELETE /api/lists/:id/share-links/:linkId
+func (h *ShareLinkHandler) RevokeShareLink(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.shareLinkUseCase.RevokeShareLink(ctx, c.Params("id"), c.Params("linkId")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetShareLinkAccesses handles GET /api/lists/:id/share-links/:linkId/accesses
+func (h *ShareLinkHandler) GetShareLinkAccesses(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	accesses, err := h.shareLinkUseCase.GetShareLinkAccesses(ctx, c.Params("id"), c.Params("linkId"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToShareLinkAccessResponses(accesses))
+}
+
+// OpenShareLink handles GET /s/:token
+func (h *ShareLinkHandler) OpenShareLink(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	// The token is in the URL: keep it out of caches and Referer headers
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+	c.Set("X-Robots-Tag", "noindex")
+
+	list, err := h.shareLinkUseCase.OpenShareLink(ctx, usecases.ShareLinkVisit{
+		Token:     c.Params("token"),
+		Password:  c.Get(SharePasswordHeader),
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToSharedListResponse(list.Name, list.Todos))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SyncHandler serves offline clients catching up with the server
+type SyncHandler struct {
+	todoUseCase  *usecases.TodoUseCase
+	maxMutations int
+}
+
+// NewSyncHandler creates a new SyncHandler
+func NewSyncHandler(todoUseCase *usecases.TodoUseCase, maxMutations int) *SyncHandler {
+	return &SyncHandler{
+		todoUseCase:  todoUseCase,
+		maxMutations: maxMutations,
+	}
+}
+
+// Sync handles POST /api/sync. Sending mutations requires the write scope.
+func (h *SyncHandler) Sync(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.SyncRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	if h.maxMutations > 0 && len(req.Mutations) > h.maxMutations {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(
+			dto.ErrorResponse(fmt.Sprintf("Sync exceeds %d mutations", h.maxMutations)),
+		)
+	}
+	if len(req.Mutations) > 0 {
+		if _, err := identity.RequireScope(ctx, identity.ScopeTodosWrite); err != nil {
+			return c.Status(errorStatus(err)).JSON(
+				dto.ErrorResponse(err.Error()),
+			)
+		}
+	}
+
+	result, err := h.todoUseCase.Sync(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toSyncResponse(result))
+}
+
+func toSyncResponse(result *usecases.SyncResult) dto.SyncResponse {
+	response := dto.SyncResponse{
+		SyncToken:      result.Token,
+		Full:           result.Full,
+		HasMore:        result.HasMore,
+		Todos:          make([]*dto.TodoDetailResponse, len(result.Todos)),
+		DeletedTodoIDs: append([]string{}, result.DeletedTodoIDs...),
+		RemovedListIDs: append([]string{}, result.RemovedListIDs...),
+		Results:        make([]dto.SyncMutationResult, len(result.Results)),
+	}
+	for i, todo := range result.Todos {
+		response.Todos[i] = dto.ToTodoDetailResponse(todo)
+	}
+
+	for i, mutation := range result.Results {
+		item := dto.SyncMutationResult{
+			ClientID: mutation.ClientID,
+			TodoID:   mutation.TodoID,
+			Status:   mutation.Status,
+			Deleted:  mutation.Deleted,
+		}
+		if mutation.Err != nil {
+			item.Error = mutation.Err.Error()
+		}
+		if mutation.Todo != nil {
+			item.Todo = dto.ToTodoDetailResponse(mutation.Todo)
+		}
+		for _, conflict := range mutation.Conflicts {
+			item.Conflicts = append(item.Conflicts, dto.SyncFieldConflict{
+				Field:       conflict.Field,
+				ClientValue: conflict.ClientValue,
+				ServerValue: conflict.ServerValue,
+			})
+		}
+		response.Results[i] = item
+	}
+
+	return response
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TenantHandler struct {
+	tenantUseCase *usecases.TenantUseCase
+}
+
+// NewTenantHandler creates a new TenantHandler
+func NewTenantHandler(tenantUseCase *usecases.TenantUseCase) *TenantHandler {
+	return &TenantHandler{
+		tenantUseCase: tenantUseCase,
+	}
+}
+
+// GetTenant handles GET /api/tenant
+func (h *TenantHandler) GetTenant(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	status, err := h.tenantUseCase.GetStatus(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToTenantResponse(status.ID, status.Usage, status.Quota))
+}
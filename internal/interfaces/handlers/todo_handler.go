@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"fmt"
+	"mime"
 	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
 	"todo-backend/internal/interfaces/dto"
 
 	"github.com/gofiber/fiber/v2"
@@ -20,12 +23,18 @@ func NewTodoHandler(todoUseCase *usecases.TodoUseCase) *TodoHandler {
 
 // GetTodos handles GET /api/todos
 func (h *TodoHandler) GetTodos(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 
-	// Get all todos
-	todos, err := h.todoUseCase.GetAllTodos(ctx)
+	// Get all accessible todos, or those of one list
+	var todos []*entities.Todo
+	var err error
+	if listID := c.Query("listId"); listID != "" {
+		todos, err = h.todoUseCase.GetTodosByList(ctx, listID)
+	} else {
+		todos, err = h.todoUseCase.GetAllTodos(ctx)
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
+		return c.Status(errorStatus(err)).JSON(
 			dto.ErrorResponse(err.Error()),
 		)
 	}
@@ -37,7 +46,7 @@ func (h *TodoHandler) GetTodos(c *fiber.Ctx) error {
 
 // CreateTodo handles POST /api/todos
 func (h *TodoHandler) CreateTodo(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
 
 	// Parse request body
 	var req dto.CreateTodoRequest
@@ -57,7 +66,7 @@ func (h *TodoHandler) CreateTodo(c *fiber.Ctx) error {
 	//create
 	todo, err := h.todoUseCase.CreateTodo(ctx, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
+		return c.Status(errorStatus(err)).JSON(
 			dto.ErrorResponse(err.Error()),
 		)
 	}
@@ -66,3 +75,33 @@ func (h *TodoHandler) CreateTodo(c *fiber.Ctx) error {
 	contractResponse := dto.ToContractTodoResponse(todo)
 	return c.Status(fiber.StatusCreated).JSON(contractResponse)
 }
+
+// PatchTodo handles PATCH /api/todos/:id
+func (h *TodoHandler) PatchTodo(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	req := dto.PatchTodoRequest{Patch: c.Body()}
+	switch mediaType {
+	case "application/merge-patch+json":
+		req.Format = dto.PatchFormatMerge
+	case "application/json-patch+json":
+		req.Format = dto.PatchFormatJSONPatch
+	default:
+		c.Set("Accept-Patch", "application/merge-patch+json, application/json-patch+json")
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(
+			dto.ErrorResponse("Content-Type must be application/merge-patch+json or application/json-patch+json"),
+		)
+	}
+
+	//patch
+	todo, err := h.todoUseCase.PatchTodo(ctx, c.Params("id"), req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	c.Set(fiber.HeaderETag, fmt.Sprintf(`"%d"`, todo.Version))
+	return c.Status(fiber.StatusOK).JSON(dto.ToTodoDetailResponse(todo))
+}
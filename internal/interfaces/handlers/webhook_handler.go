@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookUseCase *usecases.WebhookUseCase
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(webhookUseCase *usecases.WebhookUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// CreateWebhook handles POST /api/webhooks
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req dto.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Invalid request body"),
+		)
+	}
+
+	created, err := h.webhookUseCase.CreateWebhook(ctx, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreatedWebhookResponse{
+		WebhookResponse: dto.ToWebhookResponse(created.Webhook),
+		Secret:          created.Secret,
+	})
+}
+
+// ListWebhooks handles GET /api/webhooks
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	webhooks, err := h.webhookUseCase.ListWebhooks(ctx)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToWebhookResponses(webhooks))
+}
+
+// GetWebhook handles GET /api/webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	webhook, err := h.webhookUseCase.GetWebhook(ctx, c.Params("id"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToWebhookResponse(webhook))
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	if err := h.webhookUseCase.DeleteWebhook(ctx, c.Params("id")); err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/webhooks/:id/deliveries. ?status=dead lists
+// the dead-letter queue.
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	deliveries, err := h.webhookUseCase.ListDeliveries(ctx, c.Params("id"), c.Query("status"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToWebhookDeliveryResponses(deliveries))
+}
+
+// GetDelivery handles GET /api/webhooks/:id/deliveries/:deliveryId
+func (h *WebhookHandler) GetDelivery(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	detail, err := h.webhookUseCase.GetDelivery(ctx, c.Params("id"), c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ToWebhookDeliveryDetailResponse(detail.Delivery, detail.Attempts))
+}
+
+// Redeliver handles POST /api/webhooks/:id/deliveries/:deliveryId/redeliver
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	delivery, err := h.webhookUseCase.Redeliver(ctx, c.Params("id"), c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(
+			dto.ErrorResponse(err.Error()),
+		)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.ToWebhookDeliveryResponse(delivery))
+}
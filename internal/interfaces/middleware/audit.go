@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditRecorder writes completed requests to the audit log
+type AuditRecorder interface {
+	Record(ctx context.Context, record usecases.AuditRecord) error
+}
+
+// Audit records every mutating request once it has been handled: the changes
+// the use cases observed if it succeeded, else the attempt itself. Requests
+// that cannot be attributed to a tenant are not recorded.
+func Audit(recorder AuditRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		ctx, trail := audit.WithTrail(c.UserContext())
+		c.SetUserContext(ctx)
+
+		handlerErr := c.Next()
+
+		status := handledStatus(c, handlerErr)
+		record := usecases.AuditRecord{
+			Action:    c.Method() + " " + c.Route().Path,
+			TargetID:  c.Params("id"),
+			Status:    status,
+			RequestID: requestID(c),
+			ClientIP:  c.IP(),
+		}
+		// Changes of failed requests were rolled back or never happened
+		if status < fiber.StatusBadRequest {
+			record.Changes = trail.Changes()
+		}
+
+		if err := recorder.Record(c.UserContext(), record); err != nil && !errors.Is(err, tenancy.ErrTenantRequired) {
+			slog.ErrorContext(c.UserContext(), "Failed to record audit event", "action", record.Action, "error", err)
+		}
+		return handlerErr
+	}
+}
+
+// handledStatus is the status of the response to a handled request,
+// including when the error handler has yet to turn handlerErr into it
+func handledStatus(c *fiber.Ctx, handlerErr error) int {
+	if handlerErr == nil {
+		return c.Response().StatusCode()
+	}
+	var e *fiber.Error
+	if errors.As(handlerErr, &e) {
+		return e.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authenticator resolves a bearer token to the principal it was issued to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*identity.Principal, error)
+}
+
+// CertificateAuthenticator resolves the subject of a verified TLS client
+// certificate to the principal it identifies
+type CertificateAuthenticator interface {
+	AuthenticateCertificate(ctx context.Context, subject string) (*identity.Principal, error)
+}
+
+// Authenticate rejects requests without a valid bearer token and stores the
+// authenticated principal in the request's user context for the use cases.
+// The authenticators are tried in order; each rejects tokens it did not issue
+// with identity.ErrUnauthenticated. Requests already authenticated by
+// ClientCertificate are passed through.
+func Authenticate(authenticators ...Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := identity.FromContext(c.UserContext()); ok {
+			return c.Next()
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
+			return unauthorized(c, "Missing bearer token")
+		}
+
+		for _, authenticator := range authenticators {
+			principal, err := authenticator.Authenticate(c.UserContext(), token)
+			if err != nil {
+				if errors.Is(err, identity.ErrUnauthenticated) {
+					continue
+				}
+				if errors.Is(err, tenancy.ErrTenantRequired) {
+					return c.Status(fiber.StatusBadRequest).JSON(
+						dto.ErrorResponse("Tenant required"),
+					)
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(
+					dto.ErrorResponse(err.Error()),
+				)
+			}
+
+			setPrincipal(c, principal)
+			return c.Next()
+		}
+		return unauthorized(c, "Invalid or expired token")
+	}
+}
+
+// ClientCertificate authenticates machine-to-machine callers by the subject of
+// the client certificate they presented over mutual TLS. Requests without a
+// verified certificate of a known service are left to Authenticate.
+func ClientCertificate(authenticator CertificateAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state := c.Context().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 {
+			return c.Next()
+		}
+
+		subject := state.VerifiedChains[0][0].Subject.String()
+		principal, err := authenticator.AuthenticateCertificate(c.UserContext(), subject)
+		if err != nil {
+			if errors.Is(err, identity.ErrUnauthenticated) {
+				return c.Next()
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				dto.ErrorResponse(err.Error()),
+			)
+		}
+		setPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+// setPrincipal stores principal, and the tenant it was authenticated in, in the request's user context
+func setPrincipal(c *fiber.Ctx, principal *identity.Principal) {
+	ctx := identity.WithPrincipal(c.UserContext(), principal)
+	if principal.TenantID != "" {
+		ctx = tenancy.WithTenant(ctx, principal.TenantID)
+	}
+	c.SetUserContext(ctx)
+}
+
+// RequireScope rejects authenticated callers that were not granted scope
+func RequireScope(scope identity.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, err := identity.RequireScope(c.UserContext(), scope); err != nil {
+			if errors.Is(err, identity.ErrUnauthenticated) {
+				return unauthorized(c, "Missing bearer token")
+			}
+			c.Set(fiber.HeaderWWWAuthenticate, fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+			return c.Status(fiber.StatusForbidden).JSON(
+				dto.ErrorResponse(fmt.Sprintf("Scope %s required", scope)),
+			)
+		}
+		return c.Next()
+	}
+}
+
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *fiber.Ctx, message string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(fiber.StatusUnauthorized).JSON(
+		dto.ErrorResponse(message),
+	)
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-chosen key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed from a stored record
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency replays stored responses for requests retried with the same Idempotency-Key.
+// A key reused with a different request body is rejected with 422, and a key whose
+// first request is still being processed is rejected with 409. A request in flight
+// holds its key for lease and renews it while it runs, so that the key of a request
+// cut short by a crash is free again after lease rather than after ttl.
+func Idempotency(repo repositories.IdempotencyRepository, ttl, lease time.Duration) fiber.Handler {
+	if lease <= 0 || lease > ttl {
+		lease = ttl
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				dto.ErrorResponse("Idempotency-Key must be at most 255 characters"),
+			)
+		}
+
+		ctx := c.UserContext()
+		scope := idempotencyScope(c)
+		fingerprint := requestFingerprint(c)
+
+		record := entities.NewIdempotencyRecord(key, scope, fingerprint, ttl, lease)
+		existing, err := repo.Reserve(ctx, record)
+		if errors.Is(err, repositories.ErrIdempotencyKeyExists) {
+			return replayIdempotentResponse(c, existing, fingerprint)
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				dto.ErrorResponse(err.Error()),
+			)
+		}
+
+		stopRenewing := renewIdempotencyLease(ctx, repo, key, scope, lease)
+		err = c.Next()
+		stopRenewing()
+		if err != nil {
+			releaseIdempotencyKey(c, repo, key, scope)
+			return err
+		}
+
+		// Server errors are not stored so that the client can retry them
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(c, repo, key, scope)
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		contentType := string(c.Response().Header.ContentType())
+		if err := repo.Complete(ctx, key, scope, status, contentType, body); err != nil {
+			slog.ErrorContext(ctx, "Failed to store idempotent response", "idempotency_key", key, "error", err)
+		}
+
+		return nil
+	}
+}
+
+func replayIdempotentResponse(c *fiber.Ctx, record *entities.IdempotencyRecord, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			dto.ErrorResponse("Idempotency-Key was already used with a different request"),
+		)
+	}
+
+	if record.Status != entities.IdempotencyCompleted {
+		return c.Status(fiber.StatusConflict).JSON(
+			dto.ErrorResponse("A request with this Idempotency-Key is still being processed"),
+		)
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.StatusCode).Send(record.ResponseBody)
+}
+
+// renewIdempotencyLease extends the lease of an in-flight key every third of
+// lease until the returned function is called
+func renewIdempotencyLease(ctx context.Context, repo repositories.IdempotencyRepository, key, scope string, lease time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := repo.Renew(ctx, key, scope, now.Add(lease)); err != nil {
+					slog.ErrorContext(ctx, "Failed to renew idempotency key", "idempotency_key", key, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func releaseIdempotencyKey(c *fiber.Ctx, repo repositories.IdempotencyRepository, key, scope string) {
+	if err := repo.Release(c.UserContext(), key, scope); err != nil {
+		slog.ErrorContext(c.UserContext(), "Failed to release idempotency key", "idempotency_key", key, "error", err)
+	}
+}
+
+// idempotencyScope keeps keys of different callers and endpoints apart
+func idempotencyScope(c *fiber.Ctx) string {
+	scope := c.Method() + " " + c.Path()
+	if principal, ok := identity.FromContext(c.UserContext()); ok {
+		scope = principal.UserID + " " + scope
+	}
+	return scope
+}
+
+// requestFingerprint hashes the parts of a request that must match on retry
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+	"todo-backend/internal/domain/correlation"
+	"todo-backend/internal/infrastructure/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLogger logs every request once it has been handled, with its route,
+// status and latency. Records logged with the request's user context while
+// it is handled carry its request ID, and the caller and tenant once known.
+func RequestLogger(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		// The request ID is resolved here unless RequestID already did
+		if _, ok := correlation.FromContext(c.UserContext()); !ok {
+			id := requestID(c)
+			c.Locals(requestIDLocal, id)
+			c.SetUserContext(correlation.WithRequestID(c.UserContext(), id))
+		}
+
+		handlerErr := c.Next()
+
+		status := handledStatus(c, handlerErr)
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("route", c.Route().Path),
+			slog.String("path", loggedPath(c)),
+			slog.Int("status", status),
+			logging.Milliseconds("latency_ms", time.Since(start)),
+			slog.String("ip", c.IP()),
+		}
+		if handlerErr != nil {
+			attrs = append(attrs, slog.Any("error", handlerErr))
+		}
+		logger.LogAttrs(c.UserContext(), level, "Request", attrs...)
+		return handlerErr
+	}
+}
+
+// loggedPath is the request path, unless it carries a share link token
+func loggedPath(c *fiber.Ctx) string {
+	if c.Params("token") != "" {
+		return c.Route().Path
+	}
+	return c.Path()
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestObserver records handled requests, e.g. as metrics
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics observes every request once it has been handled, labeled by the
+// route template it matched rather than its path
+func Metrics(observer RequestObserver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		handlerErr := c.Next()
+		// Observations outlive the request, so the method must not share its buffer
+		observer.ObserveRequest(strings.Clone(c.Method()), c.Route().Path, handledStatus(c, handlerErr), time.Since(start))
+		return handlerErr
+	}
+}
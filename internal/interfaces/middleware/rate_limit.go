@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Rate limit headers as proposed by the IETF httpapi working group
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// RateLimiter decides whether a request may proceed
+type RateLimiter interface {
+	Take(ctx context.Context, req usecases.RateLimitRequest) (*entities.RateLimitDecision, error)
+}
+
+// RateLimit answers requests that exceed their rate limit with 429. Limited
+// responses carry the RateLimit-* headers and rejections a Retry-After
+// header. Requests are let through when the limiter's store fails, so that
+// an unavailable store does not take the API down with it.
+func RateLimit(limiter RateLimiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		decision, err := limiter.Take(c.UserContext(), usecases.RateLimitRequest{
+			Method: c.Method(),
+			Path:   c.Path(),
+			// Buckets outlive the request, so the IP must not share its buffer
+			ClientIP: strings.Clone(c.IP()),
+		})
+		if err != nil {
+			slog.WarnContext(c.UserContext(), "Rate limiting failed, letting the request through", "error", err)
+			return c.Next()
+		}
+		if decision == nil {
+			return c.Next()
+		}
+
+		policy := decision.Policy
+		c.Set(RateLimitLimitHeader, strconv.Itoa(policy.Capacity()))
+		c.Set(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		c.Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(decision.Reset)))
+		c.Set(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d;burst=%d;name=%q", policy.Requests, ceilSeconds(policy.Period), policy.Capacity(), policy.Name))
+		if !decision.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(
+				dto.ErrorResponse("Too many requests, try again later"),
+			)
+		}
+		return c.Next()
+	}
+}
+
+// ceilSeconds rounds d up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+	"todo-backend/internal/domain/correlation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID correlating a request with its logs, audit
+// events and notifications
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// requestIDLocal holds the request ID once it has been resolved, so that the
+// log and the audit trail agree on it
+const requestIDLocal = "requestID"
+
+// requestIDField is added to JSON error bodies
+const requestIDField = "requestId"
+
+// RequestID accepts the client's request ID, or generates one, and threads it
+// through the request's context. The ID is echoed in the X-Request-ID response
+// header and in the body of JSON error responses, so that a client reporting
+// an error can quote it. It must come before the other middleware.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := requestID(c)
+		c.Locals(requestIDLocal, id)
+		c.SetUserContext(correlation.WithRequestID(c.UserContext(), id))
+		c.Set(RequestIDHeader, id)
+
+		// Errors are rendered here rather than by the app, so that their body
+		// can carry the ID
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+		if c.Response().StatusCode() >= fiber.StatusBadRequest {
+			addRequestID(c, id)
+		}
+		return nil
+	}
+}
+
+// addRequestID adds id to a JSON object response body
+func addRequestID(c *fiber.Ctx, id string) {
+	if !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil || body == nil {
+		return
+	}
+	if _, ok := body[requestIDField]; ok {
+		return
+	}
+	body[requestIDField], _ = json.Marshal(id)
+	if raw, err := json.Marshal(body); err == nil {
+		c.Response().SetBodyRaw(raw)
+	}
+}
+
+// requestID returns the request ID resolved by RequestID or RequestLogger,
+// else the client's request ID, or a new one. Client IDs that are too long or
+// contain anything but visible ASCII are replaced, since they end up in logs
+// and headers.
+func requestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals(requestIDLocal).(string); ok {
+		return id
+	}
+	if id := strings.TrimSpace(c.Get(RequestIDHeader)); validRequestID(id) {
+		return strings.Clone(id)
+	}
+	return uuid.New().String()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"strings"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantOptions configures how requests name their tenant
+type TenantOptions struct {
+	// Enabled resolves the tenant per request; when false every request runs in Default
+	Enabled bool
+	// Default is used when a request names no tenant; empty rejects such requests
+	Default string
+	Header  string
+	// BaseDomain resolves acme.<BaseDomain> to tenant acme
+	BaseDomain string
+	// Allowed restricts the accepted tenants; empty accepts any valid ID
+	Allowed []string
+}
+
+// ResolveTenant stores the tenant a request operates in in its user context.
+// The tenant is named by the header, else by the subdomain. Requests naming
+// neither run in the default tenant, unless their access token names another
+// one: Authenticate then switches to the token's tenant.
+func ResolveTenant(opts TenantOptions) fiber.Handler {
+	allowed := make(map[string]bool, len(opts.Allowed))
+	for _, tenant := range opts.Allowed {
+		allowed[tenant] = true
+	}
+	accept := func(tenant string) bool {
+		return tenancy.ValidateID(tenant) == nil && (len(allowed) == 0 || allowed[tenant])
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !opts.Enabled {
+			c.SetUserContext(tenancy.WithTenant(c.UserContext(), opts.Default))
+			return c.Next()
+		}
+
+		// Fiber reuses the request's buffers, so the tenant must be copied
+		// before it outlives the request, e.g. as a tenant database key
+		tenant := strings.ToLower(strings.TrimSpace(c.Get(opts.Header)))
+		if tenant == "" {
+			tenant = subdomainTenant(c.Hostname(), opts.BaseDomain)
+		}
+		tenant = strings.Clone(tenant)
+		if tenant != "" {
+			if !accept(tenant) {
+				return c.Status(fiber.StatusNotFound).JSON(
+					dto.ErrorResponse("Unknown tenant"),
+				)
+			}
+			c.SetUserContext(tenancy.WithTenant(c.UserContext(), tenant))
+			return c.Next()
+		}
+
+		if opts.Default != "" {
+			c.SetUserContext(tenancy.WithFallbackTenant(c.UserContext(), opts.Default))
+			return c.Next()
+		}
+		if _, ok := bearerToken(c); ok {
+			// The access token names the tenant
+			return c.Next()
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(
+			dto.ErrorResponse("Tenant required: set the " + opts.Header + " header"),
+		)
+	}
+}
+
+// subdomainTenant returns acme for host acme.<baseDomain>
+func subdomainTenant(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
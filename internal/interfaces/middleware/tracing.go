@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"strings"
+	"todo-backend/internal/domain/correlation"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request, continuing the trace of
+// the caller's traceparent header, if any. Handlers pass the span on with
+// the request's user context.
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) fiber.Handler {
+	tracer := provider.Tracer("todo-backend/internal/interfaces/middleware")
+	return func(c *fiber.Ctx) error {
+		// Spans are exported after the request, so their strings must not
+		// share its buffers
+		method := strings.Clone(c.Method())
+		ctx := propagator.Extract(c.UserContext(), requestHeaderCarrier{c})
+		// The span is renamed after its route once the request was routed
+		ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		handlerErr := c.Next()
+
+		status := handledStatus(c, handlerErr)
+		route := c.Route().Path
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", strings.Clone(loggedPath(c))),
+			attribute.Int("http.response.status_code", status),
+		)
+		if id, ok := correlation.FromContext(ctx); ok {
+			span.SetAttributes(attribute.StringSlice("http.request.header.x-request-id", []string{id}))
+		}
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fiber.ErrInternalServerError.Message)
+		}
+		if handlerErr != nil {
+			span.RecordError(handlerErr)
+		}
+		return handlerErr
+	}
+}
+
+// requestHeaderCarrier reads trace context from the request headers
+type requestHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h requestHeaderCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+// Set is not used for extraction
+func (h requestHeaderCarrier) Set(key, value string) {}
+
+func (h requestHeaderCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
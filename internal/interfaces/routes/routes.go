@@ -1,35 +1,237 @@
 package routes
 
 import (
+	"todo-backend/internal/domain/identity"
 	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
+// Dependencies holds the handlers and middleware wired into the routes
+type Dependencies struct {
+	TodoHandler  *handlers.TodoHandler
+	BatchHandler *handlers.BatchHandler
+	AuthHandler  *handlers.AuthHandler
+	// APIKeyHandler serves /api/keys when set
+	APIKeyHandler *handlers.APIKeyHandler
+	// ListHandler serves /api/lists and /api/invites when set
+	ListHandler *handlers.ListHandler
+	// ChangeStreamHandler serves /api/todos/stream when set
+	ChangeStreamHandler *handlers.ChangeStreamHandler
+	// CollaborationHandler serves the /api/ws WebSocket when set
+	CollaborationHandler *handlers.CollaborationHandler
+	// SyncHandler serves /api/sync when set
+	SyncHandler *handlers.SyncHandler
+	// CommentHandler serves /api/todos/:id/comments when set
+	CommentHandler *handlers.CommentHandler
+	// WebhookHandler serves /api/webhooks when set
+	WebhookHandler *handlers.WebhookHandler
+	// ShareLinkHandler serves /api/lists/:id/share-links and the public /s/:token when set
+	ShareLinkHandler *handlers.ShareLinkHandler
+	// ShareLinkRateLimit limits how often /s/:token can be requested when set
+	ShareLinkRateLimit fiber.Handler
+	// AuditHandler serves /api/admin/audit when set
+	AuditHandler *handlers.AuditHandler
+	// Audit records every mutating /api request when set
+	Audit fiber.Handler
+	// RateLimitHandler serves /api/admin/rate-limits when set
+	RateLimitHandler *handlers.RateLimitHandler
+	// LogLevelHandler serves /api/admin/log-level when set
+	LogLevelHandler *handlers.LogLevelHandler
+	// RequestID correlates every request with its logs, audit events and
+	// notifications when set
+	RequestID fiber.Handler
+	// Tracing starts a span for every request when set
+	Tracing fiber.Handler
+	// Metrics observes every request when set
+	Metrics fiber.Handler
+	// Logger logs every request when set
+	Logger fiber.Handler
+	// RateLimit limits every /api route per client when set
+	RateLimit fiber.Handler
+	// TenantHandler serves /api/tenant when set
+	TenantHandler *handlers.TenantHandler
+	// Tenant resolves the tenant of every /api route when set
+	Tenant fiber.Handler
+	// Authenticate resolves the caller of every /api route except the public auth routes
+	Authenticate fiber.Handler
+	// ClientCertificate authenticates services over mutual TLS ahead of Authenticate when set
+	ClientCertificate fiber.Handler
+	// Idempotency is applied to todo creation when set
+	Idempotency fiber.Handler
+	// CORS answers cross-origin requests when set
+	CORS fiber.Handler
+	// SecurityHeaders hardens every response when set
+	SecurityHeaders fiber.Handler
+	// LimitJSONBody rejects oversized JSON bodies before they are parsed when set
+	LimitJSONBody fiber.Handler
+	// Ready reports whether the instance accepts new requests; /health fails
+	// while it does not, e.g. during shutdown. Unset means always ready.
+	Ready func() bool
+	// HealthHandler serves the /livez, /readyz and /startupz probes when set
+	HealthHandler *handlers.HealthHandler
+}
+
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, todoHandler *handlers.TodoHandler) {
+func SetupRoutes(app *fiber.App, deps Dependencies) {
 	// Middleware
-	app.Use(logger.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,DELETE,OPTIONS",
-		AllowHeaders: "Origin, Content-Type, Accept",
-	}))
+	app.Use(optional(deps.RequestID))
+	app.Use(optional(deps.Tracing))
+	app.Use(optional(deps.Metrics))
+	app.Use(optional(deps.Logger))
+	app.Use(optional(deps.SecurityHeaders))
+	app.Use(optional(deps.CORS))
+	app.Use(optional(deps.LimitJSONBody))
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
+		if deps.Ready != nil && !deps.Ready() {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"status":  "draining",
+				"message": "Todo API is shutting down",
+			})
+		}
 		return c.JSON(fiber.Map{
-			"status": "ok",
+			"status":  "ok",
 			"message": "Todo API is running",
 		})
 	})
 
-	// API v1 routes
-	api := app.Group("/api")
-	
-	// Todo routes - exactly as specified in requirements
-	api.Get("/todos", todoHandler.GetTodos)        // GET /api/todos - List all todos
-	api.Post("/todos", todoHandler.CreateTodo)     // POST /api/todos - Create new todo
-} 
\ No newline at end of file
+	// Orchestrator probes
+	if deps.HealthHandler != nil {
+		app.Get("/livez", deps.HealthHandler.Live)       // GET /livez - Restart when failing
+		app.Get("/readyz", deps.HealthHandler.Ready)     // GET /readyz - Route requests while passing
+		app.Get("/startupz", deps.HealthHandler.Startup) // GET /startupz - Started
+	}
+
+	// Public keys for verifying access tokens
+	app.Get("/.well-known/jwks.json", deps.AuthHandler.JWKS)
+
+	// Public read-only share links - anonymous, but still within a tenant
+	if deps.ShareLinkHandler != nil {
+		app.Get("/s/:token", optional(deps.Tenant), optional(deps.ShareLinkRateLimit), deps.ShareLinkHandler.OpenShareLink) // GET /s/:token - View a shared list
+	}
+
+	// API v1 routes - every one of them runs in a tenant and mutations are audited
+	api := app.Group("/api", optional(deps.Tenant), optional(deps.Audit))
+
+	// Public auth routes - registered before the authentication middleware so
+	// that they answer without a token. They are rate limited per client IP.
+	auth := api.Group("/auth")
+	limit := optional(deps.RateLimit)
+	auth.Post("/register", limit, deps.AuthHandler.Register) // POST /api/auth/register - Create an account
+	auth.Post("/login", limit, deps.AuthHandler.Login)       // POST /api/auth/login - Start a session
+	auth.Post("/refresh", limit, deps.AuthHandler.Refresh)   // POST /api/auth/refresh - Rotate the token pair
+
+	// Everything registered below requires an authenticated caller and is
+	// rate limited per API key or user
+	api.Use(optional(deps.ClientCertificate), deps.Authenticate, limit)
+
+	auth.Post("/logout", deps.AuthHandler.Logout)          // POST /api/auth/logout - End the current session
+	auth.Put("/password", deps.AuthHandler.ChangePassword) // PUT /api/auth/password - Change password
+	auth.Get("/me", deps.AuthHandler.Me)                   // GET /api/auth/me - Current user
+
+	// API key routes - managed from a signed-in session
+	if deps.APIKeyHandler != nil {
+		keys := api.Group("/keys")
+		keys.Get("", deps.APIKeyHandler.ListAPIKeys)         // GET /api/keys - List API keys
+		keys.Post("", deps.APIKeyHandler.CreateAPIKey)       // POST /api/keys - Create an API key
+		keys.Delete("/:id", deps.APIKeyHandler.RevokeAPIKey) // DELETE /api/keys/:id - Revoke an API key
+	}
+
+	// Admin routes
+	if deps.AuditHandler != nil || deps.RateLimitHandler != nil || deps.LogLevelHandler != nil {
+		admin := api.Group("/admin", middleware.RequireScope(identity.ScopeAdmin))
+		if deps.AuditHandler != nil {
+			admin.Get("/audit", deps.AuditHandler.GetAuditEvents)          // GET /api/admin/audit - Query the audit log
+			admin.Get("/audit/verify", deps.AuditHandler.VerifyAuditChain) // GET /api/admin/audit/verify - Verify the audit chain
+		}
+		if deps.RateLimitHandler != nil {
+			admin.Get("/rate-limits", deps.RateLimitHandler.GetBuckets)      // GET /api/admin/rate-limits - Inspect rate limit buckets
+			admin.Delete("/rate-limits", deps.RateLimitHandler.ResetBuckets) // DELETE /api/admin/rate-limits - Reset a client's buckets
+		}
+		if deps.LogLevelHandler != nil {
+			admin.Get("/log-level", deps.LogLevelHandler.GetLogLevel) // GET /api/admin/log-level - Current log level
+			admin.Put("/log-level", deps.LogLevelHandler.SetLogLevel) // PUT /api/admin/log-level - Change the log level until restart
+		}
+	}
+
+	// Todo routes
+	read := middleware.RequireScope(identity.ScopeTodosRead)
+	write := middleware.RequireScope(identity.ScopeTodosWrite)
+
+	if deps.TenantHandler != nil {
+		api.Get("/tenant", read, deps.TenantHandler.GetTenant) // GET /api/tenant - Tenant usage and quota
+	}
+
+	todos := api.Group("/todos")
+	todos.Get("", read, deps.TodoHandler.GetTodos)                                 // GET /api/todos - List all todos
+	todos.Post("", write, optional(deps.Idempotency), deps.TodoHandler.CreateTodo) // POST /api/todos - Create new todo
+	if deps.BatchHandler != nil {
+		todos.Post("/batch", write, deps.BatchHandler.ExecuteBatch) // POST /api/todos/batch - Apply several operations
+	}
+	if deps.ChangeStreamHandler != nil {
+		todos.Get("/stream", read, deps.ChangeStreamHandler.StreamChanges) // GET /api/todos/stream - Stream changes as Server-Sent Events
+	}
+	todos.Patch("/:id", write, deps.TodoHandler.PatchTodo) // PATCH /api/todos/:id - Partially update a todo
+	if deps.CommentHandler != nil {
+		todos.Get("/:id/comments", read, deps.CommentHandler.GetComments)  // GET /api/todos/:id/comments - List comments
+		todos.Post("/:id/comments", write, deps.CommentHandler.AddComment) // POST /api/todos/:id/comments - Comment on a todo
+	}
+
+	// Shared list routes
+	if deps.ListHandler != nil {
+		lists := api.Group("/lists")
+		lists.Get("", read, deps.ListHandler.GetLists)                               // GET /api/lists - Lists the caller belongs to
+		lists.Post("", write, deps.ListHandler.CreateList)                           // POST /api/lists - Create a list
+		lists.Get("/:id", read, deps.ListHandler.GetList)                            // GET /api/lists/:id - Get a list
+		lists.Delete("/:id", write, deps.ListHandler.DeleteList)                     // DELETE /api/lists/:id - Delete a list and its todos
+		lists.Get("/:id/members", read, deps.ListHandler.GetMembers)                 // GET /api/lists/:id/members - List members
+		lists.Put("/:id/members/:userId", write, deps.ListHandler.UpdateMemberRole)  // PUT /api/lists/:id/members/:userId - Change a member's role
+		lists.Delete("/:id/members/:userId", write, deps.ListHandler.RemoveMember)   // DELETE /api/lists/:id/members/:userId - Remove a member or leave
+		lists.Get("/:id/invites", read, deps.ListHandler.GetInvites)                 // GET /api/lists/:id/invites - List invites
+		lists.Post("/:id/invites", write, deps.ListHandler.CreateInvite)             // POST /api/lists/:id/invites - Create an invite
+		lists.Delete("/:id/invites/:inviteId", write, deps.ListHandler.RevokeInvite) // DELETE /api/lists/:id/invites/:inviteId - Revoke an invite
+		api.Post("/invites/accept", write, deps.ListHandler.AcceptInvite)            // POST /api/invites/accept - Join a list
+	}
+	if deps.CollaborationHandler != nil {
+		api.Get("/ws", read, deps.CollaborationHandler.Connect) // GET /api/ws - Collaborate on lists over a WebSocket
+	}
+	if deps.SyncHandler != nil {
+		api.Post("/sync", read, deps.SyncHandler.Sync) // POST /api/sync - Catch up and send offline changes
+	}
+	if deps.WebhookHandler != nil {
+		webhooks := api.Group("/webhooks")
+		webhooks.Get("", read, deps.WebhookHandler.ListWebhooks)                                     // GET /api/webhooks - List webhooks
+		webhooks.Post("", write, deps.WebhookHandler.CreateWebhook)                                  // POST /api/webhooks - Register a webhook
+		webhooks.Get("/:id", read, deps.WebhookHandler.GetWebhook)                                   // GET /api/webhooks/:id - Get a webhook
+		webhooks.Delete("/:id", write, deps.WebhookHandler.DeleteWebhook)                            // DELETE /api/webhooks/:id - Delete a webhook
+		webhooks.Get("/:id/deliveries", read, deps.WebhookHandler.ListDeliveries)                    // GET /api/webhooks/:id/deliveries - Delivery log
+		webhooks.Get("/:id/deliveries/:deliveryId", read, deps.WebhookHandler.GetDelivery)           // GET /api/webhooks/:id/deliveries/:deliveryId - Delivery with its attempts
+		webhooks.Post("/:id/deliveries/:deliveryId/redeliver", write, deps.WebhookHandler.Redeliver) // POST /api/webhooks/:id/deliveries/:deliveryId/redeliver - Redeliver
+	}
+	if deps.ShareLinkHandler != nil {
+		links := api.Group("/lists/:id/share-links")
+		links.Get("", read, deps.ShareLinkHandler.GetShareLinks)                         // GET /api/lists/:id/share-links - List share links
+		links.Post("", write, deps.ShareLinkHandler.CreateShareLink)                     // POST /api/lists/:id/share-links - Create a share link
+		links.Delete("/:linkId", write, deps.ShareLinkHandler.RevokeShareLink)           // DELETE /api/lists/:id/share-links/:linkId - Revoke a share link
+		links.Get("/:linkId/accesses", read, deps.ShareLinkHandler.GetShareLinkAccesses) // GET /api/lists/:id/share-links/:linkId/accesses - Access log
+	}
+}
+
+// SetupInternalRoutes configures the routes of the internal listener, which
+// is kept off the public port: the metrics cover every tenant
+func SetupInternalRoutes(app *fiber.App, metricsHandler fiber.Handler) {
+	app.Get("/metrics", metricsHandler) // GET /metrics - Prometheus text format
+}
+
+// optional returns a pass-through handler when middleware is not configured
+func optional(middleware fiber.Handler) fiber.Handler {
+	if middleware == nil {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	return middleware
+}
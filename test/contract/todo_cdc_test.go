@@ -8,8 +8,12 @@ import (
 	"testing"
 	"time"
 	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/config"
 	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/security"
+	"todo-backend/internal/interfaces/dto"
 	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
 	"todo-backend/internal/interfaces/routes"
 
 	"github.com/gofiber/fiber/v2"
@@ -22,8 +26,10 @@ import (
 // These tests verify that our backend satisfies the exact contract expected by TodoFrontend
 type TodoCDCProviderSuite struct {
 	suite.Suite
-	app *fiber.App
-	db  *gorm.DB
+	app    *fiber.App
+	db     *gorm.DB
+	token  string
+	userID string
 }
 
 func (suite *TodoCDCProviderSuite) SetupSuite() {
@@ -31,7 +37,7 @@ func (suite *TodoCDCProviderSuite) SetupSuite() {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	suite.Require().NoError(err)
 	
-	err = db.AutoMigrate(&database.SQLiteTodoModel{})
+	err = database.Migrate(db)
 	suite.Require().NoError(err)
 	
 	suite.db = db
@@ -40,10 +46,45 @@ func (suite *TodoCDCProviderSuite) SetupSuite() {
 	todoRepo := database.NewSQLiteTodoRepository(db)
 	todoUseCase := usecases.NewTodoUseCase(todoRepo)
 	todoHandler := handlers.NewTodoHandler(todoUseCase)
+	jwtManager, err := security.NewJWTManager(config.JWTConfig{Algorithm: "EdDSA", AccessTTL: time.Hour})
+	suite.Require().NoError(err)
+	authUseCase := usecases.NewAuthUseCase(
+		database.NewSQLiteUserRepository(db),
+		database.NewSQLiteSessionRepository(db),
+		database.NewSQLiteRefreshTokenRepository(db),
+		security.NewArgon2idHasher(security.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}),
+		jwtManager,
+		time.Hour,
+	)
 	
 	app := fiber.New()
-	routes.SetupRoutes(app, todoHandler)
+	routes.SetupRoutes(app, routes.Dependencies{
+		TodoHandler:  todoHandler,
+		AuthHandler:  handlers.NewAuthHandler(authUseCase),
+		Authenticate: middleware.Authenticate(authUseCase),
+	})
 	suite.app = app
+	suite.signIn()
+}
+
+// signIn registers the consumer's user so that contract requests can authenticate
+func (suite *TodoCDCProviderSuite) signIn() {
+	credentials, _ := json.Marshal(map[string]string{"username": "contract-consumer", "password": "correct horse battery"})
+
+	req := httptest.NewRequest("POST", "/api/auth/register", bytes.NewReader(credentials))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	req = httptest.NewRequest("POST", "/api/auth/login", bytes.NewReader(credentials))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+	suite.Require().NoError(err)
+
+	var login dto.LoginResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&login))
+	suite.token, suite.userID = login.AccessToken, login.User.ID
 }
 
 func (suite *TodoCDCProviderSuite) TearDownTest() {
@@ -57,6 +98,7 @@ func (suite *TodoCDCProviderSuite) TestGetAllTodos_NoTodosExist() {
 	// Provider State: no todos exist (database is clean)
 	
 	req := httptest.NewRequest("GET", "/api/todos", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	resp, err := suite.app.Test(req)
 	
 	suite.NoError(err)
@@ -86,11 +128,13 @@ func (suite *TodoCDCProviderSuite) TestGetAllTodos_TodosExist() {
 	suite.db.Create(&database.SQLiteTodoModel{
 		ID:        "uuid-123",
 		Text:      "buy some milk",
+		OwnerID:   suite.userID,
 		CreatedAt: fixedTime.Unix(),
 		UpdatedAt: fixedTime.Unix(),
 	})
 	
 	req := httptest.NewRequest("GET", "/api/todos", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	resp, err := suite.app.Test(req)
 	
 	suite.NoError(err)
@@ -139,6 +183,7 @@ func (suite *TodoCDCProviderSuite) TestCreateTodo_BackendReady() {
 	
 	req := httptest.NewRequest("POST", "/api/todos", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	
 	resp, err := suite.app.Test(req)
 	suite.NoError(err)
@@ -8,7 +8,6 @@ import (
 	"testing"
 	"todo-backend/internal/application/usecases"
 	"todo-backend/internal/infrastructure/database"
-	"todo-backend/internal/interfaces/handlers"
 	"todo-backend/internal/interfaces/routes"
 
 	"github.com/gofiber/fiber/v2"
@@ -21,8 +20,10 @@ import (
 // This follows the TDD workflow: integration test → routing code → business code
 type APIIntegrationTestSuite struct {
 	suite.Suite
-	app *fiber.App
-	db  *gorm.DB
+	app    *fiber.App
+	db     *gorm.DB
+	token  string
+	userID string
 }
 
 func (suite *APIIntegrationTestSuite) SetupSuite() {
@@ -30,7 +31,7 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	suite.Require().NoError(err)
 	
-	err = db.AutoMigrate(&database.SQLiteTodoModel{})
+	err = database.Migrate(db)
 	suite.Require().NoError(err)
 	
 	suite.db = db
@@ -38,11 +39,11 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 	// Setup application (integration level)
 	todoRepo := database.NewSQLiteTodoRepository(db)
 	todoUseCase := usecases.NewTodoUseCase(todoRepo)
-	todoHandler := handlers.NewTodoHandler(todoUseCase)
 	
 	app := fiber.New()
-	routes.SetupRoutes(app, todoHandler)
+	routes.SetupRoutes(app, newTestDependencies(db, todoUseCase))
 	suite.app = app
+	suite.token, suite.userID = signUp(suite.T(), app, "api-tester")
 }
 
 func (suite *APIIntegrationTestSuite) TearDownTest() {
@@ -57,6 +58,7 @@ func (suite *APIIntegrationTestSuite) TestCreateTodoAPI_Integration() {
 	
 	req := httptest.NewRequest("POST", "/api/todos", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	
 	resp, err := suite.app.Test(req)
 	suite.NoError(err)
@@ -81,15 +83,18 @@ func (suite *APIIntegrationTestSuite) TestCreateTodoAPI_Integration() {
 func (suite *APIIntegrationTestSuite) TestGetTodosAPI_Integration() {
 	// Seed test data
 	suite.db.Create(&database.SQLiteTodoModel{
-		ID:   "test-id-1",
-		Text: "Test Todo 1",
+		ID:      "test-id-1",
+		Text:    "Test Todo 1",
+		OwnerID: suite.userID,
 	})
 	suite.db.Create(&database.SQLiteTodoModel{
-		ID:   "test-id-2", 
-		Text: "Test Todo 2",
+		ID:      "test-id-2",
+		Text:    "Test Todo 2",
+		OwnerID: suite.userID,
 	})
 	
 	req := httptest.NewRequest("GET", "/api/todos", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	resp, err := suite.app.Test(req)
 	
 	suite.NoError(err)
@@ -114,6 +119,7 @@ func (suite *APIIntegrationTestSuite) TestGetTodosAPI_Integration() {
 func (suite *APIIntegrationTestSuite) TestErrorHandling_BadJSON() {
 	req := httptest.NewRequest("POST", "/api/todos", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
 	
 	resp, err := suite.app.Test(req)
 	suite.NoError(err)
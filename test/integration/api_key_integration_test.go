@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// APIKeyIntegrationTestSuite tests personal API keys and per-route scopes over HTTP
+type APIKeyIntegrationTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	db    *gorm.DB
+	token string
+}
+
+func (suite *APIKeyIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoUseCase := usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db))
+
+	app := fiber.New()
+	routes.SetupRoutes(app, newTestDependencies(db, todoUseCase, usecases.WithAdminUsernames("root")))
+	suite.app = app
+	suite.token, _ = signUp(suite.T(), app, "alice")
+}
+
+func (suite *APIKeyIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *APIKeyIntegrationTestSuite) createKey(token string, body map[string]interface{}) (*http.Response, dto.CreatedAPIKeyResponse) {
+	resp := suite.do(jsonRequest("POST", "/api/keys", token, body))
+	var created dto.CreatedAPIKeyResponse
+	if resp.StatusCode == http.StatusCreated {
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&created))
+	}
+	return resp, created
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestCreate_ShowsKeyOnceAndStoresOnlyHash() {
+	resp, created := suite.createKey(suite.token, map[string]interface{}{"name": "backup script", "scopes": []string{"todos:read"}})
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	suite.True(strings.HasPrefix(created.Key, "tdk_"+created.Prefix+"_"))
+	suite.Equal([]string{"todos:read"}, created.Scopes)
+
+	var model database.SQLiteAPIKeyModel
+	suite.Require().NoError(suite.db.First(&model).Error)
+	suite.NotEqual(created.Key, model.KeyHash)
+	suite.NotContains(model.KeyHash, created.Key)
+
+	resp = suite.do(jsonRequest("GET", "/api/keys", suite.token, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var raw []map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&raw))
+	suite.Require().Len(raw, 1)
+	suite.Equal(created.Prefix, raw[0]["prefix"])
+	suite.NotContains(raw[0], "key")
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestScopesAreEnforcedPerRoute() {
+	_, readOnly := suite.createKey(suite.token, map[string]interface{}{"name": "reader", "scopes": []string{"todos:read"}})
+	_, writer := suite.createKey(suite.token, map[string]interface{}{"name": "writer", "scopes": []string{"todos:read", "todos:write"}})
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", readOnly.Key, nil)).StatusCode)
+
+	resp := suite.do(jsonRequest("POST", "/api/todos", readOnly.Key, dto.CreateTodoRequest{Text: "from a script"}))
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+	suite.Contains(resp.Header.Get("WWW-Authenticate"), `error="insufficient_scope"`)
+
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", writer.Key, dto.CreateTodoRequest{Text: "from a script"})).StatusCode)
+
+	// Keys act for their owner
+	resp = suite.do(jsonRequest("GET", "/api/todos", suite.token, nil))
+	var todos []map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todos))
+	suite.Len(todos, 1)
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestCannotGrantScopesTheCallerLacks() {
+	resp, _ := suite.createKey(suite.token, map[string]interface{}{"name": "escalate", "scopes": []string{"admin"}})
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp, _ = suite.createKey(suite.token, map[string]interface{}{"name": "typo", "scopes": []string{"todos:delete"}})
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = suite.createKey(suite.token, map[string]interface{}{"name": "none", "scopes": []string{}})
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	rootToken, _ := signUp(suite.T(), suite.app, "root")
+	resp, created := suite.createKey(rootToken, map[string]interface{}{"name": "ops", "scopes": []string{"admin"}})
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	// admin implies the other scopes
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", created.Key, nil)).StatusCode)
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestKeyLosesScopesItsUserNoLongerHolds() {
+	rootToken, _ := signUp(suite.T(), suite.app, "root")
+	_, created := suite.createKey(rootToken, map[string]interface{}{"name": "ops", "scopes": []string{"admin"}})
+	suite.Require().NotEmpty(created.Key)
+
+	// Restart without root among the admins
+	deps := newTestDependencies(suite.db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(suite.db)))
+	deps.LogLevelHandler = handlers.NewLogLevelHandler(new(slog.LevelVar))
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+
+	resp, err := app.Test(jsonRequest("GET", "/api/admin/log-level", created.Key, nil))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	// The key still carries the scopes root holds as an ordinary user
+	resp, err = app.Test(jsonRequest("GET", "/api/todos", created.Key, nil))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestKeysCannotManageKeysOrPasswords() {
+	_, created := suite.createKey(suite.token, map[string]interface{}{"name": "writer", "scopes": []string{"todos:read", "todos:write"}})
+
+	resp, _ := suite.createKey(created.Key, map[string]interface{}{"name": "child", "scopes": []string{"todos:read"}})
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("PUT", "/api/auth/password", created.Key, dto.ChangePasswordRequest{CurrentPassword: "correct horse battery", NewPassword: "a new passphrase"}))
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestExpiryRevocationAndLastUsed() {
+	resp, _ := suite.createKey(suite.token, map[string]interface{}{"name": "past", "scopes": []string{"todos:read"}, "expiresAt": time.Now().Add(-time.Hour)})
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	_, created := suite.createKey(suite.token, map[string]interface{}{"name": "soon", "scopes": []string{"todos:read"}, "expiresAt": time.Now().Add(time.Hour)})
+	suite.Empty(created.LastUsedAt)
+	suite.NotEmpty(created.ExpiresAt)
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", created.Key, nil)).StatusCode)
+
+	var model database.SQLiteAPIKeyModel
+	suite.Require().NoError(suite.db.First(&model, "id = ?", created.ID).Error)
+	suite.NotNil(model.LastUsedAt)
+
+	// Expired keys stop working
+	suite.db.Model(&database.SQLiteAPIKeyModel{}).Where("id = ?", created.ID).Update("expires_at", time.Now().Add(-time.Minute).Unix())
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", created.Key, nil)).StatusCode)
+
+	// Revoked keys stop working, and other users cannot revoke them
+	_, other := suite.createKey(suite.token, map[string]interface{}{"name": "other", "scopes": []string{"todos:read"}})
+	bobToken, _ := signUp(suite.T(), suite.app, "bob")
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("DELETE", "/api/keys/"+other.ID, bobToken, nil)).StatusCode)
+	suite.Equal(http.StatusNoContent, suite.do(jsonRequest("DELETE", "/api/keys/"+other.ID, suite.token, nil)).StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", other.Key, nil)).StatusCode)
+}
+
+func (suite *APIKeyIntegrationTestSuite) TestRejectsTamperedKey() {
+	_, created := suite.createKey(suite.token, map[string]interface{}{"name": "reader", "scopes": []string{"todos:read"}})
+
+	tampered := created.Key[:len(created.Key)-1] + "x"
+	if tampered == created.Key {
+		tampered = created.Key[:len(created.Key)-1] + "y"
+	}
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", tampered, nil)).StatusCode)
+}
+
+func TestAPIKeyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyIntegrationTestSuite))
+}
@@ -0,0 +1,214 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// AuditIntegrationTestSuite tests the audit log and its hash chain over HTTP
+type AuditIntegrationTestSuite struct {
+	suite.Suite
+	app          *fiber.App
+	db           *gorm.DB
+	auditUseCase *usecases.AuditUseCase
+	admin        string
+}
+
+func (suite *AuditIntegrationTestSuite) SetupTest() {
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todo.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.Require().NoError(db.Use(database.NewTenantScoping(nil)))
+	suite.db = db
+
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+	)
+	suite.auditUseCase = usecases.NewAuditUseCase(database.NewSQLiteAuditRepository(db))
+
+	deps := newTestDependencies(db, todoUseCase, usecases.WithAdminUsernames("root"))
+	deps.Tenant = middleware.ResolveTenant(middleware.TenantOptions{Default: "acme"})
+	deps.AuditHandler = handlers.NewAuditHandler(suite.auditUseCase)
+	deps.Audit = middleware.Audit(suite.auditUseCase)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.admin, _ = signUp(suite.T(), app, "root")
+}
+
+func (suite *AuditIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *AuditIntegrationTestSuite) events(query string) []dto.AuditEventResponse {
+	resp := suite.do(jsonRequest("GET", "/api/admin/audit"+query, suite.admin, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var events []dto.AuditEventResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&events))
+	return events
+}
+
+func (suite *AuditIntegrationTestSuite) verify() dto.AuditVerificationResponse {
+	resp := suite.do(jsonRequest("GET", "/api/admin/audit/verify", suite.admin, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var verification dto.AuditVerificationResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&verification))
+	return verification
+}
+
+// exec runs raw SQL against the audit table, bypassing the application
+func (suite *AuditIntegrationTestSuite) exec(sql string, args ...interface{}) error {
+	return suite.db.WithContext(tenancy.WithAllTenants(context.Background())).Exec(sql, args...).Error
+}
+
+func (suite *AuditIntegrationTestSuite) createTodo(text string) dto.ContractTodoResponse {
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.admin, map[string]string{"text": text}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var todo dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todo))
+	return todo
+}
+
+func (suite *AuditIntegrationTestSuite) TestMutationsAreChained() {
+	todo := suite.createTodo("Audit me")
+	req := jsonRequest("PATCH", "/api/todos/"+todo.ID, suite.admin, map[string]bool{"completed": true})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	suite.Require().Equal(http.StatusOK, suite.do(req).StatusCode)
+
+	events := suite.events("")
+	suite.Require().Len(events, 4)
+	suite.Equal([]string{audit.ActionTodoCompleted, audit.ActionTodoCreated, audit.ActionSessionStarted, audit.ActionUserRegistered},
+		[]string{events[0].Action, events[1].Action, events[2].Action, events[3].Action})
+
+	completed := events[0]
+	suite.Equal(todo.ID, completed.TargetID)
+	suite.Equal("req-42", completed.RequestID)
+	suite.Equal(http.StatusOK, completed.Status)
+	suite.NotEmpty(completed.ActorID)
+	suite.NotEmpty(completed.BeforeHash)
+	suite.NotEqual(completed.BeforeHash, completed.AfterHash)
+	suite.Equal(events[1].AfterHash, completed.BeforeHash)
+
+	for i := 0; i < len(events)-1; i++ {
+		suite.Equal(events[i+1].Hash, events[i].PrevHash)
+	}
+	suite.Equal(entities.AuditGenesisHash, events[3].PrevHash)
+	suite.Equal(events[3].ActorID, completed.ActorID, "sign-ups are attributed to the new user")
+
+	verification := suite.verify()
+	suite.True(verification.OK)
+	suite.Equal(int64(4), verification.Events)
+	suite.Equal(completed.Hash, verification.LastHash)
+}
+
+func (suite *AuditIntegrationTestSuite) TestFailedRequestsAreRecordedWithoutChanges() {
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.admin, map[string]string{"text": ""}))
+	suite.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	events := suite.events("?limit=1")
+	suite.Require().Len(events, 1)
+	suite.Equal("POST /api/todos", events[0].Action)
+	suite.Equal(http.StatusBadRequest, events[0].Status)
+	suite.Empty(events[0].BeforeHash)
+	suite.Empty(events[0].AfterHash)
+	suite.NotEmpty(events[0].RequestID)
+}
+
+func (suite *AuditIntegrationTestSuite) TestReadsAreNotRecorded() {
+	before := len(suite.events(""))
+	suite.Require().Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", suite.admin, nil)).StatusCode)
+	suite.Len(suite.events(""), before)
+}
+
+func (suite *AuditIntegrationTestSuite) TestQueryFilters() {
+	first := suite.createTodo("First")
+	suite.createTodo("Second")
+
+	events := suite.events("?action=" + audit.ActionTodoCreated)
+	suite.Require().Len(events, 2)
+
+	events = suite.events("?target=" + first.ID)
+	suite.Require().Len(events, 1)
+	suite.Equal(first.ID, events[0].TargetID)
+
+	older := suite.events("?before=" + strconv.FormatInt(events[0].Seq, 10))
+	for _, event := range older {
+		suite.Less(event.Seq, events[0].Seq)
+	}
+
+	resp := suite.do(jsonRequest("GET", "/api/admin/audit?since=yesterday", suite.admin, nil))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *AuditIntegrationTestSuite) TestRequiresAdmin() {
+	token, _ := signUp(suite.T(), suite.app, "mallory")
+
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/admin/audit", token, nil)).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/admin/audit/verify", token, nil)).StatusCode)
+}
+
+func (suite *AuditIntegrationTestSuite) TestEventsCannotBeChanged() {
+	suite.Error(suite.exec("UPDATE audit_events SET action = 'forged'"))
+	suite.Error(suite.exec("DELETE FROM audit_events"))
+	suite.True(suite.verify().OK)
+}
+
+func (suite *AuditIntegrationTestSuite) TestVerifyDetectsTampering() {
+	suite.createTodo("First")
+	suite.createTodo("Second")
+	suite.Require().NoError(suite.exec("DROP TRIGGER audit_events_no_update"))
+	suite.Require().NoError(suite.exec("DROP TRIGGER audit_events_no_delete"))
+
+	suite.Require().NoError(suite.exec("UPDATE audit_events SET actor_id = 'someone-else' WHERE seq = 1"))
+	suite.Require().NoError(suite.exec("DELETE FROM audit_events WHERE seq = 3"))
+
+	verification := suite.verify()
+	suite.False(verification.OK)
+	kinds := map[int64]string{}
+	for _, problem := range verification.Problems {
+		kinds[problem.Seq] = problem.Kind
+	}
+	suite.Equal(map[int64]string{1: usecases.AuditProblemTampered, 4: usecases.AuditProblemGap}, kinds)
+}
+
+func (suite *AuditIntegrationTestSuite) TestVerifyEveryTenant() {
+	suite.createTodo("Audit me")
+
+	var results []*usecases.AuditVerification
+	err := database.ForEachTenant(context.Background(), suite.db, func(ctx context.Context) error {
+		var err error
+		results, err = suite.auditUseCase.VerifyAll(ctx)
+		return err
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 1)
+	suite.Equal("acme", results[0].TenantID)
+	suite.True(results[0].OK())
+	suite.Equal(int64(3), results[0].Events)
+}
+
+func TestAuditIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditIntegrationTestSuite))
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/security"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testHasherParams keeps argon2id cheap so that tests stay fast
+var testHasherParams = security.Argon2Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// testJWTConfig signs access tokens with a fixed HS256 key
+var testJWTConfig = config.JWTConfig{
+	Issuer:      "todo-backend-test",
+	Audience:    "todo-api",
+	Algorithm:   "HS256",
+	AccessTTL:   15 * time.Minute,
+	ActiveKeyID: "test-key",
+	Keys:        []config.JWTKeyConfig{{ID: "test-key", Secret: "an-hs256-secret-of-at-least-32-bytes"}},
+}
+
+// ownerContext returns a context authenticated as userID
+func ownerContext(userID string) context.Context {
+	return identity.WithPrincipal(context.Background(), &identity.Principal{UserID: userID})
+}
+
+func newTestAuthUseCase(db *gorm.DB, opts ...usecases.AuthUseCaseOption) *usecases.AuthUseCase {
+	return newTestAuthUseCaseWithJWT(db, testJWTConfig, opts...)
+}
+
+func newTestAuthUseCaseWithJWT(db *gorm.DB, jwtConfig config.JWTConfig, opts ...usecases.AuthUseCaseOption) *usecases.AuthUseCase {
+	jwtManager, err := security.NewJWTManager(jwtConfig)
+	if err != nil {
+		panic(err)
+	}
+	return usecases.NewAuthUseCase(
+		database.NewSQLiteUserRepository(db),
+		database.NewSQLiteSessionRepository(db),
+		database.NewSQLiteRefreshTokenRepository(db),
+		security.NewArgon2idHasher(testHasherParams),
+		jwtManager,
+		time.Hour,
+		opts...,
+	)
+}
+
+// newTestDependencies wires the todo, auth, API key, list, comment and share link handlers the way cmd/main.go does
+func newTestDependencies(db *gorm.DB, todoUseCase *usecases.TodoUseCase, opts ...usecases.AuthUseCaseOption) routes.Dependencies {
+	authUseCase := newTestAuthUseCase(db, opts...)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(database.NewSQLiteAPIKeyRepository(db), authUseCase)
+	listUseCase := usecases.NewListUseCase(database.NewSQLiteListRepository(db), database.NewSQLiteListInviteRepository(db), time.Hour, usecases.WithListTodos(todoUseCase))
+	commentUseCase := usecases.NewCommentUseCase(todoUseCase, database.NewSQLiteCommentRepository(db))
+	shareLinkUseCase := usecases.NewShareLinkUseCase(
+		database.NewSQLiteListRepository(db),
+		database.NewSQLiteShareLinkRepository(db),
+		security.NewArgon2idHasher(testHasherParams),
+	)
+	return routes.Dependencies{
+		TodoHandler:      handlers.NewTodoHandler(todoUseCase),
+		AuthHandler:      handlers.NewAuthHandler(authUseCase),
+		APIKeyHandler:    handlers.NewAPIKeyHandler(apiKeyUseCase),
+		ListHandler:      handlers.NewListHandler(listUseCase),
+		CommentHandler:   handlers.NewCommentHandler(commentUseCase),
+		ShareLinkHandler: handlers.NewShareLinkHandler(shareLinkUseCase),
+		Authenticate:     middleware.Authenticate(authUseCase, apiKeyUseCase),
+	}
+}
+
+// signUp registers username and logs in, returning the access token and user ID
+func signUp(t *testing.T, app *fiber.App, username string) (string, string) {
+	t.Helper()
+	login := signUpForTokens(t, app, username)
+	return login.AccessToken, login.User.ID
+}
+
+// signUpForTokens registers username and returns the full login response
+func signUpForTokens(t *testing.T, app *fiber.App, username string) dto.LoginResponse {
+	t.Helper()
+	credentials := map[string]string{"username": username, "password": "correct horse battery"}
+
+	resp, err := app.Test(jsonRequest("POST", "/api/auth/register", "", credentials))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, err = app.Test(jsonRequest("POST", "/api/auth/login", "", credentials))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var login dto.LoginResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+	return login
+}
+
+// jsonRequest builds a request with an optional JSON body and bearer token
+func jsonRequest(method, path, token string, body interface{}) *http.Request {
+	var reader io.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// AuthIntegrationTestSuite tests user accounts and per-user todo ownership over HTTP
+type AuthIntegrationTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	db  *gorm.DB
+}
+
+func (suite *AuthIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoUseCase := usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db))
+
+	app := fiber.New()
+	routes.SetupRoutes(app, newTestDependencies(db, todoUseCase))
+	suite.app = app
+}
+
+func (suite *AuthIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *AuthIntegrationTestSuite) login(username, password string) *http.Response {
+	return suite.do(jsonRequest("POST", "/api/auth/login", "", map[string]string{"username": username, "password": password}))
+}
+
+func (suite *AuthIntegrationTestSuite) TestHealthIsPublic_TodosAreNot() {
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/health", "", nil)).StatusCode)
+
+	resp := suite.do(jsonRequest("GET", "/api/todos", "", nil))
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	suite.Equal("Bearer", resp.Header.Get("WWW-Authenticate"))
+
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", "not-a-token", nil)).StatusCode)
+}
+
+func (suite *AuthIntegrationTestSuite) TestRegister_Validation() {
+	signUp(suite.T(), suite.app, "Alice")
+
+	resp := suite.do(jsonRequest("POST", "/api/auth/register", "", map[string]string{"username": "alice", "password": "another password"}))
+	suite.Equal(http.StatusConflict, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("POST", "/api/auth/register", "", map[string]string{"username": "bob", "password": "short"}))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var count int64
+	suite.db.Model(&database.SQLiteUserModel{}).Count(&count)
+	suite.Equal(int64(1), count)
+
+	// Only the argon2id hash is stored
+	var user database.SQLiteUserModel
+	suite.db.First(&user)
+	suite.Contains(user.PasswordHash, "$argon2id$")
+	suite.NotContains(user.PasswordHash, "correct horse battery")
+}
+
+func (suite *AuthIntegrationTestSuite) TestLogin_WrongPasswordAndUnknownUser() {
+	signUp(suite.T(), suite.app, "alice")
+
+	suite.Equal(http.StatusUnauthorized, suite.login("alice", "wrong password").StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.login("mallory", "correct horse battery").StatusCode)
+	suite.Equal(http.StatusOK, suite.login("ALICE", "correct horse battery").StatusCode)
+}
+
+func (suite *AuthIntegrationTestSuite) TestLogout_InvalidatesToken() {
+	token, _ := signUp(suite.T(), suite.app, "alice")
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/auth/me", token, nil)).StatusCode)
+
+	suite.Equal(http.StatusNoContent, suite.do(jsonRequest("POST", "/api/auth/logout", token, nil)).StatusCode)
+
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/auth/me", token, nil)).StatusCode)
+}
+
+func (suite *AuthIntegrationTestSuite) TestChangePassword_RevokesOtherSessions() {
+	token, _ := signUp(suite.T(), suite.app, "alice")
+	var other struct{ Token string }
+	json.NewDecoder(suite.login("alice", "correct horse battery").Body).Decode(&other)
+
+	resp := suite.do(jsonRequest("PUT", "/api/auth/password", token, map[string]string{
+		"currentPassword": "wrong password",
+		"newPassword":     "a brand new password",
+	}))
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("PUT", "/api/auth/password", token, map[string]string{
+		"currentPassword": "correct horse battery",
+		"newPassword":     "a brand new password",
+	}))
+	suite.Equal(http.StatusNoContent, resp.StatusCode)
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/auth/me", token, nil)).StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/auth/me", other.Token, nil)).StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.login("alice", "correct horse battery").StatusCode)
+	suite.Equal(http.StatusOK, suite.login("alice", "a brand new password").StatusCode)
+}
+
+func (suite *AuthIntegrationTestSuite) TestTodosAreIsolatedPerUser() {
+	aliceToken, _ := signUp(suite.T(), suite.app, "alice")
+	bobToken, _ := signUp(suite.T(), suite.app, "bob")
+
+	resp := suite.do(jsonRequest("POST", "/api/todos", aliceToken, map[string]string{"text": "Alice's todo"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&created)
+
+	var bobTodos []map[string]interface{}
+	json.NewDecoder(suite.do(jsonRequest("GET", "/api/todos", bobToken, nil)).Body).Decode(&bobTodos)
+	suite.Empty(bobTodos)
+
+	// Cross-user access looks exactly like a missing todo
+	req := jsonRequest("PATCH", "/api/todos/"+created["id"].(string), bobToken, map[string]string{"text": "Hijacked"})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp = suite.do(req)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+
+	var aliceTodos []map[string]interface{}
+	json.NewDecoder(suite.do(jsonRequest("GET", "/api/todos", aliceToken, nil)).Body).Decode(&aliceTodos)
+	suite.Len(aliceTodos, 1)
+}
+
+func TestAuthIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthIntegrationTestSuite))
+}
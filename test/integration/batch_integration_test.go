@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// BatchIntegrationTestSuite tests POST /api/todos/batch against a real database
+type BatchIntegrationTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	db     *gorm.DB
+	token  string
+	userID string
+	// mode is the database.mode the todos are stored in
+	mode string
+}
+
+func (suite *BatchIntegrationTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoRepo, err := database.NewTodoRepository(db, config.DatabaseConfig{Mode: suite.mode, SnapshotEvery: 3})
+	suite.Require().NoError(err)
+	todoUseCase := usecases.NewTodoUseCase(todoRepo)
+
+	deps := newTestDependencies(db, todoUseCase)
+	deps.BatchHandler = handlers.NewBatchHandler(todoUseCase, handlers.BatchLimits{
+		MaxOperations:   3,
+		MaxPayloadBytes: 1024,
+	})
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.token, suite.userID = signUp(suite.T(), app, "batch-tester")
+}
+
+func (suite *BatchIntegrationTestSuite) SetupTest() {
+	suite.db.Create(&database.SQLiteTodoModel{ID: "existing", Text: "Existing todo", OwnerID: suite.userID})
+}
+
+func (suite *BatchIntegrationTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todos")
+}
+
+func (suite *BatchIntegrationTestSuite) postBatch(query string, ops []dto.BatchOperation) (*http.Response, dto.BatchResponse) {
+	req := jsonRequest("POST", "/api/todos/batch"+query, suite.token, map[string]interface{}{"operations": ops})
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+
+	var response dto.BatchResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp, response
+}
+
+func (suite *BatchIntegrationTestSuite) todo(id string) (database.SQLiteTodoModel, error) {
+	var model database.SQLiteTodoModel
+	err := suite.db.Where("id = ?", id).First(&model).Error
+	return model, err
+}
+
+func (suite *BatchIntegrationTestSuite) TestAtomicBatch_Success() {
+	resp, response := suite.postBatch("?atomic=true", []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "New todo"},
+		{Op: dto.BatchOpUpdate, ID: "existing", Text: "Renamed"},
+		{Op: dto.BatchOpComplete, ID: "existing"},
+	})
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(3, response.Succeeded)
+	suite.Equal(http.StatusCreated, response.Results[0].Status)
+
+	model, err := suite.todo("existing")
+	suite.NoError(err)
+	suite.Equal("Renamed", model.Text)
+	suite.True(model.Completed)
+}
+
+func (suite *BatchIntegrationTestSuite) TestAtomicBatch_FailureRollsBackEverything() {
+	resp, response := suite.postBatch("?atomic=true", []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "New todo"},
+		{Op: dto.BatchOpDelete, ID: "existing"},
+		{Op: dto.BatchOpComplete, ID: "missing"},
+	})
+
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	suite.Equal(3, response.Failed)
+	suite.Equal(http.StatusFailedDependency, response.Results[0].Status)
+	suite.Equal(http.StatusNotFound, response.Results[2].Status)
+
+	var count int64
+	suite.db.Model(&database.SQLiteTodoModel{}).Count(&count)
+	suite.Equal(int64(1), count)
+	_, err := suite.todo("existing")
+	suite.NoError(err)
+}
+
+func (suite *BatchIntegrationTestSuite) TestBestEffortBatch_ReportsPerItemResults() {
+	resp, response := suite.postBatch("", []dto.BatchOperation{
+		{Op: dto.BatchOpDelete, ID: "existing"},
+		{Op: dto.BatchOpComplete, ID: "missing"},
+		{Op: dto.BatchOpCreate, Text: ""},
+	})
+
+	suite.Equal(http.StatusMultiStatus, resp.StatusCode)
+	suite.Equal(1, response.Succeeded)
+	suite.Equal(2, response.Failed)
+	suite.Equal(http.StatusNoContent, response.Results[0].Status)
+	suite.Equal(http.StatusNotFound, response.Results[1].Status)
+	suite.Equal(http.StatusBadRequest, response.Results[2].Status)
+
+	_, err := suite.todo("existing")
+	suite.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (suite *BatchIntegrationTestSuite) TestBatchLimits() {
+	resp, _ := suite.postBatch("", []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "1"},
+		{Op: dto.BatchOpCreate, Text: "2"},
+		{Op: dto.BatchOpCreate, Text: "3"},
+		{Op: dto.BatchOpCreate, Text: "4"},
+	})
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp, _ = suite.postBatch("", []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: strings.Repeat("x", 2048)},
+	})
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestBatchIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchIntegrationTestSuite))
+}
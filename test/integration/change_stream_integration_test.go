@@ -0,0 +1,501 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const (
+	// streamTimeout bounds every wait for an event on a change stream
+	streamTimeout = 5 * time.Second
+	// streamResetEvent tells a client to reload its todos
+	streamResetEvent = "reset"
+)
+
+// ChangeStreamIntegrationTestSuite tests the Server-Sent Events change stream
+// over a real listener
+type ChangeStreamIntegrationTestSuite struct {
+	suite.Suite
+	app          *fiber.App
+	db           *gorm.DB
+	url          string
+	changeStream *usecases.ChangeStreamUseCase
+	done         chan struct{}
+	stop         sync.Once
+	alice        string
+	aliceID      string
+	bob          string
+}
+
+// sseEvent is one block of a Server-Sent Events stream
+type sseEvent struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment string
+}
+
+// sseStream reads the events of an open stream
+type sseStream struct {
+	resp   *http.Response
+	events chan sseEvent
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) SetupTest() {
+	// A file, so that the stream and the requests may use separate connections
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todos.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	listRepo := database.NewSQLiteListRepository(db)
+	suite.changeStream = usecases.NewChangeStreamUseCase(database.NewSQLiteChangeLogRepository(db), listRepo, events.NewBus(), 64)
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(listRepo),
+		usecases.WithOutbox(followOutbox(suite.T(), db, suite.changeStream)),
+	)
+
+	suite.done = make(chan struct{})
+	suite.stop = sync.Once{}
+	deps := newTestDependencies(db, todoUseCase)
+	deps.ChangeStreamHandler = handlers.NewChangeStreamHandler(suite.changeStream, 100*time.Millisecond, suite.done)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.alice, suite.aliceID = signUp(suite.T(), app, "alice")
+	suite.bob, _ = signUp(suite.T(), app, "bob")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	go func() { _ = app.Listener(ln) }()
+	// Open streams hold up Shutdown until they end
+	suite.T().Cleanup(func() { _ = app.Shutdown() })
+	suite.T().Cleanup(suite.closeStreams)
+	suite.url = "http://" + ln.Addr().String()
+}
+
+// followOutbox subscribes changeStream to the outbox of db, which is relayed
+// every few milliseconds until the test ends, as the dispatch-events worker does
+func followOutbox(t *testing.T, db *gorm.DB, changeStream *usecases.ChangeStreamUseCase) repositories.OutboxRepository {
+	outbox := database.NewSQLiteOutboxRepository(db)
+	dispatcher := usecases.NewEventDispatcher(outbox)
+	dispatcher.Subscribe("change-stream", changeStream)
+
+	workers := server.NewWorkers()
+	workers.Every("dispatch-events", 5*time.Millisecond, func(ctx context.Context) {
+		_, _ = dispatcher.Dispatch(ctx)
+	})
+	t.Cleanup(func() { _ = workers.Stop(context.Background()) })
+	return outbox
+}
+
+// closeStreams ends every open stream, as on shutdown
+func (suite *ChangeStreamIntegrationTestSuite) closeStreams() {
+	suite.stop.Do(func() { close(suite.done) })
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// open starts streaming the changes visible to token's user
+func (suite *ChangeStreamIntegrationTestSuite) open(token, lastEventID string) *sseStream {
+	req, err := http.NewRequest("GET", suite.url+"/api/todos/stream", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { resp.Body.Close() })
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	stream := &sseStream{resp: resp, events: make(chan sseEvent, 16)}
+	go stream.read()
+	return stream
+}
+
+// read parses the stream into events until it ends
+func (s *sseStream) read() {
+	defer close(s.events)
+	scanner := bufio.NewScanner(s.resp.Body)
+	var event sseEvent
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			s.events <- event
+			event = sseEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			event.Comment = value
+		case "id":
+			event.ID = value
+		case "event":
+			event.Event = value
+		case "data":
+			event.Data = value
+		}
+	}
+}
+
+// next returns the next named event, skipping the retry block and heartbeats
+func (suite *ChangeStreamIntegrationTestSuite) next(stream *sseStream) sseEvent {
+	timeout := time.After(streamTimeout)
+	for {
+		select {
+		case event, ok := <-stream.events:
+			suite.Require().True(ok, "Stream ended unexpectedly")
+			if event.Event != "" {
+				return event
+			}
+		case <-timeout:
+			suite.FailNow("Timed out waiting for an event")
+		}
+	}
+}
+
+// change decodes the data of a change event
+func (suite *ChangeStreamIntegrationTestSuite) change(event sseEvent) dto.ChangeEventResponse {
+	var change dto.ChangeEventResponse
+	suite.Require().NoError(json.Unmarshal([]byte(event.Data), &change))
+	suite.Require().NotEmpty(change.Changes)
+	return change
+}
+
+// create creates a todo as token's user and returns its ID
+func (suite *ChangeStreamIntegrationTestSuite) create(token string, body map[string]string) string {
+	resp := suite.do(jsonRequest("POST", "/api/todos", token, body))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var todo dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todo))
+	return todo.ID
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) complete(token, todoID string) {
+	req := jsonRequest("PATCH", "/api/todos/"+todoID, token, map[string]bool{"completed": true})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp := suite.do(req)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestOwnerReceivesChanges() {
+	stream := suite.open(suite.alice, "")
+
+	todoID := suite.create(suite.alice, map[string]string{"text": "Buy milk"})
+	suite.complete(suite.alice, todoID)
+
+	created := suite.next(stream)
+	suite.Equal("todo.created", created.Event)
+	suite.NotEmpty(created.ID)
+	change := suite.change(created)
+	suite.Equal(todoID, change.Changes[0].TodoID)
+	suite.Require().NotNil(change.Changes[0].Todo)
+	suite.Equal("Buy milk", change.Changes[0].Todo.Text)
+
+	completed := suite.next(stream)
+	suite.Equal("todo.completed", completed.Event)
+	createdSeq, err := strconv.ParseInt(created.ID, 10, 64)
+	suite.Require().NoError(err)
+	completedSeq, err := strconv.ParseInt(completed.ID, 10, 64)
+	suite.Require().NoError(err)
+	suite.Greater(completedSeq, createdSeq)
+	suite.True(suite.change(completed).Changes[0].Todo.Completed)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestOthersPersonalChangesAreNotStreamed() {
+	stream := suite.open(suite.bob, "")
+
+	suite.create(suite.alice, map[string]string{"text": "Alice's secret"})
+	todoID := suite.create(suite.bob, map[string]string{"text": "Bob's chore"})
+
+	// Alice's change is skipped, so Bob's own change comes first
+	change := suite.change(suite.next(stream))
+	suite.Equal(todoID, change.Changes[0].TodoID)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestListMembersReceiveListChanges() {
+	resp := suite.do(jsonRequest("POST", "/api/lists", suite.alice, map[string]string{"name": "Groceries"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var list dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+
+	resp = suite.do(jsonRequest("POST", "/api/lists/"+list.ID+"/invites", suite.alice, map[string]string{"role": "viewer"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var invite dto.CreatedInviteResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&invite))
+	resp = suite.do(jsonRequest("POST", "/api/invites/accept", suite.bob, map[string]string{"token": invite.Token}))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	stream := suite.open(suite.bob, "")
+	todoID := suite.create(suite.alice, map[string]string{"text": "Eggs", "listId": list.ID})
+
+	change := suite.change(suite.next(stream))
+	suite.Equal(todoID, change.Changes[0].TodoID)
+	suite.Equal(list.ID, change.Changes[0].ListID)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestResumeReplaysMissedChanges() {
+	stream := suite.open(suite.alice, "")
+	suite.create(suite.alice, map[string]string{"text": "Seen"})
+	seen := suite.next(stream)
+	stream.resp.Body.Close()
+
+	missed := []string{
+		suite.create(suite.alice, map[string]string{"text": "Missed 1"}),
+		suite.create(suite.alice, map[string]string{"text": "Missed 2"}),
+	}
+
+	resumed := suite.open(suite.alice, seen.ID)
+	for _, todoID := range missed {
+		event := suite.next(resumed)
+		suite.NotEqual(streamResetEvent, event.Event)
+		suite.Equal(todoID, suite.change(event).Changes[0].TodoID)
+	}
+
+	// Live changes follow the replayed ones
+	live := suite.create(suite.alice, map[string]string{"text": "Live"})
+	suite.Equal(live, suite.change(suite.next(resumed)).Changes[0].TodoID)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestUnknownLastEventIDResetsClient() {
+	suite.create(suite.alice, map[string]string{"text": "Logged"})
+
+	stream := suite.open(suite.alice, "1000")
+
+	suite.Equal(streamResetEvent, suite.next(stream).Event)
+	todoID := suite.create(suite.alice, map[string]string{"text": "After reset"})
+	suite.Equal(todoID, suite.change(suite.next(stream)).Changes[0].TodoID)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestInvalidLastEventIDIsRejected() {
+	req := jsonRequest("GET", "/api/todos/stream", suite.alice, nil)
+	req.Header.Set("Last-Event-ID", "not-a-number")
+
+	resp := suite.do(req)
+
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestStreamRequiresAuthentication() {
+	resp := suite.do(jsonRequest("GET", "/api/todos/stream", "", nil))
+
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestIdleStreamSendsHeartbeats() {
+	stream := suite.open(suite.alice, "")
+
+	timeout := time.After(streamTimeout)
+	for {
+		select {
+		case event, ok := <-stream.events:
+			suite.Require().True(ok, "Stream ended unexpectedly")
+			if event.Comment == "heartbeat" {
+				return
+			}
+		case <-timeout:
+			suite.FailNow("Timed out waiting for a heartbeat")
+		}
+	}
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestShutdownEndsStreams() {
+	stream := suite.open(suite.alice, "")
+
+	suite.closeStreams()
+
+	timeout := time.After(streamTimeout)
+	for {
+		select {
+		case _, ok := <-stream.events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			suite.FailNow("Stream did not end")
+		}
+	}
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestSlowSubscriberCatchesUpFromLog() {
+	// Subscribers of this stream are dropped from the bus after one event
+	slow := usecases.NewChangeStreamUseCase(
+		database.NewSQLiteChangeLogRepository(suite.db),
+		database.NewSQLiteListRepository(suite.db),
+		events.NewBus(),
+		1,
+	)
+	todoUseCase := usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(suite.db))
+	ctx := identity.WithPrincipal(context.Background(), &identity.Principal{UserID: suite.aliceID})
+
+	sub, err := slow.Subscribe(ctx, 0)
+	suite.Require().NoError(err)
+	defer sub.Close()
+
+	var created []string
+	for _, text := range []string{"One", "Two", "Three", "Four"} {
+		todo, err := todoUseCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: text})
+		suite.Require().NoError(err)
+		created = append(created, todo.ID)
+		// As the outbox relays the todo's event
+		suite.Require().NoError(slow.Handle(ctx, events.ChangeEvent{}))
+	}
+
+	next, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+	for _, todoID := range created {
+		event, err := sub.Next(next)
+		suite.Require().NoError(err)
+		suite.Equal(todoID, event.Changes[0].TodoID)
+	}
+
+	idle, cancelIdle := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelIdle()
+	_, err = sub.Next(idle)
+	suite.ErrorIs(err, context.DeadlineExceeded, "No event should be delivered twice")
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestRolledBackChangesAreNotStreamed() {
+	ctx := identity.WithPrincipal(context.Background(), &identity.Principal{UserID: suite.aliceID})
+	sub, err := suite.changeStream.Subscribe(ctx, 0)
+	suite.Require().NoError(err)
+	defer sub.Close()
+
+	repo := database.NewSQLiteTodoRepository(suite.db)
+	rollback := errors.New("rolled back")
+	err = repo.WithinTransaction(ctx, func(txCtx context.Context) error {
+		todo := entities.NewTodo("Never committed")
+		todo.OwnerID = suite.aliceID
+		if _, err := repo.Create(txCtx, todo); err != nil {
+			return err
+		}
+		return rollback
+	})
+	suite.Require().ErrorIs(err, rollback)
+	todoID := suite.create(suite.alice, map[string]string{"text": "Committed"})
+
+	next, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+	event, err := sub.Next(next)
+	suite.Require().NoError(err)
+	suite.Require().Len(event.Changes, 1)
+	suite.Equal(todoID, event.Changes[0].TodoID)
+
+	idle, cancelIdle := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelIdle()
+	_, err = sub.Next(idle)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+// relayingTodoRepository relays the change log after every todo it creates,
+// as the outbox may between the operations of a batch, and runs between first
+type relayingTodoRepository struct {
+	repositories.TodoRepository
+	stream  *usecases.ChangeStreamUseCase
+	between func()
+}
+
+func (r *relayingTodoRepository) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	created, err := r.TodoRepository.Create(ctx, todo)
+	if err != nil {
+		return nil, err
+	}
+	if r.between != nil {
+		r.between()
+		r.between = nil
+	}
+	return created, r.stream.Handle(ctx, events.ChangeEvent{})
+}
+
+func (suite *ChangeStreamIntegrationTestSuite) TestBatchIsStreamedAsOneEventAcrossRelays() {
+	ctx := identity.WithPrincipal(context.Background(), &identity.Principal{UserID: suite.aliceID})
+	stream := usecases.NewChangeStreamUseCase(
+		database.NewSQLiteChangeLogRepository(suite.db),
+		database.NewSQLiteListRepository(suite.db),
+		events.NewBus(),
+		64,
+	)
+	sub, err := stream.Subscribe(ctx, 0)
+	suite.Require().NoError(err)
+	defer sub.Close()
+
+	repo := database.NewSQLiteTodoRepository(suite.db)
+	var interleaved *entities.Todo
+	todoUseCase := usecases.NewTodoUseCase(&relayingTodoRepository{
+		TodoRepository: repo,
+		stream:         stream,
+		// Another request writes while the batch is half done
+		between: func() {
+			todo := entities.NewTodo("Interleaved")
+			interleaved, err = repo.Create(ctx, todo)
+			suite.Require().NoError(err)
+		},
+	})
+
+	// Each operation of a best-effort batch commits on its own
+	results, err := todoUseCase.ExecuteBatch(ctx, dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "One"},
+		{Op: dto.BatchOpCreate, Text: "Two"},
+	}})
+	suite.Require().NoError(err)
+	// As the outbox relays the event of the batch's commit
+	suite.Require().NoError(stream.Handle(ctx, events.ChangeEvent{}))
+
+	next, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+	event, err := sub.Next(next)
+	suite.Require().NoError(err)
+	suite.Equal(events.TodoCreated, event.Type)
+	suite.Equal(interleaved.ID, event.Changes[0].TodoID)
+
+	event, err = sub.Next(next)
+	suite.Require().NoError(err)
+	suite.Equal(events.TodosBatchApplied, event.Type)
+	suite.Require().Len(event.Changes, 2)
+	suite.Equal(results[0].ID, event.Changes[0].TodoID)
+	suite.Equal(results[1].ID, event.Changes[1].TodoID)
+
+	idle, cancelIdle := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelIdle()
+	_, err = sub.Next(idle)
+	suite.ErrorIs(err, context.DeadlineExceeded, "The batch should not be split")
+}
+
+func TestChangeStreamIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangeStreamIntegrationTestSuite))
+}
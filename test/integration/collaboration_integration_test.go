@@ -0,0 +1,394 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// CollaborationIntegrationTestSuite tests the collaboration WebSocket over a real listener
+type CollaborationIntegrationTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	url    string
+	done   chan struct{}
+	stop   sync.Once
+	alice  string
+	bob    string
+	carol  string
+	listID string
+}
+
+// wsClient reads the messages of a collaboration connection
+type wsClient struct {
+	conn     *websocket.Conn
+	messages chan dto.ServerMessage
+	// closed receives the error that ended the connection
+	closed chan error
+}
+
+func (suite *CollaborationIntegrationTestSuite) SetupTest() {
+	// A file, so that the connections and the requests may use separate
+	// connections. The outbox follower writes beside the requests, so they
+	// queue for the write lock as in production.
+	db, err := gorm.Open(sqlite.Open(database.SQLiteDSN(filepath.Join(suite.T().TempDir(), "todos.db"))), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+
+	listRepo := database.NewSQLiteListRepository(db)
+	changeStream := usecases.NewChangeStreamUseCase(database.NewSQLiteChangeLogRepository(db), listRepo, events.NewBus(), 64)
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(listRepo),
+		usecases.WithOutbox(followOutbox(suite.T(), db, changeStream)),
+	)
+	collaboration := usecases.NewCollaborationUseCase(changeStream, listRepo, usecases.CollaborationLimits{MaxConnectionsPerUser: 2})
+
+	suite.done = make(chan struct{})
+	suite.stop = sync.Once{}
+	deps := newTestDependencies(db, todoUseCase)
+	deps.CollaborationHandler = handlers.NewCollaborationHandler(collaboration, todoUseCase, handlers.WebSocketSettings{
+		PingInterval:    100 * time.Millisecond,
+		PongTimeout:     100 * time.Millisecond,
+		MaxMessageBytes: 4096,
+		Batch:           handlers.BatchLimits{MaxOperations: 3},
+	}, suite.done)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.alice, _ = signUp(suite.T(), app, "alice")
+	suite.bob, _ = signUp(suite.T(), app, "bob")
+	suite.carol, _ = signUp(suite.T(), app, "carol")
+
+	// Alice shares a list with Bob as an editor
+	resp := suite.do(jsonRequest("POST", "/api/lists", suite.alice, map[string]string{"name": "Groceries"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var list dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+	suite.listID = list.ID
+	suite.share(suite.bob, "editor")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	go func() { _ = app.Listener(ln) }()
+	suite.T().Cleanup(func() { _ = app.Shutdown() })
+	suite.T().Cleanup(suite.closeConnections)
+	suite.url = "ws://" + ln.Addr().String() + "/api/ws"
+}
+
+// closeConnections closes every open connection, as on shutdown
+func (suite *CollaborationIntegrationTestSuite) closeConnections() {
+	suite.stop.Do(func() { close(suite.done) })
+}
+
+func (suite *CollaborationIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// share makes token's user a member of the list with role
+func (suite *CollaborationIntegrationTestSuite) share(token, role string) {
+	resp := suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/invites", suite.alice, map[string]string{"role": role}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var invite dto.CreatedInviteResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&invite))
+	resp = suite.do(jsonRequest("POST", "/api/invites/accept", token, map[string]string{"token": invite.Token}))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+// dial connects as token's user
+func (suite *CollaborationIntegrationTestSuite) dial(token string) *wsClient {
+	conn, resp, err := websocket.DefaultDialer.Dial(suite.url, http.Header{"Authorization": {"Bearer " + token}})
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusSwitchingProtocols, resp.StatusCode)
+	suite.T().Cleanup(func() { conn.Close() })
+
+	client := &wsClient{conn: conn, messages: make(chan dto.ServerMessage, 32), closed: make(chan error, 1)}
+	go func() {
+		defer close(client.messages)
+		for {
+			var msg dto.ServerMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				client.closed <- err
+				return
+			}
+			client.messages <- msg
+		}
+	}()
+	return client
+}
+
+func (suite *CollaborationIntegrationTestSuite) send(client *wsClient, msg dto.ClientMessage) {
+	suite.Require().NoError(client.conn.WriteJSON(msg))
+}
+
+// next returns the next message of msgType, skipping the others
+func (suite *CollaborationIntegrationTestSuite) next(client *wsClient, msgType string) dto.ServerMessage {
+	timeout := time.After(streamTimeout)
+	for {
+		select {
+		case msg, ok := <-client.messages:
+			suite.Require().True(ok, "Connection closed unexpectedly")
+			if msg.Type == msgType {
+				return msg
+			}
+		case <-timeout:
+			suite.FailNow("Timed out waiting for a " + msgType + " message")
+		}
+	}
+}
+
+// request sends msg and returns the result or error answering it
+func (suite *CollaborationIntegrationTestSuite) request(client *wsClient, msg dto.ClientMessage) dto.ServerMessage {
+	suite.send(client, msg)
+	timeout := time.After(streamTimeout)
+	for {
+		select {
+		case reply, ok := <-client.messages:
+			suite.Require().True(ok, "Connection closed unexpectedly")
+			if (reply.Type == dto.MessageResult || reply.Type == dto.MessageError) && reply.ID == msg.ID {
+				return reply
+			}
+		case <-timeout:
+			suite.FailNow("Timed out waiting for the reply to " + msg.ID)
+		}
+	}
+}
+
+// presence waits for the list's viewers to be usernames
+func (suite *CollaborationIntegrationTestSuite) presence(client *wsClient, usernames ...string) {
+	for {
+		msg := suite.next(client, dto.MessagePresence)
+		suite.Equal(suite.listID, msg.ListID)
+		var viewing []string
+		for _, viewer := range msg.Viewers {
+			viewing = append(viewing, viewer.Username)
+		}
+		if len(viewing) == len(usernames) {
+			suite.Equal(usernames, viewing)
+			return
+		}
+	}
+}
+
+// subscribe opens the list on client
+func (suite *CollaborationIntegrationTestSuite) subscribe(client *wsClient) {
+	reply := suite.request(client, dto.ClientMessage{ID: "sub", Type: dto.MessageSubscribe, ListID: suite.listID})
+	suite.Require().Equal(dto.MessageResult, reply.Type, reply.Error)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestMutationsAreAnsweredAndBroadcast() {
+	alice := suite.dial(suite.alice)
+	bob := suite.dial(suite.bob)
+	suite.subscribe(alice)
+	suite.subscribe(bob)
+
+	created := suite.request(bob, dto.ClientMessage{ID: "1", Type: dto.MessageCreate, ListID: suite.listID, Text: "Eggs"})
+	suite.Require().Equal(dto.MessageResult, created.Type, created.Error)
+	suite.Require().NotNil(created.Todo)
+	suite.Equal("Eggs", created.Todo.Text)
+	suite.Equal(suite.listID, created.Todo.ListID)
+
+	change := suite.next(alice, dto.MessageChange)
+	suite.Equal("todo.created", change.Change.Type)
+	suite.Equal(created.Todo.ID, change.Change.Changes[0].TodoID)
+
+	patched := suite.request(alice, dto.ClientMessage{ID: "2", Type: dto.MessagePatch, TodoID: created.Todo.ID, Patch: json.RawMessage(`{"completed": true}`)})
+	suite.Require().Equal(dto.MessageResult, patched.Type, patched.Error)
+	suite.True(patched.Todo.Completed)
+	// Clients receive their own changes too
+	suite.Equal("todo.created", suite.next(bob, dto.MessageChange).Change.Type)
+	suite.Equal("todo.completed", suite.next(bob, dto.MessageChange).Change.Type)
+	suite.Equal("todo.completed", suite.next(alice, dto.MessageChange).Change.Type)
+
+	deleted := suite.request(bob, dto.ClientMessage{ID: "3", Type: dto.MessageBatch, Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpDelete, ID: created.Todo.ID},
+	}})
+	suite.Require().Equal(dto.MessageResult, deleted.Type, deleted.Error)
+	suite.Equal(1, deleted.Batch.Succeeded)
+	batch := suite.next(alice, dto.MessageChange)
+	suite.Equal("todos.batch_applied", batch.Change.Type)
+	suite.Nil(batch.Change.Changes[0].Todo)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestRESTChangesAreBroadcast() {
+	bob := suite.dial(suite.bob)
+	suite.subscribe(bob)
+
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Milk", "listId": suite.listID}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	// Personal todos are not part of any subscribed list
+	resp = suite.do(jsonRequest("POST", "/api/todos", suite.bob, map[string]string{"text": "Call mom"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	resp = suite.do(jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Bread", "listId": suite.listID}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	suite.Equal("Milk", suite.next(bob, dto.MessageChange).Change.Changes[0].Todo.Text)
+	suite.Equal("Bread", suite.next(bob, dto.MessageChange).Change.Changes[0].Todo.Text)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestUnsubscribedListsAreNotBroadcast() {
+	bob := suite.dial(suite.bob)
+	suite.subscribe(bob)
+	reply := suite.request(bob, dto.ClientMessage{ID: "unsub", Type: dto.MessageUnsubscribe, ListID: suite.listID})
+	suite.Equal(dto.MessageResult, reply.Type)
+
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Milk", "listId": suite.listID}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	select {
+	case msg := <-bob.messages:
+		suite.NotEqual(dto.MessageChange, msg.Type)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestPresence() {
+	alice := suite.dial(suite.alice)
+	suite.subscribe(alice)
+	suite.presence(alice, "alice")
+
+	bob := suite.dial(suite.bob)
+	suite.subscribe(bob)
+	suite.presence(alice, "alice", "bob")
+	suite.presence(bob, "alice", "bob")
+
+	suite.Require().NoError(bob.conn.Close())
+	suite.presence(alice, "alice")
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestSubscribeRequiresMembership() {
+	carol := suite.dial(suite.carol)
+
+	reply := suite.request(carol, dto.ClientMessage{ID: "sub", Type: dto.MessageSubscribe, ListID: suite.listID})
+
+	suite.Equal(dto.MessageError, reply.Type)
+	suite.Equal(http.StatusNotFound, reply.Status)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestViewerCannotMutate() {
+	suite.share(suite.carol, "viewer")
+	carol := suite.dial(suite.carol)
+	suite.subscribe(carol)
+
+	reply := suite.request(carol, dto.ClientMessage{ID: "1", Type: dto.MessageCreate, ListID: suite.listID, Text: "Spam"})
+
+	suite.Equal(dto.MessageError, reply.Type)
+	suite.Equal(http.StatusForbidden, reply.Status)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestInvalidMessages() {
+	client := suite.dial(suite.alice)
+
+	suite.Require().NoError(client.conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	reply := suite.next(client, dto.MessageError)
+	suite.Equal(http.StatusBadRequest, reply.Status)
+
+	reply = suite.request(client, dto.ClientMessage{ID: "1", Type: "explode"})
+	suite.Equal(dto.MessageError, reply.Type)
+	suite.Equal(http.StatusBadRequest, reply.Status)
+
+	reply = suite.request(client, dto.ClientMessage{ID: "2", Type: dto.MessageCreate})
+	suite.Equal(dto.MessageError, reply.Type)
+	suite.Equal(http.StatusBadRequest, reply.Status)
+
+	reply = suite.request(client, dto.ClientMessage{ID: "3", Type: dto.MessageBatch, Operations: make([]dto.BatchOperation, 4)})
+	suite.Equal(dto.MessageError, reply.Type)
+	suite.Equal(http.StatusBadRequest, reply.Status)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestOversizedMessageClosesConnection() {
+	client := suite.dial(suite.alice)
+
+	suite.Require().NoError(client.conn.WriteMessage(websocket.TextMessage, make([]byte, 8192)))
+
+	select {
+	case <-client.closed:
+	case <-time.After(streamTimeout):
+		suite.FailNow("Connection was not closed")
+	}
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestConnectionLimitPerUser() {
+	suite.dial(suite.alice)
+	suite.dial(suite.alice)
+
+	_, resp, err := websocket.DefaultDialer.Dial(suite.url, http.Header{"Authorization": {"Bearer " + suite.alice}})
+
+	suite.ErrorIs(err, websocket.ErrBadHandshake)
+	suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	// Other users are not affected
+	suite.dial(suite.bob)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestUpgradeRequiresAuthentication() {
+	_, resp, err := websocket.DefaultDialer.Dial(suite.url, nil)
+
+	suite.ErrorIs(err, websocket.ErrBadHandshake)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestPlainRequestIsRejected() {
+	resp := suite.do(jsonRequest("GET", "/api/ws", suite.alice, nil))
+
+	suite.Equal(http.StatusUpgradeRequired, resp.StatusCode)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestIdleConnectionIsKeptAliveByPongs() {
+	client := suite.dial(suite.alice)
+
+	// Several ping intervals and pong timeouts pass
+	time.Sleep(500 * time.Millisecond)
+
+	reply := suite.request(client, dto.ClientMessage{ID: "1", Type: dto.MessageUnsubscribe, ListID: suite.listID})
+	suite.Equal(dto.MessageResult, reply.Type)
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestUnresponsiveClientIsDisconnected() {
+	conn, _, err := websocket.DefaultDialer.Dial(suite.url, http.Header{"Authorization": {"Bearer " + suite.alice}})
+	suite.Require().NoError(err)
+	defer conn.Close()
+	// The client reads, but does not answer pings
+	conn.SetPingHandler(func(string) error { return nil })
+
+	suite.Require().NoError(conn.SetReadDeadline(time.Now().Add(streamTimeout)))
+	_, _, err = conn.ReadMessage()
+	var netErr net.Error
+	suite.False(errors.As(err, &netErr) && netErr.Timeout(), "Connection was not closed")
+}
+
+func (suite *CollaborationIntegrationTestSuite) TestShutdownClosesConnections() {
+	client := suite.dial(suite.alice)
+
+	suite.closeConnections()
+
+	select {
+	case err := <-client.closed:
+		suite.True(websocket.IsCloseError(err, websocket.CloseGoingAway), err.Error())
+	case <-time.After(streamTimeout):
+		suite.FailNow("Connection was not closed")
+	}
+}
+
+func TestCollaborationIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(CollaborationIntegrationTestSuite))
+}
@@ -0,0 +1,255 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// EventSourcedIntegrationTestSuite tests the event-sourced todo store and its projection
+type EventSourcedIntegrationTestSuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repo       repositories.TodoRepository
+	projection *database.SQLiteTodoProjection
+	ctx        context.Context
+}
+
+func (suite *EventSourcedIntegrationTestSuite) SetupTest() {
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todos.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+	suite.repo, err = database.NewTodoRepository(db, config.DatabaseConfig{Mode: database.TodoModeEventSourced, SnapshotEvery: 3})
+	suite.Require().NoError(err)
+	suite.projection = database.NewSQLiteTodoProjection(db)
+	suite.ctx = ownerContext("user-1")
+}
+
+func (suite *EventSourcedIntegrationTestSuite) create(text string) *entities.Todo {
+	todo, err := suite.repo.Create(suite.ctx, entities.NewTodo(text))
+	suite.Require().NoError(err)
+	return todo
+}
+
+func (suite *EventSourcedIntegrationTestSuite) streamOf(id string) []database.SQLiteTodoEventModel {
+	var events []database.SQLiteTodoEventModel
+	suite.Require().NoError(suite.db.Where("todo_id = ?", id).Order("position").Find(&events).Error)
+	return events
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestEveryChangeIsAnEvent() {
+	todo := suite.create("Renew passport")
+	todo.UpdateText("Renew passport and ID")
+	todo, err := suite.repo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+	todo.Complete()
+	todo, err = suite.repo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Delete(suite.ctx, todo.ID))
+
+	var types []string
+	for i, event := range suite.streamOf(todo.ID) {
+		suite.Equal(int64(i+1), event.Position)
+		suite.Equal(int64(i+1), event.Version)
+		types = append(types, event.Type)
+	}
+	suite.Equal([]string{"todo.created", "todo.updated", "todo.completed", "todo.deleted"}, types)
+
+	_, err = suite.repo.GetByID(suite.ctx, todo.ID)
+	suite.ErrorIs(err, repositories.ErrTodoNotFound)
+	var rows int64
+	suite.Require().NoError(suite.db.Model(&database.SQLiteTodoModel{}).Count(&rows).Error)
+	suite.Zero(rows)
+
+	// A deleted stream cannot be started again
+	_, err = suite.repo.Create(suite.ctx, todo)
+	suite.ErrorIs(err, repositories.ErrTodoExists)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestStateIsReplayedFromSnapshots() {
+	todo := suite.create("v1")
+	for _, text := range []string{"v2", "v3", "v4", "v5"} {
+		todo.UpdateText(text)
+		var err error
+		todo, err = suite.repo.Update(suite.ctx, todo)
+		suite.Require().NoError(err)
+	}
+
+	var snapshot database.SQLiteTodoSnapshotModel
+	suite.Require().NoError(suite.db.Where("todo_id = ?", todo.ID).First(&snapshot).Error)
+	suite.Equal(int64(3), snapshot.Position)
+
+	// The events before the snapshot are no longer needed to replay the todo
+	suite.Require().NoError(suite.db.Where("todo_id = ? AND position <= ?", todo.ID, 3).Delete(&database.SQLiteTodoEventModel{}).Error)
+	replayed, err := suite.repo.GetByID(suite.ctx, todo.ID)
+	suite.Require().NoError(err)
+	suite.Equal("v5", replayed.Text)
+	suite.Equal(int64(5), replayed.Version)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestStaleVersionConflicts() {
+	todo := suite.create("Renew passport")
+	stale := *todo
+
+	todo.Complete()
+	_, err := suite.repo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+
+	stale.UpdateText("Lost update")
+	_, err = suite.repo.Update(suite.ctx, &stale)
+	suite.ErrorIs(err, repositories.ErrVersionConflict)
+	suite.Len(suite.streamOf(todo.ID), 2)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestChangesFeedTheChangeLog() {
+	todo := suite.create("Renew passport")
+	todo.Complete()
+	todo, err := suite.repo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Delete(suite.ctx, todo.ID))
+
+	entries, err := database.NewSQLiteChangeLogRepository(suite.db).Since(suite.ctx, 0, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 3)
+	suite.Equal(entities.ChangeTodoCompleted, entries[1].Kind)
+	suite.Equal([]string{entities.TodoFieldCompleted}, entries[1].Fields)
+	suite.Equal(int64(2), entries[1].Version)
+	suite.True(entries[1].Todo.Completed)
+	suite.Equal(entities.ChangeTodoDeleted, entries[2].Kind)
+	suite.Nil(entries[2].Todo)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestTodosStoredInStateModeAreAdopted() {
+	suite.Require().NoError(suite.db.Create(&database.SQLiteTodoModel{ID: "legacy", Text: "Stored in state mode", Version: 4, OwnerID: "user-1"}).Error)
+
+	// Reading the todo leaves its stream alone
+	todo, err := suite.repo.GetByID(suite.ctx, "legacy")
+	suite.Require().NoError(err)
+	suite.Equal("Stored in state mode", todo.Text)
+	suite.Equal(int64(4), todo.Version)
+	suite.Empty(suite.streamOf("legacy"))
+
+	// Its next change imports it
+	todo.Complete()
+	todo, err = suite.repo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+	suite.Equal(int64(5), todo.Version)
+
+	// Changes made after switching back to state mode are adopted the same way
+	stateRepo := database.NewSQLiteTodoRepository(suite.db)
+	todo.UpdateText("Changed in state mode")
+	todo, err = stateRepo.Update(suite.ctx, todo)
+	suite.Require().NoError(err)
+	adopted, err := suite.repo.GetByID(suite.ctx, "legacy")
+	suite.Require().NoError(err)
+	suite.Equal("Changed in state mode", adopted.Text)
+	suite.Equal(todo.Version, adopted.Version)
+	suite.Require().NoError(suite.repo.Delete(suite.ctx, "legacy"))
+
+	var types []string
+	for _, event := range suite.streamOf("legacy") {
+		types = append(types, event.Type)
+	}
+	suite.Equal([]string{"todo.imported", "todo.completed", "todo.imported", "todo.deleted"}, types)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestTodosDeletedInStateModeStayDeleted() {
+	todo := suite.create("Renew passport")
+	suite.Require().NoError(database.NewSQLiteTodoRepository(suite.db).Delete(suite.ctx, todo.ID))
+
+	stream := suite.streamOf(todo.ID)
+	suite.Require().Len(stream, 2)
+	suite.Equal("todo.deleted", stream[1].Type)
+
+	projected, err := suite.projection.Rebuild(context.Background())
+	suite.Require().NoError(err)
+	suite.Zero(projected)
+	_, err = suite.repo.GetByID(suite.ctx, todo.ID)
+	suite.ErrorIs(err, repositories.ErrTodoNotFound)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestRebuildProjection() {
+	kept := suite.create("Kept")
+	kept.Complete()
+	kept, err := suite.repo.Update(suite.ctx, kept)
+	suite.Require().NoError(err)
+	deleted := suite.create("Deleted")
+	suite.Require().NoError(suite.repo.Delete(suite.ctx, deleted.ID))
+	suite.Require().NoError(suite.db.Create(&database.SQLiteTodoModel{ID: "legacy", Text: "No stream yet", Version: 1, OwnerID: "user-1"}).Error)
+
+	// The read model drifts from the streams
+	suite.Require().NoError(suite.db.Model(&database.SQLiteTodoModel{}).Where("id = ?", kept.ID).Update("text", "Corrupted").Error)
+
+	projected, err := suite.projection.Rebuild(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(2, projected)
+
+	todos, err := suite.repo.GetAll(suite.ctx)
+	suite.Require().NoError(err)
+	texts := map[string]string{}
+	for _, todo := range todos {
+		texts[todo.ID] = todo.Text
+	}
+	// Deleted todos stay deleted, and the todo without a stream is imported
+	suite.Equal(map[string]string{kept.ID: "Kept", "legacy": "No stream yet"}, texts)
+	rebuilt, err := suite.repo.GetByID(suite.ctx, kept.ID)
+	suite.Require().NoError(err)
+	suite.True(rebuilt.Completed)
+	suite.Equal(kept.Version, rebuilt.Version)
+}
+
+func (suite *EventSourcedIntegrationTestSuite) TestCallersOnlySeeTheirTodos() {
+	todo := suite.create("Private")
+
+	_, err := suite.repo.GetByID(ownerContext("user-2"), todo.ID)
+	suite.ErrorIs(err, repositories.ErrTodoNotFound)
+	suite.ErrorIs(suite.repo.Delete(ownerContext("user-2"), todo.ID), repositories.ErrTodoNotFound)
+	suite.Len(suite.streamOf(todo.ID), 1)
+}
+
+func TestEventSourcedIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(EventSourcedIntegrationTestSuite))
+}
+
+// The batch and patch suites also run against the event-sourced store. They
+// seed their todos straight into the todos table, like todos stored in state
+// mode, and drop the streams left behind after every test.
+
+type eventSourcedBatchIntegrationTestSuite struct {
+	BatchIntegrationTestSuite
+}
+
+func (suite *eventSourcedBatchIntegrationTestSuite) TearDownTest() {
+	suite.BatchIntegrationTestSuite.TearDownTest()
+	dropTodoStreams(suite.db)
+}
+
+type eventSourcedPatchIntegrationTestSuite struct {
+	PatchIntegrationTestSuite
+}
+
+func (suite *eventSourcedPatchIntegrationTestSuite) TearDownTest() {
+	suite.PatchIntegrationTestSuite.TearDownTest()
+	dropTodoStreams(suite.db)
+}
+
+func dropTodoStreams(db *gorm.DB) {
+	db.Exec("DELETE FROM todo_events")
+	db.Exec("DELETE FROM todo_snapshots")
+}
+
+func TestEventSourcedBatchIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, &eventSourcedBatchIntegrationTestSuite{BatchIntegrationTestSuite{mode: database.TodoModeEventSourced}})
+}
+
+func TestEventSourcedPatchIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, &eventSourcedPatchIntegrationTestSuite{PatchIntegrationTestSuite{mode: database.TodoModeEventSourced}})
+}
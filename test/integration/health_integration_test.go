@@ -0,0 +1,197 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// HealthIntegrationTestSuite tests the liveness, readiness and startup probes
+type HealthIntegrationTestSuite struct {
+	suite.Suite
+	dbFile    string
+	db        *gorm.DB
+	workers   *server.Workers
+	readiness *server.Readiness
+}
+
+func (suite *HealthIntegrationTestSuite) SetupTest() {
+	suite.dbFile = filepath.Join(suite.T().TempDir(), "todo.db")
+	db, err := gorm.Open(sqlite.Open(suite.dbFile), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	suite.workers = server.NewWorkers()
+	suite.readiness = &server.Readiness{}
+	suite.readiness.SetReady(true)
+}
+
+func (suite *HealthIntegrationTestSuite) TearDownTest() {
+	suite.NoError(suite.workers.Stop(context.Background()))
+}
+
+// newApp serves the probes with checks against db, registered as in production
+func (suite *HealthIntegrationTestSuite) newApp(db *gorm.DB) *fiber.App {
+	dbCheck := func(name string, check func(ctx context.Context, db *gorm.DB) error) usecases.HealthCheck {
+		return usecases.HealthCheck{
+			Name:     name,
+			Check:    func(ctx context.Context) error { return check(ctx, db) },
+			Timeout:  time.Second,
+			CacheTTL: time.Minute,
+		}
+	}
+	ping := dbCheck("database", database.Ping)
+	migrations := dbCheck("migrations", database.CheckMigrations)
+
+	healthUseCase := usecases.NewHealthUseCase()
+	healthUseCase.Register(usecases.ProbeLiveness,
+		usecases.HealthCheck{Name: "workers", Check: suite.workers.CheckHeartbeats},
+	)
+	healthUseCase.Register(usecases.ProbeReadiness,
+		usecases.HealthCheck{Name: "serving", Check: suite.readiness.Check},
+		ping,
+		dbCheck("database-write", database.ProbeWrite),
+		migrations,
+	)
+	healthUseCase.Register(usecases.ProbeStartup, ping, migrations)
+
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db)))
+	deps.Ready = suite.readiness.Ready
+	deps.HealthHandler = handlers.NewHealthHandler(healthUseCase)
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	return app
+}
+
+func (suite *HealthIntegrationTestSuite) probe(app *fiber.App, path string) (int, dto.HealthResponse) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+	suite.Require().NoError(err)
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	var response dto.HealthResponse
+	suite.Require().NoError(json.Unmarshal(body, &response), string(body))
+	return resp.StatusCode, response
+}
+
+// checks indexes the verbose output by check name
+func checks(response dto.HealthResponse) map[string]dto.HealthCheckResponse {
+	byName := make(map[string]dto.HealthCheckResponse, len(response.Checks))
+	for _, check := range response.Checks {
+		byName[check.Name] = check
+	}
+	return byName
+}
+
+func (suite *HealthIntegrationTestSuite) TestProbesPassWhenHealthy() {
+	app := suite.newApp(suite.db)
+	suite.workers.Every("tick", time.Hour, func(context.Context) {})
+
+	for _, path := range []string{"/livez", "/readyz", "/startupz"} {
+		status, response := suite.probe(app, path)
+		suite.Equal(200, status, path)
+		suite.Equal("ok", response.Status, path)
+		suite.Empty(response.Checks, "checks are only listed with ?verbose")
+	}
+}
+
+func (suite *HealthIntegrationTestSuite) TestVerboseListsEveryCheck() {
+	app := suite.newApp(suite.db)
+
+	status, response := suite.probe(app, "/readyz?verbose")
+
+	suite.Equal(200, status)
+	byName := checks(response)
+	suite.Len(byName, 4)
+	for _, name := range []string{"serving", "database", "database-write", "migrations"} {
+		suite.Equal("ok", byName[name].Status, name)
+		suite.NotEmpty(byName[name].CheckedAt, name)
+	}
+
+	// The database checks are cached, and shared with the startup probe
+	_, response = suite.probe(app, "/startupz?verbose")
+	suite.True(checks(response)["migrations"].Cached)
+	suite.True(checks(response)["database"].Cached)
+}
+
+func (suite *HealthIntegrationTestSuite) TestReadinessFailsOnReadOnlyDatabase() {
+	readOnly, err := gorm.Open(sqlite.Open("file:"+suite.dbFile+"?mode=ro"), &gorm.Config{})
+	suite.Require().NoError(err)
+	app := suite.newApp(readOnly)
+
+	// The database answers, but does not accept writes
+	status, response := suite.probe(app, "/readyz?verbose")
+
+	suite.Equal(503, status)
+	suite.Equal("failed", response.Status)
+	byName := checks(response)
+	suite.Equal("ok", byName["database"].Status)
+	suite.Equal("failed", byName["database-write"].Status)
+	suite.Contains(byName["database-write"].Error, "readonly")
+
+	// Liveness does not depend on the database
+	status, _ = suite.probe(app, "/livez")
+	suite.Equal(200, status)
+
+	// The failing check can be excluded while it is investigated
+	status, _ = suite.probe(app, "/readyz?exclude=database-write")
+	suite.Equal(200, status)
+}
+
+func (suite *HealthIntegrationTestSuite) TestStartupFailsOnMissingMigrations() {
+	suite.Require().NoError(suite.db.Exec("DROP TABLE health_probes").Error)
+	app := suite.newApp(suite.db)
+
+	status, response := suite.probe(app, "/startupz?verbose")
+
+	suite.Equal(503, status)
+	suite.Equal("failed", checks(response)["migrations"].Status)
+	suite.Contains(checks(response)["migrations"].Error, "health_probes")
+}
+
+func (suite *HealthIntegrationTestSuite) TestLivenessFailsOnStuckWorker() {
+	app := suite.newApp(suite.db)
+	release := make(chan struct{})
+	defer close(release)
+	suite.workers.Every("stuck", 5*time.Millisecond, func(context.Context) { <-release })
+
+	suite.Eventually(func() bool {
+		status, _ := suite.probe(app, "/livez")
+		return status == 503
+	}, time.Second, 10*time.Millisecond)
+
+	_, response := suite.probe(app, "/livez?verbose")
+	suite.Contains(checks(response)["workers"].Error, "stuck workers: stuck")
+}
+
+func (suite *HealthIntegrationTestSuite) TestReadinessFailsWhileShuttingDown() {
+	app := suite.newApp(suite.db)
+	suite.readiness.SetReady(false)
+
+	status, response := suite.probe(app, "/readyz?verbose")
+
+	suite.Equal(503, status)
+	suite.Equal("failed", checks(response)["serving"].Status)
+	status, _ = suite.probe(app, "/livez")
+	suite.Equal(200, status)
+}
+
+func TestHealthIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthIntegrationTestSuite))
+}
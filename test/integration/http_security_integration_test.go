@@ -0,0 +1,187 @@
+package integration
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	testMaxJSONBytes   = 1 << 10
+	testMaxBodyBytes   = 4 << 10
+	testMaxHeaderBytes = 4 << 10
+	testOrigin         = "https://todo.example.com"
+)
+
+// HTTPSecurityIntegrationTestSuite tests CORS, security headers and request size limits
+type HTTPSecurityIntegrationTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	db    *gorm.DB
+	token string
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	cors, err := middleware.CORS(middleware.CORSOptions{
+		AllowOrigins:     []string{testOrigin},
+		AllowMethods:     []string{"GET", "POST", "PATCH"},
+		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		ExposeHeaders:    []string{"Retry-After"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	suite.Require().NoError(err)
+
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db)))
+	deps.CORS = cors
+	deps.SecurityHeaders = middleware.SecurityHeaders(middleware.SecurityHeadersOptions{
+		ContentSecurityPolicy: "default-src 'none'",
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+	})
+	deps.LimitJSONBody = middleware.LimitJSONBody(testMaxJSONBytes)
+
+	suite.app = fiber.New(fiber.Config{
+		BodyLimit:      testMaxBodyBytes,
+		ReadBufferSize: testMaxHeaderBytes,
+		// listen starts the app in some tests
+		DisableStartupMessage: true,
+	})
+	routes.SetupRoutes(suite.app, deps)
+	suite.token, _ = signUp(suite.T(), suite.app, "alice")
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// rawRequest builds an authenticated request with a body of contentType
+func (suite *HTTPSecurityIntegrationTestSuite) rawRequest(method, path, contentType string, body []byte) *http.Request {
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	suite.Require().NoError(err)
+	req.Header.Set(fiber.HeaderContentType, contentType)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+suite.token)
+	return req
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) countTodos() int64 {
+	var count int64
+	suite.Require().NoError(suite.db.Table("todos").Count(&count).Error)
+	return count
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestSecurityHeaders() {
+	resp := suite.do(jsonRequest("GET", "/health", "", nil))
+	suite.Equal("default-src 'none'", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+	suite.Equal("nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+	suite.Equal("SAMEORIGIN", resp.Header.Get(fiber.HeaderXFrameOptions))
+	suite.Empty(resp.Header.Get(fiber.HeaderStrictTransportSecurity), "HSTS is only sent over HTTPS")
+
+	req := jsonRequest("GET", "/health", "", nil)
+	req.Header.Set(fiber.HeaderXForwardedProto, "https")
+	resp = suite.do(req)
+	suite.Equal("max-age=31536000; includeSubDomains", resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestCORSAllowsConfiguredOrigins() {
+	req := jsonRequest("OPTIONS", "/api/todos", "", nil)
+	req.Header.Set(fiber.HeaderOrigin, testOrigin)
+	req.Header.Set(fiber.HeaderAccessControlRequestMethod, "POST")
+	resp := suite.do(req)
+	suite.Equal(http.StatusNoContent, resp.StatusCode)
+	suite.Equal(testOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	suite.Equal("true", resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	suite.Equal("GET,POST,PATCH", resp.Header.Get(fiber.HeaderAccessControlAllowMethods))
+	suite.Equal("600", resp.Header.Get(fiber.HeaderAccessControlMaxAge))
+
+	req = jsonRequest("GET", "/api/todos", suite.token, nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://evil.example.com")
+	resp = suite.do(req)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Empty(resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestCORSRejectsCredentialsForAnyOrigin() {
+	_, err := middleware.CORS(middleware.CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	suite.Error(err)
+	_, err = middleware.CORS(middleware.CORSOptions{AllowOrigins: []string{"*", testOrigin}})
+	suite.Error(err)
+	_, err = middleware.CORS(middleware.CORSOptions{})
+	suite.Error(err)
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestOversizedJSONIsRejectedBeforeParsing() {
+	body := `{"text": "` + strings.Repeat("a", testMaxJSONBytes) + `"}`
+	resp := suite.do(suite.rawRequest("POST", "/api/todos", fiber.MIMEApplicationJSONCharsetUTF8, []byte(body)))
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+	suite.Equal(int64(0), suite.countTodos())
+
+	// Merge patches are JSON too
+	resp = suite.do(suite.rawRequest("PATCH", "/api/todos/any", "application/merge-patch+json", []byte(body)))
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// Bodies within the limit are served
+	resp = suite.do(jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Small"}))
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+}
+
+// The server refuses oversized requests while reading them, before routing,
+// which app.Test reports as an error; these tests go over a real connection.
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestOversizedBodyIsRefusedWhileRead() {
+	baseURL := suite.listen()
+
+	resp := suite.send(suite.rawRequest("POST", baseURL+"/api/todos", fiber.MIMEOctetStream, make([]byte, testMaxBodyBytes+1)))
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+	suite.Equal(int64(0), suite.countTodos())
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) TestOversizedHeadersAreRefused() {
+	baseURL := suite.listen()
+
+	req := suite.rawRequest("GET", baseURL+"/api/todos", fiber.MIMEApplicationJSON, nil)
+	req.Header.Set("X-Padding", strings.Repeat("a", testMaxHeaderBytes))
+	suite.Equal(http.StatusRequestHeaderFieldsTooLarge, suite.send(req).StatusCode)
+
+	req = suite.rawRequest("GET", baseURL+"/api/todos", fiber.MIMEApplicationJSON, nil)
+	suite.Equal(http.StatusOK, suite.send(req).StatusCode)
+}
+
+// listen serves the app on a local port until the test ends and returns its base URL
+func (suite *HTTPSecurityIntegrationTestSuite) listen() string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	go func() { _ = suite.app.Listener(ln) }()
+	suite.T().Cleanup(func() { _ = suite.app.Shutdown() })
+	return "http://" + ln.Addr().String()
+}
+
+func (suite *HTTPSecurityIntegrationTestSuite) send(req *http.Request) *http.Response {
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHTTPSecurityIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPSecurityIntegrationTestSuite))
+}
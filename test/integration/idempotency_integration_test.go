@@ -0,0 +1,208 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// IdempotencyIntegrationTestSuite tests Idempotency-Key handling on POST /api/todos
+type IdempotencyIntegrationTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	db    *gorm.DB
+	repo  repositories.IdempotencyRepository
+	token string
+}
+
+func (suite *IdempotencyIntegrationTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoRepo := database.NewSQLiteTodoRepository(db)
+	todoUseCase := usecases.NewTodoUseCase(todoRepo)
+	suite.repo = database.NewSQLiteIdempotencyRepository(db)
+
+	deps := newTestDependencies(db, todoUseCase)
+	deps.Idempotency = middleware.Idempotency(suite.repo, time.Hour, time.Minute)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.token, _ = signUp(suite.T(), app, "idempotency-tester")
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todos")
+	suite.db.Exec("DELETE FROM idempotency_keys")
+}
+
+func (suite *IdempotencyIntegrationTestSuite) createTodo(key, text string) *http.Response {
+	req := jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": text})
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *IdempotencyIntegrationTestSuite) todoCount() int64 {
+	var count int64
+	suite.db.Model(&database.SQLiteTodoModel{}).Count(&count)
+	return count
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestRetryReplaysStoredResponse() {
+	first := suite.createTodo("key-1", "Buy milk")
+	suite.Equal(http.StatusCreated, first.StatusCode)
+	var created map[string]interface{}
+	json.NewDecoder(first.Body).Decode(&created)
+
+	retry := suite.createTodo("key-1", "Buy milk")
+	suite.Equal(http.StatusCreated, retry.StatusCode)
+	suite.Equal("true", retry.Header.Get(middleware.IdempotentReplayedHeader))
+	var replayed map[string]interface{}
+	json.NewDecoder(retry.Body).Decode(&replayed)
+
+	suite.Equal(created["id"], replayed["id"])
+	suite.Equal(int64(1), suite.todoCount())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestSameKeyDifferentBody_Returns422() {
+	suite.Equal(http.StatusCreated, suite.createTodo("key-2", "Buy milk").StatusCode)
+
+	resp := suite.createTodo("key-2", "Buy bread")
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	suite.Equal(int64(1), suite.todoCount())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) newKeyedRequest(key, text string) *http.Request {
+	body, _ := json.Marshal(map[string]string{"text": text})
+	req := httptest.NewRequest("POST", "/slow", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	return req
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestKeyInFlight_Returns409() {
+	// The retry arrives while the first request is still inside its handler
+	app := fiber.New()
+	retryStatus := 0
+	app.Post("/slow", middleware.Idempotency(suite.repo, time.Hour, time.Minute), func(c *fiber.Ctx) error {
+		resp, err := app.Test(suite.newKeyedRequest("key-3", "Buy milk"))
+		suite.Require().NoError(err)
+		retryStatus = resp.StatusCode
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	resp, err := app.Test(suite.newKeyedRequest("key-3", "Buy milk"))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal(http.StatusConflict, retryStatus)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestAbandonedKey_IsFreedAfterLease() {
+	app := fiber.New()
+	calls, retryStatus := 0, 0
+	app.Post("/slow", middleware.Idempotency(suite.repo, time.Hour, time.Minute), func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			// The process holding the key stops renewing it, as on a crash
+			suite.db.Model(&database.SQLiteIdempotencyModel{}).
+				Where("key = ?", "key-6").
+				Update("lease_expires_at", time.Now().Add(-time.Second).UnixMilli())
+
+			resp, err := app.Test(suite.newKeyedRequest("key-6", "Buy milk"))
+			suite.Require().NoError(err)
+			retryStatus = resp.StatusCode
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	_, err := app.Test(suite.newKeyedRequest("key-6", "Buy milk"))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusCreated, retryStatus)
+	suite.Equal(2, calls)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestLongRequest_RenewsItsLease() {
+	app := fiber.New()
+	retryStatus := 0
+	app.Post("/slow", middleware.Idempotency(suite.repo, time.Hour, 300*time.Millisecond), func(c *fiber.Ctx) error {
+		time.Sleep(600 * time.Millisecond)
+		resp, err := app.Test(suite.newKeyedRequest("key-7", "Buy milk"))
+		suite.Require().NoError(err)
+		retryStatus = resp.StatusCode
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	resp, err := app.Test(suite.newKeyedRequest("key-7", "Buy milk"), -1)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal(http.StatusConflict, retryStatus)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestServerError_ReleasesKey() {
+	app := fiber.New()
+	calls := 0
+	app.Post("/slow", middleware.Idempotency(suite.repo, time.Hour, time.Minute), func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	resp, err := app.Test(suite.newKeyedRequest("key-5", "Buy milk"))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = app.Test(suite.newKeyedRequest("key-5", "Buy milk"))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal(2, calls)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestExpiredKey_IsReusable() {
+	suite.Equal(http.StatusCreated, suite.createTodo("key-4", "Buy milk").StatusCode)
+	suite.db.Model(&database.SQLiteIdempotencyModel{}).
+		Where("key = ?", "key-4").
+		Update("expires_at", time.Now().Add(-time.Minute).Unix())
+
+	resp := suite.createTodo("key-4", "Buy bread")
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Empty(resp.Header.Get(middleware.IdempotentReplayedHeader))
+	suite.Equal(int64(2), suite.todoCount())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestWithoutKey_CreatesEveryTime() {
+	suite.Equal(http.StatusCreated, suite.createTodo("", "Buy milk").StatusCode)
+	suite.Equal(http.StatusCreated, suite.createTodo("", "Buy milk").StatusCode)
+	suite.Equal(int64(2), suite.todoCount())
+}
+
+func TestIdempotencyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyIntegrationTestSuite))
+}
@@ -0,0 +1,253 @@
+package integration
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/security"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// JWTIntegrationTestSuite tests access token verification and refresh token rotation
+type JWTIntegrationTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	db  *gorm.DB
+}
+
+func (suite *JWTIntegrationTestSuite) SetupTest() {
+	suite.setup(testJWTConfig)
+}
+
+func (suite *JWTIntegrationTestSuite) setup(jwtConfig config.JWTConfig) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	authUseCase := newTestAuthUseCaseWithJWT(db, jwtConfig)
+	todoUseCase := usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db))
+
+	app := fiber.New()
+	routes.SetupRoutes(app, routes.Dependencies{
+		TodoHandler:  handlers.NewTodoHandler(todoUseCase),
+		AuthHandler:  handlers.NewAuthHandler(authUseCase),
+		Authenticate: middleware.Authenticate(authUseCase),
+	})
+	suite.app = app
+}
+
+func (suite *JWTIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *JWTIntegrationTestSuite) refresh(refreshToken string) (*http.Response, dto.LoginResponse) {
+	resp := suite.do(jsonRequest("POST", "/api/auth/refresh", "", dto.RefreshRequest{RefreshToken: refreshToken}))
+	var tokens dto.LoginResponse
+	if resp.StatusCode == http.StatusOK {
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&tokens))
+	}
+	return resp, tokens
+}
+
+func (suite *JWTIntegrationTestSuite) TestLogin_IssuesSignedAccessToken() {
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+
+	suite.Equal("Bearer", login.TokenType)
+	suite.NotEmpty(login.RefreshToken)
+
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(login.AccessToken, claims)
+	suite.Require().NoError(err)
+	suite.Equal("HS256", token.Method.Alg())
+	suite.Equal("test-key", token.Header["kid"])
+	suite.Equal(login.User.ID, claims["sub"])
+	suite.Equal("todo-backend-test", claims["iss"])
+	suite.NotEmpty(claims["sid"])
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", login.AccessToken, nil)).StatusCode)
+	// The refresh token is not an access token
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", login.RefreshToken, nil)).StatusCode)
+}
+
+func (suite *JWTIntegrationTestSuite) TestProtectsAPI_KeepsHealthOpen() {
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/health", "", nil)).StatusCode)
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/.well-known/jwks.json", "", nil)).StatusCode)
+
+	for _, path := range []string{"/api/todos", "/api/auth/me", "/api/unknown"} {
+		suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", path, "", nil)).StatusCode, path)
+	}
+}
+
+func (suite *JWTIntegrationTestSuite) TestRejectsForgedTokens() {
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+	parts := strings.Split(login.AccessToken, ".")
+
+	// Signed with a different secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": login.User.ID, "iss": "todo-backend-test", "aud": "todo-api",
+		"exp": time.Now().Add(time.Hour).Unix(), "iat": time.Now().Unix(),
+	})
+	forged.Header["kid"] = "test-key"
+	forgedToken, err := forged.SignedString([]byte("not-the-configured-secret-at-all!!"))
+	suite.Require().NoError(err)
+
+	// Unsigned
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": login.User.ID})
+	noneToken, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	suite.Require().NoError(err)
+
+	for name, token := range map[string]string{
+		"forged":   forgedToken,
+		"none":     noneToken,
+		"tampered": parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"someone-else"}`)) + "." + parts[2],
+	} {
+		suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", token, nil)).StatusCode, name)
+	}
+}
+
+func (suite *JWTIntegrationTestSuite) TestRefresh_RotatesTokenPair() {
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+
+	resp, rotated := suite.refresh(login.RefreshToken)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.NotEqual(login.RefreshToken, rotated.RefreshToken)
+	suite.NotEqual(login.AccessToken, rotated.AccessToken)
+	suite.Equal(login.User.ID, rotated.User.ID)
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", rotated.AccessToken, nil)).StatusCode)
+
+	resp, _ = suite.refresh(rotated.RefreshToken)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, _ = suite.refresh("unknown-refresh-token")
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *JWTIntegrationTestSuite) TestRefresh_ReuseRevokesTokenFamily() {
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+	other := suite.loginAgain("alice")
+
+	resp, rotated := suite.refresh(login.RefreshToken)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// Replaying the rotated token signals theft
+	resp, _ = suite.refresh(login.RefreshToken)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	// The whole family is gone: the legitimate successor and its access token
+	resp, _ = suite.refresh(rotated.RefreshToken)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos", rotated.AccessToken, nil)).StatusCode)
+
+	// Other logins of the same user are unaffected
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", other.AccessToken, nil)).StatusCode)
+	resp, _ = suite.refresh(other.RefreshToken)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *JWTIntegrationTestSuite) TestLogout_RevokesRefreshToken() {
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+
+	suite.Equal(http.StatusNoContent, suite.do(jsonRequest("POST", "/api/auth/logout", login.AccessToken, nil)).StatusCode)
+
+	resp, _ := suite.refresh(login.RefreshToken)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	var count int64
+	suite.db.Model(&database.SQLiteRefreshTokenModel{}).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+func (suite *JWTIntegrationTestSuite) TestEdDSA_PublishesVerifiableJWKS() {
+	suite.setup(config.JWTConfig{Issuer: "todo-backend-test", Audience: "todo-api", Algorithm: "EdDSA", AccessTTL: time.Minute})
+	login := signUpForTokens(suite.T(), suite.app, "alice")
+
+	resp := suite.do(jsonRequest("GET", "/.well-known/jwks.json", "", nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var set identity.JSONWebKeySet
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&set))
+	suite.Require().Len(set.Keys, 1)
+	suite.Equal("OKP", set.Keys[0].KeyType)
+	suite.Equal("Ed25519", set.Keys[0].Curve)
+
+	// A third party can verify the access token from the published key alone
+	publicKey, err := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+	suite.Require().NoError(err)
+	token, err := jwt.Parse(login.AccessToken, func(token *jwt.Token) (interface{}, error) {
+		suite.Equal(set.Keys[0].KeyID, token.Header["kid"])
+		return ed25519.PublicKey(publicKey), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	suite.Require().NoError(err)
+	suite.True(token.Valid)
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", login.AccessToken, nil)).StatusCode)
+}
+
+func (suite *JWTIntegrationTestSuite) TestHS256_KeysAreNotPublished() {
+	resp := suite.do(jsonRequest("GET", "/.well-known/jwks.json", "", nil))
+	var set identity.JSONWebKeySet
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&set))
+	suite.Empty(set.Keys)
+}
+
+func (suite *JWTIntegrationTestSuite) loginAgain(username string) dto.LoginResponse {
+	resp := suite.do(jsonRequest("POST", "/api/auth/login", "", map[string]string{"username": username, "password": "correct horse battery"}))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var login dto.LoginResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&login))
+	return login
+}
+
+func TestJWTIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(JWTIntegrationTestSuite))
+}
+
+func TestJWTManager_RotationKeepsIssuedTokensValid(t *testing.T) {
+	manager, err := security.NewJWTManager(config.JWTConfig{Algorithm: "EdDSA", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	principal := &identity.Principal{UserID: "user-1", SessionID: "session-1"}
+
+	before, _, err := manager.Issue(principal)
+	require.NoError(t, err)
+	require.NoError(t, manager.Rotate())
+	after, _, err := manager.Issue(principal)
+	require.NoError(t, err)
+
+	for _, token := range []string{before, after} {
+		_, err := manager.Verify(token)
+		assert.NoError(t, err, "tokens issued before and after rotation should verify")
+	}
+	assert.Len(t, manager.JWKS().Keys, 2, "JWKS should publish the old and the new key")
+}
+
+func TestJWTManager_RejectsShortHS256Secret(t *testing.T) {
+	_, err := security.NewJWTManager(config.JWTConfig{
+		Algorithm:   "HS256",
+		AccessTTL:   time.Minute,
+		ActiveKeyID: "weak",
+		Keys:        []config.JWTKeyConfig{{ID: "weak", Secret: "short"}},
+	})
+	assert.Error(t, err)
+}
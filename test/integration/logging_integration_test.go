@@ -0,0 +1,244 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/logging"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// logBuffer collects log output written from request goroutines
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records parses the JSON records written so far
+func (b *logBuffer) records() []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		var record map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// find returns the last record with msg whose attributes include attrs
+func (b *logBuffer) find(msg string, attrs map[string]interface{}) map[string]interface{} {
+	records := b.records()
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record["msg"] != msg {
+			continue
+		}
+		matches := true
+		for key, value := range attrs {
+			if record[key] != value {
+				matches = false
+			}
+		}
+		if matches {
+			return record
+		}
+	}
+	return nil
+}
+
+// LoggingIntegrationTestSuite tests structured request, query and
+// application logs, and changing the log level at runtime
+type LoggingIntegrationTestSuite struct {
+	suite.Suite
+	logs   *logBuffer
+	logger *slog.Logger
+	// previous is the default logger, restored after each test
+	previous *slog.Logger
+	level    *slog.LevelVar
+	app      *fiber.App
+	admin    string
+	alice    string
+	aliceID  string
+}
+
+func (suite *LoggingIntegrationTestSuite) SetupTest() {
+	suite.logs = &logBuffer{}
+	logger, level, err := logging.New(config.LoggingConfig{Level: "info", Format: "json"}, suite.logs)
+	suite.Require().NoError(err)
+	suite.logger, suite.level = logger, level
+	// Application code logs through the default logger, as set by cmd/main.go
+	suite.previous = slog.Default()
+	slog.SetDefault(logger)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logging.NewGormLogger(logger, 0)})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db)), usecases.WithAdminUsernames("root"))
+	deps.Logger = middleware.RequestLogger(logger)
+	deps.LogLevelHandler = handlers.NewLogLevelHandler(level)
+	deps.Tenant = middleware.ResolveTenant(middleware.TenantOptions{Enabled: true, Default: "acme"})
+	suite.app = fiber.New()
+	routes.SetupRoutes(suite.app, deps)
+
+	suite.admin, _ = signUp(suite.T(), suite.app, "root")
+	suite.alice, suite.aliceID = signUp(suite.T(), suite.app, "alice")
+}
+
+func (suite *LoggingIntegrationTestSuite) TearDownTest() {
+	slog.SetDefault(suite.previous)
+}
+
+func (suite *LoggingIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req, -1)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *LoggingIntegrationTestSuite) TestRequestsAreLoggedWithRequestScopedFields() {
+	req := jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Logged"})
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+
+	suite.Equal(http.StatusCreated, suite.do(req).StatusCode)
+
+	record := suite.logs.find("Request", map[string]interface{}{"request_id": "req-123"})
+	suite.Require().NotNil(record)
+	suite.Equal("INFO", record["level"])
+	suite.Equal("POST", record["method"])
+	suite.Equal("/api/todos", record["route"])
+	suite.Equal(float64(http.StatusCreated), record["status"])
+	suite.Contains(record, "latency_ms")
+	suite.Equal(suite.aliceID, record["user"])
+	suite.Equal("acme", record["tenant"])
+}
+
+func (suite *LoggingIntegrationTestSuite) TestAnonymousRequestsAreLoggedWithoutCaller() {
+	req := jsonRequest("GET", "/api/todos", "", nil)
+	req.Header.Set(middleware.RequestIDHeader, "anonymous")
+
+	suite.Equal(http.StatusUnauthorized, suite.do(req).StatusCode)
+
+	record := suite.logs.find("Request", map[string]interface{}{"request_id": "anonymous"})
+	suite.Require().NotNil(record)
+	suite.Equal(float64(http.StatusUnauthorized), record["status"])
+	suite.NotContains(record, "user")
+}
+
+func (suite *LoggingIntegrationTestSuite) TestQueriesAreLoggedAtDebugWithoutParameters() {
+	create := func(requestID, text string) {
+		req := jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": text})
+		req.Header.Set(middleware.RequestIDHeader, requestID)
+		suite.Equal(http.StatusCreated, suite.do(req).StatusCode)
+	}
+
+	// Queries are not logged at info level
+	create("at-info", "Quiet")
+	suite.Nil(suite.logs.find("Query", map[string]interface{}{"request_id": "at-info"}))
+
+	suite.level.Set(slog.LevelDebug)
+	create("at-debug", "my secret todo")
+
+	record := suite.logs.find("Query", map[string]interface{}{"request_id": "at-debug"})
+	suite.Require().NotNil(record, "queries carry the request's fields")
+	suite.Contains(record["sql"], "INSERT INTO")
+	suite.NotContains(record["sql"], "my secret todo")
+	suite.Contains(record, "duration_ms")
+}
+
+func (suite *LoggingIntegrationTestSuite) TestSecretsAreRedacted() {
+	suite.logger.Info("Signing in",
+		"password", "correct horse battery",
+		"Authorization", "Bearer abc.def.ghi",
+		"refresh_token", "r3fr3sh",
+		"detail", "sent Bearer abc.def.ghi with key tdk_0123456789abcdef",
+	)
+
+	output := suite.logs.buf.String()
+	for _, secret := range []string{"correct horse battery", "abc.def.ghi", "r3fr3sh", "tdk_0123456789abcdef"} {
+		suite.NotContains(output, secret)
+	}
+	record := suite.logs.find("Signing in", nil)
+	suite.Require().NotNil(record)
+	suite.Equal(logging.Redacted, record["password"])
+	suite.Equal(logging.Redacted, record["Authorization"])
+	suite.Equal("sent Bearer [REDACTED] with key [REDACTED]", record["detail"])
+}
+
+func (suite *LoggingIntegrationTestSuite) TestAdminChangesLogLevelAtRuntime() {
+	resp := suite.do(jsonRequest("GET", "/api/admin/log-level", suite.admin, nil))
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	var level dto.LogLevelResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&level))
+	suite.Equal("info", level.Level)
+
+	suite.logger.Debug("Hidden")
+	resp = suite.do(jsonRequest("PUT", "/api/admin/log-level", suite.admin, dto.LogLevelRequest{Level: "DEBUG"}))
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&level))
+	suite.Equal("debug", level.Level)
+	suite.logger.Debug("Shown")
+
+	suite.Nil(suite.logs.find("Hidden", nil))
+	suite.NotNil(suite.logs.find("Shown", nil))
+	suite.NotNil(suite.logs.find("Log level changed", map[string]interface{}{"from": "info", "to": "debug"}))
+}
+
+func (suite *LoggingIntegrationTestSuite) TestLogLevelRequiresAdmin() {
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/admin/log-level", suite.alice, nil)).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("PUT", "/api/admin/log-level", suite.alice, dto.LogLevelRequest{Level: "debug"})).StatusCode)
+	suite.Equal(slog.LevelInfo, suite.level.Level())
+}
+
+func (suite *LoggingIntegrationTestSuite) TestLogLevelIsValidated() {
+	resp := suite.do(jsonRequest("PUT", "/api/admin/log-level", suite.admin, dto.LogLevelRequest{Level: "verbose"}))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(slog.LevelInfo, suite.level.Level())
+}
+
+func (suite *LoggingIntegrationTestSuite) TestLoggerHonorsConfiguredFormat() {
+	var out bytes.Buffer
+	logger, _, err := logging.New(config.LoggingConfig{Level: "warn", Format: "text"}, &out)
+	suite.Require().NoError(err)
+
+	logger.Info("Dropped")
+	logger.Warn("Disk filling up", "free_bytes", 1024)
+
+	suite.NotContains(out.String(), "Dropped")
+	suite.True(strings.Contains(out.String(), `level=WARN msg="Disk filling up" free_bytes=1024`), out.String())
+
+	_, _, err = logging.New(config.LoggingConfig{Format: "xml"}, &out)
+	suite.Error(err)
+	_, _, err = logging.New(config.LoggingConfig{Level: "verbose"}, &out)
+	suite.Error(err)
+}
+
+func TestLoggingIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggingIntegrationTestSuite))
+}
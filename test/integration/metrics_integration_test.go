@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/metrics"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// MetricsIntegrationTestSuite tests the Prometheus metrics served on /metrics
+type MetricsIntegrationTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	metrics *metrics.Metrics
+	workers *server.Workers
+	app     *fiber.App
+	// internal serves /metrics apart from the API
+	internal *fiber.App
+	token    string
+}
+
+func (suite *MetricsIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	suite.metrics = metrics.New()
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.metrics.RegisterDB("main", sqlDB))
+	suite.workers = server.NewWorkers()
+	suite.Require().NoError(suite.metrics.RegisterWorkers(suite.workers))
+
+	todoRepo := metrics.NewTodoRepository(database.NewSQLiteTodoRepository(db), suite.metrics)
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(todoRepo))
+	deps.Metrics = middleware.Metrics(suite.metrics)
+	suite.app = fiber.New()
+	routes.SetupRoutes(suite.app, deps)
+	suite.internal = fiber.New()
+	routes.SetupInternalRoutes(suite.internal, adaptor.HTTPHandler(suite.metrics.Handler()))
+	suite.token, _ = signUp(suite.T(), suite.app, "alice")
+}
+
+func (suite *MetricsIntegrationTestSuite) TearDownTest() {
+	suite.NoError(suite.workers.Stop(context.Background()))
+}
+
+func (suite *MetricsIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req, -1)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *MetricsIntegrationTestSuite) patchRequest(path string) *http.Request {
+	req := jsonRequest("PATCH", path, suite.token, map[string]interface{}{"completed": true})
+	req.Header.Set(fiber.HeaderContentType, "application/merge-patch+json")
+	return req
+}
+
+// scrape returns the metrics in the Prometheus text format
+func (suite *MetricsIntegrationTestSuite) scrape() string {
+	resp, err := suite.internal.Test(httptest.NewRequest("GET", "/metrics", nil), -1)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Contains(resp.Header.Get(fiber.HeaderContentType), "text/plain")
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+	return string(body)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestHTTPRequestsAreCountedByRouteTemplate() {
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Measured"})).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.do(suite.patchRequest("/api/todos/missing")).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.do(suite.patchRequest("/api/todos/also-missing")).StatusCode)
+
+	body := suite.scrape()
+	suite.Contains(body, `todo_http_requests_total{method="POST",route="/api/todos",status="201"} 1`)
+	suite.Contains(body, `todo_http_requests_total{method="PATCH",route="/api/todos/:id",status="404"} 2`)
+	suite.Contains(body, `todo_http_request_duration_seconds_count{method="POST",route="/api/todos",status="201"} 1`)
+	suite.Contains(body, `todo_http_request_duration_seconds_bucket{method="POST",route="/api/todos",status="201",le="+Inf"} 1`)
+	suite.NotContains(body, `route="/api/todos/missing"`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestRepositoryCallsAreTimedAndErrorsCounted() {
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Measured"})).StatusCode)
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", suite.token, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.do(suite.patchRequest("/api/todos/missing")).StatusCode)
+
+	body := suite.scrape()
+	suite.Contains(body, `todo_repository_operation_duration_seconds_count{method="Create",repository="todo"} 1`)
+	suite.Contains(body, `todo_repository_operation_duration_seconds_count{method="GetAll",repository="todo"} 1`)
+	suite.Contains(body, `todo_repository_operation_errors_total{method="GetByID",repository="todo"} 1`)
+	suite.NotContains(body, `todo_repository_operation_errors_total{method="Create"`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestTodosAreCountedByState() {
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Pending"})).StatusCode)
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Pending too"})).StatusCode)
+	suite.Require().NoError(suite.db.Exec("UPDATE todos SET completed = true WHERE text = ?", "Pending too").Error)
+
+	counts, err := database.CountTodosByState(context.Background(), suite.db)
+	suite.Require().NoError(err)
+	suite.metrics.SetTodoCounts(counts)
+
+	body := suite.scrape()
+	suite.Contains(body, `todo_items{state="completed"} 1`)
+	suite.Contains(body, `todo_items{state="pending"} 1`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestPoolStatsAndJobLagAreExported() {
+	release := make(chan struct{})
+	defer close(release)
+	suite.workers.Every("stuck", 10*time.Millisecond, func(context.Context) { <-release })
+	suite.workers.Every("idle", time.Hour, func(context.Context) {})
+	time.Sleep(50 * time.Millisecond)
+
+	body := suite.scrape()
+	suite.Contains(body, `go_sql_open_connections{db_name="main"}`)
+	suite.Contains(body, `go_sql_wait_count_total{db_name="main"}`)
+	suite.Contains(body, `todo_background_job_lag_seconds{job="idle"} 0`)
+	suite.Regexp(`todo_background_job_lag_seconds\{job="stuck"\} 0\.0[1-9]`, body)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestMetricsAreNotServedOnTheAPI() {
+	resp := suite.do(httptest.NewRequest("GET", "/metrics", nil))
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestMetricsIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsIntegrationTestSuite))
+}
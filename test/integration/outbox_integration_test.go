@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// recordingHandler remembers the events it handled and fails while failing is set
+type recordingHandler struct {
+	mu      sync.Mutex
+	failing bool
+	handled []events.ChangeEvent
+}
+
+func (h *recordingHandler) Handle(_ context.Context, event events.ChangeEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failing {
+		return errors.New("subscriber unavailable")
+	}
+	h.handled = append(h.handled, event)
+	return nil
+}
+
+func (h *recordingHandler) fail(failing bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failing = failing
+}
+
+// types returns the types of the handled events, oldest first
+func (h *recordingHandler) types() []events.Type {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	types := make([]events.Type, len(h.handled))
+	for i, event := range h.handled {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// OutboxIntegrationTestSuite tests the domain event outbox and its dispatcher against a real database
+type OutboxIntegrationTestSuite struct {
+	suite.Suite
+	db          *gorm.DB
+	outbox      repositories.OutboxRepository
+	dispatcher  *usecases.EventDispatcher
+	search      *recordingHandler
+	mailer      *recordingHandler
+	todoUseCase *usecases.TodoUseCase
+	ctx         context.Context
+}
+
+func (suite *OutboxIntegrationTestSuite) SetupTest() {
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todos.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	suite.outbox = database.NewSQLiteOutboxRepository(db)
+	suite.dispatcher = usecases.NewEventDispatcher(suite.outbox)
+	suite.search = &recordingHandler{}
+	suite.mailer = &recordingHandler{}
+	suite.dispatcher.Subscribe("search", suite.search)
+	suite.dispatcher.Subscribe("notifications", suite.mailer)
+	suite.todoUseCase = usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+		usecases.WithOutbox(suite.outbox),
+	)
+	suite.ctx = ownerContext("user-1")
+}
+
+func (suite *OutboxIntegrationTestSuite) dispatch() int {
+	handled, err := suite.dispatcher.Dispatch(context.Background())
+	suite.Require().NoError(err)
+	return handled
+}
+
+func (suite *OutboxIntegrationTestSuite) TestStoresEventsWithTheChange() {
+	todo, err := suite.todoUseCase.CreateTodo(suite.ctx, dto.CreateTodoRequest{Text: "Book flights"})
+	suite.Require().NoError(err)
+
+	stored, err := suite.outbox.Since(context.Background(), 0, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(stored, 1)
+	suite.Equal(events.TodoCreated, stored[0].Type)
+	suite.Require().Len(stored[0].Changes, 1)
+	suite.Equal(todo.ID, stored[0].Changes[0].TodoID)
+	suite.Equal("user-1", stored[0].Changes[0].OwnerID)
+	suite.Require().NotNil(stored[0].Changes[0].Todo)
+	suite.Equal("Book flights", stored[0].Changes[0].Todo.Text)
+}
+
+func (suite *OutboxIntegrationTestSuite) TestRelaysEventsInOrder() {
+	todo, err := suite.todoUseCase.CreateTodo(suite.ctx, dto.CreateTodoRequest{Text: "Book flights"})
+	suite.Require().NoError(err)
+	_, err = suite.todoUseCase.ExecuteBatch(suite.ctx, dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpUpdate, ID: todo.ID, Text: "Book flights and hotel"},
+		{Op: dto.BatchOpComplete, ID: todo.ID},
+		{Op: dto.BatchOpDelete, ID: todo.ID},
+	}})
+	suite.Require().NoError(err)
+
+	suite.Equal(10, suite.dispatch())
+
+	// The batch is followed by the event of its commit
+	expected := []events.Type{events.TodoCreated, events.TodoUpdated, events.TodoCompleted, events.TodoDeleted, events.TodosBatchApplied}
+	suite.Equal(expected, suite.search.types())
+	suite.Equal(expected, suite.mailer.types())
+	// Nothing is relayed twice once handled
+	suite.Equal(0, suite.dispatch())
+}
+
+func (suite *OutboxIntegrationTestSuite) TestRolledBackChangesRaiseNoEvents() {
+	_, err := suite.todoUseCase.ExecuteBatch(suite.ctx, dto.BatchRequest{
+		Atomic: true,
+		Operations: []dto.BatchOperation{
+			{Op: dto.BatchOpCreate, Text: "Rolled back"},
+			{Op: dto.BatchOpComplete, ID: "missing"},
+		},
+	})
+	suite.ErrorIs(err, usecases.ErrBatchRolledBack)
+
+	stored, err := suite.outbox.Since(context.Background(), 0, 10)
+	suite.Require().NoError(err)
+	suite.Empty(stored)
+	suite.Equal(0, suite.dispatch())
+}
+
+func (suite *OutboxIntegrationTestSuite) TestDeletedListRaisesAnEventPerTodo() {
+	lists := usecases.NewListUseCase(database.NewSQLiteListRepository(suite.db), database.NewSQLiteListInviteRepository(suite.db), time.Hour,
+		usecases.WithListTodos(suite.todoUseCase))
+	membership, err := lists.CreateList(suite.ctx, dto.CreateListRequest{Name: "Trip"})
+	suite.Require().NoError(err)
+	var todoIDs []string
+	for _, text := range []string{"Book flights", "Pack"} {
+		todo, err := suite.todoUseCase.CreateTodo(suite.ctx, dto.CreateTodoRequest{Text: text, ListID: membership.List.ID})
+		suite.Require().NoError(err)
+		todoIDs = append(todoIDs, todo.ID)
+	}
+	suite.Require().NoError(lists.DeleteList(suite.ctx, membership.List.ID))
+
+	stored, err := suite.outbox.Since(context.Background(), 0, 10)
+	suite.Require().NoError(err)
+	var deleted []string
+	for _, event := range stored {
+		if event.Type == events.TodoDeleted {
+			suite.Require().Len(event.Changes, 1)
+			suite.Equal(membership.List.ID, event.Changes[0].ListID)
+			deleted = append(deleted, event.Changes[0].TodoID)
+		}
+	}
+	suite.ElementsMatch(todoIDs, deleted)
+
+	// The change log keeps a tombstone of each, for sync clients to remove them
+	var tombstones []string
+	suite.Require().NoError(suite.db.Model(&database.SQLiteChangeModel{}).
+		Where("kind = ?", string(entities.ChangeTodoDeleted)).Pluck("todo_id", &tombstones).Error)
+	suite.ElementsMatch(todoIDs, tombstones)
+}
+
+func (suite *OutboxIntegrationTestSuite) TestFailingSubscriberIsRelayedAgain() {
+	suite.mailer.fail(true)
+	_, err := suite.todoUseCase.CreateTodo(suite.ctx, dto.CreateTodoRequest{Text: "Book flights"})
+	suite.Require().NoError(err)
+
+	// The failure does not hold back the other subscriber
+	handled, err := suite.dispatcher.Dispatch(context.Background())
+	suite.Error(err)
+	suite.Equal(1, handled)
+	suite.Equal([]events.Type{events.TodoCreated}, suite.search.types())
+	suite.Empty(suite.mailer.types())
+
+	suite.mailer.fail(false)
+	suite.Equal(1, suite.dispatch())
+	suite.Equal([]events.Type{events.TodoCreated}, suite.mailer.types())
+	suite.Len(suite.search.types(), 1)
+}
+
+func (suite *OutboxIntegrationTestSuite) TestPurgeKeepsUnhandledEvents() {
+	_, err := suite.todoUseCase.CreateTodo(suite.ctx, dto.CreateTodoRequest{Text: "Book flights"})
+	suite.Require().NoError(err)
+	suite.mailer.fail(true)
+	_, _ = suite.dispatcher.Dispatch(context.Background())
+
+	future := time.Now().Add(time.Hour)
+	purged, err := suite.dispatcher.Purge(context.Background(), future)
+	suite.Require().NoError(err)
+	suite.Zero(purged)
+
+	suite.mailer.fail(false)
+	suite.dispatch()
+	purged, err = suite.dispatcher.Purge(context.Background(), future)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), purged)
+
+	stored, err := suite.outbox.Since(context.Background(), 0, 10)
+	suite.Require().NoError(err)
+	suite.Empty(stored)
+}
+
+func TestOutboxIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxIntegrationTestSuite))
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// PatchIntegrationTestSuite tests PATCH /api/todos/:id against a real database
+type PatchIntegrationTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	db     *gorm.DB
+	token  string
+	userID string
+	// mode is the database.mode the todos are stored in
+	mode string
+}
+
+func (suite *PatchIntegrationTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoRepo, err := database.NewTodoRepository(db, config.DatabaseConfig{Mode: suite.mode, SnapshotEvery: 3})
+	suite.Require().NoError(err)
+	todoUseCase := usecases.NewTodoUseCase(todoRepo)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, newTestDependencies(db, todoUseCase))
+	suite.app = app
+	suite.token, suite.userID = signUp(suite.T(), app, "patch-tester")
+}
+
+func (suite *PatchIntegrationTestSuite) SetupTest() {
+	suite.db.Create(&database.SQLiteTodoModel{ID: "todo-1", Text: "Original", OwnerID: suite.userID})
+}
+
+func (suite *PatchIntegrationTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todos")
+}
+
+func (suite *PatchIntegrationTestSuite) patch(contentType, body string) (*http.Response, dto.TodoDetailResponse) {
+	req := httptest.NewRequest("PATCH", "/api/todos/todo-1", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+
+	var todo dto.TodoDetailResponse
+	json.NewDecoder(resp.Body).Decode(&todo)
+	return resp, todo
+}
+
+func (suite *PatchIntegrationTestSuite) TestMergePatch_ReturnsNewVersion() {
+	resp, todo := suite.patch("application/merge-patch+json", `{"completed":true}`)
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(`"2"`, resp.Header.Get("ETag"))
+	suite.True(todo.Completed)
+	suite.Equal("Original", todo.Text)
+	suite.Equal(int64(2), todo.Version)
+}
+
+func (suite *PatchIntegrationTestSuite) TestJSONPatch_TestOnVersionGuardsConcurrentEdits() {
+	patchBody := `[{"op":"test","path":"/version","value":1},{"op":"replace","path":"/text","value":"First"}]`
+
+	resp, todo := suite.patch("application/json-patch+json", patchBody)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("First", todo.Text)
+
+	// The same patch is now stale and must be rejected without changes
+	resp, _ = suite.patch("application/json-patch+json", patchBody)
+	suite.Equal(http.StatusConflict, resp.StatusCode)
+
+	var model database.SQLiteTodoModel
+	suite.db.First(&model, "id = ?", "todo-1")
+	suite.Equal("First", model.Text)
+	suite.Equal(int64(2), model.Version)
+}
+
+func (suite *PatchIntegrationTestSuite) TestPatch_Errors() {
+	resp, _ := suite.patch("application/json", `{"text":"x"}`)
+	suite.Equal(http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, _ = suite.patch("application/merge-patch+json", `{"version":9}`)
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	req := httptest.NewRequest("PATCH", "/api/todos/missing", bytes.NewReader([]byte(`{"text":"x"}`)))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestPatchIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(PatchIntegrationTestSuite))
+}
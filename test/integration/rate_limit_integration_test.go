@@ -0,0 +1,220 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/ratelimit"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testCreateTodoLimit allows bursts of 3 todo creations
+var testCreateTodoLimit = entities.RateLimitPolicy{Name: "create-todo", Requests: 3, Period: time.Hour}
+
+// newRateLimitedApp serves the API with the create-todo and login policies applied through store
+func newRateLimitedApp(db *gorm.DB, store repositories.RateLimitStore, config fiber.Config) *fiber.App {
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+	)
+	rateLimitUseCase := usecases.NewRateLimitUseCase(store,
+		entities.RateLimitPolicy{Name: "default", Requests: 1000, Period: time.Minute},
+		usecases.RateLimitRule{Method: "POST", Path: "/api/todos", Policy: testCreateTodoLimit},
+		usecases.RateLimitRule{Method: "POST", Path: "/api/auth/login", Policy: entities.RateLimitPolicy{Name: "login", Requests: 2, Period: time.Hour}},
+	)
+
+	deps := newTestDependencies(db, todoUseCase, usecases.WithAdminUsernames("root"))
+	deps.RateLimit = middleware.RateLimit(rateLimitUseCase)
+	deps.RateLimitHandler = handlers.NewRateLimitHandler(rateLimitUseCase)
+
+	app := fiber.New(config)
+	routes.SetupRoutes(app, deps)
+	return app
+}
+
+// RateLimitIntegrationTestSuite tests per-client rate limiting over HTTP
+type RateLimitIntegrationTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	db    *gorm.DB
+	admin string
+	alice string
+}
+
+func (suite *RateLimitIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	suite.app = newRateLimitedApp(db, ratelimit.NewMemoryStore(), fiber.Config{})
+	suite.admin, _ = signUp(suite.T(), suite.app, "root")
+	suite.alice, _ = signUp(suite.T(), suite.app, "alice")
+}
+
+func (suite *RateLimitIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *RateLimitIntegrationTestSuite) createTodo(token string) *http.Response {
+	return suite.do(jsonRequest("POST", "/api/todos", token, map[string]string{"text": "Limited"}))
+}
+
+func (suite *RateLimitIntegrationTestSuite) buckets(query string) []dto.RateLimitBucketResponse {
+	resp := suite.do(jsonRequest("GET", "/api/admin/rate-limits"+query, suite.admin, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var buckets []dto.RateLimitBucketResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&buckets))
+	return buckets
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestHeadersAndRejection() {
+	resp := suite.createTodo(suite.alice)
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal("3", resp.Header.Get(middleware.RateLimitLimitHeader))
+	suite.Equal("2", resp.Header.Get(middleware.RateLimitRemainingHeader))
+	suite.Equal("1200", resp.Header.Get(middleware.RateLimitResetHeader))
+	suite.Equal(`3;w=3600;burst=3;name="create-todo"`, resp.Header.Get(middleware.RateLimitPolicyHeader))
+	suite.Empty(resp.Header.Get(fiber.HeaderRetryAfter))
+
+	suite.Equal(http.StatusCreated, suite.createTodo(suite.alice).StatusCode)
+	suite.Equal(http.StatusCreated, suite.createTodo(suite.alice).StatusCode)
+
+	resp = suite.createTodo(suite.alice)
+	suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	suite.Equal("0", resp.Header.Get(middleware.RateLimitRemainingHeader))
+	suite.Equal("1200", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// Other routes have their own bucket
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", suite.alice, nil)).StatusCode)
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestBucketsArePerClient() {
+	for i := 0; i < 3; i++ {
+		suite.Require().Equal(http.StatusCreated, suite.createTodo(suite.alice).StatusCode)
+	}
+	suite.Require().Equal(http.StatusTooManyRequests, suite.createTodo(suite.alice).StatusCode)
+
+	suite.Equal(http.StatusCreated, suite.createTodo(suite.admin).StatusCode)
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestPublicRoutesAreLimitedByIP() {
+	credentials := map[string]string{"username": "alice", "password": "correct horse battery"}
+
+	// signUp already registered and logged in alice and root from the same address
+	resp := suite.do(jsonRequest("POST", "/api/auth/login", "", credentials))
+	suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+
+	remaining := map[string]int{}
+	for _, bucket := range suite.buckets("?subject=ip:0.0.0.0") {
+		remaining[bucket.Policy] = bucket.Remaining
+	}
+	suite.Equal(map[string]int{"default": 998, "login": 0}, remaining)
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestProxyHeaderIsOnlyBelievedFromTrustedProxies() {
+	credentials := map[string]string{"username": "alice", "password": "correct horse battery"}
+	login := func(app *fiber.App, forwardedFor string) int {
+		req := jsonRequest("POST", "/api/auth/login", "", credentials)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		resp, err := app.Test(req)
+		suite.Require().NoError(err)
+		return resp.StatusCode
+	}
+
+	// Test requests come from 0.0.0.0, which is not a trusted proxy here
+	untrusted := newRateLimitedApp(suite.db, ratelimit.NewMemoryStore(), fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"10.0.0.0/8"},
+		EnableIPValidation:      true,
+	})
+	suite.Equal(http.StatusOK, login(untrusted, "203.0.113.1"))
+	suite.Equal(http.StatusOK, login(untrusted, "203.0.113.2"))
+	suite.Equal(http.StatusTooManyRequests, login(untrusted, "203.0.113.3"))
+
+	trusted := newRateLimitedApp(suite.db, ratelimit.NewMemoryStore(), fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0"},
+		EnableIPValidation:      true,
+	})
+	suite.Equal(http.StatusOK, login(trusted, "203.0.113.1, 10.0.0.1"))
+	suite.Equal(http.StatusOK, login(trusted, "203.0.113.1"))
+	suite.Equal(http.StatusTooManyRequests, login(trusted, "203.0.113.1"))
+	suite.Equal(http.StatusOK, login(trusted, "203.0.113.2"))
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestAdminCanInspectAndResetBuckets() {
+	for i := 0; i < 3; i++ {
+		suite.Require().Equal(http.StatusCreated, suite.createTodo(suite.alice).StatusCode)
+	}
+
+	var me dto.UserResponse
+	resp := suite.do(jsonRequest("GET", "/api/auth/me", suite.alice, nil))
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&me))
+	subject := "user:" + me.ID
+
+	buckets := suite.buckets("?subject=" + subject)
+	policies := map[string]dto.RateLimitBucketResponse{}
+	for _, bucket := range buckets {
+		policies[bucket.Policy] = bucket
+	}
+	suite.Require().Contains(policies, "create-todo")
+	suite.Equal(0, policies["create-todo"].Remaining)
+	suite.Equal(3, policies["create-todo"].Limit)
+
+	resp = suite.do(jsonRequest("DELETE", "/api/admin/rate-limits?subject="+subject+"&policy=create-todo", suite.admin, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var reset dto.RateLimitResetResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&reset))
+	suite.Equal(int64(1), reset.Reset)
+
+	suite.Equal(http.StatusCreated, suite.createTodo(suite.alice).StatusCode)
+}
+
+func (suite *RateLimitIntegrationTestSuite) TestAdminEndpointsRequireAdmin() {
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/admin/rate-limits", suite.alice, nil)).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("DELETE", "/api/admin/rate-limits?subject=ip:0.0.0.0", suite.alice, nil)).StatusCode)
+	suite.Equal(http.StatusBadRequest, suite.do(jsonRequest("DELETE", "/api/admin/rate-limits", suite.admin, nil)).StatusCode)
+}
+
+// TestSharedStoreAcrossNodes runs two app instances on one database, as two nodes of a cluster would
+func (suite *RateLimitIntegrationTestSuite) TestSharedStoreAcrossNodes() {
+	nodeA := newRateLimitedApp(suite.db, database.NewSQLiteRateLimitStore(suite.db), fiber.Config{})
+	nodeB := newRateLimitedApp(suite.db, database.NewSQLiteRateLimitStore(suite.db), fiber.Config{})
+
+	for i, node := range []*fiber.App{nodeA, nodeB, nodeA} {
+		resp, err := node.Test(jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Shared"}))
+		suite.Require().NoError(err)
+		suite.Require().Equal(http.StatusCreated, resp.StatusCode, "request %d", i)
+	}
+
+	resp, err := nodeB.Test(jsonRequest("POST", "/api/todos", suite.alice, map[string]string{"text": "Shared"}))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+
+	deleted, err := database.NewSQLiteRateLimitStore(suite.db).DeleteFull(context.Background(), time.Now().Add(2*time.Hour))
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), deleted)
+}
+
+func TestRateLimitIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitIntegrationTestSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
 	"todo-backend/internal/domain/repositories"
 	"todo-backend/internal/infrastructure/database"
 
@@ -16,11 +17,14 @@ import (
 // Repository Integration Tests
 // These test the actual SQLite repository implementation with real database
 
+// repositoryTestOwner is the user every repository test runs as
+const repositoryTestOwner = "owner-1"
+
 func setupRepositoryTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 	
-	err = db.AutoMigrate(&database.SQLiteTodoModel{})
+	err = database.Migrate(db)
 	require.NoError(t, err)
 	
 	return db
@@ -31,7 +35,7 @@ func TestSQLiteTodoRepository_Create_Integration(t *testing.T) {
 		// Given
 		db := setupRepositoryTestDB(t)
 		repo := database.NewSQLiteTodoRepository(db)
-		ctx := context.Background()
+		ctx := ownerContext(repositoryTestOwner)
 		
 		todo := entities.NewTodo("Test todo for repository")
 		
@@ -62,17 +66,19 @@ func TestSQLiteTodoRepository_GetAll_Integration(t *testing.T) {
 		// Given
 		db := setupRepositoryTestDB(t)
 		repo := database.NewSQLiteTodoRepository(db)
-		ctx := context.Background()
+		ctx := ownerContext(repositoryTestOwner)
 		
 		// Create test data directly in database to control timestamps
 		db.Create(&database.SQLiteTodoModel{
 			ID:        "todo-1",
 			Text:      "First todo",
+			OwnerID:   repositoryTestOwner,
 			CreatedAt: 1000,
 		})
 		db.Create(&database.SQLiteTodoModel{
 			ID:        "todo-2",
 			Text:      "Second todo",
+			OwnerID:   repositoryTestOwner,
 			CreatedAt: 2000,
 		})
 		
@@ -94,7 +100,7 @@ func TestSQLiteTodoRepository_GetAll_Integration(t *testing.T) {
 		// Given
 		db := setupRepositoryTestDB(t)
 		repo := database.NewSQLiteTodoRepository(db)
-		ctx := context.Background()
+		ctx := ownerContext(repositoryTestOwner)
 		
 		// When
 		todos, err := repo.GetAll(ctx)
@@ -110,12 +116,13 @@ func TestSQLiteTodoRepository_GetByID_Integration(t *testing.T) {
 		// Given
 		db := setupRepositoryTestDB(t)
 		repo := database.NewSQLiteTodoRepository(db)
-		ctx := context.Background()
+		ctx := ownerContext(repositoryTestOwner)
 		
 		// Seed test data
 		db.Create(&database.SQLiteTodoModel{
 			ID:        "test-id-123",
 			Text:      "Find me",
+			OwnerID:   repositoryTestOwner,
 			CreatedAt: 1000,
 		})
 		
@@ -133,7 +140,7 @@ func TestSQLiteTodoRepository_GetByID_Integration(t *testing.T) {
 		// Given
 		db := setupRepositoryTestDB(t)
 		repo := database.NewSQLiteTodoRepository(db)
-		ctx := context.Background()
+		ctx := ownerContext(repositoryTestOwner)
 		
 		// When
 		todo, err := repo.GetByID(ctx, "non-existent-id")
@@ -145,6 +152,51 @@ func TestSQLiteTodoRepository_GetByID_Integration(t *testing.T) {
 	})
 }
 
+func TestSQLiteTodoRepository_OwnerScoping_Integration(t *testing.T) {
+	t.Run("should hide todos of other owners from every query", func(t *testing.T) {
+		// Given
+		db := setupRepositoryTestDB(t)
+		repo := database.NewSQLiteTodoRepository(db)
+		ctx := ownerContext(repositoryTestOwner)
+		otherCtx := ownerContext("owner-2")
+
+		todo, err := repo.Create(otherCtx, entities.NewTodo("Someone else's todo"))
+		require.NoError(t, err)
+
+		// When
+		todos, listErr := repo.GetAll(ctx)
+		_, getErr := repo.GetByID(ctx, todo.ID)
+		_, updateErr := repo.Update(ctx, todo)
+		deleteErr := repo.Delete(ctx, todo.ID)
+
+		// Then
+		assert.NoError(t, listErr)
+		assert.Empty(t, todos)
+		assert.Equal(t, repositories.ErrTodoNotFound, getErr)
+		assert.Equal(t, repositories.ErrTodoNotFound, updateErr)
+		assert.Equal(t, repositories.ErrTodoNotFound, deleteErr)
+
+		stored, err := repo.GetByID(otherCtx, todo.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "owner-2", stored.OwnerID)
+	})
+
+	t.Run("should refuse queries without an authenticated principal", func(t *testing.T) {
+		// Given
+		db := setupRepositoryTestDB(t)
+		repo := database.NewSQLiteTodoRepository(db)
+		ctx := context.Background()
+
+		// When
+		_, createErr := repo.Create(ctx, entities.NewTodo("Unowned"))
+		_, listErr := repo.GetAll(ctx)
+
+		// Then
+		assert.ErrorIs(t, createErr, identity.ErrUnauthenticated)
+		assert.ErrorIs(t, listErr, identity.ErrUnauthenticated)
+	})
+}
+
 // Entity-Model Conversion Integration Tests
 func TestSQLiteTodoModel_ToEntity_Integration(t *testing.T) {
 	t.Run("should convert model to entity correctly", func(t *testing.T) {
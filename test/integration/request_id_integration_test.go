@@ -0,0 +1,196 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/correlation"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/logging"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// requestIDRecorder records the request IDs seen by the todo repository and
+// carried by the events relayed from the outbox
+type requestIDRecorder struct {
+	repositories.TodoRepository
+
+	mu         sync.Mutex
+	repository []string
+	events     []events.ChangeEvent
+}
+
+func (r *requestIDRecorder) Create(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	r.mu.Lock()
+	id, _ := correlation.FromContext(ctx)
+	r.repository = append(r.repository, id)
+	r.mu.Unlock()
+	return r.TodoRepository.Create(ctx, todo)
+}
+
+func (r *requestIDRecorder) Handle(ctx context.Context, event events.ChangeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// RequestIDIntegrationTestSuite tests that the request ID reaches every
+// subsystem and is reported back to the client
+type RequestIDIntegrationTestSuite struct {
+	suite.Suite
+	db         *gorm.DB
+	recorder   *requestIDRecorder
+	dispatcher *usecases.EventDispatcher
+	logs       *logBuffer
+	previous   *slog.Logger
+	app        *fiber.App
+	admin      string
+}
+
+func (suite *RequestIDIntegrationTestSuite) SetupTest() {
+	suite.logs = &logBuffer{}
+	logger, _, err := logging.New(config.LoggingConfig{Level: "info", Format: "json"}, suite.logs)
+	suite.Require().NoError(err)
+	suite.previous = slog.Default()
+	slog.SetDefault(logger)
+
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todo.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.Require().NoError(db.Use(database.NewTenantScoping(nil)))
+
+	suite.db = db
+	suite.recorder = &requestIDRecorder{TodoRepository: database.NewSQLiteTodoRepository(db)}
+	outbox := database.NewSQLiteOutboxRepository(db)
+	suite.dispatcher = usecases.NewEventDispatcher(outbox)
+	suite.dispatcher.Subscribe("recorder", suite.recorder)
+	todoUseCase := usecases.NewTodoUseCase(suite.recorder, usecases.WithOutbox(outbox))
+	auditUseCase := usecases.NewAuditUseCase(database.NewSQLiteAuditRepository(db))
+
+	deps := newTestDependencies(db, todoUseCase, usecases.WithAdminUsernames("root"))
+	deps.RequestID = middleware.RequestID()
+	deps.Logger = middleware.RequestLogger(logger)
+	deps.Tenant = middleware.ResolveTenant(middleware.TenantOptions{Default: "acme"})
+	deps.AuditHandler = handlers.NewAuditHandler(auditUseCase)
+	deps.Audit = middleware.Audit(auditUseCase)
+	// Renders returned errors as JSON, as cmd/main.go does
+	suite.app = fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"success": false, "error": err.Error()})
+		},
+	})
+	routes.SetupRoutes(suite.app, deps)
+
+	suite.admin, _ = signUp(suite.T(), suite.app, "root")
+}
+
+func (suite *RequestIDIntegrationTestSuite) TearDownTest() {
+	slog.SetDefault(suite.previous)
+}
+
+func (suite *RequestIDIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req, -1)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *RequestIDIntegrationTestSuite) errorBody(resp *http.Response) map[string]interface{} {
+	var body map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+func (suite *RequestIDIntegrationTestSuite) TestClientRequestIDReachesEverySubsystem() {
+	req := jsonRequest("POST", "/api/todos", suite.admin, map[string]string{"text": "Correlated"})
+	req.Header.Set(middleware.RequestIDHeader, "support-ticket-77")
+
+	resp := suite.do(req)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal("support-ticket-77", resp.Header.Get(middleware.RequestIDHeader))
+
+	suite.Equal([]string{"support-ticket-77"}, suite.recorder.repository, "the repository context carries the ID")
+	suite.Require().NoError(database.ForEachTenant(context.Background(), suite.db, func(ctx context.Context) error {
+		_, err := suite.dispatcher.Dispatch(ctx)
+		return err
+	}))
+	suite.Require().Len(suite.recorder.events, 1)
+	suite.Equal("support-ticket-77", suite.recorder.events[0].RequestID)
+	suite.NotNil(suite.logs.find("Request", map[string]interface{}{"request_id": "support-ticket-77"}))
+
+	resp = suite.do(jsonRequest("GET", "/api/admin/audit", suite.admin, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var audited []dto.AuditEventResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&audited))
+	var requestIDs []string
+	for _, event := range audited {
+		requestIDs = append(requestIDs, event.RequestID)
+	}
+	suite.Contains(requestIDs, "support-ticket-77")
+}
+
+func (suite *RequestIDIntegrationTestSuite) TestRequestIDIsGeneratedWhenMissingOrInvalid() {
+	for name, header := range map[string]string{
+		"missing":   "",
+		"too long":  strings.Repeat("a", 129),
+		"non-ASCII": "réquest",
+	} {
+		req := jsonRequest("GET", "/api/todos", suite.admin, nil)
+		if header != "" {
+			req.Header.Set(middleware.RequestIDHeader, header)
+		}
+
+		resp := suite.do(req)
+		suite.Equal(http.StatusOK, resp.StatusCode, name)
+		_, err := uuid.Parse(resp.Header.Get(middleware.RequestIDHeader))
+		suite.NoError(err, name)
+	}
+}
+
+func (suite *RequestIDIntegrationTestSuite) TestErrorBodiesCarryTheRequestID() {
+	requests := map[string]*http.Request{
+		// Rendered by a middleware
+		"unauthenticated": jsonRequest("GET", "/api/todos", "", nil),
+		// Rendered by a handler
+		"invalid": jsonRequest("POST", "/api/todos", suite.admin, map[string]string{"text": ""}),
+	}
+	for name, req := range requests {
+		req.Header.Set(middleware.RequestIDHeader, "req-"+name)
+
+		resp := suite.do(req)
+		suite.GreaterOrEqual(resp.StatusCode, http.StatusBadRequest, name)
+		body := suite.errorBody(resp)
+		suite.Equal("req-"+name, body["requestId"], name)
+		suite.Equal(false, body["success"], name)
+	}
+
+	// Returned as an error and rendered by the app's error handler
+	req := jsonRequest("GET", "/no-such-route", "", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-unrouted")
+	resp := suite.do(req)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+	suite.Equal("req-unrouted", resp.Header.Get(middleware.RequestIDHeader))
+	suite.Equal("req-unrouted", suite.errorBody(resp)["requestId"])
+}
+
+func TestRequestIDIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDIntegrationTestSuite))
+}
@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/ratelimit"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// ShareLinkIntegrationTestSuite tests public read-only share links over HTTP
+type ShareLinkIntegrationTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	db     *gorm.DB
+	owner  string
+	listID string
+}
+
+func (suite *ShareLinkIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+	)
+	deps := newTestDependencies(db, todoUseCase)
+	deps.ShareLinkRateLimit = middleware.RateLimit(usecases.NewRateLimitUseCase(ratelimit.NewMemoryStore(),
+		entities.RateLimitPolicy{Name: "share-link", Requests: 10, Period: time.Minute}))
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.owner, _ = signUp(suite.T(), app, "alice")
+
+	resp := suite.do(jsonRequest("POST", "/api/lists", suite.owner, map[string]string{"name": "Groceries"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var list dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+	suite.listID = list.ID
+
+	suite.createListTodo("Milk", false)
+	suite.createListTodo("Eggs", true)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *ShareLinkIntegrationTestSuite) createListTodo(text string, completed bool) {
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.owner, map[string]string{"text": text, "listId": suite.listID}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	if !completed {
+		return
+	}
+	var todo dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todo))
+	req := jsonRequest("PATCH", "/api/todos/"+todo.ID, suite.owner, map[string]bool{"completed": true})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	suite.Require().Equal(http.StatusOK, suite.do(req).StatusCode)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) createLink(body map[string]interface{}) dto.CreatedShareLinkResponse {
+	resp := suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/share-links", suite.owner, body))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created dto.CreatedShareLinkResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&created))
+	suite.Require().NotEmpty(created.Token)
+	suite.Equal("/s/"+created.Token, created.Path)
+	return created
+}
+
+// open requests a share link anonymously, with an optional password
+func (suite *ShareLinkIntegrationTestSuite) open(path, password string) *http.Response {
+	req := httptest.NewRequest("GET", path, nil)
+	if password != "" {
+		req.Header.Set(handlers.SharePasswordHeader, password)
+	}
+	return suite.do(req)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) accesses(linkID string) []dto.ShareLinkAccessResponse {
+	resp := suite.do(jsonRequest("GET", "/api/lists/"+suite.listID+"/share-links/"+linkID+"/accesses", suite.owner, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var accesses []dto.ShareLinkAccessResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&accesses))
+	return accesses
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestAnonymousReadOnlyAccessLeaksNoMetadata() {
+	link := suite.createLink(map[string]interface{}{})
+
+	resp := suite.open(link.Path, "")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("no-store", resp.Header.Get("Cache-Control"))
+	suite.Equal("no-referrer", resp.Header.Get("Referrer-Policy"))
+
+	raw, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+	var shared dto.SharedListResponse
+	suite.Require().NoError(json.Unmarshal(raw, &shared))
+	suite.Equal("Groceries", shared.Name)
+	suite.Len(shared.Todos, 2)
+
+	var generic map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(raw, &generic))
+	suite.ElementsMatch([]string{"name", "todos"}, keys(generic))
+	for _, todo := range generic["todos"].([]interface{}) {
+		suite.ElementsMatch([]string{"text", "completed", "createdAt"}, keys(todo.(map[string]interface{})))
+	}
+
+	// The link is read-only: it does not authenticate API requests
+	suite.Equal(http.StatusUnauthorized, suite.do(jsonRequest("GET", "/api/todos?listId="+suite.listID, link.Token, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.open("/s/not-a-token", "").StatusCode)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestHideCompleted() {
+	link := suite.createLink(map[string]interface{}{"hideCompleted": true})
+	suite.True(link.HideCompleted)
+
+	resp := suite.open(link.Path, "")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var shared dto.SharedListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&shared))
+	suite.Require().Len(shared.Todos, 1)
+	suite.Equal("Milk", shared.Todos[0].Text)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestPasswordProtectionAndAccessLog() {
+	link := suite.createLink(map[string]interface{}{"password": "open sesame"})
+	suite.True(link.PasswordProtected)
+
+	suite.Equal(http.StatusUnauthorized, suite.open(link.Path, "").StatusCode)
+	suite.Equal(http.StatusUnauthorized, suite.open(link.Path, "wrong password").StatusCode)
+	suite.Equal(http.StatusOK, suite.open(link.Path, "open sesame").StatusCode)
+
+	accesses := suite.accesses(link.ID)
+	suite.Require().Len(accesses, 3)
+	outcomes := []string{accesses[0].Outcome, accesses[1].Outcome, accesses[2].Outcome}
+	suite.ElementsMatch([]string{"denied", "denied", "granted"}, outcomes)
+	suite.NotEmpty(accesses[0].IPAddress)
+
+	// Neither the password nor the token is ever listed
+	resp := suite.do(jsonRequest("GET", "/api/lists/"+suite.listID+"/share-links", suite.owner, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var raw []map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&raw))
+	suite.Require().Len(raw, 1)
+	suite.NotContains(raw[0], "token")
+	suite.NotContains(raw[0], "password")
+
+	resp = suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/share-links", suite.owner, map[string]interface{}{"password": "short"}))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestExpiryAndRevocation() {
+	resp := suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/share-links", suite.owner, map[string]interface{}{"expiresAt": time.Now().Add(-time.Hour)}))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	expiring := suite.createLink(map[string]interface{}{"expiresAt": time.Now().Add(time.Hour)})
+	suite.Equal(http.StatusOK, suite.open(expiring.Path, "").StatusCode)
+	suite.db.Model(&database.SQLiteShareLinkModel{}).Where("id = ?", expiring.ID).Update("expires_at", time.Now().Add(-time.Minute).Unix())
+	suite.Equal(http.StatusNotFound, suite.open(expiring.Path, "").StatusCode)
+
+	revoked := suite.createLink(map[string]interface{}{})
+	suite.Equal(http.StatusNoContent, suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID+"/share-links/"+revoked.ID, suite.owner, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.open(revoked.Path, "").StatusCode)
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID+"/share-links/"+revoked.ID, suite.owner, nil)).StatusCode)
+
+	accesses := suite.accesses(revoked.ID)
+	suite.Require().Len(accesses, 1)
+	suite.Equal("inactive", accesses[0].Outcome)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestOnlyManagersControlLinks() {
+	mallory, _ := signUp(suite.T(), suite.app, "mallory")
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/share-links", mallory, map[string]interface{}{})).StatusCode)
+
+	resp := suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/invites", suite.owner, map[string]string{"role": "editor"}))
+	var invite dto.CreatedInviteResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&invite))
+	suite.Require().Equal(http.StatusOK, suite.do(jsonRequest("POST", "/api/invites/accept", mallory, map[string]string{"token": invite.Token})).StatusCode)
+
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/share-links", mallory, map[string]interface{}{})).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/lists/"+suite.listID+"/share-links", mallory, nil)).StatusCode)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestRateLimited() {
+	link := suite.createLink(map[string]interface{}{})
+
+	for i := 0; i < 10; i++ {
+		suite.Require().Equal(http.StatusOK, suite.open(link.Path, "").StatusCode)
+	}
+	resp := suite.open(link.Path, "")
+	suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	suite.NotEmpty(resp.Header.Get("Retry-After"))
+
+	// Unknown tokens count against the limit as well, so they cannot be guessed at speed
+	suite.Equal(http.StatusTooManyRequests, suite.open("/s/guess", "").StatusCode)
+	suite.Len(suite.accesses(link.ID), 10)
+}
+
+func (suite *ShareLinkIntegrationTestSuite) TestDeletingListRemovesLinks() {
+	link := suite.createLink(map[string]interface{}{})
+	suite.Equal(http.StatusNoContent, suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID, suite.owner, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.open(link.Path, "").StatusCode)
+
+	var count int64
+	suite.Require().NoError(suite.db.Model(&database.SQLiteShareLinkModel{}).Count(&count).Error)
+	suite.Zero(count)
+}
+
+func keys(object map[string]interface{}) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestShareLinkIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ShareLinkIntegrationTestSuite))
+}
@@ -0,0 +1,238 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// SharingIntegrationTestSuite tests shared lists, invites and roles over HTTP
+type SharingIntegrationTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	db      *gorm.DB
+	owner   string
+	guest   string
+	guestID string
+	listID  string
+}
+
+func (suite *SharingIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+	)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, newTestDependencies(db, todoUseCase))
+	suite.app = app
+	suite.owner, _ = signUp(suite.T(), app, "alice")
+	suite.guest, suite.guestID = signUp(suite.T(), app, "bob")
+
+	resp := suite.do(jsonRequest("POST", "/api/lists", suite.owner, map[string]string{"name": "Groceries"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var list dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+	suite.Equal("owner", list.Role)
+	suite.listID = list.ID
+}
+
+func (suite *SharingIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// invite creates an invite for role and returns its token
+func (suite *SharingIntegrationTestSuite) invite(role string) dto.CreatedInviteResponse {
+	resp := suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/invites", suite.owner, map[string]string{"role": role}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created dto.CreatedInviteResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&created))
+	suite.Require().NotEmpty(created.Token)
+	return created
+}
+
+func (suite *SharingIntegrationTestSuite) accept(token string) *http.Response {
+	return suite.do(jsonRequest("POST", "/api/invites/accept", suite.guest, map[string]string{"token": token}))
+}
+
+// createListTodo creates a todo on the shared list as the owner
+func (suite *SharingIntegrationTestSuite) createListTodo(text string) string {
+	resp := suite.do(jsonRequest("POST", "/api/todos", suite.owner, map[string]string{"text": text, "listId": suite.listID}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var todo dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todo))
+	return todo.ID
+}
+
+// complete marks a todo as done with a merge patch
+func (suite *SharingIntegrationTestSuite) complete(todoID, token string) *http.Response {
+	req := jsonRequest("PATCH", "/api/todos/"+todoID, token, map[string]bool{"completed": true})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	return suite.do(req)
+}
+
+func (suite *SharingIntegrationTestSuite) listTodos(token string) []dto.ContractTodoResponse {
+	resp := suite.do(jsonRequest("GET", "/api/todos?listId="+suite.listID, token, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var todos []dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todos))
+	return todos
+}
+
+func (suite *SharingIntegrationTestSuite) TestNonMemberCannotSeeList() {
+	todoID := suite.createListTodo("Milk")
+
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("GET", "/api/lists/"+suite.listID, suite.guest, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("GET", "/api/todos?listId="+suite.listID, suite.guest, nil)).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.complete(todoID, suite.guest).StatusCode)
+
+	resp := suite.do(jsonRequest("GET", "/api/todos", suite.guest, nil))
+	var todos []dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todos))
+	suite.Empty(todos)
+}
+
+func (suite *SharingIntegrationTestSuite) TestViewerIsReadOnly() {
+	todoID := suite.createListTodo("Milk")
+	suite.Require().Equal(http.StatusOK, suite.accept(suite.invite("viewer").Token).StatusCode)
+
+	todos := suite.listTodos(suite.guest)
+	suite.Require().Len(todos, 1)
+	suite.Equal(todoID, todos[0].ID)
+
+	// Shared todos also show up in the viewer's overall list
+	resp := suite.do(jsonRequest("GET", "/api/todos", suite.guest, nil))
+	var all []dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&all))
+	suite.Len(all, 1)
+
+	suite.Equal(http.StatusForbidden, suite.complete(todoID, suite.guest).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("POST", "/api/todos", suite.guest, map[string]string{"text": "Eggs", "listId": suite.listID})).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("POST", "/api/todos/"+todoID+"/comments", suite.guest, map[string]string{"body": "Whole?"})).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("POST", "/api/lists/"+suite.listID+"/invites", suite.guest, map[string]string{"role": "viewer"})).StatusCode)
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID, suite.guest, nil)).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestCommenterCanComment() {
+	todoID := suite.createListTodo("Milk")
+	suite.Require().Equal(http.StatusOK, suite.accept(suite.invite("commenter").Token).StatusCode)
+
+	resp := suite.do(jsonRequest("POST", "/api/todos/"+todoID+"/comments", suite.guest, map[string]string{"body": "Oat milk please"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("GET", "/api/todos/"+todoID+"/comments", suite.owner, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var comments []dto.CommentResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&comments))
+	suite.Require().Len(comments, 1)
+	suite.Equal("Oat milk please", comments[0].Body)
+	suite.Equal(suite.guestID, comments[0].AuthorID)
+
+	suite.Equal(http.StatusForbidden, suite.complete(todoID, suite.guest).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestEditorCanChangeTodos() {
+	todoID := suite.createListTodo("Milk")
+	suite.Require().Equal(http.StatusOK, suite.accept(suite.invite("editor").Token).StatusCode)
+
+	suite.Equal(http.StatusOK, suite.complete(todoID, suite.guest).StatusCode)
+	suite.Equal(http.StatusCreated, suite.do(jsonRequest("POST", "/api/todos", suite.guest, map[string]string{"text": "Eggs", "listId": suite.listID})).StatusCode)
+	suite.Len(suite.listTodos(suite.owner), 2)
+
+	// Editors cannot manage the list
+	suite.Equal(http.StatusForbidden, suite.do(jsonRequest("GET", "/api/lists/"+suite.listID+"/invites", suite.guest, nil)).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestInviteIsSingleUse() {
+	token := suite.invite("viewer").Token
+
+	suite.Equal(http.StatusOK, suite.accept(token).StatusCode)
+	suite.Equal(http.StatusNotFound, suite.accept(token).StatusCode)
+
+	// A second invite for an existing member conflicts
+	suite.Equal(http.StatusConflict, suite.accept(suite.invite("editor").Token).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestRevokedInviteCannotBeAccepted() {
+	created := suite.invite("viewer")
+
+	resp := suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID+"/invites/"+created.ID, suite.owner, nil))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	suite.Equal(http.StatusNotFound, suite.accept(created.Token).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestExpiredInviteCannotBeAccepted() {
+	created := suite.invite("viewer")
+	suite.Require().NoError(suite.db.Model(&database.SQLiteListInviteModel{}).
+		Where("id = ?", created.ID).Update("expires_at", time.Now().Add(-time.Minute).Unix()).Error)
+
+	suite.Equal(http.StatusNotFound, suite.accept(created.Token).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestRemovedMemberLosesAccess() {
+	suite.createListTodo("Milk")
+	suite.Require().Equal(http.StatusOK, suite.accept(suite.invite("editor").Token).StatusCode)
+	suite.Len(suite.listTodos(suite.guest), 1)
+
+	resp := suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID+"/members/"+suite.guestID, suite.owner, nil))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	suite.Equal(http.StatusNotFound, suite.do(jsonRequest("GET", "/api/todos?listId="+suite.listID, suite.guest, nil)).StatusCode)
+}
+
+func (suite *SharingIntegrationTestSuite) TestRoleChangeAndMembers() {
+	suite.Require().Equal(http.StatusOK, suite.accept(suite.invite("viewer").Token).StatusCode)
+
+	resp := suite.do(jsonRequest("PUT", "/api/lists/"+suite.listID+"/members/"+suite.guestID, suite.owner, map[string]string{"role": "editor"}))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("GET", "/api/lists/"+suite.listID+"/members", suite.guest, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var members []dto.ListMemberResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&members))
+	suite.Require().Len(members, 2)
+	roles := map[string]string{}
+	for _, member := range members {
+		roles[member.Username] = member.Role
+	}
+	suite.Equal(map[string]string{"alice": "owner", "bob": "editor"}, roles)
+
+	resp = suite.do(jsonRequest("GET", "/api/lists", suite.guest, nil))
+	var lists []dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&lists))
+	suite.Require().Len(lists, 1)
+	suite.Equal("editor", lists[0].Role)
+}
+
+func (suite *SharingIntegrationTestSuite) TestDeleteListRemovesItsTodos() {
+	suite.createListTodo("Milk")
+
+	resp := suite.do(jsonRequest("DELETE", "/api/lists/"+suite.listID, suite.owner, nil))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	var count int64
+	suite.Require().NoError(suite.db.Model(&database.SQLiteTodoModel{}).Count(&count).Error)
+	suite.Zero(count)
+}
+
+func TestSharingIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(SharingIntegrationTestSuite))
+}
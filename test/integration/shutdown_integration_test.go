@@ -0,0 +1,180 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// ShutdownIntegrationTestSuite tests graceful shutdown on SIGTERM
+type ShutdownIntegrationTestSuite struct {
+	suite.Suite
+	dbFile string
+	db     *gorm.DB
+	app    *fiber.App
+	token  string
+
+	readiness *server.Readiness
+	// entered is closed when a todo creation reaches the handler, which then
+	// waits for release
+	entered     chan struct{}
+	release     chan struct{}
+	enteredOnce sync.Once
+}
+
+func (suite *ShutdownIntegrationTestSuite) SetupTest() {
+	suite.dbFile = filepath.Join(suite.T().TempDir(), "todo.db")
+	db, err := gorm.Open(sqlite.Open(suite.dbFile), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	suite.readiness = &server.Readiness{}
+	suite.entered = make(chan struct{})
+	suite.release = make(chan struct{})
+	suite.enteredOnce = sync.Once{}
+
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db)))
+	deps.Ready = suite.readiness.Ready
+	// Hold todo creations in flight until the test releases them
+	deps.Idempotency = func(c *fiber.Ctx) error {
+		suite.enteredOnce.Do(func() { close(suite.entered) })
+		<-suite.release
+		return c.Next()
+	}
+	suite.app = fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.SetupRoutes(suite.app, deps)
+	suite.token, _ = signUp(suite.T(), suite.app, "alice")
+}
+
+// newClient opens a new connection per request, as a client arriving during shutdown would
+func newClient() *http.Client {
+	return &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 5 * time.Second}
+}
+
+func (suite *ShutdownIntegrationTestSuite) TestInFlightRequestsCompleteOnSIGTERM() {
+	ln, err := server.Listen(server.ListenOptions{Address: "127.0.0.1:0"})
+	suite.Require().NoError(err)
+	baseURL := "http://" + ln.Addr().String()
+
+	var ticks atomic.Int64
+	workers := server.NewWorkers()
+	workers.Every("tick", 5*time.Millisecond, func(context.Context) { ticks.Add(1) })
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, suite.app, ln, server.ShutdownOptions{
+			Readiness:  suite.readiness,
+			DrainDelay: 200 * time.Millisecond,
+			Timeout:    5 * time.Second,
+		}, workers.Stop, func(context.Context) error {
+			return database.Close(suite.db)
+		})
+	}()
+	suite.Eventually(suite.readiness.Ready, time.Second, 5*time.Millisecond)
+
+	// A todo creation is in flight when SIGTERM arrives
+	created := make(chan *http.Response, 1)
+	go func() {
+		req, _ := http.NewRequest("POST", baseURL+"/api/todos", strings.NewReader(`{"text": "Survives shutdown"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+suite.token)
+		resp, err := newClient().Do(req)
+		if err != nil {
+			suite.T().Errorf("in-flight request failed: %v", err)
+			close(created)
+			return
+		}
+		created <- resp
+	}()
+	<-suite.entered
+	suite.Require().NoError(syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	// Readiness fails at once while requests are still served
+	suite.Eventually(func() bool { return !suite.readiness.Ready() }, time.Second, 5*time.Millisecond)
+	resp, err := newClient().Get(baseURL + "/health")
+	suite.Require().NoError(err)
+	resp.Body.Close()
+	suite.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+
+	// The request in flight completes
+	close(suite.release)
+	resp, ok := <-created
+	suite.Require().True(ok)
+	resp.Body.Close()
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Require().NoError(<-served)
+
+	// New connections are refused, workers stopped and the database closed
+	_, err = newClient().Get(baseURL + "/health")
+	suite.Error(err)
+	stopped := ticks.Load()
+	time.Sleep(20 * time.Millisecond)
+	suite.Equal(stopped, ticks.Load())
+	sqlDB, err := suite.db.DB()
+	suite.Require().NoError(err)
+	suite.Error(sqlDB.Ping())
+
+	// The todo was persisted
+	reopened, err := gorm.Open(sqlite.Open(suite.dbFile), &gorm.Config{})
+	suite.Require().NoError(err)
+	var count int64
+	suite.Require().NoError(reopened.Table("todos").Where("text = ?", "Survives shutdown").Count(&count).Error)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *ShutdownIntegrationTestSuite) TestShutdownTimeoutBoundsDraining() {
+	ln, err := server.Listen(server.ListenOptions{Address: "127.0.0.1:0"})
+	suite.Require().NoError(err)
+	defer close(suite.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, suite.app, ln, server.ShutdownOptions{
+			Readiness: suite.readiness,
+			Timeout:   100 * time.Millisecond,
+		})
+	}()
+	suite.Eventually(suite.readiness.Ready, time.Second, 5*time.Millisecond)
+
+	go func() {
+		req, _ := http.NewRequest("POST", "http://"+ln.Addr().String()+"/api/todos", strings.NewReader(`{"text": "Stuck"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+suite.token)
+		if resp, err := newClient().Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-suite.entered
+	cancel()
+
+	select {
+	case err := <-served:
+		suite.ErrorIs(err, context.DeadlineExceeded)
+	case <-time.After(2 * time.Second):
+		suite.Fail("shutdown did not give up on the stuck request")
+	}
+}
+
+func TestShutdownIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ShutdownIntegrationTestSuite))
+}
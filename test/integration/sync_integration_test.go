@@ -0,0 +1,410 @@
+package integration
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// SyncIntegrationTestSuite tests the offline sync on POST /api/sync against a real database
+type SyncIntegrationTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	db      *gorm.DB
+	alice   string
+	aliceID string
+	bob     string
+	bobID   string
+}
+
+func (suite *SyncIntegrationTestSuite) SetupTest() {
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todos.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.db = db
+
+	listRepo := database.NewSQLiteListRepository(db)
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(listRepo),
+		usecases.WithSync(database.NewSQLiteChangeLogRepository(db), usecases.SyncLimits{Retention: 24 * time.Hour, MaxChanges: 3}),
+	)
+
+	deps := newTestDependencies(db, todoUseCase)
+	deps.SyncHandler = handlers.NewSyncHandler(todoUseCase, 5)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	suite.app = app
+	suite.alice, suite.aliceID = signUp(suite.T(), app, "alice")
+	suite.bob, suite.bobID = signUp(suite.T(), app, "bob")
+}
+
+func (suite *SyncIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// sync sends req as token's user and returns the response
+func (suite *SyncIntegrationTestSuite) sync(token string, req dto.SyncRequest) dto.SyncResponse {
+	resp := suite.do(jsonRequest("POST", "/api/sync", token, req))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var body dto.SyncResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+// create creates a todo through the sync, as a client would after working offline
+func (suite *SyncIntegrationTestSuite) create(token, listID, text string) *dto.TodoDetailResponse {
+	body := suite.sync(token, dto.SyncRequest{Mutations: []dto.SyncMutation{
+		{ClientID: "c", Op: dto.SyncOpCreate, TodoID: uuid.NewString(), ListID: listID, Text: &text},
+	}})
+	suite.Require().Equal(dto.SyncStatusApplied, body.Results[0].Status, body.Results[0].Error)
+	return body.Results[0].Todo
+}
+
+// mutate applies one mutation and returns its result
+func (suite *SyncIntegrationTestSuite) mutate(token string, mutation dto.SyncMutation) dto.SyncMutationResult {
+	body := suite.sync(token, dto.SyncRequest{Mutations: []dto.SyncMutation{mutation}})
+	suite.Require().Len(body.Results, 1)
+	return body.Results[0]
+}
+
+// createList creates a list owned by Alice and shares it with Bob as an editor
+func (suite *SyncIntegrationTestSuite) createList() string {
+	resp := suite.do(jsonRequest("POST", "/api/lists", suite.alice, map[string]string{"name": "Groceries"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var list dto.ListResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+
+	resp = suite.do(jsonRequest("POST", "/api/lists/"+list.ID+"/invites", suite.alice, map[string]string{"role": "editor"}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var invite dto.CreatedInviteResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&invite))
+	resp = suite.do(jsonRequest("POST", "/api/invites/accept", suite.bob, map[string]string{"token": invite.Token}))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	return list.ID
+}
+
+func todoIDs(todos []*dto.TodoDetailResponse) []string {
+	result := make([]string, len(todos))
+	for i, todo := range todos {
+		result[i] = todo.ID
+	}
+	return result
+}
+
+func text(s string) *string { return &s }
+
+func completed(b bool) *bool { return &b }
+
+func (suite *SyncIntegrationTestSuite) TestFirstSync_ReturnsEveryTodo() {
+	first := suite.create(suite.alice, "", "Buy milk")
+	second := suite.create(suite.alice, "", "Walk the dog")
+	suite.create(suite.bob, "", "Not Alice's")
+
+	body := suite.sync(suite.alice, dto.SyncRequest{})
+
+	suite.True(body.Full)
+	suite.False(body.HasMore)
+	suite.NotEmpty(body.SyncToken)
+	suite.ElementsMatch([]string{first.ID, second.ID}, todoIDs(body.Todos))
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_ReturnsChangesSinceToken() {
+	kept := suite.create(suite.alice, "", "Kept")
+	changed := suite.create(suite.alice, "", "Changed")
+	deleted := suite.create(suite.alice, "", "Deleted")
+	token := suite.sync(suite.alice, dto.SyncRequest{}).SyncToken
+
+	// Another device of Alice's changes todos meanwhile
+	suite.Equal(dto.SyncStatusApplied, suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: changed.ID, BaseVersion: 1, Completed: completed(true)}).Status)
+	suite.Equal(dto.SyncStatusApplied, suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpDelete, TodoID: deleted.ID, BaseVersion: 1}).Status)
+	created := suite.create(suite.alice, "", "Created")
+	suite.create(suite.bob, "", "Not Alice's")
+
+	body := suite.sync(suite.alice, dto.SyncRequest{SyncToken: token})
+
+	suite.False(body.Full)
+	suite.ElementsMatch([]string{changed.ID, created.ID}, todoIDs(body.Todos))
+	suite.NotContains(todoIDs(body.Todos), kept.ID)
+	suite.Equal([]string{deleted.ID}, body.DeletedTodoIDs)
+	for _, todo := range body.Todos {
+		if todo.ID == changed.ID {
+			suite.True(todo.Completed)
+			suite.Equal(int64(2), todo.Version)
+		}
+	}
+
+	// Nothing changed since
+	body = suite.sync(suite.alice, dto.SyncRequest{SyncToken: body.SyncToken})
+	suite.Empty(body.Todos)
+	suite.Empty(body.DeletedTodoIDs)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_SeesChangesMadeThroughTheRESTAPI() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	token := suite.sync(suite.alice, dto.SyncRequest{}).SyncToken
+
+	req := jsonRequest("PATCH", "/api/todos/"+todo.ID, suite.alice, map[string]string{"text": "Buy oat milk"})
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	suite.Require().Equal(http.StatusOK, suite.do(req).StatusCode)
+
+	body := suite.sync(suite.alice, dto.SyncRequest{SyncToken: token})
+	suite.Require().Len(body.Todos, 1)
+	suite.Equal("Buy oat milk", body.Todos[0].Text)
+}
+
+func (suite *SyncIntegrationTestSuite) TestCreate_KeepsTheClientIDAndIsIdempotent() {
+	id := uuid.NewString()
+	mutation := dto.SyncMutation{ClientID: "local-1", Op: dto.SyncOpCreate, TodoID: id, Text: text("Offline todo"), Completed: completed(true)}
+
+	result := suite.mutate(suite.alice, mutation)
+	suite.Equal(dto.SyncStatusApplied, result.Status)
+	suite.Equal("local-1", result.ClientID)
+	suite.Require().NotNil(result.Todo)
+	suite.Equal(id, result.Todo.ID)
+	suite.True(result.Todo.Completed)
+
+	// The client resends the mutation after losing the response
+	result = suite.mutate(suite.alice, mutation)
+	suite.Equal(dto.SyncStatusApplied, result.Status)
+	suite.Equal(int64(1), result.Todo.Version)
+
+	var count int64
+	suite.db.Model(&database.SQLiteTodoModel{}).Where("id = ?", id).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *SyncIntegrationTestSuite) TestCreate_RejectsInvalidMutations() {
+	body := suite.sync(suite.alice, dto.SyncRequest{Mutations: []dto.SyncMutation{
+		{ClientID: "no-uuid", Op: dto.SyncOpCreate, TodoID: "local", Text: text("Offline todo")},
+		{ClientID: "no-text", Op: dto.SyncOpCreate, TodoID: uuid.NewString()},
+		{ClientID: "unknown", Op: dto.SyncOpDelete + "d", TodoID: uuid.NewString()},
+		{ClientID: "valid", Op: dto.SyncOpCreate, TodoID: uuid.NewString(), Text: text("Offline todo")},
+	}})
+
+	suite.Require().Len(body.Results, 4)
+	for _, result := range body.Results[:3] {
+		suite.Equal(dto.SyncStatusRejected, result.Status, result.ClientID)
+		suite.NotEmpty(result.Error)
+	}
+	suite.Equal(dto.SyncStatusApplied, body.Results[3].Status)
+}
+
+func (suite *SyncIntegrationTestSuite) TestUpdate_MergesFieldsPerField() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	// Another device renames the todo
+	suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Text: text("Buy oat milk")})
+
+	// The offline device renamed and completed the version it had
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Text: text("Buy soy milk"), Completed: completed(true)})
+
+	suite.Equal(dto.SyncStatusConflict, result.Status)
+	suite.Require().Len(result.Conflicts, 1)
+	suite.Equal(dto.SyncFieldConflict{Field: "text", ClientValue: "Buy soy milk", ServerValue: "Buy oat milk"}, result.Conflicts[0])
+	suite.Require().NotNil(result.Todo)
+	suite.Equal("Buy oat milk", result.Todo.Text)
+	suite.True(result.Todo.Completed)
+	suite.Equal(int64(3), result.Todo.Version)
+}
+
+func (suite *SyncIntegrationTestSuite) TestUpdate_SameValueOnBothSidesIsNoConflict() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+
+	suite.Equal(dto.SyncStatusApplied, result.Status)
+	suite.Empty(result.Conflicts)
+	suite.Equal(int64(2), result.Todo.Version)
+}
+
+func (suite *SyncIntegrationTestSuite) TestUpdate_ListTodoChangedByAnotherMember() {
+	listID := suite.createList()
+	todo := suite.create(suite.alice, listID, "Buy milk")
+	suite.mutate(suite.bob, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Text: text("Buy oat milk"), Completed: completed(false)})
+
+	suite.Equal(dto.SyncStatusConflict, result.Status)
+	suite.Require().Len(result.Conflicts, 1)
+	suite.Equal("completed", result.Conflicts[0].Field)
+	suite.Equal("Buy oat milk", result.Todo.Text)
+	suite.True(result.Todo.Completed)
+}
+
+func (suite *SyncIntegrationTestSuite) TestUpdate_WithoutHistoryEveryFieldConflicts() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Text: text("Buy oat milk")})
+	// The log was purged
+	suite.db.Exec("DELETE FROM todo_changes")
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+
+	suite.Equal(dto.SyncStatusConflict, result.Status)
+	suite.False(result.Todo.Completed)
+}
+
+func (suite *SyncIntegrationTestSuite) TestUpdate_OfDeletedTodoConflicts() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpDelete, TodoID: todo.ID, BaseVersion: 1})
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+
+	suite.Equal(dto.SyncStatusConflict, result.Status)
+	suite.True(result.Deleted)
+	suite.Nil(result.Todo)
+}
+
+func (suite *SyncIntegrationTestSuite) TestDelete_OfChangedTodoConflicts() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Text: text("Buy oat milk")})
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpDelete, TodoID: todo.ID, BaseVersion: 1})
+
+	suite.Equal(dto.SyncStatusConflict, result.Status)
+	suite.False(result.Deleted)
+	suite.Require().Len(result.Conflicts, 1)
+	suite.Equal("text", result.Conflicts[0].Field)
+	suite.Equal("Buy oat milk", result.Todo.Text)
+
+	// Deleting the version the client now knows succeeds, and so does a replay
+	for i := 0; i < 2; i++ {
+		result = suite.mutate(suite.alice, dto.SyncMutation{ClientID: "3", Op: dto.SyncOpDelete, TodoID: todo.ID, BaseVersion: 2})
+		suite.Equal(dto.SyncStatusApplied, result.Status)
+		suite.True(result.Deleted)
+	}
+}
+
+func (suite *SyncIntegrationTestSuite) TestMutations_OfOthersTodosAreRejected() {
+	todo := suite.create(suite.bob, "", "Bob's")
+
+	result := suite.mutate(suite.alice, dto.SyncMutation{ClientID: "1", Op: dto.SyncOpUpdate, TodoID: todo.ID, BaseVersion: 1, Completed: completed(true)})
+	suite.Equal(dto.SyncStatusRejected, result.Status)
+
+	// Alice cannot take over Bob's todo by creating it under its ID
+	result = suite.mutate(suite.alice, dto.SyncMutation{ClientID: "2", Op: dto.SyncOpCreate, TodoID: todo.ID, Text: text("Alice's")})
+	suite.Equal(dto.SyncStatusRejected, result.Status)
+
+	// Nor does Alice learn that Bob deleted it
+	suite.mutate(suite.bob, dto.SyncMutation{ClientID: "3", Op: dto.SyncOpDelete, TodoID: todo.ID, BaseVersion: 1})
+	result = suite.mutate(suite.alice, dto.SyncMutation{ClientID: "4", Op: dto.SyncOpDelete, TodoID: todo.ID, BaseVersion: 1})
+	suite.Equal(dto.SyncStatusRejected, result.Status)
+	suite.False(result.Deleted)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_FollowsListMembership() {
+	bobToken := suite.sync(suite.bob, dto.SyncRequest{}).SyncToken
+	listID := suite.createList()
+	todo := suite.create(suite.alice, listID, "Buy milk")
+
+	body := suite.sync(suite.bob, dto.SyncRequest{SyncToken: bobToken})
+	suite.Equal([]string{todo.ID}, todoIDs(body.Todos))
+	suite.Empty(body.RemovedListIDs)
+
+	resp := suite.do(jsonRequest("DELETE", "/api/lists/"+listID+"/members/"+suite.bobID, suite.alice, nil))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	body = suite.sync(suite.bob, dto.SyncRequest{SyncToken: body.SyncToken})
+	suite.Empty(body.Todos)
+	suite.Equal([]string{listID}, body.RemovedListIDs)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_DeletedListIsRemoved() {
+	listID := suite.createList()
+	suite.create(suite.alice, listID, "Buy milk")
+	token := suite.sync(suite.bob, dto.SyncRequest{}).SyncToken
+
+	resp := suite.do(jsonRequest("DELETE", "/api/lists/"+listID, suite.alice, nil))
+	suite.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	body := suite.sync(suite.bob, dto.SyncRequest{SyncToken: token})
+	suite.Equal([]string{listID}, body.RemovedListIDs)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_PagesChanges() {
+	token := suite.sync(suite.alice, dto.SyncRequest{}).SyncToken
+	var created []string
+	for i := 0; i < 5; i++ {
+		created = append(created, suite.create(suite.alice, "", fmt.Sprintf("Todo %d", i)).ID)
+	}
+
+	body := suite.sync(suite.alice, dto.SyncRequest{SyncToken: token})
+	suite.True(body.HasMore)
+	received := todoIDs(body.Todos)
+
+	body = suite.sync(suite.alice, dto.SyncRequest{SyncToken: body.SyncToken})
+	suite.False(body.HasMore)
+	received = append(received, todoIDs(body.Todos)...)
+	suite.Equal(created, received)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_ExpiredTokenGetsEveryTodo() {
+	todo := suite.create(suite.alice, "", "Buy milk")
+	expired := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("0.%d", time.Now().Add(-48*time.Hour).Unix())))
+
+	body := suite.sync(suite.alice, dto.SyncRequest{SyncToken: expired})
+
+	suite.True(body.Full)
+	suite.Equal([]string{todo.ID}, todoIDs(body.Todos))
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_InvalidRequests() {
+	resp := suite.do(jsonRequest("POST", "/api/sync", suite.alice, dto.SyncRequest{SyncToken: "not a token"}))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	mutations := make([]dto.SyncMutation, 6)
+	resp = suite.do(jsonRequest("POST", "/api/sync", suite.alice, dto.SyncRequest{Mutations: mutations}))
+	suite.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp = suite.do(jsonRequest("POST", "/api/sync", "", dto.SyncRequest{}))
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *SyncIntegrationTestSuite) TestSync_MutationsRequireTheWriteScope() {
+	resp := suite.do(jsonRequest("POST", "/api/keys", suite.alice, map[string]interface{}{"name": "reader", "scopes": []string{"todos:read"}}))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var key dto.CreatedAPIKeyResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&key))
+
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("POST", "/api/sync", key.Key, dto.SyncRequest{})).StatusCode)
+
+	resp = suite.do(jsonRequest("POST", "/api/sync", key.Key, dto.SyncRequest{Mutations: []dto.SyncMutation{
+		{ClientID: "1", Op: dto.SyncOpCreate, TodoID: uuid.NewString(), Text: text("From a reader")},
+	}}))
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *SyncIntegrationTestSuite) TestChangeLog_IsPurgedAfterRetention() {
+	suite.create(suite.alice, "", "Buy milk")
+	repo := database.NewSQLiteChangeLogRepository(suite.db)
+
+	purged, err := repo.DeleteBefore(ownerContext(suite.aliceID), time.Now().Add(time.Minute))
+
+	suite.Require().NoError(err)
+	suite.Positive(purged)
+	var count int64
+	suite.db.Model(&database.SQLiteChangeModel{}).Where("kind = ?", entities.ChangeTodoCreated).Count(&count)
+	suite.Zero(count)
+}
+
+func TestSyncIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncIntegrationTestSuite))
+}
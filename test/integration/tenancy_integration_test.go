@@ -0,0 +1,284 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/interfaces/dto"
+	"todo-backend/internal/interfaces/handlers"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testQuotas limits tenant "small"; every other tenant is unlimited
+func testQuotas(tenantID string) entities.TenantQuota {
+	if tenantID == "small" {
+		return entities.TenantQuota{MaxTodos: 2, MaxStorageBytes: 20}
+	}
+	return entities.TenantQuota{}
+}
+
+func openTestDatabase(path string) (*gorm.DB, error) {
+	return gorm.Open(sqlite.Open(path), &gorm.Config{})
+}
+
+// newTenantApp serves the API with tenant resolution enabled on db
+func newTenantApp(db *gorm.DB, opts middleware.TenantOptions) *fiber.App {
+	usageRepo := database.NewSQLiteTenantUsageRepository(db)
+	todoUseCase := usecases.NewTodoUseCase(
+		database.NewSQLiteTodoRepository(db),
+		usecases.WithListRepository(database.NewSQLiteListRepository(db)),
+		usecases.WithQuotas(usageRepo, testQuotas),
+	)
+
+	deps := newTestDependencies(db, todoUseCase)
+	deps.BatchHandler = handlers.NewBatchHandler(todoUseCase, handlers.BatchLimits{MaxOperations: 10, MaxPayloadBytes: 1 << 16})
+	deps.TenantHandler = handlers.NewTenantHandler(usecases.NewTenantUseCase(usageRepo, testQuotas))
+	deps.Tenant = middleware.ResolveTenant(opts)
+
+	app := fiber.New()
+	routes.SetupRoutes(app, deps)
+	return app
+}
+
+// withTenant sets the tenant header on req
+func withTenant(req *http.Request, tenant string) *http.Request {
+	req.Header.Set("X-Tenant-ID", tenant)
+	return req
+}
+
+// TenancyIntegrationTestSuite tests tenant isolation, resolution and quotas over HTTP
+type TenancyIntegrationTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	db  *gorm.DB
+}
+
+func (suite *TenancyIntegrationTestSuite) SetupTest() {
+	db, err := openTestDatabase(filepath.Join(suite.T().TempDir(), "todo.db"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+	suite.Require().NoError(db.Use(database.NewTenantScoping(nil)))
+	suite.db = db
+
+	suite.app = newTenantApp(db, middleware.TenantOptions{
+		Enabled:    true,
+		Header:     "X-Tenant-ID",
+		BaseDomain: "todo.test",
+		Allowed:    []string{"acme", "globex", "small"},
+	})
+}
+
+func (suite *TenancyIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// signUpIn registers username in tenant and returns an access token
+func (suite *TenancyIntegrationTestSuite) signUpIn(tenant, username string) string {
+	credentials := map[string]string{"username": username, "password": "correct horse battery"}
+
+	resp := suite.do(withTenant(jsonRequest("POST", "/api/auth/register", "", credentials), tenant))
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+	resp = suite.do(withTenant(jsonRequest("POST", "/api/auth/login", "", credentials), tenant))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var login dto.LoginResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&login))
+	return login.AccessToken
+}
+
+func (suite *TenancyIntegrationTestSuite) createTodo(token, text string) *http.Response {
+	return suite.do(jsonRequest("POST", "/api/todos", token, map[string]string{"text": text}))
+}
+
+func (suite *TenancyIntegrationTestSuite) todos(req *http.Request) []dto.ContractTodoResponse {
+	resp := suite.do(req)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var todos []dto.ContractTodoResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todos))
+	return todos
+}
+
+func (suite *TenancyIntegrationTestSuite) TestTenantsAreIsolated() {
+	// The same username can exist in two tenants
+	acme := suite.signUpIn("acme", "alice")
+	globex := suite.signUpIn("globex", "alice")
+
+	// Tokens name their tenant, so no header is needed afterwards
+	suite.Require().Equal(http.StatusCreated, suite.createTodo(acme, "Acme secret").StatusCode)
+
+	suite.Len(suite.todos(jsonRequest("GET", "/api/todos", acme, nil)), 1)
+	suite.Empty(suite.todos(jsonRequest("GET", "/api/todos", globex, nil)))
+
+	var rows []database.SQLiteTodoModel
+	suite.Require().NoError(suite.db.WithContext(tenancy.WithAllTenants(context.Background())).Find(&rows).Error)
+	suite.Require().Len(rows, 1)
+	suite.Equal("acme", rows[0].TenantID)
+}
+
+func (suite *TenancyIntegrationTestSuite) TestTokenCannotBeReplayedInAnotherTenant() {
+	acme := suite.signUpIn("acme", "alice")
+	suite.signUpIn("globex", "bob")
+
+	resp := suite.do(withTenant(jsonRequest("GET", "/api/todos", acme, nil), "globex"))
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.do(withTenant(jsonRequest("GET", "/api/todos", acme, nil), "acme"))
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *TenancyIntegrationTestSuite) TestTenantResolution() {
+	credentials := map[string]string{"username": "carol", "password": "correct horse battery"}
+
+	// Without header, subdomain, default or token the tenant is unknown
+	resp := suite.do(jsonRequest("POST", "/api/auth/register", "", credentials))
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	// Tenants outside the configured list are rejected
+	resp = suite.do(withTenant(jsonRequest("POST", "/api/auth/register", "", credentials), "initech"))
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+
+	// The subdomain names the tenant
+	req := jsonRequest("POST", "/api/auth/register", "", credentials)
+	req.Host = "globex.todo.test"
+	suite.Equal(http.StatusCreated, suite.do(req).StatusCode)
+
+	resp = suite.do(withTenant(jsonRequest("POST", "/api/auth/login", "", credentials), "acme"))
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode, "carol only exists in globex")
+	resp = suite.do(withTenant(jsonRequest("POST", "/api/auth/login", "", credentials), "globex"))
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *TenancyIntegrationTestSuite) TestUnscopedQueriesFailLoudly() {
+	repo := database.NewSQLiteTodoRepository(suite.db)
+
+	_, err := repo.GetAll(ownerContext("user-1"))
+	suite.ErrorIs(err, tenancy.ErrTenantRequired)
+
+	err = suite.db.Exec("DELETE FROM todos").Error
+	suite.ErrorIs(err, tenancy.ErrTenantRequired)
+
+	var count int64
+	err = suite.db.Model(&database.SQLiteUserModel{}).Count(&count).Error
+	suite.ErrorIs(err, tenancy.ErrTenantRequired)
+
+	_, err = repo.Create(tenancy.WithAllTenants(ownerContext("user-1")), entities.NewTodo("Nowhere"))
+	suite.ErrorIs(err, tenancy.ErrTenantRequired)
+}
+
+func (suite *TenancyIntegrationTestSuite) TestQuotas() {
+	token := suite.signUpIn("small", "dave")
+
+	suite.Equal(http.StatusCreated, suite.createTodo(token, "one").StatusCode)
+	suite.Equal(http.StatusCreated, suite.createTodo(token, "two").StatusCode)
+	suite.Equal(http.StatusForbidden, suite.createTodo(token, "three").StatusCode)
+
+	resp := suite.do(jsonRequest("GET", "/api/tenant", token, nil))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	var status dto.TenantResponse
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&status))
+	suite.Equal("small", status.ID)
+	suite.Equal(int64(2), status.Usage.Todos)
+	suite.Equal(int64(6), status.Usage.StorageBytes)
+	suite.Equal(int64(2), status.Quota.MaxTodos)
+
+	// Comments count towards storage
+	todos := suite.todos(jsonRequest("GET", "/api/todos", token, nil))
+	resp = suite.do(jsonRequest("POST", "/api/todos/"+todos[0].ID+"/comments", token, map[string]string{"body": "far too long a comment"}))
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	// Other tenants are not limited
+	other := suite.signUpIn("acme", "erin")
+	for i := 0; i < 3; i++ {
+		suite.Equal(http.StatusCreated, suite.createTodo(other, "unlimited").StatusCode)
+	}
+}
+
+func (suite *TenancyIntegrationTestSuite) TestQuotaAppliesAcrossABatch() {
+	token := suite.signUpIn("small", "frank")
+
+	resp := suite.do(jsonRequest("POST", "/api/todos/batch?atomic=true", token, map[string]interface{}{
+		"operations": []map[string]string{
+			{"op": "create", "text": "a"},
+			{"op": "create", "text": "b"},
+			{"op": "create", "text": "c"},
+		},
+	}))
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	suite.Empty(suite.todos(jsonRequest("GET", "/api/todos", token, nil)))
+}
+
+func TestTenancyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TenancyIntegrationTestSuite))
+}
+
+func TestDatabasePerTenant(t *testing.T) {
+	dir := t.TempDir()
+	tenantDir := filepath.Join(dir, "tenants")
+
+	root, err := openTestDatabase(filepath.Join(dir, "root.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(root); err != nil {
+		t.Fatal(err)
+	}
+	databases := database.NewTenantDatabases(tenantDir, openTestDatabase)
+	if err := root.Use(database.NewTenantScoping(databases)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = databases.Close() })
+
+	s := &TenancyIntegrationTestSuite{db: root}
+	s.SetT(t)
+	s.app = newTenantApp(root, middleware.TenantOptions{Enabled: true, Header: "X-Tenant-ID"})
+
+	acme := s.signUpIn("acme", "alice")
+	globex := s.signUpIn("globex", "alice")
+	s.Require().Equal(http.StatusCreated, s.createTodo(acme, "Acme only").StatusCode)
+	s.Len(s.todos(jsonRequest("GET", "/api/todos", acme, nil)), 1)
+	s.Empty(s.todos(jsonRequest("GET", "/api/todos", globex, nil)))
+
+	// Each tenant has its own file and nothing lands in the root database
+	for _, tenant := range []string{"acme", "globex"} {
+		_, err := os.Stat(filepath.Join(tenantDir, tenant+".db"))
+		s.NoError(err, tenant)
+	}
+	var users int64
+	s.Require().NoError(root.WithContext(tenancy.WithAllTenants(context.Background())).Model(&database.SQLiteUserModel{}).Count(&users).Error)
+	s.Zero(users)
+
+	acmeDB, err := databases.Get("acme")
+	s.Require().NoError(err)
+	var todos int64
+	s.Require().NoError(acmeDB.WithContext(tenancy.WithTenant(context.Background(), "acme")).Model(&database.SQLiteTodoModel{}).Count(&todos).Error)
+	s.Equal(int64(1), todos)
+
+	// Maintenance jobs visit every tenant database
+	var visited []string
+	err = database.ForEachTenant(context.Background(), root, func(ctx context.Context) error {
+		tenant, _ := tenancy.FromContext(ctx)
+		visited = append(visited, tenant)
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"acme", "globex"}, visited)
+
+	// Tenant IDs never escape the directory
+	_, err = databases.Get("../escape")
+	s.ErrorIs(err, tenancy.ErrUnknownTenant)
+}
@@ -0,0 +1,335 @@
+package integration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/security"
+	"todo-backend/internal/infrastructure/server"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testCA issues certificates generated at test time
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key for subject, serving
+// 127.0.0.1 or authenticating a client
+func (ca *testCA) issue(t *testing.T, subject pkix.Name, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+// clientCertificate issues a client certificate for subject
+func (ca *testCA) clientCertificate(t *testing.T, subject pkix.Name) tls.Certificate {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, subject, 100, x509.ExtKeyUsageClientAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+var billingSubject = pkix.Name{CommonName: "billing", Organization: []string{"Acme"}}
+
+// TLSIntegrationTestSuite tests HTTPS, mutual TLS and Unix socket serving
+type TLSIntegrationTestSuite struct {
+	suite.Suite
+	app      *fiber.App
+	dir      string
+	ca       *testCA
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func (suite *TLSIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+
+	serviceAuthUseCase, err := usecases.NewServiceAuthUseCase(usecases.ServiceClient{
+		Subject: billingSubject.String(),
+		Name:    "billing",
+		Scopes:  []identity.Scope{identity.ScopeTodosRead, identity.ScopeTodosWrite},
+	})
+	suite.Require().NoError(err)
+
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(database.NewSQLiteTodoRepository(db)))
+	deps.ClientCertificate = middleware.ClientCertificate(serviceAuthUseCase)
+	suite.app = fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.SetupRoutes(suite.app, deps)
+
+	suite.dir = suite.T().TempDir()
+	suite.ca = newTestCA(suite.T(), "Test CA")
+	suite.certFile = filepath.Join(suite.dir, "tls.crt")
+	suite.keyFile = filepath.Join(suite.dir, "tls.key")
+	suite.caFile = filepath.Join(suite.dir, "ca.crt")
+	suite.writeServerCertificate(1)
+	suite.Require().NoError(os.WriteFile(suite.caFile, suite.ca.pem, 0o600))
+}
+
+// writeServerCertificate replaces the serving certificate files the way
+// certificate renewal tools do: by renaming complete files into place
+func (suite *TLSIntegrationTestSuite) writeServerCertificate(serial int64) {
+	certPEM, keyPEM := suite.ca.issue(suite.T(), pkix.Name{CommonName: "127.0.0.1"}, serial, x509.ExtKeyUsageServerAuth)
+	for _, file := range []struct {
+		path string
+		data []byte
+	}{{suite.keyFile, keyPEM}, {suite.certFile, certPEM}} {
+		tmp := file.path + ".tmp"
+		suite.Require().NoError(os.WriteFile(tmp, file.data, 0o600))
+		suite.Require().NoError(os.Rename(tmp, file.path))
+	}
+}
+
+// serve serves the app over TLS configured by cfg and returns its address
+func (suite *TLSIntegrationTestSuite) serve(cfg config.TLSConfig) (string, *security.CertificateReloader) {
+	certs, err := security.NewCertificateReloader(suite.certFile, suite.keyFile)
+	suite.Require().NoError(err)
+	tlsConfig, err := security.NewTLSConfig(cfg, certs)
+	suite.Require().NoError(err)
+
+	ln, err := server.Listen(server.ListenOptions{Address: "127.0.0.1:0", TLS: tlsConfig})
+	suite.Require().NoError(err)
+	go func() { _ = suite.app.Listener(ln) }()
+	suite.T().Cleanup(func() { _ = suite.app.Shutdown() })
+	return ln.Addr().String(), certs
+}
+
+// client trusts the test CA and presents certs
+func (suite *TLSIntegrationTestSuite) client(certs ...tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(suite.ca.cert)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs},
+	}}
+}
+
+func (suite *TLSIntegrationTestSuite) get(client *http.Client, url, token string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	suite.Require().NoError(err)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		suite.T().Cleanup(func() { resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func (suite *TLSIntegrationTestSuite) TestServesHTTPS() {
+	addr, _ := suite.serve(config.TLSConfig{MinVersion: "1.3"})
+
+	resp, err := suite.get(suite.client(), "https://"+addr+"/health", "")
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(uint16(tls.VersionTLS13), resp.TLS.Version)
+
+	// Clients limited to an older version are refused
+	old := suite.client()
+	old.Transport.(*http.Transport).TLSClientConfig.MaxVersion = tls.VersionTLS12
+	_, err = suite.get(old, "https://"+addr+"/health", "")
+	suite.Error(err)
+}
+
+func (suite *TLSIntegrationTestSuite) TestRejectsUnknownCipherSuites() {
+	certs, err := security.NewCertificateReloader(suite.certFile, suite.keyFile)
+	suite.Require().NoError(err)
+
+	_, err = security.NewTLSConfig(config.TLSConfig{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, certs)
+	suite.Error(err)
+	_, err = security.NewTLSConfig(config.TLSConfig{MinVersion: "1.0"}, certs)
+	suite.Error(err)
+
+	tlsConfig, err := security.NewTLSConfig(config.TLSConfig{CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}}, certs)
+	suite.Require().NoError(err)
+	suite.Equal([]uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+}
+
+func (suite *TLSIntegrationTestSuite) TestClientCertificateAuthenticatesService() {
+	addr, _ := suite.serve(config.TLSConfig{ClientCAFile: suite.caFile})
+	service := suite.client(suite.ca.clientCertificate(suite.T(), billingSubject))
+
+	req, err := http.NewRequest("POST", "https://"+addr+"/api/todos", strings.NewReader(`{"text": "Invoice"}`))
+	suite.Require().NoError(err)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := service.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var todo map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&todo))
+	suite.Equal("Invoice", todo["text"])
+
+	// Services have no session, so they cannot manage API keys
+	resp, err = suite.get(service, "https://"+addr+"/api/keys", "")
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *TLSIntegrationTestSuite) TestUnknownClientCertificateFallsBackToTokens() {
+	addr, _ := suite.serve(config.TLSConfig{ClientCAFile: suite.caFile})
+	client := suite.client(suite.ca.clientCertificate(suite.T(), pkix.Name{CommonName: "laptop"}))
+
+	resp, err := suite.get(client, "https://"+addr+"/api/todos", "")
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	token, _ := signUp(suite.T(), suite.app, "alice")
+	resp, err = suite.get(client, "https://"+addr+"/api/todos", token)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *TLSIntegrationTestSuite) TestRequiredClientCertificate() {
+	addr, _ := suite.serve(config.TLSConfig{ClientCAFile: suite.caFile, ClientAuth: "require"})
+
+	_, err := suite.get(suite.client(), "https://"+addr+"/health", "")
+	suite.Error(err, "connections without a certificate are refused")
+
+	impostor := newTestCA(suite.T(), "Other CA").clientCertificate(suite.T(), billingSubject)
+	_, err = suite.get(suite.client(impostor), "https://"+addr+"/health", "")
+	suite.Error(err, "certificates of other CAs are refused")
+
+	resp, err := suite.get(suite.client(suite.ca.clientCertificate(suite.T(), billingSubject)), "https://"+addr+"/health", "")
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (suite *TLSIntegrationTestSuite) TestReloadsRenewedCertificate() {
+	addr, certs := suite.serve(config.TLSConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = certs.Watch(ctx, func(err error) { suite.T().Log(err) })
+	}()
+
+	serial := func() int64 {
+		// A new client per request, so that every request makes a new handshake
+		client := suite.client()
+		client.Transport.(*http.Transport).DisableKeepAlives = true
+		resp, err := suite.get(client, "https://"+addr+"/health", "")
+		suite.Require().NoError(err)
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+	suite.Require().Equal(int64(1), serial())
+
+	// Let the watcher start before renewing
+	time.Sleep(50 * time.Millisecond)
+	suite.writeServerCertificate(2)
+	suite.Eventually(func() bool { return serial() == 2 }, 5*time.Second, 20*time.Millisecond)
+}
+
+func (suite *TLSIntegrationTestSuite) TestServesUnixSocket() {
+	socket := filepath.Join(suite.dir, "todo.sock")
+	// A socket left over by a previous run is replaced
+	stale, err := net.Listen("unix", socket)
+	suite.Require().NoError(err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	suite.Require().NoError(stale.Close())
+
+	ln, err := server.Listen(server.ListenOptions{Socket: socket})
+	suite.Require().NoError(err)
+	go func() { _ = suite.app.Listener(ln) }()
+	suite.T().Cleanup(func() { _ = suite.app.Shutdown() })
+
+	info, err := os.Stat(socket)
+	suite.Require().NoError(err)
+	suite.Equal(server.SocketMode, info.Mode().Perm())
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := suite.get(client, "http://todo/health", "")
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	// Regular files are never removed
+	_, err = server.Listen(server.ListenOptions{Socket: suite.caFile})
+	suite.Error(err)
+}
+
+func TestTLSIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TLSIntegrationTestSuite))
+}
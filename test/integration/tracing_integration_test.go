@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/infrastructure/config"
+	"todo-backend/internal/infrastructure/database"
+	"todo-backend/internal/infrastructure/tracing"
+	"todo-backend/internal/interfaces/middleware"
+	"todo-backend/internal/interfaces/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const incomingTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+var (
+	installTracing sync.Once
+	spanRecorder   *tracetest.SpanRecorder
+	recordingSpans *sdktrace.TracerProvider
+)
+
+// recordSpans installs a global tracer provider recording every span. Tracers
+// obtained before a provider is installed, such as the use cases', are bound
+// to the first one, so it is installed once for the whole package.
+func recordSpans() {
+	installTracing.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		recordingSpans = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+		otel.SetTracerProvider(recordingSpans)
+	})
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// TracingIntegrationTestSuite tests the spans of requests, use cases and
+// repository calls, and trace context propagation
+type TracingIntegrationTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	token string
+}
+
+func (suite *TracingIntegrationTestSuite) SetupSuite() {
+	recordSpans()
+}
+
+func (suite *TracingIntegrationTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(database.Migrate(db))
+
+	todoRepo := tracing.NewTodoRepository(database.NewSQLiteTodoRepository(db))
+	deps := newTestDependencies(db, usecases.NewTodoUseCase(todoRepo))
+	deps.Tracing = middleware.Tracing(recordingSpans, propagation.TraceContext{})
+	suite.app = fiber.New()
+	routes.SetupRoutes(suite.app, deps)
+
+	suite.token, _ = signUp(suite.T(), suite.app, "alice")
+}
+
+func (suite *TracingIntegrationTestSuite) do(req *http.Request) *http.Response {
+	resp, err := suite.app.Test(req, -1)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// spans returns the ended spans of traceID, by name
+func (suite *TracingIntegrationTestSuite) spans(traceID string) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spanRecorder.Ended() {
+		if span.SpanContext().TraceID().String() == traceID {
+			spans[span.Name()] = span
+		}
+	}
+	return spans
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func (suite *TracingIntegrationTestSuite) TestIncomingTraceIsContinuedThroughUseCaseAndRepository() {
+	req := jsonRequest("POST", "/api/todos", suite.token, map[string]string{"text": "Traced"})
+	req.Header.Set("traceparent", incomingTraceparent)
+
+	suite.Equal(http.StatusCreated, suite.do(req).StatusCode)
+
+	spans := suite.spans("4bf92f3577b34da6a3ce929d0e0e4736")
+	server, ok := spans["POST /api/todos"]
+	suite.Require().True(ok, "the request span is named after its route")
+	suite.Equal(trace.SpanKindServer, server.SpanKind())
+	suite.Equal("00f067aa0ba902b7", server.Parent().SpanID().String())
+	suite.True(server.Parent().IsRemote())
+	suite.Equal(int64(http.StatusCreated), attributeValue(server, "http.response.status_code").AsInt64())
+	suite.Equal("/api/todos", attributeValue(server, "http.route").AsString())
+
+	useCase, ok := spans["TodoUseCase.CreateTodo"]
+	suite.Require().True(ok)
+	suite.Equal(server.SpanContext().SpanID(), useCase.Parent().SpanID())
+
+	repository, ok := spans["TodoRepository.Create"]
+	suite.Require().True(ok)
+	suite.Equal(useCase.SpanContext().SpanID(), repository.Parent().SpanID())
+	suite.Equal(trace.SpanKindClient, repository.SpanKind())
+	suite.Equal("sqlite", attributeValue(repository, "db.system").AsString())
+}
+
+func (suite *TracingIntegrationTestSuite) TestFailedCallsAreMarkedAsErrors() {
+	req := jsonRequest("PATCH", "/api/todos/missing", suite.token, map[string]bool{"completed": true})
+	req.Header.Set(fiber.HeaderContentType, "application/merge-patch+json")
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	suite.Equal(http.StatusNotFound, suite.do(req).StatusCode)
+
+	spans := suite.spans("0af7651916cd43dd8448eb211c80319c")
+	server, ok := spans["PATCH /api/todos/:id"]
+	suite.Require().True(ok, "the span name is the route template, not the path")
+	suite.Equal(int64(http.StatusNotFound), attributeValue(server, "http.response.status_code").AsInt64())
+	suite.Equal("/api/todos/missing", attributeValue(server, "url.path").AsString())
+	// Client errors are not server span errors
+	suite.NotEqual(codes.Error, server.Status().Code)
+
+	useCase, ok := spans["TodoUseCase.PatchTodo"]
+	suite.Require().True(ok)
+	suite.Equal(codes.Error, useCase.Status().Code)
+	suite.NotEmpty(useCase.Events(), "the error is recorded on the span")
+}
+
+func (suite *TracingIntegrationTestSuite) TestRequestsWithoutTraceparentStartTraces() {
+	suite.Equal(http.StatusOK, suite.do(jsonRequest("GET", "/api/todos", suite.token, nil)).StatusCode)
+
+	var server sdktrace.ReadOnlySpan
+	for _, span := range spanRecorder.Ended() {
+		if span.Name() == "GET /api/todos" {
+			server = span
+		}
+	}
+	suite.Require().NotNil(server)
+	suite.False(server.Parent().IsValid())
+	suite.Contains(suite.spans(server.SpanContext().TraceID().String()), "TodoUseCase.GetAllTodos")
+}
+
+func (suite *TracingIntegrationTestSuite) TestTraceContextIsInjectedIntoOutgoingRequests() {
+	ctx, span := recordingSpans.Tracer("test").Start(context.Background(), "outgoing")
+	defer span.End()
+
+	header := http.Header{}
+	tracing.InjectHeaders(ctx, header)
+
+	suite.Equal("00-"+span.SpanContext().TraceID().String()+"-"+span.SpanContext().SpanID().String()+"-01", header.Get("traceparent"))
+}
+
+func (suite *TracingIntegrationTestSuite) TestFileExporterWritesSpansOnShutdown() {
+	// NewProvider installs itself globally; the recording provider is restored
+	defer otel.SetTracerProvider(recordingSpans)
+	defer otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	file := filepath.Join(suite.T().TempDir(), "traces.json")
+	provider, err := tracing.NewProvider(context.Background(), config.TracingConfig{
+		ServiceName: "todo-test",
+		Exporter:    "file",
+		File:        file,
+		SampleRatio: 1,
+	})
+	suite.Require().NoError(err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "exported span")
+	span.End()
+	suite.Require().NoError(provider.Shutdown(context.Background()))
+
+	raw, err := os.ReadFile(file)
+	suite.Require().NoError(err)
+	suite.Contains(string(raw), "exported span")
+	suite.Contains(string(raw), "todo-test")
+}
+
+func (suite *TracingIntegrationTestSuite) TestProviderConfigurationIsValidated() {
+	_, err := tracing.NewProvider(context.Background(), config.TracingConfig{Exporter: "stdout", SampleRatio: 1.5})
+	suite.Error(err)
+	_, err = tracing.NewProvider(context.Background(), config.TracingConfig{Exporter: "zipkin", SampleRatio: 1})
+	suite.Error(err)
+}
+
+func TestTracingIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingIntegrationTestSuite))
+}
@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/audit"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository for application layer testing
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, event *entities.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) Query(ctx context.Context, filter repositories.AuditFilter) ([]*entities.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) Scan(ctx context.Context, afterSeq int64, limit int) ([]*entities.AuditEvent, error) {
+	args := m.Called(ctx, afterSeq, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) Tenants(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// auditChain seals n events into a valid chain
+func auditChain(n int) []*entities.AuditEvent {
+	var chain []*entities.AuditEvent
+	var prev *entities.AuditEvent
+	for i := 0; i < n; i++ {
+		event := entities.NewAuditEvent(audit.ActionTodoCreated, "todo-1")
+		event.TenantID = "acme"
+		event.Seal(prev)
+		chain = append(chain, event)
+		prev = event
+	}
+	return chain
+}
+
+func TestAuditUseCase_Record_WritesOneEventPerChange(t *testing.T) {
+	// Given
+	repo := &MockAuditRepository{}
+	useCase := usecases.NewAuditUseCase(repo)
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+	var appended []*entities.AuditEvent
+	repo.On("Append", ctx, mock.AnythingOfType("*entities.AuditEvent")).
+		Run(func(args mock.Arguments) { appended = append(appended, args.Get(1).(*entities.AuditEvent)) }).
+		Return(nil)
+
+	// When
+	err := useCase.Record(ctx, usecases.AuditRecord{
+		Action:    "POST /api/todos/batch",
+		Status:    200,
+		RequestID: "req-1",
+		Changes: []audit.Change{
+			{Action: audit.ActionTodoCreated, TargetID: "todo-1", AfterHash: "after"},
+			{Action: audit.ActionTodoDeleted, TargetID: "todo-2", BeforeHash: "before"},
+		},
+	})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Len(t, appended, 2)
+	assert.Equal(t, audit.ActionTodoCreated, appended[0].Action)
+	assert.Equal(t, "before", appended[1].BeforeHash)
+	for _, event := range appended {
+		assert.Equal(t, "acme", event.TenantID)
+		assert.Equal(t, testPrincipal.UserID, event.ActorID)
+		assert.Equal(t, "req-1", event.RequestID)
+	}
+}
+
+func TestAuditUseCase_Record_RequiresTenant(t *testing.T) {
+	// Given
+	repo := &MockAuditRepository{}
+	useCase := usecases.NewAuditUseCase(repo)
+
+	// When
+	err := useCase.Record(authenticatedContext(), usecases.AuditRecord{Action: "POST /api/todos"})
+
+	// Then
+	assert.ErrorIs(t, err, tenancy.ErrTenantRequired)
+	repo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
+
+func TestAuditUseCase_Verify_IntactChain(t *testing.T) {
+	// Given
+	repo := &MockAuditRepository{}
+	useCase := usecases.NewAuditUseCase(repo)
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	chain := auditChain(3)
+	repo.On("Scan", ctx, int64(0), mock.Anything).Return(chain, nil)
+
+	// When
+	result, err := useCase.Verify(ctx)
+
+	// Then
+	assert.NoError(t, err)
+	assert.True(t, result.OK())
+	assert.Equal(t, int64(3), result.Events)
+	assert.Equal(t, chain[2].Hash, result.LastHash)
+}
+
+func TestAuditUseCase_Verify_DetectsProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(chain []*entities.AuditEvent) []*entities.AuditEvent
+		seq    int64
+		kind   string
+	}{
+		{"altered event", func(chain []*entities.AuditEvent) []*entities.AuditEvent {
+			chain[1].ActorID = "someone-else"
+			return chain
+		}, 2, usecases.AuditProblemTampered},
+		{"missing event", func(chain []*entities.AuditEvent) []*entities.AuditEvent {
+			return append(chain[:1], chain[2:]...)
+		}, 3, usecases.AuditProblemGap},
+		{"relinked event", func(chain []*entities.AuditEvent) []*entities.AuditEvent {
+			chain[2].PrevHash = entities.AuditGenesisHash
+			chain[2].Hash = chain[2].ComputeHash()
+			return chain
+		}, 3, usecases.AuditProblemBrokenLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			repo := &MockAuditRepository{}
+			useCase := usecases.NewAuditUseCase(repo)
+			ctx := tenancy.WithTenant(context.Background(), "acme")
+			repo.On("Scan", ctx, int64(0), mock.Anything).Return(tt.damage(auditChain(3)), nil)
+
+			// When
+			result, err := useCase.Verify(ctx)
+
+			// Then
+			assert.NoError(t, err)
+			assert.False(t, result.OK())
+			assert.Equal(t, []usecases.AuditChainProblem{{Seq: tt.seq, Kind: tt.kind, Detail: result.Problems[0].Detail}}, result.Problems)
+		})
+	}
+}
+
+func TestAuditUseCase_VerifyChain_RequiresAdmin(t *testing.T) {
+	// Given
+	repo := &MockAuditRepository{}
+	useCase := usecases.NewAuditUseCase(repo)
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+
+	// When
+	result, err := useCase.VerifyChain(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, identity.ErrForbidden)
+	assert.Nil(t, result)
+	repo.AssertNotCalled(t, "Scan", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCheck returns a check that counts its runs and fails with err
+func countingCheck(name string, runs *atomic.Int64, err error) usecases.HealthCheck {
+	return usecases.HealthCheck{
+		Name: name,
+		Check: func(context.Context) error {
+			runs.Add(1)
+			return err
+		},
+	}
+}
+
+func TestHealthUseCase_Check(t *testing.T) {
+	t.Run("should report every check of the probe", func(t *testing.T) {
+		// Given
+		var runs atomic.Int64
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness,
+			countingCheck("database", &runs, nil),
+			countingCheck("disk", &runs, errors.New("disk full")),
+		)
+
+		// When
+		report := useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// Then
+		assert.False(t, report.OK())
+		require.Len(t, report.Results, 2)
+		assert.Equal(t, "database", report.Results[0].Name)
+		assert.NoError(t, report.Results[0].Err)
+		assert.Equal(t, "disk", report.Results[1].Name)
+		assert.EqualError(t, report.Results[1].Err, "disk full")
+	})
+
+	t.Run("should skip excluded checks", func(t *testing.T) {
+		// Given
+		var runs atomic.Int64
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness,
+			countingCheck("database", &runs, nil),
+			countingCheck("disk", &runs, errors.New("disk full")),
+		)
+
+		// When
+		report := useCase.Check(context.Background(), usecases.ProbeReadiness, "disk")
+
+		// Then
+		assert.True(t, report.OK())
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, "database", report.Results[0].Name)
+	})
+
+	t.Run("should pass probes without checks", func(t *testing.T) {
+		// When
+		report := usecases.NewHealthUseCase().Check(context.Background(), usecases.ProbeLiveness)
+
+		// Then
+		assert.True(t, report.OK())
+		assert.Empty(t, report.Results)
+	})
+
+	t.Run("should reuse results within the cache TTL", func(t *testing.T) {
+		// Given
+		var runs atomic.Int64
+		check := countingCheck("database", &runs, nil)
+		check.CacheTTL = time.Hour
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness, check)
+		first := useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// When
+		second := useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// Then
+		assert.Equal(t, int64(1), runs.Load())
+		assert.False(t, first.Results[0].Cached)
+		assert.True(t, second.Results[0].Cached)
+		assert.Equal(t, first.Results[0].CheckedAt, second.Results[0].CheckedAt)
+	})
+
+	t.Run("should run uncached checks on every probe", func(t *testing.T) {
+		// Given
+		var runs atomic.Int64
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness, countingCheck("database", &runs, nil))
+
+		// When
+		useCase.Check(context.Background(), usecases.ProbeReadiness)
+		useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// Then
+		assert.Equal(t, int64(2), runs.Load())
+	})
+
+	t.Run("should share a check registered with several probes", func(t *testing.T) {
+		// Given
+		var runs atomic.Int64
+		check := countingCheck("migrations", &runs, nil)
+		check.CacheTTL = time.Hour
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness, check)
+		useCase.Register(usecases.ProbeStartup, check)
+
+		// When
+		useCase.Check(context.Background(), usecases.ProbeStartup)
+		report := useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// Then
+		assert.Equal(t, int64(1), runs.Load())
+		assert.True(t, report.Results[0].Cached)
+	})
+
+	t.Run("should fail checks that outlive their timeout", func(t *testing.T) {
+		// Given
+		release := make(chan struct{})
+		defer close(release)
+		useCase := usecases.NewHealthUseCase()
+		useCase.Register(usecases.ProbeReadiness, usecases.HealthCheck{
+			Name: "database",
+			// The check ignores its context
+			Check:   func(context.Context) error { <-release; return nil },
+			Timeout: 20 * time.Millisecond,
+		})
+
+		// When
+		start := time.Now()
+		report := useCase.Check(context.Background(), usecases.ProbeReadiness)
+
+		// Then
+		assert.Less(t, time.Since(start), time.Second)
+		assert.False(t, report.OK())
+		assert.ErrorContains(t, report.Results[0].Err, "timed out after 20ms")
+	})
+}
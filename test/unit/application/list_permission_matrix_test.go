@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentRepository for application layer testing
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *entities.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) ListByTodo(ctx context.Context, todoID string) ([]*entities.Comment, error) {
+	args := m.Called(ctx, todoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Comment), args.Error(1)
+}
+
+// outcome is how a use case answered a caller
+type outcome string
+
+const (
+	allowed   outcome = "allowed"
+	forbidden outcome = "forbidden"
+	hidden    outcome = "not found"
+)
+
+func outcomeOf(err error) outcome {
+	switch {
+	case err == nil:
+		return allowed
+	case errors.Is(err, identity.ErrForbidden):
+		return forbidden
+	case errors.Is(err, repositories.ErrListNotFound), errors.Is(err, repositories.ErrTodoNotFound):
+		return hidden
+	default:
+		return outcome("unexpected error: " + err.Error())
+	}
+}
+
+// sharedListFixture wires every list aware use case to mocks in which the
+// caller holds role on testListID. An empty role makes the caller a non-member.
+type sharedListFixture struct {
+	todos    *usecases.TodoUseCase
+	lists    *usecases.ListUseCase
+	comments *usecases.CommentUseCase
+	todo     *entities.Todo
+}
+
+func newSharedListFixture(role entities.ListRole) *sharedListFixture {
+	todoRepo := &MockTodoRepository{}
+	listRepo := &MockListRepository{}
+	inviteRepo := &MockListInviteRepository{}
+	commentRepo := &MockCommentRepository{}
+
+	todo := entities.NewTodo("Shared")
+	todo.ListID = testListID
+	todo.OwnerID = "user-2"
+
+	if role == "" {
+		listRepo.On("GetMember", mock.Anything, testListID, testPrincipal.UserID).Return(nil, repositories.ErrListMemberNotFound)
+	} else {
+		listRepo.On("GetMember", mock.Anything, testListID, testPrincipal.UserID).
+			Return(entities.NewListMember(testListID, testPrincipal.UserID, role), nil)
+	}
+	listRepo.On("GetByID", mock.Anything, testListID).Return(&entities.TodoList{ID: testListID, Name: "Shared", OwnerID: "user-2"}, nil).Maybe()
+	listRepo.On("Delete", mock.Anything, testListID).Return(nil).Maybe()
+	listRepo.On("ListMembers", mock.Anything, testListID).Return([]*entities.ListMember{}, nil).Maybe()
+	listRepo.On("GetMember", mock.Anything, testListID, "user-3").
+		Return(entities.NewListMember(testListID, "user-3", entities.ListRoleViewer), nil).Maybe()
+	listRepo.On("UpdateMemberRole", mock.Anything, testListID, "user-3", mock.Anything).Return(nil).Maybe()
+	listRepo.On("RemoveMember", mock.Anything, testListID, "user-3").Return(nil).Maybe()
+
+	inviteRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	inviteRepo.On("ListByList", mock.Anything, testListID).Return([]*entities.ListInvite{}, nil).Maybe()
+	inviteRepo.On("Revoke", mock.Anything, testListID, "invite-1").Return(nil).Maybe()
+
+	todoRepo.On("GetByID", mock.Anything, todo.ID).Return(todo, nil).Maybe()
+	todoRepo.On("GetByList", mock.Anything, testListID).Return([]*entities.Todo{todo}, nil).Maybe()
+	todoRepo.On("Create", mock.Anything, mock.Anything).Return(todo, nil).Maybe()
+	todoRepo.On("Update", mock.Anything, todo).Return(todo, nil).Maybe()
+	todoRepo.On("Delete", mock.Anything, todo.ID).Return(nil).Maybe()
+	todoRepo.On("CommitBatch", mock.Anything).Return(nil).Maybe()
+
+	commentRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	commentRepo.On("ListByTodo", mock.Anything, todo.ID).Return([]*entities.Comment{}, nil).Maybe()
+
+	todos := usecases.NewTodoUseCase(todoRepo, usecases.WithListRepository(listRepo))
+	return &sharedListFixture{
+		todos:    todos,
+		lists:    usecases.NewListUseCase(listRepo, inviteRepo, time.Hour),
+		comments: usecases.NewCommentUseCase(todos, commentRepo),
+		todo:     todo,
+	}
+}
+
+// batchError runs a single batch operation and returns its result
+func batchError(ctx context.Context, f *sharedListFixture, op dto.BatchOperation) error {
+	results, err := f.todos.ExecuteBatch(ctx, dto.BatchRequest{Operations: []dto.BatchOperation{op}})
+	if err != nil {
+		return err
+	}
+	return results[0].Err
+}
+
+func TestListPermissionMatrix(t *testing.T) {
+	actions := []struct {
+		name string
+		run  func(ctx context.Context, f *sharedListFixture) error
+	}{
+		{"view todos", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.todos.GetTodosByList(ctx, testListID)
+			return err
+		}},
+		{"create todo", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.todos.CreateTodo(ctx, dto.CreateTodoRequest{Text: "New", ListID: testListID})
+			return err
+		}},
+		{"batch create todo", func(ctx context.Context, f *sharedListFixture) error {
+			return batchError(ctx, f, dto.BatchOperation{Op: dto.BatchOpCreate, Text: "New", ListID: testListID})
+		}},
+		{"update todo", func(ctx context.Context, f *sharedListFixture) error {
+			return batchError(ctx, f, dto.BatchOperation{Op: dto.BatchOpUpdate, ID: f.todo.ID, Text: "Changed"})
+		}},
+		{"complete todo", func(ctx context.Context, f *sharedListFixture) error {
+			return batchError(ctx, f, dto.BatchOperation{Op: dto.BatchOpComplete, ID: f.todo.ID})
+		}},
+		{"delete todo", func(ctx context.Context, f *sharedListFixture) error {
+			return batchError(ctx, f, dto.BatchOperation{Op: dto.BatchOpDelete, ID: f.todo.ID})
+		}},
+		{"patch todo", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.todos.PatchTodo(ctx, f.todo.ID, dto.PatchTodoRequest{
+				Format: dto.PatchFormatMerge,
+				Patch:  []byte(`{"completed":true}`),
+			})
+			return err
+		}},
+		{"view comments", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.comments.GetComments(ctx, f.todo.ID)
+			return err
+		}},
+		{"add comment", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.comments.AddComment(ctx, f.todo.ID, dto.CreateCommentRequest{Body: "Looks good"})
+			return err
+		}},
+		{"view list", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.lists.GetList(ctx, testListID)
+			return err
+		}},
+		{"view members", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.lists.GetMembers(ctx, testListID)
+			return err
+		}},
+		{"change member role", func(ctx context.Context, f *sharedListFixture) error {
+			return f.lists.UpdateMemberRole(ctx, testListID, "user-3", dto.UpdateMemberRoleRequest{Role: "editor"})
+		}},
+		{"remove member", func(ctx context.Context, f *sharedListFixture) error {
+			return f.lists.RemoveMember(ctx, testListID, "user-3")
+		}},
+		{"create invite", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.lists.CreateInvite(ctx, testListID, dto.CreateInviteRequest{Role: "viewer"})
+			return err
+		}},
+		{"view invites", func(ctx context.Context, f *sharedListFixture) error {
+			_, err := f.lists.GetInvites(ctx, testListID)
+			return err
+		}},
+		{"revoke invite", func(ctx context.Context, f *sharedListFixture) error {
+			return f.lists.RevokeInvite(ctx, testListID, "invite-1")
+		}},
+		{"delete list", func(ctx context.Context, f *sharedListFixture) error {
+			return f.lists.DeleteList(ctx, testListID)
+		}},
+	}
+
+	// expected[role][action] - every action missing from a role's row is forbidden
+	expected := map[entities.ListRole]map[string]outcome{
+		entities.ListRoleOwner: {
+			"view todos": allowed, "create todo": allowed, "batch create todo": allowed,
+			"update todo": allowed, "complete todo": allowed, "delete todo": allowed, "patch todo": allowed,
+			"view comments": allowed, "add comment": allowed,
+			"view list": allowed, "view members": allowed,
+			"change member role": allowed, "remove member": allowed,
+			"create invite": allowed, "view invites": allowed, "revoke invite": allowed,
+			"delete list": allowed,
+		},
+		entities.ListRoleEditor: {
+			"view todos": allowed, "create todo": allowed, "batch create todo": allowed,
+			"update todo": allowed, "complete todo": allowed, "delete todo": allowed, "patch todo": allowed,
+			"view comments": allowed, "add comment": allowed,
+			"view list": allowed, "view members": allowed,
+		},
+		entities.ListRoleCommenter: {
+			"view todos":    allowed,
+			"view comments": allowed, "add comment": allowed,
+			"view list": allowed, "view members": allowed,
+		},
+		entities.ListRoleViewer: {
+			"view todos":    allowed,
+			"view comments": allowed,
+			"view list":     allowed, "view members": allowed,
+		},
+	}
+
+	roles := []entities.ListRole{
+		entities.ListRoleOwner,
+		entities.ListRoleEditor,
+		entities.ListRoleCommenter,
+		entities.ListRoleViewer,
+		"", // not a member
+	}
+
+	for _, role := range roles {
+		for _, action := range actions {
+			name := string(role)
+			if role == "" {
+				name = "non-member"
+			}
+
+			want := forbidden
+			if role == "" {
+				// Non-members cannot tell a list they are not on from a missing one
+				want = hidden
+			} else if got, ok := expected[role][action.name]; ok {
+				want = got
+			}
+
+			t.Run(name+"/"+action.name, func(t *testing.T) {
+				f := newSharedListFixture(role)
+
+				err := action.run(authenticatedContext(), f)
+
+				assert.Equal(t, want, outcomeOf(err))
+			})
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockListRepository for application layer testing
+type MockListRepository struct {
+	mock.Mock
+}
+
+func (m *MockListRepository) Create(ctx context.Context, list *entities.TodoList) error {
+	args := m.Called(ctx, list)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) GetByID(ctx context.Context, id string) (*entities.TodoList, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TodoList), args.Error(1)
+}
+
+func (m *MockListRepository) ListForUser(ctx context.Context, userID string) ([]*entities.ListMembership, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ListMembership), args.Error(1)
+}
+
+func (m *MockListRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) GetMember(ctx context.Context, listID, userID string) (*entities.ListMember, error) {
+	args := m.Called(ctx, listID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ListMember), args.Error(1)
+}
+
+func (m *MockListRepository) ListMembers(ctx context.Context, listID string) ([]*entities.ListMember, error) {
+	args := m.Called(ctx, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ListMember), args.Error(1)
+}
+
+func (m *MockListRepository) AddMember(ctx context.Context, member *entities.ListMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) UpdateMemberRole(ctx context.Context, listID, userID string, role entities.ListRole) error {
+	args := m.Called(ctx, listID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) RemoveMember(ctx context.Context, listID, userID string) error {
+	args := m.Called(ctx, listID, userID)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// MockListInviteRepository for application layer testing
+type MockListInviteRepository struct {
+	mock.Mock
+}
+
+func (m *MockListInviteRepository) Create(ctx context.Context, invite *entities.ListInvite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockListInviteRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.ListInvite, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ListInvite), args.Error(1)
+}
+
+func (m *MockListInviteRepository) ListByList(ctx context.Context, listID string) ([]*entities.ListInvite, error) {
+	args := m.Called(ctx, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ListInvite), args.Error(1)
+}
+
+func (m *MockListInviteRepository) Revoke(ctx context.Context, listID, id string) error {
+	args := m.Called(ctx, listID, id)
+	return args.Error(0)
+}
+
+func (m *MockListInviteRepository) MarkAccepted(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+const testListID = "list-1"
+
+func TestListUseCase_CreateList_ShouldMakeCallerOwner(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	useCase := usecases.NewListUseCase(listRepo, &MockListInviteRepository{}, time.Hour)
+	ctx := authenticatedContext()
+	listRepo.On("Create", ctx, mock.AnythingOfType("*entities.TodoList")).Return(nil)
+
+	// When
+	membership, err := useCase.CreateList(ctx, dto.CreateListRequest{Name: "  Groceries "})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", membership.List.Name)
+	assert.Equal(t, testPrincipal.UserID, membership.List.OwnerID)
+	assert.Equal(t, entities.ListRoleOwner, membership.Role)
+}
+
+func TestListUseCase_RemoveMember_OwnerCannotBeRemoved(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	useCase := usecases.NewListUseCase(listRepo, &MockListInviteRepository{}, time.Hour)
+	ctx := authenticatedContext()
+	listRepo.On("GetMember", ctx, testListID, testPrincipal.UserID).
+		Return(entities.NewListMember(testListID, testPrincipal.UserID, entities.ListRoleOwner), nil)
+
+	// When
+	err := useCase.RemoveMember(ctx, testListID, testPrincipal.UserID)
+
+	// Then
+	assert.ErrorIs(t, err, usecases.ErrInvalidInput)
+	listRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListUseCase_RemoveMember_ViewerCanLeave(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	useCase := usecases.NewListUseCase(listRepo, &MockListInviteRepository{}, time.Hour)
+	ctx := authenticatedContext()
+	listRepo.On("GetMember", ctx, testListID, testPrincipal.UserID).
+		Return(entities.NewListMember(testListID, testPrincipal.UserID, entities.ListRoleViewer), nil)
+	listRepo.On("RemoveMember", ctx, testListID, testPrincipal.UserID).Return(nil)
+
+	// When
+	err := useCase.RemoveMember(ctx, testListID, testPrincipal.UserID)
+
+	// Then
+	assert.NoError(t, err)
+	listRepo.AssertExpectations(t)
+}
+
+func TestListUseCase_CreateInvite_RejectsOwnerRole(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	useCase := usecases.NewListUseCase(listRepo, &MockListInviteRepository{}, time.Hour)
+	ctx := authenticatedContext()
+	listRepo.On("GetMember", ctx, testListID, testPrincipal.UserID).
+		Return(entities.NewListMember(testListID, testPrincipal.UserID, entities.ListRoleOwner), nil)
+
+	// When
+	created, err := useCase.CreateInvite(ctx, testListID, dto.CreateInviteRequest{Role: "owner"})
+
+	// Then
+	assert.ErrorIs(t, err, usecases.ErrInvalidInput)
+	assert.Nil(t, created)
+}
+
+func TestListUseCase_CreateInvite_StoresOnlyTokenHash(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	inviteRepo := &MockListInviteRepository{}
+	useCase := usecases.NewListUseCase(listRepo, inviteRepo, time.Hour)
+	ctx := authenticatedContext()
+	listRepo.On("GetMember", ctx, testListID, testPrincipal.UserID).
+		Return(entities.NewListMember(testListID, testPrincipal.UserID, entities.ListRoleOwner), nil)
+	inviteRepo.On("Create", ctx, mock.AnythingOfType("*entities.ListInvite")).Return(nil)
+
+	// When
+	created, err := useCase.CreateInvite(ctx, testListID, dto.CreateInviteRequest{Role: "viewer"})
+
+	// Then
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Token)
+	assert.NotEqual(t, created.Token, created.Invite.TokenHash)
+	assert.Equal(t, entities.ListRoleViewer, created.Invite.Role)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), created.Invite.ExpiresAt, time.Minute)
+}
+
+func TestListUseCase_AcceptInvite_UnusableInvites_ShouldFail(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		invite *entities.ListInvite
+	}{
+		{"expired", entities.NewListInvite(testListID, "user-2", "hash", entities.ListRoleViewer, past)},
+		{"revoked", &entities.ListInvite{ID: "i", ListID: testListID, Role: entities.ListRoleViewer, ExpiresAt: now.Add(time.Hour), RevokedAt: &past}},
+		{"accepted", &entities.ListInvite{ID: "i", ListID: testListID, Role: entities.ListRoleViewer, ExpiresAt: now.Add(time.Hour), AcceptedAt: &past}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			listRepo := &MockListRepository{}
+			inviteRepo := &MockListInviteRepository{}
+			useCase := usecases.NewListUseCase(listRepo, inviteRepo, time.Hour)
+			ctx := authenticatedContext()
+			inviteRepo.On("GetByHash", ctx, mock.Anything).Return(tt.invite, nil)
+
+			// When
+			membership, err := useCase.AcceptInvite(ctx, dto.AcceptInviteRequest{Token: "token"})
+
+			// Then
+			assert.ErrorIs(t, err, repositories.ErrListInviteNotFound)
+			assert.Nil(t, membership)
+			listRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListUseCase_AcceptInvite_ExistingMember_ShouldConflict(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	inviteRepo := &MockListInviteRepository{}
+	useCase := usecases.NewListUseCase(listRepo, inviteRepo, time.Hour)
+	ctx := authenticatedContext()
+	invite := entities.NewListInvite(testListID, "user-2", "hash", entities.ListRoleEditor, time.Now().Add(time.Hour))
+	inviteRepo.On("GetByHash", ctx, mock.Anything).Return(invite, nil)
+	listRepo.On("AddMember", ctx, mock.AnythingOfType("*entities.ListMember")).Return(repositories.ErrListMemberExists)
+
+	// When
+	_, err := useCase.AcceptInvite(ctx, dto.AcceptInviteRequest{Token: "token"})
+
+	// Then
+	assert.ErrorIs(t, err, repositories.ErrListMemberExists)
+	inviteRepo.AssertNotCalled(t, "MarkAccepted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListUseCase_Unauthenticated_ShouldFail(t *testing.T) {
+	useCase := usecases.NewListUseCase(&MockListRepository{}, &MockListInviteRepository{}, time.Hour)
+
+	_, err := useCase.GetLists(context.Background())
+
+	assert.ErrorIs(t, err, identity.ErrUnauthenticated)
+}
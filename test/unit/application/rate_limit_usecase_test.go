@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRateLimitStore for application layer testing
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) Take(ctx context.Context, tenant, subject string, policy entities.RateLimitPolicy, now time.Time) (entities.RateLimitDecision, error) {
+	args := m.Called(ctx, tenant, subject, policy, now)
+	return args.Get(0).(entities.RateLimitDecision), args.Error(1)
+}
+
+func (m *MockRateLimitStore) List(ctx context.Context, tenant, subject string) ([]*entities.RateLimitBucket, error) {
+	args := m.Called(ctx, tenant, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.RateLimitBucket), args.Error(1)
+}
+
+func (m *MockRateLimitStore) Reset(ctx context.Context, tenant, subject, policy string) (int64, error) {
+	args := m.Called(ctx, tenant, subject, policy)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRateLimitStore) DeleteFull(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var (
+	defaultRateLimit = entities.RateLimitPolicy{Name: "default", Requests: 100, Period: time.Minute}
+	createTodoLimit  = entities.RateLimitPolicy{Name: "create-todo", Requests: 10, Period: time.Minute}
+	todoWriteLimit   = entities.RateLimitPolicy{Name: "todo-write", Requests: 20, Period: time.Minute}
+)
+
+func newTestRateLimitUseCase(store *MockRateLimitStore) *usecases.RateLimitUseCase {
+	return usecases.NewRateLimitUseCase(store, defaultRateLimit,
+		usecases.RateLimitRule{Method: "POST", Path: "/api/todos", Policy: createTodoLimit},
+		usecases.RateLimitRule{Path: "/api/todos/:id/*", Policy: todoWriteLimit},
+	)
+}
+
+func TestRateLimitUseCase_Take_SelectsPolicyByRoute(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		policy entities.RateLimitPolicy
+	}{
+		{"POST", "/api/todos", createTodoLimit},
+		{"POST", "/api/todos/", createTodoLimit},
+		{"GET", "/api/todos", defaultRateLimit},
+		{"POST", "/api/lists", defaultRateLimit},
+		{"PATCH", "/api/todos/todo-1", todoWriteLimit},
+		{"POST", "/api/todos/todo-1/comments", todoWriteLimit},
+		{"GET", "/api/todos/todo-1/comments/extra", todoWriteLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			// Given
+			store := &MockRateLimitStore{}
+			useCase := newTestRateLimitUseCase(store)
+			ctx := context.Background()
+			store.On("Take", ctx, "", "ip:1.2.3.4", tt.policy, mock.Anything).
+				Return(entities.RateLimitDecision{Policy: tt.policy, Allowed: true}, nil)
+
+			// When
+			decision, err := useCase.Take(ctx, usecases.RateLimitRequest{Method: tt.method, Path: tt.path, ClientIP: "1.2.3.4"})
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.policy.Name, decision.Policy.Name)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitUseCase_Take_KeysByAPIKeyThenUserThenIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		subject string
+	}{
+		{"api key", identity.WithPrincipal(context.Background(), &identity.Principal{UserID: "user-1", APIKeyID: "key-1"}), "key:key-1"},
+		{"user", authenticatedContext(), "user:" + testPrincipal.UserID},
+		{"anonymous", context.Background(), "ip:1.2.3.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			store := &MockRateLimitStore{}
+			useCase := newTestRateLimitUseCase(store)
+			ctx := tenancy.WithTenant(tt.ctx, "acme")
+			store.On("Take", ctx, "acme", tt.subject, defaultRateLimit, mock.Anything).
+				Return(entities.RateLimitDecision{Policy: defaultRateLimit, Allowed: true}, nil)
+
+			// When
+			_, err := useCase.Take(ctx, usecases.RateLimitRequest{Method: "GET", Path: "/api/todos", ClientIP: "1.2.3.4"})
+
+			// Then
+			assert.NoError(t, err)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitUseCase_Take_DisabledPolicy(t *testing.T) {
+	// Given
+	store := &MockRateLimitStore{}
+	useCase := usecases.NewRateLimitUseCase(store, entities.RateLimitPolicy{Name: "default"})
+
+	// When
+	decision, err := useCase.Take(context.Background(), usecases.RateLimitRequest{Method: "GET", Path: "/api/todos"})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Nil(t, decision)
+	store.AssertNotCalled(t, "Take", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateLimitUseCase_ResetBuckets(t *testing.T) {
+	t.Run("should require admin", func(t *testing.T) {
+		// Given
+		store := &MockRateLimitStore{}
+		useCase := newTestRateLimitUseCase(store)
+
+		// When
+		_, err := useCase.ResetBuckets(authenticatedContext(), "user:user-2", "")
+
+		// Then
+		assert.ErrorIs(t, err, identity.ErrForbidden)
+		store.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reset within the caller's tenant", func(t *testing.T) {
+		// Given
+		store := &MockRateLimitStore{}
+		useCase := newTestRateLimitUseCase(store)
+		admin := &identity.Principal{UserID: "root", Scopes: []identity.Scope{identity.ScopeAdmin}}
+		ctx := tenancy.WithTenant(identity.WithPrincipal(context.Background(), admin), "acme")
+		store.On("Reset", ctx, "acme", "user:user-2", "").Return(int64(2), nil)
+
+		// When
+		reset, err := useCase.ResetBuckets(ctx, "user:user-2", "")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), reset)
+	})
+}
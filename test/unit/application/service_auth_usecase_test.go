@@ -0,0 +1,89 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/identity"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var billingService = usecases.ServiceClient{
+	Subject: "CN=billing,O=Acme",
+	Name:    "billing",
+	Scopes:  []identity.Scope{identity.ScopeTodosRead},
+}
+
+func TestServiceAuthUseCase_AuthenticateCertificate(t *testing.T) {
+	t.Run("should map a known subject to its service principal", func(t *testing.T) {
+		// Given
+		useCase, err := usecases.NewServiceAuthUseCase(billingService)
+		require.NoError(t, err)
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+
+		// When
+		principal, err := useCase.AuthenticateCertificate(ctx, "CN=billing,O=Acme")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "service:billing", principal.UserID)
+		assert.Equal(t, "billing", principal.Username)
+		assert.Equal(t, []identity.Scope{identity.ScopeTodosRead}, principal.Scopes)
+		assert.Equal(t, "acme", principal.TenantID)
+		assert.Empty(t, principal.SessionID)
+	})
+
+	t.Run("should pin the service to its tenant", func(t *testing.T) {
+		// Given
+		pinned := billingService
+		pinned.TenantID = "globex"
+		useCase, err := usecases.NewServiceAuthUseCase(pinned)
+		require.NoError(t, err)
+
+		// When
+		principal, err := useCase.AuthenticateCertificate(tenancy.WithTenant(context.Background(), "acme"), "CN=billing,O=Acme")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "globex", principal.TenantID)
+	})
+
+	t.Run("should reject unknown subjects", func(t *testing.T) {
+		// Given
+		useCase, err := usecases.NewServiceAuthUseCase(billingService)
+		require.NoError(t, err)
+
+		// When
+		_, err = useCase.AuthenticateCertificate(context.Background(), "CN=billing")
+
+		// Then
+		assert.ErrorIs(t, err, identity.ErrUnauthenticated)
+	})
+}
+
+func TestNewServiceAuthUseCase_ValidatesClients(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients []usecases.ServiceClient
+	}{
+		{"missing subject", []usecases.ServiceClient{{Name: "billing"}}},
+		{"missing name", []usecases.ServiceClient{{Subject: "CN=billing"}}},
+		{"unknown scope", []usecases.ServiceClient{{Subject: "CN=billing", Name: "billing", Scopes: []identity.Scope{"todos:delete"}}}},
+		{"invalid tenant", []usecases.ServiceClient{{Subject: "CN=billing", Name: "billing", TenantID: "Not A Tenant"}}},
+		{"duplicate subject", []usecases.ServiceClient{billingService, {Subject: billingService.Subject, Name: "other"}}},
+		{"duplicate name", []usecases.ServiceClient{billingService, {Subject: "CN=other", Name: billingService.Name}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			_, err := usecases.NewServiceAuthUseCase(tt.clients...)
+
+			// Then
+			assert.ErrorIs(t, err, usecases.ErrInvalidInput)
+		})
+	}
+}
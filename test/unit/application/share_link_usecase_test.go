@@ -0,0 +1,177 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShareLinkRepository for application layer testing
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link *entities.ShareLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) ListByList(ctx context.Context, listID string) ([]*entities.ShareLink, error) {
+	args := m.Called(ctx, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, listID, id string) error {
+	args := m.Called(ctx, listID, id)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) SharedTodos(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	args := m.Called(ctx, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Todo), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) RecordAccess(ctx context.Context, access *entities.ShareLinkAccess) error {
+	args := m.Called(ctx, access)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) ListAccesses(ctx context.Context, listID, linkID string, limit int) ([]*entities.ShareLinkAccess, error) {
+	args := m.Called(ctx, listID, linkID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ShareLinkAccess), args.Error(1)
+}
+
+// plainHasher stands in for argon2id so that tests stay fast
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) {
+	return "plain:" + password, nil
+}
+
+func (plainHasher) Verify(password, encoded string) error {
+	if encoded != "plain:"+password {
+		return errors.New("password does not match")
+	}
+	return nil
+}
+
+func recordedOutcome(outcome entities.ShareLinkOutcome) interface{} {
+	return mock.MatchedBy(func(access *entities.ShareLinkAccess) bool {
+		return access.Outcome == outcome
+	})
+}
+
+func TestShareLinkUseCase_CreateShareLink_StoresOnlyHashes(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	linkRepo := &MockShareLinkRepository{}
+	useCase := usecases.NewShareLinkUseCase(listRepo, linkRepo, plainHasher{})
+	ctx := authenticatedContext()
+	listRepo.On("GetMember", ctx, testListID, testPrincipal.UserID).
+		Return(entities.NewListMember(testListID, testPrincipal.UserID, entities.ListRoleOwner), nil)
+	linkRepo.On("Create", ctx, mock.AnythingOfType("*entities.ShareLink")).Return(nil)
+
+	// When
+	created, err := useCase.CreateShareLink(ctx, testListID, dto.CreateShareLinkRequest{Password: "open sesame"})
+
+	// Then
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Token)
+	assert.NotContains(t, created.Link.TokenHash, created.Token)
+	assert.Equal(t, "plain:open sesame", created.Link.PasswordHash)
+	assert.Nil(t, created.Link.ExpiresAt)
+}
+
+func TestShareLinkUseCase_OpenShareLink_RevokedLinkIsLoggedAndNotFound(t *testing.T) {
+	// Given
+	linkRepo := &MockShareLinkRepository{}
+	useCase := usecases.NewShareLinkUseCase(&MockListRepository{}, linkRepo, plainHasher{})
+	ctx := context.Background()
+	link := entities.NewShareLink(testListID, testPrincipal.UserID, "hash")
+	revokedAt := time.Now()
+	link.RevokedAt = &revokedAt
+	linkRepo.On("GetByHash", ctx, mock.Anything).Return(link, nil)
+	linkRepo.On("RecordAccess", ctx, recordedOutcome(entities.ShareLinkInactive)).Return(nil)
+
+	// When
+	shared, err := useCase.OpenShareLink(ctx, usecases.ShareLinkVisit{Token: "token"})
+
+	// Then
+	assert.ErrorIs(t, err, repositories.ErrShareLinkNotFound)
+	assert.Nil(t, shared)
+	linkRepo.AssertExpectations(t)
+	linkRepo.AssertNotCalled(t, "SharedTodos", mock.Anything, mock.Anything)
+}
+
+func TestShareLinkUseCase_OpenShareLink_HidesCompletedAndTruncatesUserAgent(t *testing.T) {
+	// Given
+	listRepo := &MockListRepository{}
+	linkRepo := &MockShareLinkRepository{}
+	useCase := usecases.NewShareLinkUseCase(listRepo, linkRepo, plainHasher{})
+	ctx := context.Background()
+	link := entities.NewShareLink(testListID, testPrincipal.UserID, "hash")
+	link.HideCompleted = true
+	done := entities.NewTodo("Eggs")
+	done.Complete()
+	linkRepo.On("GetByHash", ctx, mock.Anything).Return(link, nil)
+	linkRepo.On("RecordAccess", ctx, mock.MatchedBy(func(access *entities.ShareLinkAccess) bool {
+		return access.Outcome == entities.ShareLinkGranted && len(access.UserAgent) == 256
+	})).Return(nil)
+	listRepo.On("GetByID", ctx, testListID).Return(entities.NewTodoList("Groceries", testPrincipal.UserID), nil)
+	linkRepo.On("SharedTodos", ctx, testListID).Return([]*entities.Todo{entities.NewTodo("Milk"), done}, nil)
+
+	// When
+	shared, err := useCase.OpenShareLink(ctx, usecases.ShareLinkVisit{Token: "token", UserAgent: strings.Repeat("a", 1000)})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "Groceries", shared.Name)
+	if assert.Len(t, shared.Todos, 1) {
+		assert.Equal(t, "Milk", shared.Todos[0].Text)
+	}
+	linkRepo.AssertExpectations(t)
+}
+
+func TestShareLinkUseCase_OpenShareLink_WrongPasswordIsDenied(t *testing.T) {
+	// Given
+	linkRepo := &MockShareLinkRepository{}
+	useCase := usecases.NewShareLinkUseCase(&MockListRepository{}, linkRepo, plainHasher{})
+	ctx := context.Background()
+	link := entities.NewShareLink(testListID, testPrincipal.UserID, "hash")
+	link.PasswordHash = "plain:open sesame"
+	linkRepo.On("GetByHash", ctx, mock.Anything).Return(link, nil)
+	linkRepo.On("RecordAccess", ctx, recordedOutcome(entities.ShareLinkDenied)).Return(nil)
+
+	// When
+	_, err := useCase.OpenShareLink(ctx, usecases.ShareLinkVisit{Token: "token", Password: "guess"})
+
+	// Then
+	assert.ErrorIs(t, err, usecases.ErrShareLinkPassword)
+	linkRepo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/tenancy"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantUsageRepository for application layer testing
+type MockTenantUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantUsageRepository) Usage(ctx context.Context) (*entities.TenantUsage, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TenantUsage), args.Error(1)
+}
+
+func fixedQuota(quota entities.TenantQuota) usecases.QuotaFunc {
+	return func(string) entities.TenantQuota { return quota }
+}
+
+func TestTodoUseCase_CreateTodo_OverTodoQuota_ShouldFail(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	usageRepo := &MockTenantUsageRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo, usecases.WithQuotas(usageRepo, fixedQuota(entities.TenantQuota{MaxTodos: 3})))
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+	usageRepo.On("Usage", ctx).Return(&entities.TenantUsage{Todos: 3}, nil)
+
+	// When
+	todo, err := useCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: "One too many"})
+
+	// Then
+	assert.ErrorIs(t, err, tenancy.ErrQuotaExceeded)
+	assert.Nil(t, todo)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTodoUseCase_CreateTodo_OverStorageQuota_ShouldFail(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	usageRepo := &MockTenantUsageRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo, usecases.WithQuotas(usageRepo, fixedQuota(entities.TenantQuota{MaxStorageBytes: 10})))
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+	usageRepo.On("Usage", ctx).Return(&entities.TenantUsage{StorageBytes: 8}, nil)
+
+	// When
+	_, err := useCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: "abc"})
+
+	// Then
+	assert.ErrorIs(t, err, tenancy.ErrQuotaExceeded)
+}
+
+func TestTodoUseCase_CreateTodo_WithinQuota_ShouldSucceed(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	usageRepo := &MockTenantUsageRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo, usecases.WithQuotas(usageRepo, fixedQuota(entities.TenantQuota{MaxTodos: 3, MaxStorageBytes: 100})))
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+	usageRepo.On("Usage", ctx).Return(&entities.TenantUsage{Todos: 2, StorageBytes: 50}, nil)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entities.Todo")).Return(entities.NewTodo("Fits"), nil)
+
+	// When
+	_, err := useCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: "Fits"})
+
+	// Then
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTodoUseCase_CreateTodo_UnlimitedTenant_SkipsMeasuring(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	usageRepo := &MockTenantUsageRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo, usecases.WithQuotas(usageRepo, fixedQuota(entities.TenantQuota{})))
+	ctx := tenancy.WithTenant(authenticatedContext(), "acme")
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entities.Todo")).Return(entities.NewTodo("Free"), nil)
+
+	// When
+	_, err := useCase.CreateTodo(ctx, dto.CreateTodoRequest{Text: "Free"})
+
+	// Then
+	assert.NoError(t, err)
+	usageRepo.AssertNotCalled(t, "Usage", mock.Anything)
+}
@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+	"todo-backend/internal/domain/repositories"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// inBatch matches the contexts of changes logged as part of a batch
+var inBatch = mock.MatchedBy(func(ctx context.Context) bool {
+	_, ok := events.BatchFromContext(ctx)
+	return ok
+})
+
+func TestTodoUseCase_ExecuteBatch_BestEffort(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo)
+	ctx := authenticatedContext()
+
+	existing := entities.NewTodo("Existing")
+	mockRepo.On("Create", inBatch, mock.AnythingOfType("*entities.Todo")).Return(entities.NewTodo("New"), nil)
+	mockRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	mockRepo.On("Update", inBatch, existing).Return(existing, nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return(nil, repositories.ErrTodoNotFound)
+	mockRepo.On("CommitBatch", inBatch).Return(nil)
+
+	req := dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "New"},
+		{Op: dto.BatchOpComplete, ID: existing.ID},
+		{Op: dto.BatchOpDelete, ID: "missing"},
+		{Op: "archive", ID: existing.ID},
+	}}
+
+	// When
+	results, err := useCase.ExecuteBatch(ctx, req)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.True(t, results[1].Todo.Completed)
+	assert.True(t, errors.Is(results[2].Err, repositories.ErrTodoNotFound))
+	assert.True(t, errors.Is(results[3].Err, usecases.ErrInvalidInput))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTodoUseCase_ExecuteBatch_AtomicFailure_RollsBack(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo)
+	ctx := authenticatedContext()
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Todo")).Return(entities.NewTodo("New"), nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return(nil, repositories.ErrTodoNotFound)
+
+	req := dto.BatchRequest{Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "New"},
+		{Op: dto.BatchOpComplete, ID: "missing"},
+	}}
+
+	// When
+	results, err := useCase.ExecuteBatch(ctx, req)
+
+	// Then
+	assert.ErrorIs(t, err, usecases.ErrBatchRolledBack)
+	assert.ErrorIs(t, results[0].Err, usecases.ErrBatchAborted)
+	assert.Nil(t, results[0].Todo)
+	assert.ErrorIs(t, results[1].Err, repositories.ErrTodoNotFound)
+}
+
+func TestTodoUseCase_ExecuteBatch_AtomicInvalidOperation_TouchesNothing(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo)
+	ctx := authenticatedContext()
+
+	req := dto.BatchRequest{Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchOpCreate, Text: "New"},
+		{Op: dto.BatchOpUpdate, ID: "some-id"},
+	}}
+
+	// When
+	results, err := useCase.ExecuteBatch(ctx, req)
+
+	// Then
+	assert.ErrorIs(t, err, usecases.ErrBatchRolledBack)
+	assert.ErrorIs(t, results[1].Err, usecases.ErrInvalidInput)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestTodoUseCase_ExecuteBatch_Empty_ShouldFail(t *testing.T) {
+	useCase := usecases.NewTodoUseCase(&MockTodoRepository{})
+
+	results, err := useCase.ExecuteBatch(authenticatedContext(), dto.BatchRequest{})
+
+	assert.ErrorIs(t, err, usecases.ErrInvalidInput)
+	assert.Nil(t, results)
+}
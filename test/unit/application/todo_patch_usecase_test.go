@@ -0,0 +1,112 @@
+package application
+
+import (
+	"testing"
+	"todo-backend/internal/application/patch"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/interfaces/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPatchFixture(t *testing.T) (*MockTodoRepository, *usecases.TodoUseCase, *entities.Todo) {
+	t.Helper()
+	mockRepo := &MockTodoRepository{}
+	todo := entities.NewTodo("Original")
+	mockRepo.On("GetByID", authenticatedContext(), todo.ID).Return(todo, nil)
+	return mockRepo, usecases.NewTodoUseCase(mockRepo), todo
+}
+
+func TestTodoUseCase_PatchTodo_MergePatch(t *testing.T) {
+	// Given
+	mockRepo, useCase, todo := newPatchFixture(t)
+	ctx := authenticatedContext()
+	mockRepo.On("Update", ctx, todo).Return(todo, nil)
+
+	// When
+	result, err := useCase.PatchTodo(ctx, todo.ID, dto.PatchTodoRequest{
+		Format: dto.PatchFormatMerge,
+		Patch:  []byte(`{"text":"Merged","completed":true}`),
+	})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "Merged", result.Text)
+	assert.True(t, result.Completed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTodoUseCase_PatchTodo_JSONPatch(t *testing.T) {
+	// Given
+	mockRepo, useCase, todo := newPatchFixture(t)
+	ctx := authenticatedContext()
+	mockRepo.On("Update", ctx, todo).Return(todo, nil)
+
+	// When
+	result, err := useCase.PatchTodo(ctx, todo.ID, dto.PatchTodoRequest{
+		Format: dto.PatchFormatJSONPatch,
+		Patch: []byte(`[
+			{"op":"test","path":"/version","value":1},
+			{"op":"test","path":"/text","value":"Original"},
+			{"op":"replace","path":"/text","value":"Patched"},
+			{"op":"add","path":"/completed","value":true}
+		]`),
+	})
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "Patched", result.Text)
+	assert.True(t, result.Completed)
+}
+
+func TestTodoUseCase_PatchTodo_FailedTest_RejectsWholePatch(t *testing.T) {
+	// Given
+	mockRepo, useCase, todo := newPatchFixture(t)
+	ctx := authenticatedContext()
+
+	// When
+	_, err := useCase.PatchTodo(ctx, todo.ID, dto.PatchTodoRequest{
+		Format: dto.PatchFormatJSONPatch,
+		Patch: []byte(`[
+			{"op":"replace","path":"/text","value":"Patched"},
+			{"op":"test","path":"/version","value":7}
+		]`),
+	})
+
+	// Then
+	assert.ErrorIs(t, err, patch.ErrTestFailed)
+	assert.Equal(t, "Original", todo.Text)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestTodoUseCase_PatchTodo_RejectsInvalidPatches(t *testing.T) {
+	cases := map[string]struct {
+		format string
+		patch  string
+		err    error
+	}{
+		"immutable field in merge patch": {dto.PatchFormatMerge, `{"id":"other"}`, patch.ErrFieldNotAllowed},
+		"null text in merge patch":       {dto.PatchFormatMerge, `{"text":null}`, patch.ErrInvalidPatch},
+		"wrong type":                     {dto.PatchFormatMerge, `{"completed":"yes"}`, patch.ErrInvalidPatch},
+		"immutable field in json patch":  {dto.PatchFormatJSONPatch, `[{"op":"replace","path":"/createdAt","value":"x"}]`, patch.ErrFieldNotAllowed},
+		"move from immutable field":      {dto.PatchFormatJSONPatch, `[{"op":"move","from":"/id","path":"/text"}]`, patch.ErrFieldNotAllowed},
+		"remove required field":          {dto.PatchFormatJSONPatch, `[{"op":"remove","path":"/text"}]`, patch.ErrInvalidPatch},
+		"unknown operation":              {dto.PatchFormatJSONPatch, `[{"op":"swap","path":"/text"}]`, patch.ErrInvalidPatch},
+		"malformed document":             {dto.PatchFormatJSONPatch, `{"op":"replace"}`, patch.ErrInvalidPatch},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo, useCase, todo := newPatchFixture(t)
+
+			_, err := useCase.PatchTodo(authenticatedContext(), todo.ID, dto.PatchTodoRequest{
+				Format: tc.format,
+				Patch:  []byte(tc.patch),
+			})
+
+			assert.ErrorIs(t, err, tc.err)
+			mockRepo.AssertNotCalled(t, "Update")
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package application
 
 import (
-	"context"
 	"testing"
 	"time"
 	"todo-backend/internal/application/usecases"
@@ -20,7 +19,7 @@ func TestTodoUseCase_CreateTodo_ContractCompliance(t *testing.T) {
 		// Given: a todo creation request
 		mockRepo := &MockTodoRepository{}
 		useCase := usecases.NewTodoUseCase(mockRepo)
-		ctx := context.Background()
+		ctx := authenticatedContext()
 		
 		req := dto.CreateTodoRequest{Text: "Contract test todo"}
 		todo := entities.NewTodo("Contract test todo")
@@ -46,7 +45,7 @@ func TestTodoUseCase_CreateTodo_ContractCompliance(t *testing.T) {
 		// Given: a todo creation request
 		mockRepo := &MockTodoRepository{}
 		useCase := usecases.NewTodoUseCase(mockRepo)
-		ctx := context.Background()
+		ctx := authenticatedContext()
 		
 		req := dto.CreateTodoRequest{Text: "Time format test"}
 		
@@ -83,7 +82,7 @@ func TestTodoUseCase_GetAllTodos_ContractCompliance(t *testing.T) {
 		// Given: todos exist in repository
 		mockRepo := &MockTodoRepository{}
 		useCase := usecases.NewTodoUseCase(mockRepo)
-		ctx := context.Background()
+		ctx := authenticatedContext()
 		
 		todos := []*entities.Todo{
 			{
@@ -115,7 +114,7 @@ func TestTodoUseCase_GetAllTodos_ContractCompliance(t *testing.T) {
 		// Given: no todos in repository
 		mockRepo := &MockTodoRepository{}
 		useCase := usecases.NewTodoUseCase(mockRepo)
-		ctx := context.Background()
+		ctx := authenticatedContext()
 		
 		emptyTodos := []*entities.Todo{}
 		mockRepo.On("GetAll", ctx).Return(emptyTodos, nil)
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"todo-backend/internal/application/usecases"
 	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/identity"
 	"todo-backend/internal/domain/repositories"
 	"todo-backend/internal/interfaces/dto"
 
@@ -13,6 +14,14 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// testPrincipal is the caller every use case test runs as
+var testPrincipal = &identity.Principal{UserID: "user-1", Username: "alice"}
+
+// authenticatedContext returns a context carrying testPrincipal
+func authenticatedContext() context.Context {
+	return identity.WithPrincipal(context.Background(), testPrincipal)
+}
+
 // MockTodoRepository for application layer testing
 type MockTodoRepository struct {
 	mock.Mock
@@ -34,6 +43,14 @@ func (m *MockTodoRepository) GetAll(ctx context.Context) ([]*entities.Todo, erro
 	return args.Get(0).([]*entities.Todo), args.Error(1)
 }
 
+func (m *MockTodoRepository) GetByList(ctx context.Context, listID string) ([]*entities.Todo, error) {
+	args := m.Called(ctx, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Todo), args.Error(1)
+}
+
 func (m *MockTodoRepository) GetByID(ctx context.Context, id string) (*entities.Todo, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -42,6 +59,28 @@ func (m *MockTodoRepository) GetByID(ctx context.Context, id string) (*entities.
 	return args.Get(0).(*entities.Todo), args.Error(1)
 }
 
+func (m *MockTodoRepository) Update(ctx context.Context, todo *entities.Todo) (*entities.Todo, error) {
+	args := m.Called(ctx, todo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *MockTodoRepository) CommitBatch(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 // Application Layer Use Case Tests
 // These test BUSINESS LOGIC ORCHESTRATION only
 
@@ -49,7 +88,7 @@ func TestTodoUseCase_CreateTodo_Success(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	req := dto.CreateTodoRequest{Text: "Test Todo"}
 	expectedTodo := entities.NewTodo("Test Todo")
@@ -72,7 +111,7 @@ func TestTodoUseCase_CreateTodo_EmptyText_ShouldFail(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	req := dto.CreateTodoRequest{Text: ""}
 	
@@ -88,11 +127,25 @@ func TestTodoUseCase_CreateTodo_EmptyText_ShouldFail(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Create")
 }
 
+func TestTodoUseCase_CreateTodo_Unauthenticated_ShouldFail(t *testing.T) {
+	// Given
+	mockRepo := &MockTodoRepository{}
+	useCase := usecases.NewTodoUseCase(mockRepo)
+
+	// When
+	result, err := useCase.CreateTodo(context.Background(), dto.CreateTodoRequest{Text: "Test Todo"})
+
+	// Then
+	assert.ErrorIs(t, err, identity.ErrUnauthenticated)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
 func TestTodoUseCase_CreateTodo_RepositoryError(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	req := dto.CreateTodoRequest{Text: "Test Todo"}
 	repoError := errors.New("database connection failed")
@@ -115,7 +168,7 @@ func TestTodoUseCase_GetAllTodos_Success(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	expectedTodos := []*entities.Todo{
 		entities.NewTodo("Todo 1"),
@@ -144,7 +197,7 @@ func TestTodoUseCase_GetAllTodos_RepositoryError(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	repoError := errors.New("connection timeout")
 	mockRepo.On("GetAll", ctx).Return(nil, repoError)
@@ -165,7 +218,7 @@ func TestTodoUseCase_GetTodoByID_Success(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	todoID := "test-id-123"
 	expectedTodo := entities.NewTodo("Test Todo")
@@ -189,7 +242,7 @@ func TestTodoUseCase_GetTodoByID_NotFound(t *testing.T) {
 	// Given
 	mockRepo := &MockTodoRepository{}
 	useCase := usecases.NewTodoUseCase(mockRepo)
-	ctx := context.Background()
+	ctx := authenticatedContext()
 	
 	todoID := "non-existent-id"
 	mockRepo.On("GetByID", ctx, todoID).Return(nil, repositories.ErrTodoNotFound)
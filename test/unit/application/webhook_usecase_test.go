@@ -0,0 +1,227 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-backend/internal/application/usecases"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository for application layer testing
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id, ownerID string) (*entities.Webhook, error) {
+	args := m.Called(ctx, id, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetForDelivery(ctx context.Context, id string) (*entities.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListByOwner(ctx context.Context, ownerID string) ([]*entities.Webhook, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id, ownerID string) error {
+	args := m.Called(ctx, id, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Subscribed(ctx context.Context, ownerID, listID string) ([]*entities.Webhook, error) {
+	args := m.Called(ctx, ownerID, listID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error {
+	args := m.Called(ctx, delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, status entities.WebhookDeliveryStatus, limit int) ([]*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]*entities.WebhookAttempt, error) {
+	args := m.Called(ctx, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.WebhookAttempt), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, webhookID, id string) (*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// stubSender answers every delivery with the same status code or error
+type stubSender struct {
+	statusCode int
+	err        error
+}
+
+func (s stubSender) Send(context.Context, *entities.Webhook, *entities.WebhookDelivery) (int, error) {
+	return s.statusCode, s.err
+}
+
+var testWebhookLimits = usecases.WebhookLimits{
+	Timeout:     time.Second,
+	MaxAttempts: 4,
+	BackoffBase: time.Minute,
+	BackoffMax:  3 * time.Minute,
+}
+
+// claimOnly makes delivery the only one due
+func claimOnly(webhookRepo *MockWebhookRepository, delivery *entities.WebhookDelivery) {
+	webhookRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*entities.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("GetForDelivery", mock.Anything, delivery.WebhookID).
+		Return(entities.NewWebhook(testPrincipal.UserID, "https://example.com/hooks", "whsec_test", "", nil), nil)
+	webhookRepo.On("RecordAttempt", mock.Anything, delivery, mock.AnythingOfType("*entities.WebhookAttempt")).Return(nil)
+}
+
+func TestWebhookUseCase_Handle_OnlySubscribedEventTypes(t *testing.T) {
+	// Given
+	webhookRepo := &MockWebhookRepository{}
+	useCase := usecases.NewWebhookUseCase(webhookRepo, &MockListRepository{}, stubSender{}, testWebhookLimits)
+	ctx := context.Background()
+	todo := entities.NewTodo("Ship it")
+	todo.OwnerID = testPrincipal.UserID
+	all := entities.NewWebhook(testPrincipal.UserID, "https://example.com/all", "s1", "", nil)
+	completions := entities.NewWebhook(testPrincipal.UserID, "https://example.com/done", "s2", "", []string{"todo.completed"})
+	webhookRepo.On("Subscribed", ctx, testPrincipal.UserID, "").Return([]*entities.Webhook{all, completions}, nil)
+	webhookRepo.On("Enqueue", ctx, mock.Anything).Return(nil)
+	event := events.NewChangeEvent(events.TodoCreated, events.TodoChange(events.TodoCreated, todo))
+
+	// When
+	err := useCase.Handle(ctx, event)
+
+	// Then
+	assert.NoError(t, err)
+	deliveries := webhookRepo.Calls[1].Arguments.Get(1).([]*entities.WebhookDelivery)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, all.ID, deliveries[0].WebhookID)
+	assert.Equal(t, event.ID, deliveries[0].EventID)
+	assert.Equal(t, entities.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Contains(t, string(deliveries[0].Payload), `"text":"Ship it"`)
+}
+
+func TestWebhookUseCase_DeliverDue_SuccessMarksDelivered(t *testing.T) {
+	// Given
+	webhookRepo := &MockWebhookRepository{}
+	useCase := usecases.NewWebhookUseCase(webhookRepo, &MockListRepository{}, stubSender{statusCode: 204}, testWebhookLimits)
+	delivery := entities.NewWebhookDelivery("webhook-1", "event-1", "todo.created", []byte(`{}`))
+	claimOnly(webhookRepo, delivery)
+
+	// When
+	attempted, err := useCase.DeliverDue(context.Background())
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.Equal(t, entities.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestWebhookUseCase_DeliverDue_FailureBacksOffUpToTheMaximum(t *testing.T) {
+	// Given
+	webhookRepo := &MockWebhookRepository{}
+	useCase := usecases.NewWebhookUseCase(webhookRepo, &MockListRepository{}, stubSender{statusCode: 500}, testWebhookLimits)
+	delivery := entities.NewWebhookDelivery("webhook-1", "event-1", "todo.created", []byte(`{}`))
+	delivery.Attempts = 2
+	claimOnly(webhookRepo, delivery)
+
+	// When
+	_, err := useCase.DeliverDue(context.Background())
+
+	// Then - the third failure would wait 4 minutes, capped at 3
+	assert.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Contains(t, delivery.LastError, "500")
+	assert.WithinDuration(t, time.Now().Add(3*time.Minute), delivery.NextAttemptAt, 5*time.Second)
+}
+
+func TestWebhookUseCase_DeliverDue_LastAttemptGoesToDeadLetterQueue(t *testing.T) {
+	// Given
+	webhookRepo := &MockWebhookRepository{}
+	sender := stubSender{err: errors.New("connection refused")}
+	useCase := usecases.NewWebhookUseCase(webhookRepo, &MockListRepository{}, sender, testWebhookLimits)
+	delivery := entities.NewWebhookDelivery("webhook-1", "event-1", "todo.created", []byte(`{}`))
+	delivery.Attempts = 3
+	claimOnly(webhookRepo, delivery)
+
+	// When
+	_, err := useCase.DeliverDue(context.Background())
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryDead, delivery.Status)
+	assert.Equal(t, 4, delivery.Attempts)
+	assert.Equal(t, "connection refused", delivery.LastError)
+	webhookRepo.AssertCalled(t, "RecordAttempt", mock.Anything, delivery, mock.MatchedBy(func(attempt *entities.WebhookAttempt) bool {
+		return attempt.StatusCode == 0 && attempt.Error == "connection refused"
+	}))
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"todo-backend/internal/domain/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should deliver events to every subscriber", func(t *testing.T) {
+		// Given
+		bus := events.NewBus()
+		first := bus.Subscribe(1)
+		second := bus.Subscribe(1)
+		event := events.NewChangeEvent(events.TodoCreated)
+
+		// When
+		bus.Publish(ctx, event)
+
+		// Then
+		assert.Equal(t, event.ID, (<-first.Events()).ID)
+		assert.Equal(t, event.ID, (<-second.Events()).ID)
+	})
+
+	t.Run("should drop a subscriber that falls behind its buffer", func(t *testing.T) {
+		// Given
+		bus := events.NewBus()
+		slow := bus.Subscribe(1)
+		fast := bus.Subscribe(2)
+
+		// When
+		bus.Publish(ctx, events.NewChangeEvent(events.TodoCreated))
+		bus.Publish(ctx, events.NewChangeEvent(events.TodoUpdated))
+
+		// Then
+		assert.Equal(t, 1, bus.Subscribers())
+		_, ok := <-slow.Events()
+		assert.True(t, ok, "Queued events should still be received")
+		_, ok = <-slow.Events()
+		assert.False(t, ok, "Events channel should be closed")
+		assert.Len(t, fast.Events(), 2)
+	})
+
+	t.Run("should stop delivering after Close", func(t *testing.T) {
+		// Given
+		bus := events.NewBus()
+		sub := bus.Subscribe(1)
+
+		// When
+		sub.Close()
+		sub.Close()
+		bus.Publish(ctx, events.NewChangeEvent(events.TodoCreated))
+
+		// Then
+		assert.Zero(t, bus.Subscribers())
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromChangeEntries(t *testing.T) {
+	t.Run("should describe every todo change by its sequence number", func(t *testing.T) {
+		// Given
+		todo := entities.NewTodo("Buy milk")
+		entries := []*entities.ChangeEntry{
+			{Seq: 1, Kind: entities.ChangeTodoCreated, TodoID: todo.ID, Todo: todo},
+			{Seq: 2, Kind: entities.ChangeTodoDeleted, TodoID: todo.ID},
+		}
+
+		// When
+		changeEvents := events.FromChangeEntries(entries, nil)
+
+		// Then
+		require.Len(t, changeEvents, 2)
+		assert.Equal(t, "1", changeEvents[0].ID)
+		assert.Equal(t, events.TodoCreated, changeEvents[0].Type)
+		assert.Equal(t, "Buy milk", changeEvents[0].Changes[0].Todo.Text)
+		assert.Equal(t, int64(2), changeEvents[1].Seq)
+		assert.Nil(t, changeEvents[1].Changes[0].Todo)
+	})
+
+	t.Run("should merge the changes of a batch into one event at its commit", func(t *testing.T) {
+		// Given
+		batch := []*entities.ChangeEntry{
+			{Seq: 1, Kind: entities.ChangeTodoCreated, TodoID: "a", BatchID: "batch-1"},
+			{Seq: 3, Kind: entities.ChangeTodoUpdated, TodoID: "b", BatchID: "batch-1"},
+		}
+		entries := []*entities.ChangeEntry{
+			batch[1],
+			{Seq: 4, Kind: entities.ChangeTodoUpdated, TodoID: "c"},
+			{Seq: 5, Kind: entities.ChangeBatchCommitted, BatchID: "batch-1"},
+		}
+
+		// When
+		changeEvents := events.FromChangeEntries(entries, map[string][]*entities.ChangeEntry{"batch-1": batch})
+
+		// Then
+		require.Len(t, changeEvents, 2)
+		assert.Equal(t, "c", changeEvents[0].Changes[0].TodoID)
+		assert.Equal(t, "batch-1", changeEvents[1].ID)
+		assert.Equal(t, events.TodosBatchApplied, changeEvents[1].Type)
+		assert.Equal(t, int64(5), changeEvents[1].Seq)
+		assert.Len(t, changeEvents[1].Changes, 2)
+	})
+
+	t.Run("should hold back a batch until it commits", func(t *testing.T) {
+		// Given
+		entries := []*entities.ChangeEntry{
+			{Seq: 1, Kind: entities.ChangeTodoCreated, TodoID: "a", BatchID: "batch-1"},
+		}
+
+		// When
+		changeEvents := events.FromChangeEntries(entries, nil)
+
+		// Then
+		assert.Empty(t, changeEvents)
+		assert.Empty(t, events.CommittedBatches(entries))
+	})
+
+	t.Run("should skip list memberships", func(t *testing.T) {
+		// Given
+		entries := []*entities.ChangeEntry{
+			{Seq: 1, Kind: entities.ChangeListJoined, ListID: "list-1", UserID: "user-1"},
+			{Seq: 2, Kind: entities.ChangeListLeft, ListID: "list-1", UserID: "user-1"},
+		}
+
+		// When
+		changeEvents := events.FromChangeEntries(entries, nil)
+
+		// Then
+		assert.Empty(t, changeEvents)
+	})
+}
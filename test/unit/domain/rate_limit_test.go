@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	"todo-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitBucket_Take(t *testing.T) {
+	// 6 requests per minute: one token every 10 seconds, bursts of 3
+	policy := entities.RateLimitPolicy{Name: "test", Requests: 6, Period: time.Minute, Burst: 3}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should allow a burst up to the capacity", func(t *testing.T) {
+		// Given
+		bucket := entities.NewRateLimitBucket("acme", policy, "ip:1.2.3.4", start)
+
+		// When
+		var decisions []entities.RateLimitDecision
+		for i := 0; i < 4; i++ {
+			decisions = append(decisions, bucket.Take(policy, start))
+		}
+
+		// Then
+		assert.True(t, decisions[0].Allowed)
+		assert.Equal(t, 2, decisions[0].Remaining)
+		assert.True(t, decisions[2].Allowed)
+		assert.Equal(t, 0, decisions[2].Remaining)
+		assert.False(t, decisions[3].Allowed)
+		assert.Equal(t, 10*time.Second, decisions[3].RetryAfter)
+		assert.Equal(t, 30*time.Second, decisions[3].Reset)
+	})
+
+	t.Run("should refill over time", func(t *testing.T) {
+		// Given
+		bucket := entities.NewRateLimitBucket("acme", policy, "ip:1.2.3.4", start)
+		for i := 0; i < 3; i++ {
+			bucket.Take(policy, start)
+		}
+
+		// When
+		early := bucket.Take(policy, start.Add(5*time.Second))
+		onTime := bucket.Take(policy, start.Add(10*time.Second))
+
+		// Then
+		assert.False(t, early.Allowed)
+		assert.Equal(t, 5*time.Second, early.RetryAfter)
+		assert.True(t, onTime.Allowed)
+	})
+
+	t.Run("should not refill beyond the capacity", func(t *testing.T) {
+		// Given
+		bucket := entities.NewRateLimitBucket("acme", policy, "ip:1.2.3.4", start)
+		bucket.Take(policy, start)
+
+		// When
+		decision := bucket.Take(policy, start.Add(time.Hour))
+
+		// Then
+		assert.Equal(t, 2, decision.Remaining)
+		assert.Equal(t, start.Add(time.Hour+10*time.Second), bucket.FullAt)
+	})
+
+	t.Run("should default the capacity to the requests per period", func(t *testing.T) {
+		assert.Equal(t, 6, entities.RateLimitPolicy{Requests: 6, Period: time.Minute}.Capacity())
+		assert.False(t, entities.RateLimitPolicy{Period: time.Minute}.Enabled())
+	})
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"todo-backend/internal/domain/entities"
+	"todo-backend/internal/domain/tenancy"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenancy_ValidateID(t *testing.T) {
+	valid := []string{"acme", "a", "team-42", "0x"}
+	for _, id := range valid {
+		assert.NoError(t, tenancy.ValidateID(id), id)
+	}
+
+	invalid := []string{"", "Acme", "-acme", "acme-", "../acme", "acme.db", "acme corp"}
+	for _, id := range invalid {
+		assert.ErrorIs(t, tenancy.ValidateID(id), tenancy.ErrUnknownTenant, id)
+	}
+}
+
+func TestTenancy_Bind(t *testing.T) {
+	t.Run("should adopt the token's tenant over the fallback", func(t *testing.T) {
+		// Given
+		ctx := tenancy.WithFallbackTenant(context.Background(), "default")
+
+		// When
+		bound, err := tenancy.Bind(ctx, "acme")
+
+		// Then
+		assert.NoError(t, err)
+		tenant, _ := tenancy.FromContext(bound)
+		assert.Equal(t, "acme", tenant)
+	})
+
+	t.Run("should refuse a token for another explicit tenant", func(t *testing.T) {
+		// Given
+		ctx := tenancy.WithTenant(context.Background(), "globex")
+
+		// When
+		_, err := tenancy.Bind(ctx, "acme")
+
+		// Then
+		assert.ErrorIs(t, err, tenancy.ErrUnknownTenant)
+	})
+
+	t.Run("should keep the request's tenant for tokens without one", func(t *testing.T) {
+		ctx := tenancy.WithTenant(context.Background(), "globex")
+
+		bound, err := tenancy.Bind(ctx, "")
+
+		assert.NoError(t, err)
+		tenant, _ := tenancy.FromContext(bound)
+		assert.Equal(t, "globex", tenant)
+	})
+}
+
+func TestTenancy_Require(t *testing.T) {
+	_, err := tenancy.Require(context.Background())
+	assert.ErrorIs(t, err, tenancy.ErrTenantRequired)
+
+	_, err = tenancy.Require(tenancy.WithAllTenants(context.Background()))
+	assert.ErrorIs(t, err, tenancy.ErrTenantRequired, "all tenants is not a tenant")
+}
+
+func TestTenantQuota_Allows(t *testing.T) {
+	usage := &entities.TenantUsage{Todos: 9, StorageBytes: 90}
+
+	assert.True(t, entities.TenantQuota{}.Allows(usage, 100, 1000), "zero limits are unlimited")
+	assert.True(t, entities.TenantQuota{MaxTodos: 10}.Allows(usage, 1, 0))
+	assert.False(t, entities.TenantQuota{MaxTodos: 10}.Allows(usage, 2, 0))
+	assert.True(t, entities.TenantQuota{MaxStorageBytes: 100}.Allows(usage, 0, 10))
+	assert.False(t, entities.TenantQuota{MaxStorageBytes: 100}.Allows(usage, 0, 11))
+	assert.True(t, entities.TenantQuota{MaxTodos: 1, MaxStorageBytes: 1}.Allows(usage, 0, -5), "shrinking is always allowed")
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"todo-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodo_RaisesEvents(t *testing.T) {
+	t.Run("should raise an event for every change, oldest first", func(t *testing.T) {
+		// Given
+		todo := entities.NewTodo("Plan the sprint")
+
+		// When
+		todo.UpdateText("Plan the next sprint")
+		todo.Complete()
+		todo.Reopen()
+		todo.Delete()
+		raised := todo.PullEvents()
+
+		// Then
+		types := make([]entities.TodoEventType, len(raised))
+		for i, event := range raised {
+			types[i] = event.Type
+			assert.NotEmpty(t, event.ID)
+			assert.NotZero(t, event.OccurredAt)
+		}
+		assert.Equal(t, []entities.TodoEventType{
+			entities.TodoCreatedEvent,
+			entities.TodoUpdatedEvent,
+			entities.TodoCompletedEvent,
+			entities.TodoUpdatedEvent,
+			entities.TodoDeletedEvent,
+		}, types)
+	})
+
+	t.Run("should forget the events once pulled", func(t *testing.T) {
+		// Given
+		todo := entities.NewTodo("Plan the sprint")
+		todo.PullEvents()
+
+		// When
+		todo.Complete()
+		raised := todo.PullEvents()
+
+		// Then
+		assert.Len(t, raised, 1)
+		assert.Equal(t, entities.TodoCompletedEvent, raised[0].Type)
+		assert.Empty(t, todo.PullEvents())
+	})
+}
@@ -24,4 +24,19 @@ func TestTodo_Creation(t *testing.T) {
 		assert.NotZero(t, todo.CreatedAt, "CreatedAt should be set")
 		assert.NotZero(t, todo.UpdatedAt, "UpdatedAt should be set")
 	})
-} 
\ No newline at end of file
+}
+
+func TestTodo_Complete(t *testing.T) {
+	t.Run("should mark todo as completed and touch UpdatedAt", func(t *testing.T) {
+		// Given
+		todo := entities.NewTodo("Write batch endpoint")
+		before := todo.UpdatedAt
+
+		// When
+		todo.Complete()
+
+		// Then
+		assert.True(t, todo.Completed)
+		assert.False(t, todo.UpdatedAt.Before(before))
+	})
+}